@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"sshManager/internal/config"
+)
+
+// runAudit prints the local audit log of changes to hosts, passwords and keys,
+// oldest first. Only the log file is read; the encryption key is not needed.
+func runAudit(w io.Writer) error {
+	configPath, err := config.GetDefaultConfigPath()
+	if err != nil {
+		return err
+	}
+
+	entries, err := config.NewManager(configPath).GetAuditLog()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "No changes recorded")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tUSER\tACTION\tKIND\tNAME")
+	for _, entry := range entries {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			entry.Time.Local().Format("2006-01-02 15:04:05"), entry.User, entry.Action, entry.Kind, entry.Name)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"sshManager/internal/config"
+	"sshManager/internal/crypto"
+)
+
+// runExportBundle writes the whole configuration, including secrets, to an
+// encrypted bundle at bundlePath
+func runExportBundle(w io.Writer, bundlePath string) error {
+	configPath, err := config.GetDefaultConfigPath()
+	if err != nil {
+		return err
+	}
+	// Load would create (and sync) an empty configuration, so check first
+	if _, err := os.Stat(configPath); err != nil {
+		return fmt.Errorf("no configuration found at %s", configPath)
+	}
+
+	manager := config.NewManager(configPath)
+	if err := manager.Load(); err != nil {
+		return err
+	}
+
+	password, err := readEncryptionKey()
+	if err != nil {
+		return err
+	}
+	cipher := crypto.NewCipher(string(crypto.GenerateKeyFromPassword(password)))
+	if err := manager.VerifyCipher(cipher); err != nil {
+		return err
+	}
+
+	if err := manager.ExportBundle(bundlePath, cipher); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Exported %d host(s), %d password(s) and %d key(s) to %s\n",
+		len(manager.GetHosts()), len(manager.GetPasswords()), len(manager.GetKeys()), bundlePath)
+	return nil
+}
+
+// runImportBundle replaces the configuration with the content of a bundle.
+// The bundle must have been exported with the same encryption key.
+func runImportBundle(w io.Writer, bundlePath string) error {
+	configPath, err := config.GetDefaultConfigPath()
+	if err != nil {
+		return err
+	}
+
+	password, err := readEncryptionKey()
+	if err != nil {
+		return err
+	}
+	cipher := crypto.NewCipher(string(crypto.GenerateKeyFromPassword(password)))
+
+	manager := config.NewManager(configPath)
+	manager.SetCipher(cipher)
+	if err := manager.Load(); err != nil {
+		return err
+	}
+
+	if err := manager.ImportBundle(bundlePath, cipher); err != nil {
+		return err
+	}
+	if err := manager.Save(); err != nil {
+		return err
+	}
+	pushSavedChanges(manager, cipher)
+
+	fmt.Fprintf(w, "Imported %d host(s), %d password(s) and %d key(s) from %s\n",
+		len(manager.GetHosts()), len(manager.GetPasswords()), len(manager.GetKeys()), bundlePath)
+	fmt.Fprintf(w, "Previous configuration saved as %s.old\n", configPath)
+	return nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+
+	"sshManager/internal/config"
+	"sshManager/internal/crypto"
+	"sshManager/internal/ssh"
+	"sshManager/internal/ui"
+)
+
+// runExec runs a single command on the named host with the built-in client
+// and copies its output to stdout and stderr. It returns the remote exit code;
+// an error means the command could not be run at all.
+func runExec(stdout, stderr io.Writer, hostName, command string) (int, error) {
+	if command == "" {
+		return -1, errors.New("no command given; usage: sshm --exec <host> <command>")
+	}
+
+	configPath, err := config.GetDefaultConfigPath()
+	if err != nil {
+		return -1, err
+	}
+	// Load would create (and sync) an empty configuration, so check first
+	if _, err := os.Stat(configPath); err != nil {
+		return -1, fmt.Errorf("no configuration found at %s", configPath)
+	}
+
+	password, err := readEncryptionKey()
+	if err != nil {
+		return -1, err
+	}
+	cipher := crypto.NewCipher(string(crypto.GenerateKeyFromPassword(password)))
+
+	// The UI model resolves credentials and jump hosts the same way as the host list
+	model := ui.NewModel()
+	if err := model.GetConfig().VerifyCipher(cipher); err != nil {
+		return -1, err
+	}
+	model.SetCipher(cipher)
+
+	host, _, err := model.GetConfig().FindHostByName(hostName)
+	if err != nil {
+		return -1, fmt.Errorf("host '%s' not found in configuration", hostName)
+	}
+
+	authData, err := model.GetHostAuthData(&host)
+	if err != nil {
+		return -1, fmt.Errorf("cannot prepare credentials: %v", err)
+	}
+	defer crypto.Wipe(authData)
+	if err := ssh.RunPreConnectHook(&host); err != nil {
+		return -1, fmt.Errorf("connection aborted: %v", err)
+	}
+
+	sshClient := ssh.NewSSHClient(model.GetPasswords())
+	sshClient.SetJumpHostResolver(model.ResolveJumpHost)
+	sshClient.SetDefaultAlgorithms(model.GetConfig().GetAlgorithms())
+	sshClient.SetAuthPrompter(promptOnTerminal)
+
+	if err := sshClient.Dial(&host, authData); err != nil {
+		// Unknown host keys can only be accepted interactively
+		var verificationRequired *ssh.HostKeyVerificationRequired
+		if errors.As(err, &verificationRequired) && !verificationRequired.Changed {
+			return -1, fmt.Errorf("host key of %s is not known yet; connect once from the host list to verify it",
+				net.JoinHostPort(verificationRequired.IP, verificationRequired.Port))
+		}
+		return -1, err
+	}
+	defer sshClient.Disconnect()
+
+	for _, warning := range sshClient.Warnings() {
+		fmt.Fprintf(stderr, "Warning: %s\n", warning)
+	}
+
+	return sshClient.RunCommand(command, stdout, stderr)
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"sshManager/internal/config"
+	"sshManager/internal/crypto"
+	"sshManager/internal/models"
+
+	"golang.org/x/term"
+)
+
+// EncryptionKeyEnv names the environment variable that can supply the
+// encryption key for non-interactive commands such as --export
+const EncryptionKeyEnv = "SSHM_ENCRYPTION_KEY"
+
+// exportedHost is the public view of a host; secrets are never included
+type exportedHost struct {
+	Name              string            `json:"name"`
+	Description       string            `json:"description,omitempty"`
+	Notes             string            `json:"notes,omitempty"`
+	Group             string            `json:"group,omitempty"`
+	Environment       string            `json:"environment,omitempty"`
+	Tags              []string          `json:"tags,omitempty"`
+	Login             string            `json:"login"`
+	IP                string            `json:"ip"`
+	Port              string            `json:"port"`
+	Auth              string            `json:"auth"` // Credential type and name, e.g. "key:deploy"
+	JumpHost          string            `json:"jump_host,omitempty"`
+	LocalForwards     []string          `json:"local_forwards,omitempty"`
+	RemoteForwards    []string          `json:"remote_forwards,omitempty"`
+	ConnectTimeout    int               `json:"connect_timeout,omitempty"`
+	KeepAliveInterval int               `json:"keep_alive_interval,omitempty"`
+	ReconnectAttempts int               `json:"reconnect_attempts,omitempty"`
+	TerminalType      string            `json:"terminal_type,omitempty"`
+	InitCommands      []string          `json:"init_commands,omitempty"`
+	Env               map[string]string `json:"env,omitempty"`
+	RemoteDir         string            `json:"remote_dir,omitempty"`
+	PreConnectCommand string            `json:"pre_connect_command,omitempty"`
+	Compression       bool              `json:"compression,omitempty"`
+	LogSession        bool              `json:"log_session,omitempty"`
+	UseSystemSSH      bool              `json:"use_system_ssh,omitempty"`
+	Algorithms        string            `json:"algorithms,omitempty"` // Overrides in the host form syntax, e.g. "kex=+diffie-hellman-group1-sha1"
+}
+
+// runExport prints the configured hosts to w in the given format ("json" or
+// "table"). It only reads the local configuration and never talks to the API.
+func runExport(w io.Writer, format string) error {
+	if format != "json" && format != "table" {
+		return fmt.Errorf("unsupported export format %q (use json or table)", format)
+	}
+
+	configPath, err := config.GetDefaultConfigPath()
+	if err != nil {
+		return err
+	}
+	// Load would create (and sync) an empty configuration, so check first
+	if _, err := os.Stat(configPath); err != nil {
+		return fmt.Errorf("no configuration found at %s", configPath)
+	}
+
+	manager := config.NewManager(configPath)
+	if err := manager.Load(); err != nil {
+		return err
+	}
+
+	password, err := readEncryptionKey()
+	if err != nil {
+		return err
+	}
+	cipher := crypto.NewCipher(string(crypto.GenerateKeyFromPassword(password)))
+	if err := manager.VerifyCipher(cipher); err != nil {
+		return err
+	}
+
+	hosts := make([]exportedHost, 0, len(manager.GetHosts()))
+	for _, host := range manager.GetHosts() {
+		hosts = append(hosts, exportedHost{
+			Name:              host.Name,
+			Description:       host.Description,
+			Notes:             host.Notes,
+			Group:             host.Group,
+			Environment:       host.Environment,
+			Tags:              host.Tags,
+			Login:             host.Login,
+			IP:                host.IP,
+			Port:              host.Port,
+			Auth:              describeAuth(manager, host),
+			JumpHost:          host.JumpHost,
+			LocalForwards:     host.LocalForwards,
+			RemoteForwards:    host.RemoteForwards,
+			ConnectTimeout:    host.ConnectTimeout,
+			KeepAliveInterval: host.KeepAliveInterval,
+			ReconnectAttempts: host.ReconnectAttempts,
+			TerminalType:      host.TerminalType,
+			InitCommands:      host.InitCommands,
+			Env:               host.Env,
+			RemoteDir:         host.RemoteDir,
+			PreConnectCommand: host.PreConnectCommand,
+			Compression:       host.Compression,
+			LogSession:        host.LogSession,
+			UseSystemSSH:      host.UseSystemSSH,
+			Algorithms:        host.Algorithms.String(),
+		})
+	}
+
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "    ")
+		return encoder.Encode(hosts)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tGROUP\tLOGIN\tADDRESS\tAUTH\tJUMP HOST\tDESCRIPTION")
+	for _, host := range hosts {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			host.Name, host.Group, host.Login, net.JoinHostPort(host.IP, host.Port),
+			host.Auth, host.JumpHost, host.Description)
+	}
+	return tw.Flush()
+}
+
+// readEncryptionKey takes the key from EncryptionKeyEnv or prompts for it on
+// the terminal (the prompt goes to stderr so stdout stays clean for piping)
+func readEncryptionKey() (string, error) {
+	if key := os.Getenv(EncryptionKeyEnv); key != "" {
+		return key, nil
+	}
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("no terminal to prompt for the encryption key; set %s", EncryptionKeyEnv)
+	}
+
+	fmt.Fprint(os.Stderr, "Encryption key: ")
+	key, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read encryption key: %v", err)
+	}
+	if len(key) == 0 {
+		return "", errors.New("encryption key cannot be empty")
+	}
+	return string(key), nil
+}
+
+// describeAuth names the credentials of a host without revealing them;
+// several authentication methods are listed in the order they are tried
+func describeAuth(manager *config.Manager, host models.Host) string {
+	var methods []string
+	for _, id := range host.GetAuthIDs() {
+		methods = append(methods, describeAuthID(manager, id))
+	}
+	return strings.Join(methods, ",")
+}
+
+// describeAuthID names a single password or key encoded like Host.PasswordID
+func describeAuthID(manager *config.Manager, id int) string {
+	if id >= 0 {
+		if password, err := manager.GetPassword(id); err == nil {
+			return "password:" + password.Description
+		}
+		return "password"
+	}
+
+	if key, ok := models.FindKeyByAuthID(manager.GetKeys(), id); ok {
+		kind := "key"
+		if key.UseAgent {
+			kind = "agent"
+		}
+		return kind + ":" + strings.TrimSpace(key.Description)
+	}
+	return "key"
+}
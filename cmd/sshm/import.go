@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"sshManager/internal/config"
+	"sshManager/internal/crypto"
+)
+
+// runImportSSHConfig imports the hosts of an OpenSSH config file (the user's
+// ~/.ssh/config when sshConfigPath is empty) and saves the configuration
+func runImportSSHConfig(w io.Writer, sshConfigPath string) error {
+	if sshConfigPath == "" {
+		defaultPath, err := config.DefaultSSHConfigPath()
+		if err != nil {
+			return err
+		}
+		sshConfigPath = defaultPath
+	}
+
+	configPath, err := config.GetDefaultConfigPath()
+	if err != nil {
+		return err
+	}
+
+	password, err := readEncryptionKey()
+	if err != nil {
+		return err
+	}
+	cipher := crypto.NewCipher(string(crypto.GenerateKeyFromPassword(password)))
+
+	manager := config.NewManager(configPath)
+	manager.SetCipher(cipher)
+	if err := manager.Load(); err != nil {
+		return err
+	}
+	if err := manager.VerifyCipher(cipher); err != nil {
+		return err
+	}
+
+	result, err := manager.ImportSSHConfig(sshConfigPath)
+	if err != nil {
+		return err
+	}
+	if len(result.Imported) > 0 {
+		if err := manager.Save(); err != nil {
+			return err
+		}
+		pushSavedChanges(manager, cipher)
+	}
+
+	fmt.Fprintf(w, "Imported %d host(s) from %s\n", len(result.Imported), sshConfigPath)
+	if len(result.Imported) > 0 {
+		fmt.Fprintf(w, "  added:   %s\n", strings.Join(result.Imported, ", "))
+	}
+	if len(result.Skipped) > 0 {
+		fmt.Fprintf(w, "  skipped (name already exists): %s\n", strings.Join(result.Skipped, ", "))
+	}
+	if len(result.Keys) > 0 {
+		fmt.Fprintf(w, "  new keys: %s\n", strings.Join(result.Keys, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"sshManager/internal/crypto"
+	"sshManager/internal/ui/messages"
+	"sshManager/internal/ui/views"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// idleCheckInterval is how often the idle timeout is checked.
+const idleCheckInterval = 10 * time.Second
+
+// lockCheckPlaintext is encrypted with the key when the application locks, so
+// the key entered to unlock can be verified against it.
+const lockCheckPlaintext = "sshManager lock"
+
+// idleCheckMsg asks for an idle timeout check. gen ties it to the timer that
+// scheduled it, so checks left over from an earlier run of the program (before
+// a shell session) are ignored.
+type idleCheckMsg struct {
+	gen int
+}
+
+// startIdleTimer resets the idle time and starts checking the idle timeout
+// (if one is configured). It is called whenever the program starts running.
+func (m *programModel) startIdleTimer() tea.Cmd {
+	m.lastActivity = time.Now()
+	m.idleGen++
+	if m.uiModel.GetConfig().GetIdleLock() <= 0 {
+		return nil
+	}
+	return m.scheduleIdleCheck()
+}
+
+// scheduleIdleCheck schedules the next idle timeout check.
+func (m *programModel) scheduleIdleCheck() tea.Cmd {
+	gen := m.idleGen
+	return tea.Tick(idleCheckInterval, func(time.Time) tea.Msg {
+		return idleCheckMsg{gen: gen}
+	})
+}
+
+// checkIdle locks the application when no key was pressed for the configured
+// idle timeout. Nothing is locked before the key has been entered.
+func (m *programModel) checkIdle(msg idleCheckMsg) tea.Cmd {
+	if msg.gen != m.idleGen {
+		return nil
+	}
+	timeout := m.uiModel.GetConfig().GetIdleLock()
+	if timeout <= 0 {
+		return nil
+	}
+	if m.cipher != nil && m.lockedView == nil && time.Since(m.lastActivity) >= timeout {
+		m.lock(timeout)
+	}
+	return m.scheduleIdleCheck()
+}
+
+// lock forgets the encryption key and shows the key prompt in place of the
+// current view. The view is kept, together with any file transfer it is
+// running, and comes back once the key is entered again.
+func (m *programModel) lock(timeout time.Duration) {
+	check, err := m.cipher.Encrypt(lockCheckPlaintext)
+	if err != nil {
+		// Without the check a different key could unlock the application
+		return
+	}
+	m.lockCheck = check
+
+	// The config manager keeps a reference to the cipher, so its key is cleared
+	// instead of only dropping the reference
+	m.cipher.Wipe()
+	m.cipher = nil
+	m.uiModel.SetCipher(nil)
+
+	m.lockedView = m.currentView
+	m.lockedActiveView = m.uiModel.GetActiveView()
+	notice := fmt.Sprintf("Locked after %d min of inactivity", int(timeout.Minutes()))
+	m.currentView = views.NewLockPromptModel(m.uiModel.GetConfig().GetConfigPath(), notice,
+		m.uiModel.GetTerminalWidth(), m.uiModel.GetTerminalHeight())
+}
+
+// updateLocked handles messages while the application is locked. Keys and
+// the mouse only reach the key prompt; other messages go to the locked view,
+// so its background work (e.g. a file transfer) keeps running.
+func (m *programModel) updateLocked(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case messages.PasswordEnteredMsg:
+		return m, m.unlock(string(msg))
+
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			m.quitting = true
+			return m, tea.Quit
+		}
+		m.currentView, cmd = m.currentView.Update(msg)
+		return m, cmd
+
+	case tea.MouseMsg, messages.PasswordRejectedMsg:
+		m.currentView, cmd = m.currentView.Update(msg)
+		return m, cmd
+
+	case tea.WindowSizeMsg:
+		var lockedCmd tea.Cmd
+		m.currentView, cmd = m.currentView.Update(msg)
+		m.lockedView, lockedCmd = m.lockedView.Update(msg)
+		return m, tea.Batch(cmd, lockedCmd)
+
+	default:
+		m.lockedView, cmd = m.lockedView.Update(msg)
+		return m, cmd
+	}
+}
+
+// unlock restores the locked view when password is the key the application
+// was locked with.
+func (m *programModel) unlock(password string) tea.Cmd {
+	key := crypto.GenerateKeyFromPassword(password)
+	cipher := crypto.NewCipher(string(key))
+	if plain, err := cipher.Decrypt(m.lockCheck); err != nil || plain != lockCheckPlaintext {
+		return func() tea.Msg {
+			return messages.PasswordRejectedMsg("Incorrect encryption key")
+		}
+	}
+
+	m.cipher = cipher
+	m.uiModel.SetCipher(cipher)
+	m.uiModel.GetConfig().SetCipher(cipher)
+	m.lockCheck = ""
+	m.lastActivity = time.Now()
+
+	m.currentView = m.lockedView
+	m.lockedView = nil
+	// The locked view may have switched views in the meantime
+	if m.uiModel.GetActiveView() != m.lockedActiveView {
+		m.updateCurrentView()
+		return m.currentView.Init()
+	}
+	return nil
+}
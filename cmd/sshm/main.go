@@ -1,16 +1,21 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sshManager/internal/config"
 	"sshManager/internal/crypto"
+	"sshManager/internal/ssh"
 	"sshManager/internal/sync"
 	"sshManager/internal/ui"
 	"sshManager/internal/ui/messages"
 	"sshManager/internal/ui/views"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"golang.org/x/term"
@@ -23,10 +28,21 @@ type programModel struct {
 	currentView tea.Model      // Represents the current active view
 	cipher      *crypto.Cipher // Handles encryption/decryption
 	restarting  bool           // Indicates if the program is restarting
+	connectHost string         // Host to connect to right after startup (--connect)
+	exitErr     error          // Error reported after the program exits, with a non-zero status
+
+	lastActivity     time.Time // Time of the last keypress, for the idle lock
+	idleGen          int       // Current idle timer (see idleCheckMsg)
+	lockedView       tea.Model // View hidden behind the key prompt while locked (nil when unlocked)
+	lockedActiveView ui.View   // Active view at the time of locking
+	lockCheck        string    // Known plaintext encrypted with the key the application was locked with
+
+	pushing bool // A background push of saved changes to the API is running
+	pushGen int  // Current background push (see configPushedMsg)
 }
 
 // Initializes the initial program model
-func initialModel() *programModel {
+func initialModel(connectHost string) *programModel {
 	uiModel := ui.NewModel()
 
 	// Retrieve the path to the configuration file
@@ -47,6 +63,7 @@ func initialModel() *programModel {
 	return &programModel{
 		uiModel:     uiModel,
 		currentView: initialPrompt,
+		connectHost: connectHost,
 	}
 }
 
@@ -55,9 +72,14 @@ func (m *programModel) IsRestarting() bool {
 	return m.restarting
 }
 
-// Initialize the program's initial view
+// Initialize the program's initial view; also called when the program runs
+// again after a shell session
 func (m *programModel) Init() tea.Cmd {
-	return m.currentView.Init()
+	// A push started before a shell session reports to the previous run, so
+	// stop waiting for it; config.Manager keeps a new push from overlapping it
+	m.pushing = false
+	m.pushGen++
+	return tea.Batch(m.currentView.Init(), m.startIdleTimer())
 }
 
 // Sets the tea.Program instance for the UI model
@@ -99,11 +121,34 @@ func (m *programModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, tea.Quit
 	}
 
+	// Idle lock: any keypress counts as activity
+	if msg, ok := msg.(idleCheckMsg); ok {
+		return m, m.checkIdle(msg)
+	}
+	if msg, ok := msg.(configPushedMsg); ok {
+		return m, m.handleConfigPushed(msg)
+	}
+	if m.lockedView != nil {
+		return m.updateLocked(msg)
+	}
+	if _, ok := msg.(tea.KeyMsg); ok {
+		m.lastActivity = time.Now()
+	}
+
 	switch msg := msg.(type) {
 	case messages.PasswordEnteredMsg:
 		// Initialize the encryption cipher
 		key := crypto.GenerateKeyFromPassword(string(msg))
-		m.cipher = crypto.NewCipher(string(key))
+		cipher := crypto.NewCipher(string(key))
+
+		// A mistyped key would only show up later as decryption errors, so ask again
+		if err := m.uiModel.GetConfig().VerifyCipher(cipher); err != nil {
+			return m, func() tea.Msg {
+				return messages.PasswordRejectedMsg("Incorrect encryption key")
+			}
+		}
+
+		m.cipher = cipher
 		m.uiModel.SetCipher(m.cipher)
 		m.uiModel.GetConfig().SetCipher(m.cipher) // Set the cipher in the config
 
@@ -122,8 +167,8 @@ func (m *programModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.LocalMode {
 			// User selected local mode (pressed ESC)
 			m.uiModel.SetLocalMode(true)
-			m.updateCurrentView()
-			return m, m.currentView.Init()
+			m.ensureKeyCheck()
+			return m, m.startMainView()
 		}
 
 		// Save the new API key
@@ -140,6 +185,16 @@ func (m *programModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.quitting = true
 		return m, tea.Quit
 
+	case messages.SwitchProfileMsg:
+		// The other profile has its own configuration and encryption key, so
+		// start over with the key prompt
+		if err := config.SetProfile(string(msg)); err != nil {
+			return m, nil
+		}
+		m.restarting = true
+		m.quitting = true
+		return m, tea.Quit
+
 	default:
 		// Store the currently active view
 		currentActiveView := m.uiModel.GetActiveView()
@@ -153,7 +208,8 @@ func (m *programModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.updateCurrentView()
 		}
 
-		return m, cmd
+		// Push changes the view saved, without making it wait for the API
+		return m, tea.Batch(cmd, m.pushConfig())
 	}
 }
 
@@ -161,8 +217,8 @@ func (m *programModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m *programModel) handleApiKeyAndSync(apiKey string, isLocalMode bool) tea.Cmd {
 	if isLocalMode {
 		m.uiModel.SetLocalMode(true)
-		m.updateCurrentView()
-		return m.currentView.Init()
+		m.ensureKeyCheck()
+		return m.startMainView()
 	}
 
 	// Retrieve paths
@@ -173,28 +229,36 @@ func (m *programModel) handleApiKeyAndSync(apiKey string, isLocalMode bool) tea.
 	}
 	keysDir := filepath.Join(filepath.Dir(configPath), config.DefaultKeysDir)
 
-	// Create backups
-	if err := sync.BackupConfigFile(configPath); err != nil {
-		fmt.Printf("Warning: Could not create config backup: %v\n", err)
+	// Create a backup
+	if _, err := sync.CreateBackup(configPath, keysDir); err != nil {
+		fmt.Printf("Warning: Could not create backup: %v\n", err)
 	}
-	if err := sync.BackupKeys(keysDir); err != nil {
-		fmt.Printf("Warning: Could not create keys backup: %v\n", err)
+
+	// Push changes made while offline first, so the pull below does not overwrite them
+	if err := m.uiModel.GetConfig().FlushPendingChanges(apiKey); err != nil {
+		m.uiModel.SetSyncError(fmt.Errorf("could not push pending changes: %v", err))
+		m.uiModel.SetLocalMode(true)
+		m.ensureKeyCheck()
+		return m.startMainView()
 	}
 
 	// Synchronize with the API
 	syncResp, err := sync.SyncWithAPI(apiKey)
 	if err != nil {
-		fmt.Printf("Warning: Could not sync with API: %v\n", err)
+		m.uiModel.SetSyncError(fmt.Errorf("could not sync with API: %v", err))
 		m.uiModel.SetLocalMode(true)
 	} else {
 		// Save data from the API
 		if err := sync.SaveAPIData(configPath, keysDir, syncResp.Data, m.cipher); err != nil {
-			fmt.Printf("Warning: Could not save API data: %v\n", err)
+			m.uiModel.SetSyncError(fmt.Errorf("could not save API data: %v", err))
 			if err := sync.RestoreFromBackup(configPath, keysDir); err != nil {
 				fmt.Printf("Error: Could not restore from backup: %v\n", err)
 				os.Exit(1)
 			}
 			m.uiModel.SetLocalMode(true)
+			// The synced data could not be decrypted, so the key is not confirmed
+			// and no key check is written
+			return m.startMainView()
 		} else {
 			// Load the saved configuration into the UI model
 			if err := m.uiModel.GetConfig().Load(); err != nil {
@@ -206,8 +270,39 @@ func (m *programModel) handleApiKeyAndSync(apiKey string, isLocalMode bool) tea.
 	}
 
 	// Switch to the main view
+	m.ensureKeyCheck()
+	return m.startMainView()
+}
+
+// Stores a key check for the entered encryption key on first use, so a
+// mistyped key is rejected on later starts
+func (m *programModel) ensureKeyCheck() {
+	if err := m.uiModel.GetConfig().EnsureKeyCheck(m.cipher); err != nil {
+		fmt.Printf("Warning: Could not save key check: %v\n", err)
+	}
+}
+
+// Switches to the main view once the configuration is ready and, when started
+// with --connect, immediately connects to the requested host
+func (m *programModel) startMainView() tea.Cmd {
 	m.updateCurrentView()
-	return m.currentView.Init()
+	if m.connectHost == "" {
+		return m.currentView.Init()
+	}
+
+	// Connect only once; after the session ends the regular host list is shown
+	name := m.connectHost
+	m.connectHost = ""
+
+	mainView := views.NewMainView(m.uiModel)
+	cmd, err := mainView.ConnectToHostByName(name)
+	if err != nil {
+		m.exitErr = err
+		m.quitting = true
+		return tea.Quit
+	}
+	m.currentView = mainView
+	return tea.Batch(mainView.Init(), cmd)
 }
 
 // Renders the current view or a goodbye message if quitting
@@ -220,7 +315,101 @@ func (m *programModel) View() string {
 
 // Main entry point of the application
 func main() {
-	m := initialModel()
+	connectHost := flag.String("connect", "", "connect directly to the host with the given name")
+	execHost := flag.String("exec", "", "run the command given after the flags on the host with the given name, print its output and exit with its exit code")
+	export := flag.Bool("export", false, "print the host list (without secrets) to stdout and exit")
+	exportFormat := flag.String("format", "json", "output format for --export: json or table")
+	importSSHConfig := flag.Bool("import-ssh-config", false, "import hosts from ~/.ssh/config (or the file given as argument) and exit")
+	logRaw := flag.Bool("log-raw", false, "keep terminal control sequences in session logs")
+	stats := flag.Bool("stats", false, "print the hosts ranked by number of connections and exit")
+	audit := flag.Bool("audit", false, "print the local log of changes to hosts, passwords and keys and exit")
+	exportBundle := flag.String("export-bundle", "", "write the whole configuration to an encrypted bundle file and exit")
+	importBundle := flag.String("import-bundle", "", "replace the configuration with an encrypted bundle file and exit")
+	changeKey := flag.Bool("change-key", false, "re-encrypt all stored secrets with a new encryption key and exit")
+	profile := flag.String("profile", "", "use the configuration profile with the given name (created on first use)")
+	theme := flag.String("theme", "", "color theme for this run, overriding the saved one ("+strings.Join(ui.ThemeNames(), ", ")+")")
+	flag.Parse()
+
+	// Every command below reads the configuration of the selected profile
+	if err := config.SetProfile(*profile); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *execHost != "" {
+		code, err := runExec(os.Stdout, os.Stderr, *execHost, strings.Join(flag.Args(), " "))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(255) // Like ssh, to tell connection errors from remote exit codes
+		}
+		os.Exit(code)
+	}
+
+	if *stats {
+		if err := runStats(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *audit {
+		if err := runAudit(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *changeKey {
+		if err := runChangeKey(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *exportBundle != "" {
+		if err := runExportBundle(os.Stdout, *exportBundle); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *importBundle != "" {
+		if err := runImportBundle(os.Stdout, *importBundle); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *importSSHConfig {
+		if err := runImportSSHConfig(os.Stdout, flag.Arg(0)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *export {
+		if err := runExport(os.Stdout, *exportFormat); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	m := initialModel(*connectHost)
+
+	// The theme given on the command line wins over the one saved in the configuration
+	if *theme != "" {
+		if err := ui.SetThemeByName(*theme); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
 	var p *tea.Program
 	var savedProgram *tea.Program // Variable for storing the program instance
 
@@ -230,7 +419,7 @@ func main() {
 			p = savedProgram
 			savedProgram = nil
 		} else {
-			p = tea.NewProgram(m, tea.WithAltScreen())
+			p = tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
 			m.SetProgram(p)
 		}
 
@@ -245,7 +434,24 @@ func main() {
 		}
 
 		m = model.(*programModel)
+		if m.exitErr != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", m.exitErr)
+			os.Exit(1)
+		}
+		if m.restarting {
+			// Start over (e.g. with another profile): the configuration is
+			// loaded again and the encryption key is asked for
+			m.uiModel.CloseConnection()
+			ui.SetThemeByName(ui.ThemeNames()[0]) // The new configuration may not save a theme
+			m = initialModel("")
+			if *theme != "" {
+				ui.SetThemeByName(*theme)
+			}
+			continue
+		}
 		if m.quitting {
+			// Close the connection kept open for the next shell or transfer
+			m.uiModel.CloseConnection()
 			break
 		}
 
@@ -258,28 +464,59 @@ func main() {
 					continue
 				}
 
-				// Handle SSH session
-				sessionDone := make(chan error)
-				go func() {
-					if err := session.ConfigureTerminal("xterm-256color"); err != nil {
-						sessionDone <- fmt.Errorf("failed to configure terminal: %v", err)
-						return
+				// Report connection warnings (e.g. ssh-agent fallback) before the shell starts
+				for _, warning := range sshClient.Warnings() {
+					fmt.Fprintf(os.Stderr, "Warning: %s\r\n", warning)
+				}
+
+				// Transcript of the session; a log that cannot be created is only a warning
+				var logFile *os.File
+				var sessionLog io.Writer
+				if host := sshClient.GetCurrentHost(); host != nil && host.LogSession {
+					var err error
+					if logFile, err = ssh.OpenSessionLog(host); err != nil {
+						fmt.Fprintf(os.Stderr, "Warning: session will not be logged: %v\r\n", err)
+					} else {
+						sessionLog = ssh.NewSessionLogWriter(logFile, *logRaw)
 					}
-					sessionDone <- session.StartShell()
-				}()
+				}
 
-				if err := <-sessionDone; err != nil {
+				// Handle SSH session, reconnecting if the connection drops
+				err := runSession(sshClient, sessionLog)
+				openTransfer := errors.Is(err, ssh.ErrTransferRequested)
+				if err != nil && !openTransfer {
 					fmt.Fprintf(os.Stderr, "Session error: %v\n", err)
 				}
+				if logFile != nil {
+					logFile.Close()
+				}
 
-				// Close the session
+				// The transfer key hands the open connection to file transfer mode
+				var transferErr error
+				if openTransfer {
+					host := sshClient.GetCurrentHost()
+					if transferErr = m.uiModel.GetTransfer().ConnectUsing(sshClient); transferErr == nil {
+						m.uiModel.SetSSHClient(nil)
+						m.uiModel.SetSelectedHost(host)
+						m.uiModel.ClearSelection()
+						m.uiModel.SetActiveView(ui.ViewTransfer)
+						m.currentView = views.NewTransferView(m.uiModel)
+						continue
+					}
+				}
+
+				// Close the session (also stops all port forwards)
 				sshClient.Disconnect()
 				m.uiModel.SetSSHClient(nil)
 				m.uiModel.SetActiveView(ui.ViewMain)
 
 				// Create a new main view with a popup
 				mainView := views.NewMainView(m.uiModel)
-				mainView.ShowSessionEndedPopup()
+				if transferErr != nil {
+					mainView.ShowTransferFailedError(transferErr)
+				} else {
+					mainView.ShowSessionEndedPopup()
+				}
 				m.currentView = mainView
 
 				continue
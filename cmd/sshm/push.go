@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"sshManager/internal/config"
+	"sshManager/internal/crypto"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// configPushedMsg reports the end of a background push of saved changes. gen
+// ties it to the push that sent it, so a result left over from an earlier run
+// of the program (before a shell session) is ignored.
+type configPushedMsg struct {
+	gen    int
+	pushed bool
+	err    error
+}
+
+// pushConfig starts pushing the saved changes to the API in the background
+// when a view saved the configuration since the last push. Saving never waits
+// for the API; the changes stay queued until a push succeeds.
+func (m *programModel) pushConfig() tea.Cmd {
+	if m.pushing || m.cipher == nil || !m.uiModel.TakePushRequest() {
+		return nil
+	}
+	cfg := m.uiModel.GetConfig()
+	apiKey, err := cfg.LoadApiKey(m.cipher)
+	if err != nil {
+		// Local mode: there is no API to push to
+		return nil
+	}
+
+	// The push gets its own copy of the cipher, so locking the application
+	// (which wipes the key) or changing the key does not affect it
+	cipher := m.cipher.Clone()
+	m.pushing = true
+	m.pushGen++
+	gen := m.pushGen
+	return func() tea.Msg {
+		defer cipher.Wipe()
+		pushed, err := cfg.PushPendingChanges(apiKey, cipher)
+		return configPushedMsg{gen: gen, pushed: pushed, err: err}
+	}
+}
+
+// handleConfigPushed records the result of a background push. A failure is
+// shown in the status bar and the changes are pushed again with the next save
+// or sync.
+func (m *programModel) handleConfigPushed(msg configPushedMsg) tea.Cmd {
+	if msg.gen != m.pushGen {
+		return nil
+	}
+	m.pushing = false
+	if msg.err != nil {
+		m.uiModel.SetPushError(msg.err)
+		return nil
+	}
+	m.uiModel.SetPushError(nil)
+	if msg.pushed {
+		if err := m.uiModel.GetConfig().MarkSynced(); err != nil {
+			m.uiModel.SetPushError(fmt.Errorf("failed to save sync time: %v", err))
+		}
+	}
+	return m.pushConfig()
+}
+
+// pushSavedChanges pushes the changes a command saved to the API when sync is
+// configured and reports whether they reached it. The command does not fail
+// when the API cannot be reached: the changes stay queued for the next sync.
+func pushSavedChanges(manager *config.Manager, cipher *crypto.Cipher) bool {
+	apiKey, err := manager.LoadApiKey(cipher)
+	if err != nil {
+		return false
+	}
+	if err := manager.FlushPendingChanges(apiKey); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v; the changes are queued for the next sync\n", err)
+		return false
+	}
+	return true
+}
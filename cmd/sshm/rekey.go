@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"sshManager/internal/config"
+	"sshManager/internal/crypto"
+
+	"golang.org/x/term"
+)
+
+// runChangeKey re-encrypts all stored secrets with a new encryption key.
+// The current key is taken like for the other commands; the new one is always
+// prompted for twice on the terminal.
+func runChangeKey(w io.Writer) error {
+	configPath, err := config.GetDefaultConfigPath()
+	if err != nil {
+		return err
+	}
+	// Load would create (and sync) an empty configuration, so check first
+	if _, err := os.Stat(configPath); err != nil {
+		return fmt.Errorf("no configuration found at %s", configPath)
+	}
+
+	manager := config.NewManager(configPath)
+	if err := manager.Load(); err != nil {
+		return err
+	}
+
+	password, err := readEncryptionKey()
+	if err != nil {
+		return err
+	}
+	oldCipher := crypto.NewCipher(string(crypto.GenerateKeyFromPassword(password)))
+	if err := manager.VerifyCipher(oldCipher); err != nil {
+		return err
+	}
+
+	newPassword, err := readNewEncryptionKey()
+	if err != nil {
+		return err
+	}
+	if newPassword == password {
+		return errors.New("the new encryption key is the same as the current one")
+	}
+	newCipher := crypto.NewCipher(string(crypto.GenerateKeyFromPassword(newPassword)))
+
+	if err := manager.ChangeCipher(oldCipher, newCipher); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Encryption key changed; previous configuration saved as %s.old\n", configPath)
+
+	// Push the re-encrypted data, so the sync API does not keep the old encryption
+	if manager.HasApiKey() {
+		if err := manager.Save(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		} else if pushSavedChanges(manager, newCipher) {
+			fmt.Fprintln(w, "Synced data re-encrypted; use the new key on your other devices")
+		}
+	}
+	return nil
+}
+
+// readNewEncryptionKey prompts for the new encryption key and its confirmation
+func readNewEncryptionKey() (string, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", errors.New("no terminal to prompt for the new encryption key")
+	}
+
+	fmt.Fprint(os.Stderr, "New encryption key: ")
+	key, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read encryption key: %v", err)
+	}
+	if len(key) == 0 {
+		return "", errors.New("encryption key cannot be empty")
+	}
+
+	fmt.Fprint(os.Stderr, "Repeat new encryption key: ")
+	confirm, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read encryption key: %v", err)
+	}
+	if string(confirm) != string(key) {
+		return "", errors.New("encryption keys do not match")
+	}
+	return string(key), nil
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"sshManager/internal/models"
+	"sshManager/internal/ssh"
+
+	"golang.org/x/term"
+)
+
+// maxReconnectDelay caps the exponential backoff between reconnect attempts.
+const maxReconnectDelay = 30 * time.Second
+
+// runSession runs the interactive shell of the connected client. When the
+// connection drops (not a clean exit), it reconnects up to the host's
+// ReconnectAttempts times and starts a new shell; after every successful
+// reconnect the full number of attempts is available again.
+func runSession(sshClient *ssh.SSHClient, log io.Writer) error {
+	maxAttempts := 0
+	if host := sshClient.GetCurrentHost(); host != nil {
+		maxAttempts = host.ReconnectAttempts
+	}
+	// The TUI is gone, so reconnects ask keyboard-interactive questions here
+	sshClient.SetAuthPrompter(promptOnTerminal)
+
+	for {
+		err := runShell(sshClient, log)
+		if maxAttempts == 0 || !errors.Is(err, ssh.ErrConnectionLost) {
+			return err
+		}
+
+		fmt.Fprintf(os.Stderr, "\r\n%v\r\n", err)
+		if !reconnect(sshClient, maxAttempts) {
+			return fmt.Errorf("%w (gave up after %d reconnect attempts)", err, maxAttempts)
+		}
+		if log != nil {
+			fmt.Fprintf(log, "\r\n--- reconnected at %s ---\r\n", time.Now().Format(time.RFC3339))
+		}
+	}
+}
+
+// reconnect tries to restore the connection with exponential backoff and
+// reports whether it succeeded.
+func reconnect(sshClient *ssh.SSHClient, maxAttempts int) bool {
+	delay := time.Second
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		fmt.Fprintf(os.Stderr, "Reconnecting in %v (attempt %d of %d)...\r\n", delay, attempt, maxAttempts)
+		time.Sleep(delay)
+
+		if err := sshClient.Reconnect(); err != nil {
+			fmt.Fprintf(os.Stderr, "Reconnect failed: %v\r\n", err)
+			delay = min(delay*2, maxReconnectDelay)
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "Reconnected.\r\n")
+		return true
+	}
+	return false
+}
+
+// runShell configures the client's current session for its host and runs the
+// shell until it ends.
+func runShell(sshClient *ssh.SSHClient, log io.Writer) error {
+	session := sshClient.Session()
+	if session == nil {
+		return errors.New("no active session")
+	}
+
+	// Keep-alive interval, init commands, environment and TERM configured for the host
+	termType := models.DefaultTerminalType
+	if host := sshClient.GetCurrentHost(); host != nil {
+		session.SetKeepAlive(host.GetKeepAliveInterval())
+		session.SetInitCommands(host.InitCommands)
+		session.SetEnv(host.Env)
+		termType = host.GetTerminalType()
+	}
+	if log != nil {
+		session.SetLog(log)
+	}
+
+	// Start remote port forwards once the shell is running; a failed
+	// forward is reported but does not end the session
+	session.SetShellStartedHook(func() {
+		for _, err := range sshClient.StartRemoteForwards() {
+			fmt.Fprintf(os.Stderr, "Warning: %v\r\n", err)
+		}
+	})
+
+	sessionDone := make(chan error)
+	go func() {
+		if err := session.ConfigureTerminal(termType); err != nil {
+			sessionDone <- fmt.Errorf("failed to configure terminal: %v", err)
+			return
+		}
+		sessionDone <- session.StartShell()
+	}()
+	return <-sessionDone
+}
+
+// promptOnTerminal answers keyboard-interactive questions (e.g. a one-time
+// code) on the terminal; answers to hidden questions are not echoed.
+func promptOnTerminal(challenge ssh.AuthChallenge) ([]string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return nil, errors.New("no terminal to answer the authentication prompt")
+	}
+
+	for _, text := range []string{challenge.Name, challenge.Instruction} {
+		if text = strings.TrimSpace(text); text != "" {
+			fmt.Fprintf(os.Stderr, "%s\r\n", text)
+		}
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	answers := make([]string, len(challenge.Questions))
+	for i, question := range challenge.Questions {
+		fmt.Fprint(os.Stderr, question)
+		if challenge.Echos[i] {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return nil, fmt.Errorf("failed to read answer: %v", err)
+			}
+			answers[i] = strings.TrimRight(line, "\r\n")
+			continue
+		}
+
+		answer, err := term.ReadPassword(fd)
+		fmt.Fprint(os.Stderr, "\r\n")
+		if err != nil {
+			return nil, fmt.Errorf("failed to read answer: %v", err)
+		}
+		answers[i] = string(answer)
+	}
+	return answers, nil
+}
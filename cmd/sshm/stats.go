@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"sshManager/internal/config"
+	"sshManager/internal/models"
+)
+
+// runStats prints the hosts ranked by the number of successful connections.
+// Hosts that were never used are listed last, which helps to find servers
+// that can be retired. Only the local configuration is read.
+func runStats(w io.Writer) error {
+	configPath, err := config.GetDefaultConfigPath()
+	if err != nil {
+		return err
+	}
+	// Load would create (and sync) an empty configuration, so check first
+	if _, err := os.Stat(configPath); err != nil {
+		return fmt.Errorf("no configuration found at %s", configPath)
+	}
+
+	manager := config.NewManager(configPath)
+	if err := manager.Load(); err != nil {
+		return err
+	}
+
+	hosts := make([]models.Host, len(manager.GetHosts()))
+	copy(hosts, manager.GetHosts())
+	sort.SliceStable(hosts, func(i, j int) bool {
+		if hosts[i].ConnectCount != hosts[j].ConnectCount {
+			return hosts[i].ConnectCount > hosts[j].ConnectCount
+		}
+		return hosts[i].LastConnected.After(hosts[j].LastConnected)
+	})
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RANK\tNAME\tGROUP\tCONNECTIONS\tLAST CONNECTED")
+	for i, host := range hosts {
+		lastConnected := "never"
+		if !host.LastConnected.IsZero() {
+			lastConnected = host.LastConnected.Local().Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%d\t%s\n",
+			i+1, host.Name, host.Group, host.ConnectCount, lastConnected)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,91 @@
+// internal/config/audit.go
+//
+// The audit log is a local, append-only record of changes to hosts, passwords
+// and keys. Every line is a JSON object, so the file can also be read with
+// other tools. It is never synced.
+
+package config
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"time"
+)
+
+// AuditLogFileName specifies the file in the config directory holding the audit log.
+const AuditLogFileName = "audit.log"
+
+// AuditEntry is one change recorded in the audit log.
+type AuditEntry struct {
+	Time   time.Time `json:"time"`           // When the change was made
+	User   string    `json:"user,omitempty"` // Local account that made the change
+	Action string    `json:"action"`         // add, update or delete
+	Kind   string    `json:"kind"`           // host, password, key or backup
+	Name   string    `json:"name"`           // Host name, password/key description or backup name
+}
+
+// auditLogPath returns the path of the audit log file.
+func (m *Manager) auditLogPath() string {
+	return filepath.Join(filepath.Dir(m.configPath), AuditLogFileName)
+}
+
+// audit appends a change to the audit log. A change must not fail because it
+// cannot be logged, so write errors are ignored.
+func (m *Manager) audit(entry AuditEntry) {
+	entry.User = currentUserName()
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	f, err := os.OpenFile(m.auditLogPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, DefaultFilePerms)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	f.Write(append(line, '\n'))
+}
+
+// GetAuditLog returns the changes recorded in the audit log, oldest first. A
+// missing log file means no changes were recorded; lines that cannot be parsed
+// are skipped.
+func (m *Manager) GetAuditLog() ([]AuditEntry, error) {
+	f, err := os.Open(m.auditLogPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %v", err)
+	}
+	defer f.Close()
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %v", err)
+	}
+	return entries, nil
+}
+
+// currentUserName returns the name of the local account running the program.
+func currentUserName() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return os.Getenv("USERNAME")
+}
@@ -0,0 +1,125 @@
+// internal/config/backup_diff.go
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sshManager/internal/models"
+	"sshManager/internal/sync"
+	"time"
+)
+
+// BackupDiff summarizes what restoring a backup would change in the current
+// configuration. Hosts are matched by name, passwords and keys by description.
+type BackupDiff struct {
+	HostsBefore, HostsAfter         int
+	PasswordsBefore, PasswordsAfter int
+	KeysBefore, KeysAfter           int
+
+	AddedHosts, RemovedHosts, ChangedHosts []string
+	AddedPasswords, RemovedPasswords       []string
+	AddedKeys, RemovedKeys                 []string
+}
+
+// IsEmpty reports whether the backup matches the current configuration.
+func (d BackupDiff) IsEmpty() bool {
+	return len(d.AddedHosts) == 0 && len(d.RemovedHosts) == 0 && len(d.ChangedHosts) == 0 &&
+		len(d.AddedPasswords) == 0 && len(d.RemovedPasswords) == 0 &&
+		len(d.AddedKeys) == 0 && len(d.RemovedKeys) == 0
+}
+
+// DiffBackup compares the current configuration with a backup without changing anything.
+func (m *Manager) DiffBackup(backup sync.Backup) (BackupDiff, error) {
+	data, err := os.ReadFile(backup.ConfigFile)
+	if err != nil {
+		return BackupDiff{}, fmt.Errorf("failed to read backup: %v", err)
+	}
+	var restored models.Config
+	if err := json.Unmarshal(data, &restored); err != nil {
+		return BackupDiff{}, fmt.Errorf("backup is corrupt: %v", err)
+	}
+
+	m.dataMutex.RLock()
+	defer m.dataMutex.RUnlock()
+
+	diff := BackupDiff{
+		HostsBefore:     len(m.config.Hosts),
+		HostsAfter:      len(restored.Hosts),
+		PasswordsBefore: len(m.config.Passwords),
+		PasswordsAfter:  len(restored.Passwords),
+		KeysBefore:      len(m.config.Keys),
+		KeysAfter:       len(restored.Keys),
+	}
+
+	current := make(map[string]models.Host, len(m.config.Hosts))
+	for _, host := range m.config.Hosts {
+		current[host.Name] = host
+	}
+	restoredHosts := make(map[string]bool, len(restored.Hosts))
+	for _, host := range restored.Hosts {
+		restoredHosts[host.Name] = true
+		previous, ok := current[host.Name]
+		switch {
+		case !ok:
+			diff.AddedHosts = append(diff.AddedHosts, host.Name)
+		case !sameHostSettings(previous, host):
+			diff.ChangedHosts = append(diff.ChangedHosts, host.Name)
+		}
+	}
+	for _, host := range m.config.Hosts {
+		if !restoredHosts[host.Name] {
+			diff.RemovedHosts = append(diff.RemovedHosts, host.Name)
+		}
+	}
+
+	diff.AddedPasswords, diff.RemovedPasswords = diffNames(passwordNames(m.config.Passwords), passwordNames(restored.Passwords))
+	diff.AddedKeys, diff.RemovedKeys = diffNames(keyNames(m.config.Keys), keyNames(restored.Keys))
+	return diff, nil
+}
+
+// sameHostSettings compares two hosts, ignoring the connection statistics.
+func sameHostSettings(a, b models.Host) bool {
+	a.LastConnected, b.LastConnected = time.Time{}, time.Time{}
+	a.ConnectCount, b.ConnectCount = 0, 0
+	return reflect.DeepEqual(a, b)
+}
+
+// diffNames returns the names only in after (added) and only in before (removed).
+func diffNames(before, after []string) (added, removed []string) {
+	inBefore := make(map[string]bool, len(before))
+	for _, name := range before {
+		inBefore[name] = true
+	}
+	inAfter := make(map[string]bool, len(after))
+	for _, name := range after {
+		inAfter[name] = true
+		if !inBefore[name] {
+			added = append(added, name)
+		}
+	}
+	for _, name := range before {
+		if !inAfter[name] {
+			removed = append(removed, name)
+		}
+	}
+	return added, removed
+}
+
+func passwordNames(passwords []models.Password) []string {
+	names := make([]string, len(passwords))
+	for i, password := range passwords {
+		names[i] = password.Description
+	}
+	return names
+}
+
+func keyNames(keys []models.Key) []string {
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		names[i] = key.Description
+	}
+	return names
+}
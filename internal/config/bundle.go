@@ -0,0 +1,171 @@
+// internal/config/bundle.go
+//
+// Encrypted bundles hold the complete configuration (hosts, passwords, keys and
+// bookmarks) in a single file, so a setup can be moved between machines
+// without the sync API.
+
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"sshManager/internal/crypto"
+	"sshManager/internal/models"
+	"sshManager/internal/sync"
+)
+
+const (
+	// BundleFormat identifies sshManager bundle files.
+	BundleFormat = "sshm-bundle"
+
+	// BundleVersion is the version written by ExportBundle. ImportBundle accepts
+	// this and all earlier versions.
+	BundleVersion = 1
+)
+
+// bundleFile is the on-disk envelope of a bundle. Only Data is encrypted, so
+// the format and version can be checked before decryption.
+type bundleFile struct {
+	Format  string `json:"format"`
+	Version int    `json:"version"`
+	Data    string `json:"data"` // Encrypted JSON of bundleData
+}
+
+// bundleData is the encrypted content of a version 1 bundle. Passwords and
+// key data stay encrypted with the same cipher as in the configuration file.
+type bundleData struct {
+	Hosts       []models.Host       `json:"hosts"`
+	Passwords   []models.Password   `json:"passwords"`
+	Keys        []models.Key        `json:"keys"`
+	Bookmarks   []models.Bookmark   `json:"bookmarks,omitempty"`
+	HostSort    string              `json:"host_sort,omitempty"`
+	Theme       string              `json:"theme,omitempty"`
+	KeyBindings map[string][]string `json:"key_bindings,omitempty"`
+	LocalDir    string              `json:"local_dir,omitempty"`
+	NoPreserve  bool                `json:"no_preserve,omitempty"`
+	RateLimit   int                 `json:"rate_limit,omitempty"`
+	LastHost    string              `json:"last_host,omitempty"`
+	IdleLock    int                 `json:"idle_lock,omitempty"`
+}
+
+// ExportBundle writes the whole configuration to an encrypted bundle at path.
+func (m *Manager) ExportBundle(path string, cipher *crypto.Cipher) error {
+	m.dataMutex.RLock()
+	data, err := json.Marshal(bundleData{
+		Hosts:       m.config.Hosts,
+		Passwords:   m.config.Passwords,
+		Keys:        m.config.Keys,
+		Bookmarks:   m.config.Bookmarks,
+		HostSort:    m.config.HostSort,
+		Theme:       m.config.Theme,
+		KeyBindings: m.config.KeyBindings,
+		LocalDir:    m.config.LocalDir,
+		NoPreserve:  m.config.NoPreserve,
+		RateLimit:   m.config.RateLimit,
+		LastHost:    m.config.LastHost,
+		IdleLock:    m.config.IdleLock,
+	})
+	m.dataMutex.RUnlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal bundle: %v", err)
+	}
+
+	encrypted, err := cipher.Encrypt(string(data))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt bundle: %v", err)
+	}
+
+	content, err := json.MarshalIndent(bundleFile{
+		Format:  BundleFormat,
+		Version: BundleVersion,
+		Data:    encrypted,
+	}, "", "    ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal bundle: %v", err)
+	}
+
+	if err := os.WriteFile(path, content, DefaultFilePerms); err != nil {
+		return fmt.Errorf("failed to write bundle: %v", err)
+	}
+	return nil
+}
+
+// ImportBundle replaces the configuration with the content of a bundle.
+// The bundle is fully read and decrypted before anything is changed, so a
+// wrong key or a damaged file leaves the current configuration untouched.
+// Locally stored keys are written back to the keys directory and the previous
+// configuration file is kept as a backup. The caller is responsible for Save.
+func (m *Manager) ImportBundle(path string, cipher *crypto.Cipher) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read bundle: %v", err)
+	}
+
+	var file bundleFile
+	if err := json.Unmarshal(content, &file); err != nil || file.Format != BundleFormat {
+		return errors.New("not an sshManager bundle")
+	}
+	if file.Version < 1 || file.Version > BundleVersion {
+		return fmt.Errorf("unsupported bundle version %d (supported up to %d)", file.Version, BundleVersion)
+	}
+
+	decrypted, err := cipher.Decrypt(file.Data)
+	if err != nil {
+		return errors.New("invalid encryption key or damaged bundle")
+	}
+
+	var data bundleData
+	if err := json.Unmarshal([]byte(decrypted), &data); err != nil {
+		return fmt.Errorf("failed to parse bundle: %v", err)
+	}
+
+	// Decrypt the stored keys up front, so a failure does not leave a partial import
+	keys := make([]models.Key, len(data.Keys))
+	for i, key := range data.Keys {
+		keys[i] = key
+		if key.KeyData != "" {
+			raw, err := key.GetKeyData(cipher)
+			if err != nil {
+				return fmt.Errorf("failed to decrypt key '%s': %v", key.Description, err)
+			}
+			keys[i].RawKeyData = raw
+		}
+	}
+
+	// Bundles from older versions reference keys by position
+	models.AssignKeyIDs(keys)
+
+	if _, err := os.Stat(m.configPath); err == nil {
+		if err := sync.BackupConfigFile(m.configPath); err != nil {
+			return fmt.Errorf("failed to back up configuration: %v", err)
+		}
+	}
+
+	for _, key := range keys {
+		if key.RawKeyData != "" {
+			if err := key.SaveKeyToFile(); err != nil {
+				return err
+			}
+		}
+	}
+
+	m.dataMutex.Lock()
+	defer m.dataMutex.Unlock()
+	m.config.Hosts = data.Hosts
+	m.config.Passwords = data.Passwords
+	m.config.Keys = keys
+	m.config.Bookmarks = data.Bookmarks
+	m.config.HostSort = data.HostSort
+	m.config.Theme = data.Theme
+	m.config.KeyBindings = data.KeyBindings
+	m.config.LocalDir = data.LocalDir
+	m.config.NoPreserve = data.NoPreserve
+	m.config.RateLimit = data.RateLimit
+	m.config.LastHost = data.LastHost
+	m.config.IdleLock = data.IdleLock
+	m.recordChange(PendingActionUpdate, PendingKindConfig, path)
+	return nil
+}
@@ -10,12 +10,17 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"sshManager/internal/crypto"
 	"sshManager/internal/models"
 	"sshManager/internal/sync"
+	"sshManager/internal/utils"
 	"strings"
+	gosync "sync"
+	"time"
 )
 
 const (
@@ -39,9 +44,22 @@ const (
 
 // Manager manages the configuration state, including hosts, passwords, and keys.
 type Manager struct {
-	configPath string         // Path to the configuration file.
-	config     *models.Config // In-memory representation of the configuration.
-	cipher     *crypto.Cipher // Cipher for encrypting and decrypting sensitive data.
+	configPath string          // Path to the configuration file.
+	config     *models.Config  // In-memory representation of the configuration.
+	cipher     *crypto.Cipher  // Cipher for encrypting and decrypting sensitive data.
+	unsynced   []PendingChange // Changes made since the last Save, queued for the API by Save.
+	pending    []PendingChange // Changes queued on disk, waiting for the next successful sync.
+	profile    string          // Profile selected when the manager was created.
+
+	// queueMutex guards pending and the queue file, which a background push
+	// (see PushPendingChanges) updates while the configuration is being edited.
+	queueMutex gosync.Mutex
+	// pushMutex lets only one push run at a time.
+	pushMutex gosync.Mutex
+	// dataMutex guards config, unsynced and cipher, which commands running in
+	// the background (connecting, transfers, syncs) read while the UI edits
+	// them. It is taken before queueMutex.
+	dataMutex gosync.RWMutex
 }
 
 // NewManager creates a new configuration manager.
@@ -61,6 +79,7 @@ func NewManager(configPath string) *Manager {
 	return &Manager{
 		configPath: configPath,
 		config:     &models.Config{},
+		profile:    activeProfile,
 	}
 }
 
@@ -84,27 +103,76 @@ func (m *Manager) Load() error {
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
 			// If the config file does not exist, initialize an empty configuration.
+			m.dataMutex.Lock()
 			m.config = &models.Config{
 				Hosts:     make([]models.Host, 0),
 				Passwords: make([]models.Password, 0),
 				Keys:      make([]models.Key, 0), // New keys slice initialized
 			}
+			m.dataMutex.Unlock()
+			if err := sync.SetAPIBaseURL(""); err != nil {
+				return err
+			}
 			return m.Save() // Save the empty configuration to create the file.
 		}
 		return fmt.Errorf("failed to read config file: %v", err)
 	}
 
-	// Parse the JSON configuration data.
-	if err := json.Unmarshal(data, m.config); err != nil {
+	// Parse the JSON configuration data into a new configuration, so that
+	// copies handed out by the getters are left untouched.
+	config := &models.Config{}
+	if err := json.Unmarshal(data, config); err != nil {
 		return fmt.Errorf("failed to parse config file: %v", err)
 	}
 
-	return nil
+	// Older versions referenced keys by position; give them stable IDs that
+	// match those references, so hosts keep pointing at the same keys.
+	models.AssignKeyIDs(config.Keys)
+
+	// Older versions saved hosts with an empty port, which cannot be dialed.
+	for i := range config.Hosts {
+		if strings.TrimSpace(config.Hosts[i].Port) == "" {
+			config.Hosts[i].Port = models.DefaultPort
+		}
+	}
+
+	m.dataMutex.Lock()
+	m.config = config
+	m.dataMutex.Unlock()
+
+	m.queueMutex.Lock()
+	m.pending = m.loadPendingChanges()
+	m.queueMutex.Unlock()
+
+	// Use the self-hosted sync API from the configuration or SSHM_API_URL.
+	return sync.SetAPIBaseURL(config.ApiURL)
 }
 
-// Save writes the current configuration to the config file.
-// It also synchronizes the configuration with an external API if an API key is available.
+// Save writes the current configuration to the config file. It does not
+// contact the API: when sync is configured, the changes are queued on disk
+// (see GetPendingChanges) and reach the API with PushPendingChanges or the
+// next sync.
 func (m *Manager) Save() error {
+	if err := m.SaveLocal(); err != nil {
+		return err
+	}
+	if !m.HasApiKey() {
+		m.dataMutex.Lock()
+		m.unsynced = nil
+		m.dataMutex.Unlock()
+		return nil
+	}
+	return m.queuePendingChanges()
+}
+
+// SaveLocal writes the current configuration to the config file without
+// synchronizing it. It is meant for changes to local-only settings.
+func (m *Manager) SaveLocal() error {
+	// Hold the write lock until the file is replaced, so that concurrent
+	// saves write it in order.
+	m.dataMutex.Lock()
+	defer m.dataMutex.Unlock()
+
 	// Marshal the configuration into JSON with indentation for readability.
 	data, err := json.MarshalIndent(m.config, "", "    ")
 	if err != nil {
@@ -112,70 +180,78 @@ func (m *Manager) Save() error {
 	}
 
 	// Write the JSON data to the configuration file with appropriate permissions.
-	if err := os.WriteFile(m.configPath, data, DefaultFilePerms); err != nil {
+	if err := utils.WriteFileAtomic(m.configPath, data, DefaultFilePerms); err != nil {
 		return fmt.Errorf("failed to write config file: %v", err)
 	}
-
-	// If an API key is available and not in local mode, synchronize the configuration with the API.
-	if apiKey, err := m.LoadApiKey(m.cipher); err == nil {
-		keysDir := filepath.Join(filepath.Dir(m.configPath), DefaultKeysDir)
-
-		// Push data to the API, encrypting sensitive information using the cipher.
-		if err := sync.PushToAPI(apiKey, m.configPath, keysDir, m.cipher); err != nil {
-			return fmt.Errorf("failed to sync with API: %v", err)
-		}
-	}
-
 	return nil
 }
 
-// GetHosts returns a slice of all configured SSH hosts.
+// GetHosts returns a copy of all configured SSH hosts.
 func (m *Manager) GetHosts() []models.Host {
-	return m.config.Hosts
+	m.dataMutex.RLock()
+	defer m.dataMutex.RUnlock()
+	return slices.Clone(m.config.Hosts)
 }
 
 // AddHost adds a new SSH host to the configuration.
 func (m *Manager) AddHost(host models.Host) {
+	m.dataMutex.Lock()
+	defer m.dataMutex.Unlock()
 	m.config.Hosts = append(m.config.Hosts, host)
+	m.recordChange(PendingActionAdd, PendingKindHost, host.Name)
 }
 
 // UpdateHost updates an existing SSH host at the specified index.
 // It returns an error if the index is out of bounds.
 func (m *Manager) UpdateHost(index int, host models.Host) error {
+	m.dataMutex.Lock()
+	defer m.dataMutex.Unlock()
 	if index < 0 || index >= len(m.config.Hosts) {
 		return errors.New("invalid host index")
 	}
 	m.config.Hosts[index] = host
+	m.recordChange(PendingActionUpdate, PendingKindHost, host.Name)
 	return nil
 }
 
 // DeleteHost removes an SSH host from the configuration at the specified index.
 // It returns an error if the index is out of bounds.
 func (m *Manager) DeleteHost(index int) error {
+	m.dataMutex.Lock()
+	defer m.dataMutex.Unlock()
 	if index < 0 || index >= len(m.config.Hosts) {
 		return errors.New("invalid host index")
 	}
-	m.config.Hosts = append(m.config.Hosts[:index], m.config.Hosts[index+1:]...)
+	m.recordChange(PendingActionDelete, PendingKindHost, m.config.Hosts[index].Name)
+	m.config.Hosts = slices.Delete(m.config.Hosts, index, index+1)
 	return nil
 }
 
-// GetPasswords returns a slice of all stored passwords.
+// GetPasswords returns a copy of all stored passwords.
 func (m *Manager) GetPasswords() []models.Password {
-	return m.config.Passwords
+	m.dataMutex.RLock()
+	defer m.dataMutex.RUnlock()
+	return slices.Clone(m.config.Passwords)
 }
 
 // AddPassword adds a new password to the configuration.
 func (m *Manager) AddPassword(password models.Password) {
+	m.dataMutex.Lock()
+	defer m.dataMutex.Unlock()
 	m.config.Passwords = append(m.config.Passwords, password)
+	m.recordChange(PendingActionAdd, PendingKindPassword, password.Description)
 }
 
 // UpdatePassword updates an existing password at the specified index.
 // It returns an error if the index is out of bounds.
 func (m *Manager) UpdatePassword(index int, password models.Password) error {
+	m.dataMutex.Lock()
+	defer m.dataMutex.Unlock()
 	if index < 0 || index >= len(m.config.Passwords) {
 		return errors.New("invalid password index")
 	}
 	m.config.Passwords[index] = password
+	m.recordChange(PendingActionUpdate, PendingKindPassword, password.Description)
 	return nil
 }
 
@@ -183,22 +259,43 @@ func (m *Manager) UpdatePassword(index int, password models.Password) error {
 // It ensures that the password is not in use by any host before deletion.
 // Returns an error if the index is invalid or the password is in use.
 func (m *Manager) DeletePassword(index int) error {
+	m.dataMutex.Lock()
+	defer m.dataMutex.Unlock()
 	if index < 0 || index >= len(m.config.Passwords) {
 		return errors.New("invalid password index")
 	}
 	// Check if the password is used by any host.
 	for _, host := range m.config.Hosts {
-		if host.PasswordID == index {
+		if host.UsesAuth(index) {
 			return errors.New("password is in use by a host")
 		}
 	}
-	m.config.Passwords = append(m.config.Passwords[:index], m.config.Passwords[index+1:]...)
+	m.recordChange(PendingActionDelete, PendingKindPassword, m.config.Passwords[index].Description)
+	m.config.Passwords = slices.Delete(m.config.Passwords, index, index+1)
+	m.shiftHostAuth(index)
 	return nil
 }
 
+// shiftHostAuth keeps the hosts pointing at the same passwords after the
+// password at index deleted was removed. AuthIDs is copied before it is
+// shifted, because copies of the hosts returned by GetHosts share it. The
+// caller holds dataMutex.
+func (m *Manager) shiftHostAuth(deleted int) {
+	for i := range m.config.Hosts {
+		host := m.config.Hosts[i]
+		host.AuthIDs = slices.Clone(host.AuthIDs)
+		if host.ShiftAuthIDs(deleted) {
+			m.config.Hosts[i] = host
+			m.recordChange(PendingActionUpdate, PendingKindHost, host.Name)
+		}
+	}
+}
+
 // GetPassword retrieves a password by its index.
 // Returns an error if the index is out of bounds.
 func (m *Manager) GetPassword(index int) (models.Password, error) {
+	m.dataMutex.RLock()
+	defer m.dataMutex.RUnlock()
 	if index < 0 || index >= len(m.config.Passwords) {
 		return models.Password{}, errors.New("invalid password index")
 	}
@@ -208,6 +305,8 @@ func (m *Manager) GetPassword(index int) (models.Password, error) {
 // FindHostByName searches for an SSH host by its name.
 // Returns the host, its index, or an error if not found.
 func (m *Manager) FindHostByName(name string) (models.Host, int, error) {
+	m.dataMutex.RLock()
+	defer m.dataMutex.RUnlock()
 	for i, host := range m.config.Hosts {
 		if host.Name == name {
 			return host, i, nil
@@ -216,16 +315,213 @@ func (m *Manager) FindHostByName(name string) (models.Host, int, error) {
 	return models.Host{}, -1, errors.New("host not found")
 }
 
-// GetDefaultConfigPath returns the default path for the configuration file.
+// MoveHost moves the host at index by offset positions (negative moves it up).
+// Only the host order changes; PasswordID refers to passwords and keys, so the
+// credential references of all hosts stay valid.
+func (m *Manager) MoveHost(index, offset int) error {
+	m.dataMutex.Lock()
+	defer m.dataMutex.Unlock()
+	target := index + offset
+	if index < 0 || index >= len(m.config.Hosts) || target < 0 || target >= len(m.config.Hosts) {
+		return errors.New("invalid host index")
+	}
+	host := m.config.Hosts[index]
+	if offset < 0 {
+		copy(m.config.Hosts[target+1:index+1], m.config.Hosts[target:index])
+	} else {
+		copy(m.config.Hosts[index:target], m.config.Hosts[index+1:target+1])
+	}
+	m.config.Hosts[target] = host
+	m.recordChange(PendingActionUpdate, PendingKindHost, host.Name)
+	return nil
+}
+
+// MarkHostConnected records a successful SSH session with a host: it sets the
+// time of the last connection and increments the connection count.
+func (m *Manager) MarkHostConnected(name string, at time.Time) error {
+	m.dataMutex.Lock()
+	defer m.dataMutex.Unlock()
+	for i := range m.config.Hosts {
+		if m.config.Hosts[i].Name == name {
+			m.config.Hosts[i].LastConnected = at
+			m.config.Hosts[i].ConnectCount++
+			return nil
+		}
+	}
+	return errors.New("host not found")
+}
+
+// GetLastSync returns the time of the last successful sync with the API
+// (zero if the configuration was never synced).
+func (m *Manager) GetLastSync() time.Time {
+	m.dataMutex.RLock()
+	defer m.dataMutex.RUnlock()
+	return m.config.LastSync
+}
+
+// MarkSynced records a successful push to the API in the local configuration.
+// It is called by the owner of the configuration after PushPendingChanges.
+func (m *Manager) MarkSynced() error {
+	m.dataMutex.Lock()
+	m.config.LastSync = time.Now()
+	m.dataMutex.Unlock()
+	return m.SaveLocal()
+}
+
+// GetHostSort returns the sort order of the host list (models.HostSortGroup by default).
+func (m *Manager) GetHostSort() string {
+	m.dataMutex.RLock()
+	defer m.dataMutex.RUnlock()
+	if m.config.HostSort == "" {
+		return models.HostSortGroup
+	}
+	return m.config.HostSort
+}
+
+// SetHostSort sets the sort order of the host list.
+func (m *Manager) SetHostSort(sort string) {
+	m.dataMutex.Lock()
+	defer m.dataMutex.Unlock()
+	m.config.HostSort = sort
+}
+
+// GetAlgorithms returns the algorithm overrides that apply to all hosts.
+func (m *Manager) GetAlgorithms() models.Algorithms {
+	m.dataMutex.RLock()
+	defer m.dataMutex.RUnlock()
+	return m.config.Algorithms
+}
+
+// GetTheme returns the name of the selected color theme (empty for the default).
+func (m *Manager) GetTheme() string {
+	m.dataMutex.RLock()
+	defer m.dataMutex.RUnlock()
+	return m.config.Theme
+}
+
+// SetTheme sets the name of the selected color theme.
+func (m *Manager) SetTheme(name string) {
+	m.dataMutex.Lock()
+	defer m.dataMutex.Unlock()
+	m.config.Theme = name
+}
+
+// GetLocalDir returns the starting directory of the local transfer panel
+// (empty for the home directory).
+func (m *Manager) GetLocalDir() string {
+	m.dataMutex.RLock()
+	defer m.dataMutex.RUnlock()
+	return m.config.LocalDir
+}
+
+// GetPreserveAttributes reports whether transfers keep the source's permissions
+// and modification time (true by default).
+func (m *Manager) GetPreserveAttributes() bool {
+	m.dataMutex.RLock()
+	defer m.dataMutex.RUnlock()
+	return !m.config.NoPreserve
+}
+
+// SetPreserveAttributes sets whether transfers keep the source's permissions
+// and modification time.
+func (m *Manager) SetPreserveAttributes(preserve bool) {
+	m.dataMutex.Lock()
+	defer m.dataMutex.Unlock()
+	m.config.NoPreserve = !preserve
+}
+
+// GetRateLimit returns the transfer speed limit in KB/s (0 for unlimited).
+func (m *Manager) GetRateLimit() int {
+	m.dataMutex.RLock()
+	defer m.dataMutex.RUnlock()
+	return max(m.config.RateLimit, 0)
+}
+
+// GetIdleLock returns how long the application may stay without a keypress
+// before it locks and asks for the encryption key again (0 disables locking).
+func (m *Manager) GetIdleLock() time.Duration {
+	m.dataMutex.RLock()
+	defer m.dataMutex.RUnlock()
+	return time.Duration(max(m.config.IdleLock, 0)) * time.Minute
+}
+
+// GetLastHost returns the name of the host that was connected to or opened in
+// the transfer view last (empty if none).
+func (m *Manager) GetLastHost() string {
+	m.dataMutex.RLock()
+	defer m.dataMutex.RUnlock()
+	return m.config.LastHost
+}
+
+// SetLastHost sets the name of the host used last.
+func (m *Manager) SetLastHost(name string) {
+	m.dataMutex.Lock()
+	defer m.dataMutex.Unlock()
+	m.config.LastHost = name
+}
+
+// GetKeyBindings returns the key binding overrides from the configuration file,
+// keyed by action name.
+func (m *Manager) GetKeyBindings() map[string][]string {
+	m.dataMutex.RLock()
+	defer m.dataMutex.RUnlock()
+	return maps.Clone(m.config.KeyBindings)
+}
+
+// GetBookmarks returns the bookmarked paths of a host for the local or remote panel.
+func (m *Manager) GetBookmarks(host string, remote bool) []string {
+	m.dataMutex.RLock()
+	defer m.dataMutex.RUnlock()
+	var paths []string
+	for _, b := range m.config.Bookmarks {
+		if b.Host == host && b.Remote == remote {
+			paths = append(paths, b.Path)
+		}
+	}
+	return paths
+}
+
+// AddBookmark saves a bookmark in the configuration.
+// Returns an error if the same path is already bookmarked for the host and panel.
+func (m *Manager) AddBookmark(bookmark models.Bookmark) error {
+	m.dataMutex.Lock()
+	defer m.dataMutex.Unlock()
+	for _, b := range m.config.Bookmarks {
+		if b == bookmark {
+			return fmt.Errorf("'%s' is already bookmarked", bookmark.Path)
+		}
+	}
+	m.config.Bookmarks = append(m.config.Bookmarks, bookmark)
+	return nil
+}
+
+// DeleteBookmark removes a bookmark from the configuration.
+// Returns an error if the bookmark does not exist.
+func (m *Manager) DeleteBookmark(bookmark models.Bookmark) error {
+	m.dataMutex.Lock()
+	defer m.dataMutex.Unlock()
+	for i, b := range m.config.Bookmarks {
+		if b == bookmark {
+			m.config.Bookmarks = slices.Delete(m.config.Bookmarks, i, i+1)
+			return nil
+		}
+	}
+	return errors.New("bookmark not found")
+}
+
+// GetDefaultConfigPath returns the default path for the configuration file
+// of the selected profile (see SetProfile).
 // It ensures that the configuration directory exists.
 func GetDefaultConfigPath() (string, error) {
-	homeDir, err := os.UserHomeDir()
+	configDir, err := baseConfigDir()
 	if err != nil {
-		return "", fmt.Errorf("could not get home directory: %v", err)
+		return "", err
+	}
+	if activeProfile != DefaultProfile {
+		configDir = filepath.Join(configDir, ProfilesDir, activeProfile)
 	}
 
 	// Create the configuration directory if it does not exist.
-	configDir := filepath.Join(homeDir, DefaultConfigDir)
 	if err := os.MkdirAll(configDir, 0755); err != nil {
 		return "", fmt.Errorf("could not create config directory: %v", err)
 	}
@@ -233,14 +529,19 @@ func GetDefaultConfigPath() (string, error) {
 	return filepath.Join(configDir, DefaultConfigFileName), nil
 }
 
-// GetKeys returns a slice of all stored SSH keys.
+// GetKeys returns a copy of all stored SSH keys.
 func (m *Manager) GetKeys() []models.Key {
-	return m.config.Keys
+	m.dataMutex.RLock()
+	defer m.dataMutex.RUnlock()
+	return slices.Clone(m.config.Keys)
 }
 
 // AddKey adds a new SSH key to the configuration.
 // It ensures that the key description is unique and handles local key storage if required.
 func (m *Manager) AddKey(key models.Key) error {
+	m.dataMutex.Lock()
+	defer m.dataMutex.Unlock()
+
 	// Check if a key with the same description already exists.
 	for _, k := range m.config.Keys {
 		if k.Description == key.Description {
@@ -264,20 +565,38 @@ func (m *Manager) AddKey(key models.Key) error {
 
 		// Write the raw key data to the file, trimming any whitespace.
 		keyContent := strings.TrimSpace(key.RawKeyData)
-		if err := os.WriteFile(keyPath, []byte(keyContent), 0600); err != nil {
+		if err := utils.WriteFileAtomic(keyPath, []byte(keyContent), 0600); err != nil {
 			return fmt.Errorf("failed to write key file: %v", err)
 		}
 	}
 
+	// Give the key a stable ID for host references; a restored key keeps its own
+	if key.ID <= 0 || m.keyIDInUse(key.ID) {
+		key.ID = models.NextKeyID(m.config.Keys)
+	}
+
 	// Append the new key to the configuration.
 	m.config.Keys = append(m.config.Keys, key)
+	m.recordChange(PendingActionAdd, PendingKindKey, key.Description)
 	return nil
 }
 
+// keyIDInUse reports whether a key with the ID exists. The caller holds dataMutex.
+func (m *Manager) keyIDInUse(id int) bool {
+	for _, k := range m.config.Keys {
+		if k.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
 // UpdateKey updates an existing SSH key at the specified index.
 // It handles the transition between local and external storage and ensures file integrity.
 // Returns an error if the index is invalid.
 func (m *Manager) UpdateKey(index int, key models.Key) error {
+	m.dataMutex.Lock()
+	defer m.dataMutex.Unlock()
 	if index < 0 || index >= len(m.config.Keys) {
 		return errors.New("invalid key index")
 	}
@@ -308,13 +627,15 @@ func (m *Manager) UpdateKey(index int, key models.Key) error {
 
 		// Write the raw key data to the file, trimming any whitespace.
 		keyContent := strings.TrimSpace(key.RawKeyData)
-		if err := os.WriteFile(keyPath, []byte(keyContent), 0600); err != nil {
+		if err := utils.WriteFileAtomic(keyPath, []byte(keyContent), 0600); err != nil {
 			return fmt.Errorf("failed to write key file: %v", err)
 		}
 	}
 
-	// Update the key in the configuration.
+	// Update the key in the configuration, keeping the ID hosts refer to.
+	key.ID = oldKey.ID
 	m.config.Keys[index] = key
+	m.recordChange(PendingActionUpdate, PendingKindKey, key.Description)
 	return nil
 }
 
@@ -322,16 +643,17 @@ func (m *Manager) UpdateKey(index int, key models.Key) error {
 // It ensures that the key is not in use by any host before deletion and handles file removal if stored locally.
 // Returns an error if the index is invalid or the key is in use.
 func (m *Manager) DeleteKey(index int) error {
+	m.dataMutex.Lock()
+	defer m.dataMutex.Unlock()
 	if index < 0 || index >= len(m.config.Keys) {
 		return fmt.Errorf("invalid key index: %d", index)
 	}
 
 	key := m.config.Keys[index]
-	actualIndex := -(index + 1) // Convert to negative index used in PasswordID
 
 	// Check if the key is used by any host.
 	for _, host := range m.config.Hosts {
-		if host.PasswordID == actualIndex {
+		if host.UsesAuth(key.AuthID()) {
 			return fmt.Errorf("key '%s' is in use by host '%s'", key.Description, host.Name)
 		}
 	}
@@ -344,7 +666,8 @@ func (m *Manager) DeleteKey(index int) error {
 	}
 
 	// Remove the key from the configuration.
-	m.config.Keys = append(m.config.Keys[:index], m.config.Keys[index+1:]...)
+	m.recordChange(PendingActionDelete, PendingKindKey, key.Description)
+	m.config.Keys = slices.Delete(m.config.Keys, index, index+1)
 	return nil
 }
 
@@ -369,7 +692,7 @@ func (m *Manager) SaveApiKey(apiKey string, cipher *crypto.Cipher) error {
 	}
 
 	// Write the encrypted API key to the file with secure permissions.
-	return os.WriteFile(apiKeyPath, []byte(encryptedKey), 0600)
+	return utils.WriteFileAtomic(apiKeyPath, []byte(encryptedKey), 0600)
 }
 
 // LoadApiKey reads and decrypts the API key from the designated file.
@@ -407,9 +730,18 @@ func (m *Manager) RemoveApiKey() error {
 
 // SetCipher assigns a cipher to the Manager for encrypting and decrypting sensitive data.
 func (m *Manager) SetCipher(cipher *crypto.Cipher) {
+	m.dataMutex.Lock()
+	defer m.dataMutex.Unlock()
 	m.cipher = cipher
 }
 
+// getCipher returns the cipher assigned with SetCipher.
+func (m *Manager) getCipher() *crypto.Cipher {
+	m.dataMutex.RLock()
+	defer m.dataMutex.RUnlock()
+	return m.cipher
+}
+
 // GetConfigPath returns the file path of the current configuration.
 func (m *Manager) GetConfigPath() string {
 	return m.configPath
@@ -0,0 +1,83 @@
+// internal/config/keycheck.go
+//
+// The key check is a known plaintext encrypted with the encryption key. It lets
+// the application reject a mistyped key right away instead of failing later
+// when a password or key cannot be decrypted.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"sshManager/internal/crypto"
+)
+
+// ErrInvalidEncryptionKey is returned when a cipher does not match the key the
+// configuration is encrypted with.
+var ErrInvalidEncryptionKey = errors.New("incorrect encryption key")
+
+// keyCheckPlaintext is the value encrypted into Config.KeyCheck.
+const keyCheckPlaintext = "sshManager key check"
+
+// VerifyCipher checks that cipher uses the key the configuration is encrypted
+// with. Configurations without a key check (created by older versions) are
+// verified against a stored secret instead; with no secrets at all any key is
+// accepted.
+func (m *Manager) VerifyCipher(cipher *crypto.Cipher) error {
+	m.dataMutex.RLock()
+	defer m.dataMutex.RUnlock()
+	if m.config.KeyCheck != "" {
+		plain, err := cipher.Decrypt(m.config.KeyCheck)
+		if err != nil || plain != keyCheckPlaintext {
+			return ErrInvalidEncryptionKey
+		}
+		return nil
+	}
+
+	if secret := m.storedSecret(); secret != "" {
+		if _, err := cipher.Decrypt(secret); err != nil {
+			return ErrInvalidEncryptionKey
+		}
+	}
+	return nil
+}
+
+// EnsureKeyCheck stores a key check for cipher when the configuration does not
+// have one yet. The cipher must already be verified with VerifyCipher.
+func (m *Manager) EnsureKeyCheck(cipher *crypto.Cipher) error {
+	m.dataMutex.Lock()
+	if m.config.KeyCheck != "" {
+		m.dataMutex.Unlock()
+		return nil
+	}
+
+	encrypted, err := cipher.Encrypt(keyCheckPlaintext)
+	if err != nil {
+		m.dataMutex.Unlock()
+		return fmt.Errorf("failed to create key check: %v", err)
+	}
+	m.config.KeyCheck = encrypted
+	m.dataMutex.Unlock()
+	return m.SaveLocal()
+}
+
+// storedSecret returns any secret encrypted with the encryption key: a
+// password, stored key data or the API key. The caller holds dataMutex.
+func (m *Manager) storedSecret() string {
+	if len(m.config.Passwords) > 0 {
+		return m.config.Passwords[0].Password
+	}
+	for _, key := range m.config.Keys {
+		if key.KeyData != "" {
+			return key.KeyData
+		}
+	}
+	if apiKeyPath, err := m.GetApiKeyPath(); err == nil {
+		if data, err := os.ReadFile(apiKeyPath); err == nil {
+			return string(data)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,173 @@
+// internal/config/pending.go
+
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sshManager/internal/crypto"
+	"sshManager/internal/sync"
+	"sshManager/internal/utils"
+	"time"
+)
+
+// PendingSyncFileName specifies the file holding changes that have not reached the API yet.
+const PendingSyncFileName = "pending_sync.json"
+
+// Kinds of configuration entries recorded in the pending sync queue.
+const (
+	PendingKindHost     = "host"
+	PendingKindPassword = "password"
+	PendingKindKey      = "key"
+	PendingKindBackup   = "backup" // The whole configuration was restored from a backup
+	PendingKindConfig   = "config" // The whole configuration was replaced or re-encrypted
+)
+
+// Actions recorded in the pending sync queue.
+const (
+	PendingActionAdd    = "add"
+	PendingActionUpdate = "update"
+	PendingActionDelete = "delete"
+)
+
+// PendingChange describes a local change that could not be pushed to the API.
+type PendingChange struct {
+	Action string    `json:"action"` // add, update or delete
+	Kind   string    `json:"kind"`   // host, password or key
+	Name   string    `json:"name"`   // Host name or password/key description
+	Time   time.Time `json:"time"`   // When the change was made
+}
+
+// recordChange notes a mutation so that the next Save queues it for the API,
+// and writes it to the audit log. The caller holds dataMutex.
+func (m *Manager) recordChange(action, kind, name string) {
+	now := time.Now()
+	m.unsynced = append(m.unsynced, PendingChange{
+		Action: action,
+		Kind:   kind,
+		Name:   name,
+		Time:   now,
+	})
+	m.audit(AuditEntry{Time: now, Action: action, Kind: kind, Name: name})
+}
+
+// pendingSyncPath returns the path of the pending sync queue file.
+func (m *Manager) pendingSyncPath() string {
+	return filepath.Join(filepath.Dir(m.configPath), PendingSyncFileName)
+}
+
+// GetPendingChanges returns the changes waiting to be pushed to the API.
+func (m *Manager) GetPendingChanges() []PendingChange {
+	m.queueMutex.Lock()
+	defer m.queueMutex.Unlock()
+	return m.pending
+}
+
+// loadPendingChanges reads the pending sync queue file. A missing or
+// unreadable file counts as an empty queue. The caller holds queueMutex.
+func (m *Manager) loadPendingChanges() []PendingChange {
+	data, err := os.ReadFile(m.pendingSyncPath())
+	if err != nil {
+		return nil
+	}
+	var changes []PendingChange
+	if err := json.Unmarshal(data, &changes); err != nil {
+		return nil
+	}
+	return changes
+}
+
+// writePendingChanges replaces the queue file with changes, removing it when
+// the queue is empty. The caller holds queueMutex.
+func (m *Manager) writePendingChanges(changes []PendingChange) error {
+	if len(changes) == 0 {
+		m.pending = nil
+		if err := os.Remove(m.pendingSyncPath()); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove pending changes: %v", err)
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(changes, "", "    ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal pending changes: %v", err)
+	}
+	if err := utils.WriteFileAtomic(m.pendingSyncPath(), data, DefaultFilePerms); err != nil {
+		return fmt.Errorf("failed to write pending changes: %v", err)
+	}
+	m.pending = changes
+	return nil
+}
+
+// queuePendingChanges appends the changes recorded since the last Save to the
+// queue file.
+func (m *Manager) queuePendingChanges() error {
+	m.dataMutex.Lock()
+	defer m.dataMutex.Unlock()
+	if len(m.unsynced) == 0 {
+		return nil
+	}
+	m.queueMutex.Lock()
+	defer m.queueMutex.Unlock()
+	if err := m.writePendingChanges(append(m.loadPendingChanges(), m.unsynced...)); err != nil {
+		return err
+	}
+	m.unsynced = nil
+	return nil
+}
+
+// ClearPendingChanges empties the pending sync queue, e.g. after the whole
+// configuration has been pushed to the API.
+func (m *Manager) ClearPendingChanges() error {
+	m.dataMutex.Lock()
+	m.unsynced = nil
+	m.dataMutex.Unlock()
+	m.queueMutex.Lock()
+	defer m.queueMutex.Unlock()
+	return m.writePendingChanges(nil)
+}
+
+// PushPendingChanges pushes the saved configuration to the API when changes
+// are queued and reports whether anything was pushed. It only touches the
+// files on disk and the queue, and encrypts with the given cipher instead of
+// the manager's, so it can run in the background while the configuration is
+// being edited (pass a copy from crypto.Cipher.Clone); the caller then records
+// the sync with MarkSynced. Changes queued during the push stay queued for the
+// next one. Pushes do not overlap: a second call waits for the first.
+func (m *Manager) PushPendingChanges(apiKey string, cipher *crypto.Cipher) (bool, error) {
+	m.pushMutex.Lock()
+	defer m.pushMutex.Unlock()
+
+	m.queueMutex.Lock()
+	queued := len(m.loadPendingChanges())
+	m.queueMutex.Unlock()
+	if queued == 0 {
+		return false, nil
+	}
+
+	keysDir := filepath.Join(filepath.Dir(m.configPath), DefaultKeysDir)
+	if err := sync.PushToAPI(apiKey, m.configPath, keysDir, cipher); err != nil {
+		return false, fmt.Errorf("failed to push pending changes: %v", err)
+	}
+
+	m.queueMutex.Lock()
+	defer m.queueMutex.Unlock()
+	changes := m.loadPendingChanges()
+	if queued > len(changes) {
+		queued = len(changes)
+	}
+	return true, m.writePendingChanges(changes[queued:])
+}
+
+// FlushPendingChanges pushes the local configuration to the API when changes
+// are queued, so that a following pull does not overwrite them. The queue is
+// cleared only after a successful push.
+func (m *Manager) FlushPendingChanges(apiKey string) error {
+	pushed, err := m.PushPendingChanges(apiKey, m.getCipher())
+	if err != nil || !pushed {
+		return err
+	}
+	return m.MarkSynced()
+}
@@ -0,0 +1,96 @@
+// internal/config/profile.go
+//
+// Profiles keep separate configurations, e.g. for different clients. The
+// default profile lives directly in the config directory; every other profile
+// has its own directory under profiles/ with its own hosts, passwords, keys,
+// API key and key check, so it can use a different encryption key.
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"sshManager/internal/models"
+)
+
+// DefaultProfile is the name of the profile stored directly in the config directory.
+const DefaultProfile = "default"
+
+// ProfilesDir is the directory in the config directory holding the other profiles.
+const ProfilesDir = "profiles"
+
+// profileNamePattern limits profile names to safe directory names.
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// activeProfile is the profile used by GetDefaultConfigPath.
+var activeProfile = DefaultProfile
+
+// SetProfile selects the profile whose configuration GetDefaultConfigPath
+// returns. An empty name selects the default profile. The profile directory
+// is created on first use.
+func SetProfile(name string) error {
+	if name == "" {
+		name = DefaultProfile
+	}
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name '%s': use letters, digits, '.', '_' and '-'", name)
+	}
+
+	activeProfile = name
+	if name == DefaultProfile {
+		models.SetKeyProfile("")
+	} else {
+		models.SetKeyProfile(name)
+	}
+	return nil
+}
+
+// GetActiveProfile returns the name of the selected profile.
+func GetActiveProfile() string {
+	return activeProfile
+}
+
+// ListProfiles returns the default profile followed by the other existing
+// profiles in alphabetical order.
+func ListProfiles() ([]string, error) {
+	baseDir, err := baseConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := []string{DefaultProfile}
+	entries, err := os.ReadDir(filepath.Join(baseDir, ProfilesDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return profiles, nil
+		}
+		return nil, fmt.Errorf("failed to read profiles: %v", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() && entry.Name() != DefaultProfile && profileNamePattern.MatchString(entry.Name()) {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return append(profiles, names...), nil
+}
+
+// GetProfile returns the profile the manager's configuration belongs to.
+func (m *Manager) GetProfile() string {
+	return m.profile
+}
+
+// baseConfigDir returns the config directory of the default profile.
+func baseConfigDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("could not get home directory: %v", err)
+	}
+	return filepath.Join(homeDir, DefaultConfigDir), nil
+}
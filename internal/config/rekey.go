@@ -0,0 +1,130 @@
+// internal/config/rekey.go
+//
+// Changing the encryption key re-encrypts every stored secret. All secrets are
+// re-encrypted in memory first and the files are replaced atomically, so a
+// wrong key or a failure on the way leaves the previous store usable.
+
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"sshManager/internal/crypto"
+	"sshManager/internal/models"
+	"sshManager/internal/sync"
+	"sshManager/internal/utils"
+)
+
+// ChangeCipher re-encrypts the passwords, the stored key data and the API key
+// from oldCipher to newCipher, writes a new key check and saves everything to
+// disk. The previous files are kept as backups with the ".old" suffix. Nothing
+// is changed if any secret cannot be decrypted with oldCipher. The new
+// configuration is only saved locally; the caller decides whether to sync it.
+func (m *Manager) ChangeCipher(oldCipher, newCipher *crypto.Cipher) error {
+	if err := m.VerifyCipher(oldCipher); err != nil {
+		return err
+	}
+	m.dataMutex.RLock()
+	current := *m.config
+	current.Passwords = slices.Clone(m.config.Passwords)
+	current.Keys = slices.Clone(m.config.Keys)
+	m.dataMutex.RUnlock()
+	newConfig := current
+
+	keyCheck, err := newCipher.Encrypt(keyCheckPlaintext)
+	if err != nil {
+		return fmt.Errorf("failed to create key check: %v", err)
+	}
+	newConfig.KeyCheck = keyCheck
+
+	newConfig.Passwords = make([]models.Password, len(current.Passwords))
+	for i, password := range current.Passwords {
+		encrypted, err := reencrypt(password.Password, oldCipher, newCipher)
+		if err != nil {
+			return fmt.Errorf("password '%s': %v", password.Description, err)
+		}
+		newConfig.Passwords[i] = password
+		newConfig.Passwords[i].Password = encrypted
+	}
+
+	newConfig.Keys = make([]models.Key, len(current.Keys))
+	for i, key := range current.Keys {
+		newConfig.Keys[i] = key
+		if key.KeyData == "" {
+			continue
+		}
+		encrypted, err := reencrypt(key.KeyData, oldCipher, newCipher)
+		if err != nil {
+			return fmt.Errorf("key '%s': %v", key.Description, err)
+		}
+		newConfig.Keys[i].KeyData = encrypted
+	}
+
+	apiKeyPath, err := m.GetApiKeyPath()
+	if err != nil {
+		return err
+	}
+	var apiKey string
+	storedApiKey, err := os.ReadFile(apiKeyPath)
+	switch {
+	case err == nil:
+		if apiKey, err = reencrypt(string(storedApiKey), oldCipher, newCipher); err != nil {
+			return fmt.Errorf("API key: %v", err)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return fmt.Errorf("failed to read API key: %v", err)
+	}
+
+	data, err := json.MarshalIndent(&newConfig, "", "    ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %v", err)
+	}
+
+	// Back up both files before anything is replaced
+	previousConfig, err := os.ReadFile(m.configPath)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %v", err)
+	}
+	if err := sync.BackupConfigFile(m.configPath); err != nil {
+		return fmt.Errorf("failed to back up configuration: %v", err)
+	}
+	if apiKey != "" {
+		if err := os.WriteFile(apiKeyPath+".old", storedApiKey, 0600); err != nil {
+			return fmt.Errorf("failed to back up API key: %v", err)
+		}
+	}
+
+	if err := utils.WriteFileAtomic(m.configPath, data, DefaultFilePerms); err != nil {
+		return fmt.Errorf("failed to write config file: %v", err)
+	}
+	if apiKey != "" {
+		if err := utils.WriteFileAtomic(apiKeyPath, []byte(apiKey), 0600); err != nil {
+			// The configuration must stay readable with the same key as the API key
+			if restoreErr := utils.WriteFileAtomic(m.configPath, previousConfig, DefaultFilePerms); restoreErr != nil {
+				return fmt.Errorf("failed to write API key: %v (restoring the configuration also failed: %v; the previous file is %s.old)",
+					err, restoreErr, m.configPath)
+			}
+			return fmt.Errorf("failed to write API key: %v", err)
+		}
+	}
+
+	m.dataMutex.Lock()
+	defer m.dataMutex.Unlock()
+	m.config = &newConfig
+	m.cipher = newCipher
+	m.recordChange(PendingActionUpdate, PendingKindConfig, "encryption key")
+	return nil
+}
+
+// reencrypt decrypts a secret with oldCipher and encrypts it with newCipher.
+func reencrypt(encrypted string, oldCipher, newCipher *crypto.Cipher) (string, error) {
+	plain, err := oldCipher.Decrypt(encrypted)
+	if err != nil {
+		return "", errors.New("cannot be decrypted with the current encryption key")
+	}
+	return newCipher.Encrypt(plain)
+}
@@ -0,0 +1,77 @@
+// internal/config/remote_sync.go
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sshManager/internal/sync"
+)
+
+// HasApiKey reports whether an API key is stored, i.e. whether sync is configured.
+func (m *Manager) HasApiKey() bool {
+	path, err := m.GetApiKeyPath()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// SyncNow runs a full synchronization on demand, like the one at startup:
+// queued local changes are pushed first, then the data from the API replaces
+// the local configuration file. The previous files are restored if the API data
+// cannot be saved. The caller reloads the configuration with Load afterwards.
+func (m *Manager) SyncNow() error {
+	cipher := m.getCipher()
+	apiKey, err := m.LoadApiKey(cipher)
+	if err != nil {
+		return errors.New("sync is not configured (local mode)")
+	}
+	keysDir := filepath.Join(filepath.Dir(m.configPath), DefaultKeysDir)
+
+	// Back up the current files before they are replaced by the API data.
+	if _, err := sync.CreateBackup(m.configPath, keysDir); err != nil {
+		return fmt.Errorf("failed to back up configuration: %v", err)
+	}
+
+	if err := m.FlushPendingChanges(apiKey); err != nil {
+		return err
+	}
+
+	syncResp, err := sync.SyncWithAPI(apiKey)
+	if err != nil {
+		return fmt.Errorf("failed to sync with API: %v", err)
+	}
+	if err := sync.SaveAPIData(m.configPath, keysDir, syncResp.Data, cipher); err != nil {
+		if restoreErr := sync.RestoreFromBackup(m.configPath, keysDir); restoreErr != nil {
+			return fmt.Errorf("failed to save API data: %v (restoring backup failed: %v)", err, restoreErr)
+		}
+		return fmt.Errorf("failed to save API data: %v", err)
+	}
+	return nil
+}
+
+// ListBackups returns the backups made before syncs, newest first.
+func (m *Manager) ListBackups() ([]sync.Backup, error) {
+	return sync.ListBackups(m.configPath)
+}
+
+// RestoreBackup replaces the configuration and keys with a backup and reloads
+// them. The restored configuration is then saved like any other change and
+// queued for the API when sync is configured.
+func (m *Manager) RestoreBackup(backup sync.Backup) error {
+	keysDir := filepath.Join(filepath.Dir(m.configPath), DefaultKeysDir)
+	if err := sync.RestoreBackup(m.configPath, keysDir, backup); err != nil {
+		return err
+	}
+	if err := m.Load(); err != nil {
+		return err
+	}
+	m.dataMutex.Lock()
+	m.recordChange(PendingActionUpdate, PendingKindBackup, backup.Name)
+	m.dataMutex.Unlock()
+	return m.Save()
+}
@@ -0,0 +1,249 @@
+// internal/config/sshconfig.go
+//
+// Import of hosts from an OpenSSH client configuration file (~/.ssh/config).
+
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+
+	"sshManager/internal/models"
+)
+
+// agentKeyDescription is the description of the key assigned to imported
+// hosts without an IdentityFile, which OpenSSH would authenticate via the agent.
+const agentKeyDescription = "ssh-agent (imported)"
+
+// SSHConfigHost holds the directives of a single Host entry of an OpenSSH config.
+type SSHConfigHost struct {
+	Alias        string // Host pattern used as the host name
+	HostName     string // HostName directive (defaults to Alias)
+	User         string // User directive
+	Port         string // Port directive
+	IdentityFile string // First IdentityFile directive
+}
+
+// ImportResult summarizes an import from an OpenSSH config file.
+type ImportResult struct {
+	Imported []string // Names of the hosts that were added
+	Skipped  []string // Names of the hosts that already existed
+	Keys     []string // Descriptions of the keys that were added
+}
+
+// DefaultSSHConfigPath returns the path of the user's OpenSSH client configuration.
+func DefaultSSHConfigPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("could not get home directory: %v", err)
+	}
+	return filepath.Join(homeDir, ".ssh", "config"), nil
+}
+
+// ParseSSHConfig reads Host entries from an OpenSSH config. Only the Host,
+// HostName, User, Port and IdentityFile directives are used; wildcard patterns
+// (e.g. "Host *"), negated patterns and Match blocks are ignored.
+func ParseSSHConfig(r io.Reader) ([]SSHConfigHost, error) {
+	var hosts []SSHConfigHost
+	var current []int // Indexes in hosts of the entries of the current Host block
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		keyword, value := splitSSHConfigLine(line)
+		switch strings.ToLower(keyword) {
+		case "host":
+			current = nil
+			for _, pattern := range strings.Fields(value) {
+				if strings.ContainsAny(pattern, "*?!") {
+					continue
+				}
+				hosts = append(hosts, SSHConfigHost{Alias: pattern})
+				current = append(current, len(hosts)-1)
+			}
+		case "match":
+			// Conditional blocks cannot be mapped to a single host
+			current = nil
+		case "hostname", "user", "port", "identityfile":
+			// OpenSSH uses the first value given for a directive
+			for _, i := range current {
+				host := &hosts[i]
+				switch strings.ToLower(keyword) {
+				case "hostname":
+					if host.HostName == "" {
+						host.HostName = value
+					}
+				case "user":
+					if host.User == "" {
+						host.User = value
+					}
+				case "port":
+					if host.Port == "" {
+						host.Port = value
+					}
+				case "identityfile":
+					if host.IdentityFile == "" {
+						host.IdentityFile = value
+					}
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read ssh config: %v", err)
+	}
+
+	return hosts, nil
+}
+
+// splitSSHConfigLine splits a config line into keyword and value; both
+// "Keyword value" and "Keyword=value" forms are accepted.
+func splitSSHConfigLine(line string) (string, string) {
+	i := strings.IndexAny(line, " \t=")
+	if i < 0 {
+		return line, ""
+	}
+	keyword := line[:i]
+	value := strings.TrimSpace(line[i:])
+	value = strings.TrimSpace(strings.TrimPrefix(value, "="))
+	return keyword, strings.Trim(value, "\"")
+}
+
+// ImportSSHConfig adds the hosts of an OpenSSH config file to the configuration.
+// Hosts whose name already exists are skipped. Each IdentityFile becomes a key
+// referencing the file (existing keys with the same path are reused); hosts
+// without one use a shared ssh-agent key. The caller is responsible for Save.
+func (m *Manager) ImportSSHConfig(path string) (*ImportResult, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ssh config: %v", err)
+	}
+	defer file.Close()
+
+	entries, err := ParseSSHConfig(file)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultUser := ""
+	if current, err := user.Current(); err == nil {
+		defaultUser = current.Username
+		// On Windows the username includes the domain ("DOMAIN\user")
+		if i := strings.LastIndex(defaultUser, "\\"); i >= 0 {
+			defaultUser = defaultUser[i+1:]
+		}
+	}
+
+	result := &ImportResult{}
+	for _, entry := range entries {
+		if _, _, err := m.FindHostByName(entry.Alias); err == nil {
+			result.Skipped = append(result.Skipped, entry.Alias)
+			continue
+		}
+
+		authID, added, err := m.importKey(entry.IdentityFile)
+		if err != nil {
+			return result, err
+		}
+		if added != "" {
+			result.Keys = append(result.Keys, added)
+		}
+
+		host := models.Host{
+			Name:       entry.Alias,
+			Login:      entry.User,
+			IP:         models.NormalizeAddress(entry.HostName),
+			Port:       entry.Port,
+			PasswordID: authID,
+		}
+		if host.Login == "" {
+			host.Login = defaultUser
+		}
+		if host.IP == "" {
+			host.IP = entry.Alias
+		}
+		if host.Port == "" {
+			host.Port = models.DefaultPort
+		}
+
+		m.AddHost(host)
+		result.Imported = append(result.Imported, host.Name)
+	}
+
+	return result, nil
+}
+
+// importKey returns the reference (see models.Key.AuthID) of the key for an
+// IdentityFile, adding the key if needed. The second value is the description
+// of a newly added key.
+func (m *Manager) importKey(identityFile string) (int, string, error) {
+	key := models.Key{Description: agentKeyDescription, UseAgent: true}
+	if identityFile != "" {
+		path := expandHome(identityFile)
+		key = models.Key{Description: filepath.Base(path), Path: path}
+	} else {
+		// Like OpenSSH, fall back to a default identity when the agent is unavailable
+		for _, name := range []string{"id_ed25519", "id_ecdsa", "id_rsa"} {
+			if path := expandHome(filepath.Join("~", ".ssh", name)); fileExists(path) {
+				key.Path = path
+				break
+			}
+		}
+	}
+
+	for _, existing := range m.GetKeys() {
+		if existing.Path == key.Path && existing.UseAgent == key.UseAgent &&
+			(key.Path != "" || existing.Description == key.Description) {
+			return existing.AuthID(), "", nil
+		}
+	}
+
+	// Key descriptions must be unique
+	base := key.Description
+	for n := 2; m.hasKeyDescription(key.Description); n++ {
+		key.Description = fmt.Sprintf("%s (%d)", base, n)
+	}
+
+	if err := m.AddKey(key); err != nil {
+		return 0, "", err
+	}
+	keys := m.GetKeys()
+	return keys[len(keys)-1].AuthID(), key.Description, nil
+}
+
+// hasKeyDescription reports whether a key with the description exists.
+func (m *Manager) hasKeyDescription(description string) bool {
+	for _, k := range m.GetKeys() {
+		if k.Description == description {
+			return true
+		}
+	}
+	return false
+}
+
+// expandHome replaces a leading "~" with the user's home directory.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+		return path
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(homeDir, path[1:])
+}
+
+// fileExists reports whether path names an existing regular file.
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -7,6 +7,7 @@
 package crypto
 
 import (
+	"bytes"
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
@@ -83,28 +84,40 @@ func (c *Cipher) Encrypt(plaintext string) (string, error) {
 // Decrypt decrypts the given hex-encoded ciphertext using AES-256-GCM.
 // It returns the decrypted plaintext as a string.
 func (c *Cipher) Decrypt(encryptedHex string) (string, error) {
+	plaintext, err := c.DecryptBytes(encryptedHex)
+	if err != nil {
+		return "", err
+	}
+	defer Wipe(plaintext)
+	return string(plaintext), nil
+}
+
+// DecryptBytes decrypts the given hex-encoded ciphertext like Decrypt, but
+// returns the plaintext as a byte slice. Unlike a string, the slice can be
+// cleared with Wipe as soon as the secret is no longer needed.
+func (c *Cipher) DecryptBytes(encryptedHex string) ([]byte, error) {
 	// Decode the hex-encoded ciphertext.
 	combined, err := hex.DecodeString(encryptedHex)
 	if err != nil {
-		return "", fmt.Errorf("failed to decode hex: %v", err)
+		return nil, fmt.Errorf("failed to decode hex: %v", err)
 	}
 
 	// Create a new AES cipher block using the key.
 	block, err := aes.NewCipher(c.key)
 	if err != nil {
-		return "", fmt.Errorf("failed to create cipher: %v", err)
+		return nil, fmt.Errorf("failed to create cipher: %v", err)
 	}
 
 	// Wrap the cipher block in Galois/Counter Mode (GCM) for authenticated decryption.
 	aesGCM, err := cipher.NewGCM(block)
 	if err != nil {
-		return "", fmt.Errorf("failed to create GCM: %v", err)
+		return nil, fmt.Errorf("failed to create GCM: %v", err)
 	}
 
 	// Retrieve the nonce size from the GCM.
 	nonceSize := aesGCM.NonceSize()
 	if len(combined) < nonceSize {
-		return "", fmt.Errorf("ciphertext too short")
+		return nil, fmt.Errorf("ciphertext too short")
 	}
 
 	// Extract the nonce and ciphertext from the combined data.
@@ -114,10 +127,32 @@ func (c *Cipher) Decrypt(encryptedHex string) (string, error) {
 	// Decrypt the ciphertext using Open, which verifies the authentication tag.
 	plaintext, err := aesGCM.Open(nil, nonce, ciphertext, nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to decrypt: %v", err)
+		return nil, fmt.Errorf("failed to decrypt: %v", err)
 	}
 
-	return string(plaintext), nil
+	return plaintext, nil
+}
+
+// Clone returns an independent copy of the cipher, e.g. for a background task
+// that must keep working when the original is wiped or replaced. The copy has
+// to be wiped separately.
+func (c *Cipher) Clone() *Cipher {
+	return &Cipher{key: bytes.Clone(c.key)}
+}
+
+// Wipe clears the encryption key of the cipher. Afterwards every Encrypt and
+// Decrypt fails, also for code that still holds a reference to the cipher.
+func (c *Cipher) Wipe() {
+	clear(c.key)
+	c.key = nil
+}
+
+// Wipe overwrites a secret held in a byte slice with zeros. It only clears
+// this slice: copies made earlier, e.g. by converting it to a string (Go
+// strings are immutable and cannot be cleared), stay in memory until the
+// garbage collector reuses it.
+func Wipe(secret []byte) {
+	clear(secret)
 }
 
 // GenerateKeyFromPassword generates a 32-byte key from the given password.
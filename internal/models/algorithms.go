@@ -0,0 +1,89 @@
+// internal/models/algorithms.go
+
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Algorithms overrides the SSH algorithm preferences of the built-in client.
+// An empty list keeps the inherited one. Entries prefixed with "+", "-" or "^"
+// append to, remove from or prepend to the inherited list, like in OpenSSH;
+// plain entries replace it.
+type Algorithms struct {
+	HostKeyAlgorithms []string `json:"host_key_algorithms,omitempty"` // Accepted host key types
+	Ciphers           []string `json:"ciphers,omitempty"`             // Symmetric ciphers
+	KeyExchanges      []string `json:"key_exchanges,omitempty"`       // Key exchange methods
+}
+
+// algorithmKeys are the names used for the lists in ParseAlgorithms.
+const (
+	algorithmKeyKex      = "kex"
+	algorithmKeyCiphers  = "ciphers"
+	algorithmKeyHostKeys = "hostkeys"
+)
+
+// IsEmpty reports whether no list is overridden.
+func (a Algorithms) IsEmpty() bool {
+	return len(a.HostKeyAlgorithms) == 0 && len(a.Ciphers) == 0 && len(a.KeyExchanges) == 0
+}
+
+// ParseAlgorithms parses overrides written as space separated "name=list"
+// pairs with comma separated algorithms, e.g.
+// "kex=+diffie-hellman-group1-sha1 ciphers=+aes128-cbc hostkeys=+ssh-dss".
+func ParseAlgorithms(value string) (Algorithms, error) {
+	var result Algorithms
+	for _, field := range strings.Fields(value) {
+		name, list, found := strings.Cut(field, "=")
+		if !found {
+			return Algorithms{}, fmt.Errorf("algorithms: expected name=list, got %q", field)
+		}
+
+		var items []string
+		for _, item := range strings.Split(list, ",") {
+			item = strings.TrimSpace(item)
+			if item == "" {
+				continue
+			}
+			if strings.Trim(item, "+-^") == "" {
+				return Algorithms{}, fmt.Errorf("algorithms: missing algorithm name in %q", field)
+			}
+			items = append(items, item)
+		}
+		if len(items) == 0 {
+			return Algorithms{}, fmt.Errorf("algorithms: empty list for %q", name)
+		}
+
+		switch strings.ToLower(name) {
+		case algorithmKeyKex:
+			result.KeyExchanges = append(result.KeyExchanges, items...)
+		case algorithmKeyCiphers:
+			result.Ciphers = append(result.Ciphers, items...)
+		case algorithmKeyHostKeys:
+			result.HostKeyAlgorithms = append(result.HostKeyAlgorithms, items...)
+		default:
+			return Algorithms{}, fmt.Errorf("algorithms: unknown list %q (use %s, %s or %s)",
+				name, algorithmKeyKex, algorithmKeyCiphers, algorithmKeyHostKeys)
+		}
+	}
+	return result, nil
+}
+
+// String formats the overrides in the syntax accepted by ParseAlgorithms.
+func (a Algorithms) String() string {
+	var fields []string
+	for _, list := range []struct {
+		name  string
+		items []string
+	}{
+		{algorithmKeyKex, a.KeyExchanges},
+		{algorithmKeyCiphers, a.Ciphers},
+		{algorithmKeyHostKeys, a.HostKeyAlgorithms},
+	} {
+		if len(list.items) > 0 {
+			fields = append(fields, list.name+"="+strings.Join(list.items, ","))
+		}
+	}
+	return strings.Join(fields, " ")
+}
@@ -0,0 +1,11 @@
+// internal/models/bookmark.go
+
+package models
+
+// Bookmark is a saved directory of the transfer view. Bookmarks are kept
+// per host and separately for the local and the remote panel.
+type Bookmark struct {
+	Host   string `json:"host"`   // Name of the host the bookmark belongs to
+	Remote bool   `json:"remote"` // True for a remote panel path, false for a local one
+	Path   string `json:"path"`   // Bookmarked directory
+}
@@ -4,22 +4,317 @@
 
 package models
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultConnectTimeout is used when a host does not set its own ConnectTimeout.
+const DefaultConnectTimeout = 15 * time.Second
+
+// DefaultPort is used when a host is saved or loaded without a port.
+const DefaultPort = "22"
+
+// DefaultTerminalType is requested for the remote PTY when a host does not set TerminalType.
+const DefaultTerminalType = "xterm-256color"
+
+// DefaultKeepAliveInterval is used when a host does not set its own KeepAliveInterval.
+const DefaultKeepAliveInterval = 30 * time.Second
+
 // Host represents the configuration details of an SSH host.
 type Host struct {
-	Name         string `json:"name"`          // Unique identifier for the host
-	Description  string `json:"description"`   // Description of the host
-	Login        string `json:"login"`         // Username for SSH authentication
-	IP           string `json:"ip"`            // IP address or hostname of the SSH server
-	Port         string `json:"port"`          // SSH server port
-	PasswordID   int    `json:"password_id"`   // Reference to the associated password
-	TerminalType string `json:"terminal_type"` // Type of terminal to emulate (e.g., xterm)
-	KeepAlive    bool   `json:"keep_alive"`    // Enable keep-alive messages
-	Compression  bool   `json:"compression"`   // Enable compression for the SSH connection
+	Name              string            `json:"name"`                 // Unique identifier for the host
+	Description       string            `json:"description"`          // Description of the host
+	Notes             string            `json:"notes,omitempty"`      // Freeform multiline notes, e.g. runbook links or maintenance windows
+	Login             string            `json:"login"`                // Username for SSH authentication
+	IP                string            `json:"ip"`                   // IP address or hostname of the SSH server
+	Port              string            `json:"port"`                 // SSH server port
+	PasswordID        int               `json:"password_id"`          // Reference to the associated password (>= 0) or key (-ID, see Key.AuthID)
+	AuthIDs           []int             `json:"auth_ids,omitempty"`   // Authentication methods tried in order, encoded like PasswordID (see GetAuthIDs)
+	TerminalType      string            `json:"terminal_type"`        // Type of terminal to emulate (e.g., xterm)
+	KeepAlive         bool              `json:"keep_alive"`           // Legacy flag kept for stored configs; see KeepAliveInterval
+	Compression       bool              `json:"compression"`          // Enable compression for the SSH connection
+	LogSession        bool              `json:"log_session"`          // Save a transcript of shell sessions under the config dir
+	UseSystemSSH      bool              `json:"use_system_ssh"`       // Connect with the system ssh binary instead of the built-in client
+	Group             string            `json:"group"`                // Optional group used to organize hosts in the list
+	Environment       string            `json:"environment"`          // Optional environment label, e.g. "prod" (see NormalizeEnvironment)
+	Tags              []string          `json:"tags,omitempty"`       // Optional labels across groups, e.g. "k8s" or "eu-west" (see NormalizeTags)
+	JumpHost          string            `json:"jump_host"`            // Name of another host used as a bastion (optional)
+	LocalForwards     []string          `json:"local_forwards"`       // Local port forwards, e.g. "8080:localhost:80"
+	RemoteForwards    []string          `json:"remote_forwards"`      // Remote (reverse) port forwards, e.g. "9000:localhost:3000"
+	ConnectTimeout    int               `json:"connect_timeout"`      // Connection timeout in seconds (0 = DefaultConnectTimeout)
+	KeepAliveInterval int               `json:"keep_alive_interval"`  // Keep-alive interval in seconds (0 = DefaultKeepAliveInterval)
+	ReconnectAttempts int               `json:"reconnect_attempts"`   // Reconnect attempts after a dropped session (0 = no reconnect)
+	InitCommands      []string          `json:"init_commands"`        // Commands typed into the remote shell right after login
+	Env               map[string]string `json:"env,omitempty"`        // Environment variables sent before the shell starts (subject to the server's AcceptEnv)
+	RemoteDir         string            `json:"remote_dir,omitempty"` // Starting directory of the remote transfer panel (empty = home directory)
+	PreConnectCommand string            `json:"pre_connect_command"`  // Local command run before connecting, e.g. to start a VPN (local only, not synced)
+	LastConnected     time.Time         `json:"last_connected"`       // Time of the last successful SSH session (local only, not synced)
+	ConnectCount      int               `json:"connect_count"`        // Number of successful SSH sessions (local only, not synced)
+	Algorithms                          // Algorithm overrides applied after the global ones (see Algorithms)
+}
+
+// Known host environments. Other labels are allowed but are not color coded.
+const (
+	EnvironmentProduction  = "prod"
+	EnvironmentStaging     = "staging"
+	EnvironmentTest        = "test"
+	EnvironmentDevelopment = "dev"
+)
+
+// environmentAliases maps common spellings to the known environment names.
+var environmentAliases = map[string]string{
+	"production":  EnvironmentProduction,
+	"prd":         EnvironmentProduction,
+	"stage":       EnvironmentStaging,
+	"stg":         EnvironmentStaging,
+	"qa":          EnvironmentTest,
+	"testing":     EnvironmentTest,
+	"development": EnvironmentDevelopment,
+	"devel":       EnvironmentDevelopment,
+}
+
+// NormalizeEnvironment lowercases an environment label and maps common
+// aliases (e.g. "Production") to the known environment names.
+func NormalizeEnvironment(environment string) string {
+	environment = strings.ToLower(strings.TrimSpace(environment))
+	if known, ok := environmentAliases[environment]; ok {
+		return known
+	}
+	return environment
+}
+
+// IsProduction reports whether the host belongs to the production environment.
+func (h *Host) IsProduction() bool {
+	return NormalizeEnvironment(h.Environment) == EnvironmentProduction
+}
+
+// NormalizeAddress trims an IP address or hostname and removes the brackets
+// around an IPv6 literal ("[2001:db8::1]" becomes "2001:db8::1"). Addresses are
+// stored without brackets; they are added where a port follows.
+func NormalizeAddress(address string) string {
+	address = strings.TrimSpace(address)
+	if strings.HasPrefix(address, "[") && strings.HasSuffix(address, "]") {
+		address = address[1 : len(address)-1]
+	}
+	return address
+}
+
+// NormalizeTags lowercases and trims tags, dropping empty and duplicate ones
+// while keeping their order.
+func NormalizeTags(tags []string) []string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+// HasTag reports whether the host is labeled with tag (ignoring case).
+func (h *Host) HasTag(tag string) bool {
+	for _, t := range h.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// HostTags returns all tags used by hosts, sorted, with the number of hosts
+// labeled with each.
+func HostTags(hosts []Host) ([]string, map[string]int) {
+	counts := make(map[string]int)
+	for _, host := range hosts {
+		for _, tag := range NormalizeTags(host.Tags) {
+			counts[tag]++
+		}
+	}
+	tags := make([]string, 0, len(counts))
+	for tag := range counts {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags, counts
+}
+
+// GetAuthIDs returns the host's authentication methods in the order they are
+// offered to the server. Hosts without AuthIDs (including configurations saved
+// before it existed) use their single PasswordID.
+func (h *Host) GetAuthIDs() []int {
+	if len(h.AuthIDs) > 0 {
+		return h.AuthIDs
+	}
+	return []int{h.PasswordID}
 }
 
+// SetAuthIDs sets the host's authentication methods. PasswordID always follows
+// the first method, so older versions still connect with it; AuthIDs is only
+// stored when there is more than one method.
+func (h *Host) SetAuthIDs(ids []int) {
+	if len(ids) == 0 {
+		return
+	}
+	h.PasswordID = ids[0]
+	if len(ids) == 1 {
+		h.AuthIDs = nil
+		return
+	}
+	h.AuthIDs = append([]int(nil), ids...)
+}
+
+// UsesAuth reports whether the password or key encoded as id (see PasswordID)
+// is one of the host's authentication methods.
+func (h *Host) UsesAuth(id int) bool {
+	for _, authID := range h.GetAuthIDs() {
+		if authID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// ShiftAuthIDs updates the host's references after the password at index
+// deleted was removed. Passwords are referenced by their position, so later
+// passwords move down by one; keys have stable IDs and are not affected. It
+// reports whether any reference changed. The deleted password must not be in use.
+func (h *Host) ShiftAuthIDs(deleted int) bool {
+	shift := func(id int) int {
+		if id > deleted {
+			return id - 1
+		}
+		return id
+	}
+
+	changed := false
+	if id := shift(h.PasswordID); id != h.PasswordID {
+		h.PasswordID = id
+		changed = true
+	}
+	for i, id := range h.AuthIDs {
+		if shifted := shift(id); shifted != id {
+			h.AuthIDs[i] = shifted
+			changed = true
+		}
+	}
+	return changed
+}
+
+// GetConnectTimeout returns the host's connection timeout, falling back to
+// DefaultConnectTimeout when none is configured.
+func (h *Host) GetConnectTimeout() time.Duration {
+	if h.ConnectTimeout <= 0 {
+		return DefaultConnectTimeout
+	}
+	return time.Duration(h.ConnectTimeout) * time.Second
+}
+
+// GetTerminalType returns the TERM value requested for the remote PTY,
+// falling back to DefaultTerminalType when none is configured.
+func (h *Host) GetTerminalType() string {
+	if h.TerminalType == "" {
+		return DefaultTerminalType
+	}
+	return h.TerminalType
+}
+
+// GetKeepAliveInterval returns how often keep-alive requests are sent during
+// a shell session, falling back to DefaultKeepAliveInterval when none is configured.
+func (h *Host) GetKeepAliveInterval() time.Duration {
+	if h.KeepAliveInterval <= 0 {
+		return DefaultKeepAliveInterval
+	}
+	return time.Duration(h.KeepAliveInterval) * time.Second
+}
+
+// ParseEnv parses environment variables written as comma separated
+// "NAME=value" pairs, e.g. "LANG=en_US.UTF-8, EDITOR=vim". Values may contain
+// "=" but not commas; names must be valid shell variable names.
+func ParseEnv(value string) (map[string]string, error) {
+	env := make(map[string]string)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, val, ok := strings.Cut(item, "=")
+		name = strings.TrimSpace(name)
+		if !ok || !isEnvName(name) {
+			return nil, fmt.Errorf("invalid environment variable %q (expected NAME=value)", item)
+		}
+		env[name] = strings.TrimSpace(val)
+	}
+	if len(env) == 0 {
+		return nil, nil
+	}
+	return env, nil
+}
+
+// FormatEnv formats environment variables in the syntax accepted by ParseEnv,
+// sorted by name.
+func FormatEnv(env map[string]string) string {
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + "=" + env[name]
+	}
+	return strings.Join(pairs, ", ")
+}
+
+// isEnvName reports whether name is a valid environment variable name:
+// letters, digits and underscores, not starting with a digit.
+func isEnvName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Host list sort orders stored in Config.HostSort.
+const (
+	HostSortGroup  = "group"  // By group, then by name (default)
+	HostSortName   = "name"   // By name, ignoring groups
+	HostSortRecent = "recent" // Most recently connected first
+	HostSortManual = "manual" // Order of the hosts in the configuration
+)
+
 // Config holds the application's configuration, including hosts, passwords, and keys.
+// Only Hosts, Passwords and Keys are synced; a sync keeps every other field
+// from the local file, so new local-only settings need no changes in the sync code.
 type Config struct {
-	Hosts     []Host     `json:"hosts"`     // List of SSH hosts
-	Passwords []Password `json:"passwords"` // List of passwords
-	Keys      []Key      `json:"keys"`      // List of SSH keys
+	Hosts       []Host              `json:"hosts"`                  // List of SSH hosts
+	Passwords   []Password          `json:"passwords"`              // List of passwords
+	Keys        []Key               `json:"keys"`                   // List of SSH keys
+	Bookmarks   []Bookmark          `json:"bookmarks,omitempty"`    // Transfer view bookmarks (local only, not synced)
+	HostSort    string              `json:"host_sort,omitempty"`    // Host list sort order (local only, not synced)
+	Theme       string              `json:"theme,omitempty"`        // Name of the selected color theme (local only, not synced)
+	KeyCheck    string              `json:"key_check,omitempty"`    // Known plaintext encrypted with the encryption key, used to verify it (local only, not synced)
+	LastSync    time.Time           `json:"last_sync,omitempty"`    // Time of the last successful sync with the API (local only, not synced)
+	ApiURL      string              `json:"api_url,omitempty"`      // Base URL of a self-hosted sync API (local only, not synced)
+	KeyBindings map[string][]string `json:"key_bindings,omitempty"` // Key overrides by action name, e.g. "up" (local only, not synced)
+	LocalDir    string              `json:"local_dir,omitempty"`    // Starting directory of the local transfer panel (local only, not synced)
+	NoPreserve  bool                `json:"no_preserve,omitempty"`  // Don't copy permissions and modification times on transfers (local only, not synced)
+	RateLimit   int                 `json:"rate_limit,omitempty"`   // Transfer speed limit in KB/s, 0 for unlimited (local only, not synced)
+	LastHost    string              `json:"last_host,omitempty"`    // Name of the host used last, selected on startup (local only, not synced)
+	IdleLock    int                 `json:"idle_lock,omitempty"`    // Minutes without a keypress before the app locks, 0 to disable (local only, not synced)
+	Algorithms                      // Algorithm overrides for all hosts (local only, not synced)
 }
@@ -6,15 +6,18 @@ import (
 	"os"
 	"path/filepath"
 	"sshManager/internal/crypto"
+	"sshManager/internal/utils"
 	"strings"
 	"unicode"
 )
 
 type Key struct {
+	ID          int    `json:"id,omitempty"` // Stały identyfikator; hosty odwołują się do klucza przez -ID (zob. AuthID)
 	Description string `json:"description"`
 	Path        string `json:"path,omitempty"`     // Ścieżka do klucza (jeśli używamy zewnętrznego)
 	KeyData     string `json:"key_data,omitempty"` // Zawartość klucza (jeśli przechowujemy lokalnie)
 	RawKeyData  string `json:"-"`                  // Niezaszyfrowane dane klucza - nie zapisywane do JSON
+	UseAgent    bool   `json:"use_agent"`          // Autoryzacja przez ssh-agent (path/key_data jako zapasowe)
 }
 
 const (
@@ -22,9 +25,20 @@ const (
 	KeyPrefix    = "K" // Dodane
 )
 
+// keyProfile to profil konfiguracji, do którego należą lokalnie zapisane klucze
+// (pusty dla profilu domyślnego)
+var keyProfile string
+
+// SetKeyProfile ustawia profil konfiguracji dla ścieżek kluczy zapisanych
+// lokalnie; każdy profil ma własny katalog, więc klucze o tym samym opisie
+// w różnych profilach się nie nadpisują
+func SetKeyProfile(profile string) {
+	keyProfile = profile
+}
+
 // NewKey tworzy nową instancję Key
 // NewKey tworzy nową instancję Key
-func NewKey(description string, path string, keyData string, cipher *crypto.Cipher) (*Key, error) {
+func NewKey(description string, path string, keyData string, useAgent bool, cipher *crypto.Cipher) (*Key, error) {
 	if description == "" {
 		return nil, errors.New("description cannot be empty")
 	}
@@ -34,8 +48,8 @@ func NewKey(description string, path string, keyData string, cipher *crypto.Ciph
 		return nil, errors.New("cannot specify both path and key data")
 	}
 
-	// Sprawdzenie czy podano przynajmniej jedno: path lub keyData
-	if path == "" && keyData == "" {
+	// Sprawdzenie czy podano przynajmniej jedno: path lub keyData (nie dotyczy ssh-agenta)
+	if path == "" && keyData == "" && !useAgent {
 		return nil, errors.New("either path or key data must be provided")
 	}
 
@@ -43,6 +57,7 @@ func NewKey(description string, path string, keyData string, cipher *crypto.Ciph
 		Description: description,
 		Path:        path,
 		RawKeyData:  keyData, // Zachowujemy oryginalne dane
+		UseAgent:    useAgent,
 	}
 
 	// Jeśli podano dane klucza, szyfrujemy je dla KeyData (do zapisu w konfiguracji/API)
@@ -70,7 +85,7 @@ func (k *Key) Validate() error {
 		return errors.New("description cannot be empty")
 	}
 
-	if k.Path == "" && k.KeyData == "" {
+	if k.Path == "" && k.KeyData == "" && !k.UseAgent {
 		return errors.New("either path or key data must be provided")
 	}
 
@@ -117,18 +132,81 @@ func (k *Key) GetKeyPath() (string, error) {
 			return '_'
 		}, k.Description)
 
-		return filepath.Join(homeDir, ".config", "sshmen", LocalKeysDir, safeFileName+".key"), nil
+		keysDir := filepath.Join(homeDir, ".config", "sshmen")
+		if keyProfile != "" {
+			keysDir = filepath.Join(keysDir, "profiles", keyProfile)
+		}
+		return filepath.Join(keysDir, LocalKeysDir, safeFileName+".key"), nil
 	}
 
 	return "", errors.New("no key path or data available")
 }
 
+// AuthID zwraca odwołanie do klucza zapisywane w hoście (PasswordID, AuthIDs);
+// klucze są kodowane liczbami ujemnymi, aby nie myliły się z indeksami haseł
+func (k *Key) AuthID() int {
+	return -k.ID
+}
+
+// FindKeyByAuthID odnajduje klucz, do którego odwołuje się host (id < 0);
+// odwołanie nie zależy od kolejności kluczy, więc przetrwa usunięcie innego klucza
+func FindKeyByAuthID(keys []Key, id int) (Key, bool) {
+	if id >= 0 {
+		return Key{}, false
+	}
+	for _, key := range keys {
+		if key.AuthID() == id {
+			return key, true
+		}
+	}
+	return Key{}, false
+}
+
+// NextKeyID zwraca identyfikator dla nowego klucza (większy od wszystkich użytych)
+func NextKeyID(keys []Key) int {
+	next := 1
+	for _, key := range keys {
+		next = max(next, key.ID+1)
+	}
+	return next
+}
+
+// AssignKeyIDs nadaje identyfikatory kluczom, które ich nie mają (konfiguracje
+// z wcześniejszych wersji). Klucz dostaje, o ile jest wolny, numer pozycji+1:
+// tak wcześniejsze wersje kodowały odwołania (-(indeks+1)), więc hosty nadal
+// wskazują te same klucze. Zwraca true, jeśli coś zmieniono.
+func AssignKeyIDs(keys []Key) bool {
+	used := make(map[int]bool)
+	for _, key := range keys {
+		if key.ID > 0 {
+			used[key.ID] = true
+		}
+	}
+
+	changed := false
+	for i := range keys {
+		if keys[i].ID > 0 {
+			continue
+		}
+		id := i + 1
+		if used[id] {
+			id = NextKeyID(keys)
+		}
+		keys[i].ID = id
+		used[id] = true
+		changed = true
+	}
+	return changed
+}
+
 // Clone tworzy kopię klucza
 func (k *Key) Clone() *Key {
 	return &Key{
+		ID:          k.ID,
 		Description: k.Description,
 		Path:        k.Path,
 		KeyData:     k.KeyData,
+		UseAgent:    k.UseAgent,
 	}
 }
 
@@ -149,8 +227,8 @@ func (k *Key) SaveKeyToFile() error {
 		return fmt.Errorf("failed to create key directory: %v", err)
 	}
 
-	// Zapisz niezaszyfrowane dane
-	if err := os.WriteFile(keyPath, []byte(k.RawKeyData), 0600); err != nil {
+	// Zapisz niezaszyfrowane dane (atomowo, aby przerwany zapis nie uszkodził klucza)
+	if err := utils.WriteFileAtomic(keyPath, []byte(k.RawKeyData), 0600); err != nil {
 		return fmt.Errorf("failed to write key file: %v", err)
 	}
 
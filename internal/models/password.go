@@ -49,6 +49,12 @@ func (p *Password) GetDecrypted(cipher *crypto.Cipher) (string, error) {
 	return cipher.Decrypt(p.Password)
 }
 
+// GetDecryptedBytes zwraca odszyfrowane hasło jako []byte, które po użyciu
+// należy wyczyścić przez crypto.Wipe
+func (p *Password) GetDecryptedBytes(cipher *crypto.Cipher) ([]byte, error) {
+	return cipher.DecryptBytes(p.Password)
+}
+
 // UpdatePassword aktualizuje zaszyfrowane hasło
 func (p *Password) UpdatePassword(newPlainPassword string, cipher *crypto.Cipher) error {
 	if newPlainPassword == "" {
@@ -0,0 +1,94 @@
+// internal/models/password_strength.go
+
+package models
+
+import (
+	"math"
+	"unicode"
+)
+
+// Progi entropii (w bitach) dla kolejnych poziomów siły hasła
+const (
+	entropyWeak   = 28
+	entropyFair   = 36
+	entropyStrong = 60
+	entropyMax    = 128
+)
+
+// PasswordStrength to szacunkowa siła hasła; służy tylko jako podpowiedź
+type PasswordStrength struct {
+	Entropy float64 // Szacowana entropia w bitach
+	Classes int     // Liczba użytych klas znaków (małe, wielkie, cyfry, symbole)
+	Label   string  // "very weak", "weak", "fair", "strong" lub "very strong"
+}
+
+// EstimatePasswordStrength szacuje entropię hasła na podstawie długości
+// i wielkości alfabetu użytych klas znaków; powtarzające się znaki ją obniżają
+func EstimatePasswordStrength(password string) PasswordStrength {
+	var lower, upper, digit, symbol, other bool
+	distinct := make(map[rune]bool)
+	length := 0
+	for _, r := range password {
+		length++
+		distinct[r] = true
+		switch {
+		case r <= unicode.MaxASCII && unicode.IsLower(r):
+			lower = true
+		case r <= unicode.MaxASCII && unicode.IsUpper(r):
+			upper = true
+		case r <= unicode.MaxASCII && unicode.IsDigit(r):
+			digit = true
+		case r <= unicode.MaxASCII:
+			symbol = true
+		default:
+			other = true
+		}
+	}
+
+	pool := 0
+	for _, class := range []struct {
+		used bool
+		size int
+	}{
+		{lower, 26},
+		{upper, 26},
+		{digit, 10},
+		{symbol, 33},
+		{other, 100},
+	} {
+		if class.used {
+			pool += class.size
+		}
+	}
+
+	// Znaki spoza ASCII liczą się do klasy symboli
+	classes := 0
+	for _, used := range []bool{lower, upper, digit, symbol || other} {
+		if used {
+			classes++
+		}
+	}
+
+	strength := PasswordStrength{Classes: classes, Label: "very weak"}
+	if length == 0 {
+		return strength
+	}
+
+	strength.Entropy = float64(length) * math.Log2(float64(pool))
+	// Hasła typu "aaaaaaaa" mają znacznie mniejszą entropię niż wynika z długości
+	if variety := float64(len(distinct)) * 2 / float64(length); variety < 1 {
+		strength.Entropy *= variety
+	}
+
+	switch {
+	case strength.Entropy >= entropyMax:
+		strength.Label = "very strong"
+	case strength.Entropy >= entropyStrong:
+		strength.Label = "strong"
+	case strength.Entropy >= entropyFair:
+		strength.Label = "fair"
+	case strength.Entropy >= entropyWeak:
+		strength.Label = "weak"
+	}
+	return strength
+}
@@ -0,0 +1,97 @@
+// internal/ssh/agent_auth.go
+
+package ssh
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"sshManager/internal/models"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// agentAuthPrefix oznacza dane autoryzacji klucza obsługiwanego przez ssh-agent
+const agentAuthPrefix = "ssh-agent:"
+
+// AgentAuthData buduje dane autoryzacji dla klucza w trybie ssh-agent.
+// fallback to ścieżka klucza używana, gdy agent jest niedostępny (może być pusta).
+func AgentAuthData(fallback string) []byte {
+	return []byte(agentAuthPrefix + fallback)
+}
+
+// isAgentAuthData sprawdza, czy dane autoryzacji wskazują na ssh-agent
+func isAgentAuthData(host *models.Host, authData []byte) bool {
+	return host.PasswordID < 0 && bytes.HasPrefix(authData, []byte(agentAuthPrefix))
+}
+
+// dialAgent łączy się z ssh-agentem przez gniazdo z SSH_AUTH_SOCK
+func dialAgent() (net.Conn, error) {
+	socket := os.Getenv("SSH_AUTH_SOCK")
+	if socket == "" {
+		return nil, fmt.Errorf("SSH_AUTH_SOCK is not set")
+	}
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to ssh-agent: %v", err)
+	}
+	return conn, nil
+}
+
+// CheckAgent sprawdza, czy ssh-agent jest dostępny
+func CheckAgent() error {
+	conn, err := dialAgent()
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// newAgentAuthMethod przygotowuje autoryzację kluczami z ssh-agenta,
+// a przy jego braku wraca do klucza zapasowego
+func newAgentAuthMethod(host *models.Host, authData []byte) (ssh.AuthMethod, error) {
+	fallback := bytes.TrimPrefix(authData, []byte(agentAuthPrefix))
+
+	conn, err := dialAgent()
+	if err == nil {
+		// Połączenie z agentem pozostaje otwarte - klient SSH pobiera
+		// sygnatury także przy ponownej autoryzacji
+		return ssh.PublicKeysCallback(agent.NewClient(conn).Signers), nil
+	}
+
+	if len(fallback) == 0 {
+		return nil, fmt.Errorf("ssh-agent unavailable and no fallback key configured: %v", err)
+	}
+	return newAuthMethod(host, fallback)
+}
+
+// agentWarnings zwraca ostrzeżenie, gdy host miał używać agenta, ale ten jest niedostępny
+func agentWarnings(host *models.Host, authData []byte) []string {
+	if isAuthListData(host, authData) {
+		if !authListUsesAgent(authData) {
+			return nil
+		}
+	} else if !isAgentAuthData(host, authData) {
+		return nil
+	}
+	if err := CheckAgent(); err != nil {
+		return []string{fmt.Sprintf("ssh-agent unavailable (%v), using the configured key instead", err)}
+	}
+	return nil
+}
+
+// authListUsesAgent sprawdza, czy któraś z metod na liście hosta korzysta z ssh-agenta
+func authListUsesAgent(authData []byte) bool {
+	entries, err := parseAuthList(authData)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if entry.Key && bytes.HasPrefix(entry.Data, []byte(agentAuthPrefix)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+// internal/ssh/algorithms.go
+
+package ssh
+
+import (
+	"strings"
+
+	"sshManager/internal/models"
+)
+
+// Domyślne listy algorytmów klienta wbudowanego; ustawienia globalne i hosta
+// (models.Algorithms) mogą je zastąpić albo zmodyfikować
+var (
+	defaultHostKeyAlgorithms = []string{
+		KeyAlgoECDSA256,
+		KeyAlgoECDSA384,
+		KeyAlgoECDSA521,
+		KeyAlgoED25519,
+		KeyAlgoRSA,
+		KeyAlgoRSASHA2256,
+		KeyAlgoRSASHA2512,
+	}
+	defaultCiphers = []string{
+		"aes128-gcm@openssh.com",
+		"aes256-gcm@openssh.com",
+		"chacha20-poly1305@openssh.com",
+		"aes128-ctr",
+		"aes192-ctr",
+		"aes256-ctr",
+	}
+	defaultKeyExchanges = []string{
+		"curve25519-sha256@libssh.org",
+		"ecdh-sha2-nistp256",
+		"ecdh-sha2-nistp384",
+		"ecdh-sha2-nistp521",
+		"diffie-hellman-group14-sha256",
+		"diffie-hellman-group16-sha512",
+	}
+)
+
+// resolveAlgorithms wylicza algorytmy dla hosta: listy domyślne zmienione
+// najpierw przez ustawienia globalne, a potem przez ustawienia hosta
+func resolveAlgorithms(global models.Algorithms, host *models.Host) models.Algorithms {
+	return models.Algorithms{
+		HostKeyAlgorithms: mergeAlgorithms(mergeAlgorithms(defaultHostKeyAlgorithms, global.HostKeyAlgorithms), host.HostKeyAlgorithms),
+		Ciphers:           mergeAlgorithms(mergeAlgorithms(defaultCiphers, global.Ciphers), host.Ciphers),
+		KeyExchanges:      mergeAlgorithms(mergeAlgorithms(defaultKeyExchanges, global.KeyExchanges), host.KeyExchanges),
+	}
+}
+
+// mergeAlgorithms stosuje nadpisania do listy base: zwykłe wpisy zastępują
+// listę, a wpisy z przedrostkiem "+", "-" lub "^" dodają algorytm na końcu,
+// usuwają go albo dodają na początku (jak w OpenSSH)
+func mergeAlgorithms(base, overrides []string) []string {
+	if len(overrides) == 0 {
+		return base
+	}
+
+	var replacement []string
+	for _, item := range overrides {
+		if item != "" && !strings.ContainsAny(item[:1], "+-^") {
+			replacement = append(replacement, item)
+		}
+	}
+	result := append([]string(nil), base...)
+	if len(replacement) > 0 {
+		result = replacement
+	}
+
+	for _, item := range overrides {
+		if len(item) < 2 {
+			continue
+		}
+		name := item[1:]
+		switch item[0] {
+		case '+':
+			result = append(removeAlgorithm(result, name), name)
+		case '^':
+			result = append([]string{name}, removeAlgorithm(result, name)...)
+		case '-':
+			result = removeAlgorithm(result, name)
+		}
+	}
+	return result
+}
+
+// removeAlgorithm zwraca listę bez podanego algorytmu
+func removeAlgorithm(list []string, name string) []string {
+	result := make([]string, 0, len(list))
+	for _, item := range list {
+		if item != name {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// SetDefaultAlgorithms ustawia globalne nadpisania algorytmów (z konfiguracji),
+// stosowane przed ustawieniami hosta
+func (s *SSHClient) SetDefaultAlgorithms(algorithms models.Algorithms) {
+	s.algorithms = algorithms
+}
+
+// SetDefaultAlgorithms ustawia globalne nadpisania algorytmów dla transferów
+func (ft *FileTransfer) SetDefaultAlgorithms(algorithms models.Algorithms) {
+	ft.algorithms = algorithms
+}
@@ -0,0 +1,214 @@
+// internal/ssh/auth_list.go
+
+package ssh
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"sshManager/internal/crypto"
+	"sshManager/internal/models"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// authListPrefix oznacza dane autoryzacji hosta z kilkoma metodami (AuthIDs)
+const authListPrefix = "auth-list:"
+
+// AuthEntry to jedna metoda autoryzacji z listy hosta
+type AuthEntry struct {
+	Key  bool   // true: Data to ścieżka klucza lub dane ssh-agenta
+	Data []byte // Ścieżka klucza, AgentAuthData albo hasło
+}
+
+// MultiAuthData buduje dane autoryzacji dla hosta z kilkoma metodami,
+// w kolejności, w jakiej mają być proponowane serwerowi. Każda pozycja to
+// rodzaj, długość i dane bez żadnego kodowania, więc parseAuthList zwraca
+// fragmenty wyniku, a crypto.Wipe na wyniku czyści wszystkie hasła z listy.
+func MultiAuthData(entries []AuthEntry) []byte {
+	size := len(authListPrefix)
+	for _, entry := range entries {
+		size += 1 + binary.MaxVarintLen64 + len(entry.Data)
+	}
+
+	data := make([]byte, 0, size)
+	data = append(data, authListPrefix...)
+	for _, entry := range entries {
+		kind := byte('p')
+		if entry.Key {
+			kind = 'k'
+		}
+		data = append(data, kind)
+		data = binary.AppendUvarint(data, uint64(len(entry.Data)))
+		data = append(data, entry.Data...)
+	}
+	return data
+}
+
+// isAuthListData sprawdza, czy dane autoryzacji zawierają listę metod
+func isAuthListData(host *models.Host, authData []byte) bool {
+	return len(host.AuthIDs) > 1 && bytes.HasPrefix(authData, []byte(authListPrefix))
+}
+
+// parseAuthList odczytuje listę metod zbudowaną przez MultiAuthData; dane
+// pozycji wskazują na authData, bez kopiowania haseł
+func parseAuthList(authData []byte) ([]AuthEntry, error) {
+	var entries []AuthEntry
+	rest := bytes.TrimPrefix(authData, []byte(authListPrefix))
+	for len(rest) > 0 {
+		kind := rest[0]
+		size, n := binary.Uvarint(rest[1:])
+		if (kind != 'k' && kind != 'p') || n <= 0 || uint64(len(rest)-1-n) < size {
+			return nil, errors.New("invalid authentication methods")
+		}
+		start := 1 + n
+		end := start + int(size)
+		entries = append(entries, AuthEntry{Key: kind == 'k', Data: rest[start:end:end]})
+		rest = rest[end:]
+	}
+	return entries, nil
+}
+
+// newAuthMethods przygotowuje metody autoryzacji hosta. Klient SSH próbuje
+// każdego typu metody tylko raz, dlatego wszystkie klucze trafiają do jednej
+// metody publickey, a hasła do jednej metody ponawianej dla kolejnych haseł.
+// Typy metod są proponowane w kolejności pierwszego wystąpienia na liście.
+func newAuthMethods(host *models.Host, authData []byte) ([]ssh.AuthMethod, error) {
+	if !isAuthListData(host, authData) {
+		method, err := newAuthMethod(host, authData)
+		if err != nil {
+			return nil, err
+		}
+		return []ssh.AuthMethod{method}, nil
+	}
+
+	entries, err := parseAuthList(authData)
+	if err != nil {
+		return nil, err
+	}
+
+	var signerSources []func() ([]ssh.Signer, error)
+	var passwords [][]byte
+	var order []string
+	var problems []string
+	for _, entry := range entries {
+		if !entry.Key {
+			if len(passwords) == 0 {
+				order = append(order, "password")
+			}
+			passwords = append(passwords, entry.Data)
+			continue
+		}
+
+		source, err := newSignerSource(entry.Data)
+		if err != nil {
+			// Niedostępny klucz nie blokuje pozostałych metod
+			problems = append(problems, err.Error())
+			continue
+		}
+		if len(signerSources) == 0 {
+			order = append(order, "publickey")
+		}
+		signerSources = append(signerSources, source)
+	}
+
+	if len(order) == 0 {
+		if len(problems) > 0 {
+			return nil, fmt.Errorf("no usable authentication method: %s", strings.Join(problems, "; "))
+		}
+		return nil, errors.New("no authentication method configured")
+	}
+
+	methods := make([]ssh.AuthMethod, 0, len(order))
+	for _, kind := range order {
+		if kind == "publickey" {
+			methods = append(methods, ssh.PublicKeysCallback(combineSigners(signerSources)))
+			continue
+		}
+		if len(passwords) == 1 {
+			methods = append(methods, passwordMethod(passwords[0]))
+			continue
+		}
+		next := 0
+		methods = append(methods, ssh.RetryableAuthMethod(ssh.PasswordCallback(func() (string, error) {
+			if next >= len(passwords) {
+				return "", errors.New("no more passwords to try")
+			}
+			password := passwords[next]
+			next++
+			return string(password), nil
+		}), len(passwords)))
+	}
+	return methods, nil
+}
+
+// newSignerSource przygotowuje źródło kluczy dla jednej pozycji listy:
+// ssh-agenta (z kluczem zapasowym) albo plik klucza
+func newSignerSource(authData []byte) (func() ([]ssh.Signer, error), error) {
+	if bytes.HasPrefix(authData, []byte(agentAuthPrefix)) {
+		fallback := bytes.TrimPrefix(authData, []byte(agentAuthPrefix))
+		conn, err := dialAgent()
+		if err == nil {
+			return agent.NewClient(conn).Signers, nil
+		}
+		if len(fallback) == 0 {
+			return nil, fmt.Errorf("ssh-agent unavailable and no fallback key configured: %v", err)
+		}
+		authData = fallback
+	}
+
+	signer, err := loadKeySigner(string(authData))
+	if err != nil {
+		return nil, err
+	}
+	return func() ([]ssh.Signer, error) {
+		return []ssh.Signer{signer}, nil
+	}, nil
+}
+
+// combineSigners łączy klucze z kilku źródeł, pomijając te, które zawiodły
+func combineSigners(sources []func() ([]ssh.Signer, error)) func() ([]ssh.Signer, error) {
+	return func() ([]ssh.Signer, error) {
+		var signers []ssh.Signer
+		var lastErr error
+		for _, source := range sources {
+			sourceSigners, err := source()
+			if err != nil {
+				lastErr = err
+				continue
+			}
+			signers = append(signers, sourceSigners...)
+		}
+		if len(signers) == 0 && lastErr != nil {
+			return nil, lastErr
+		}
+		return signers, nil
+	}
+}
+
+// passwordMethod przygotowuje autoryzację hasłem; tekst hasła powstaje dopiero,
+// gdy serwer o nie poprosi, a nie przy budowaniu metody
+func passwordMethod(password []byte) ssh.AuthMethod {
+	return ssh.PasswordCallback(func() (string, error) {
+		return string(password), nil
+	})
+}
+
+// loadKeySigner wczytuje klucz prywatny z pliku; odczytana zawartość pliku
+// jest czyszczona po sparsowaniu klucza
+func loadKeySigner(path string) (ssh.Signer, error) {
+	key, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SSH key: %v", err)
+	}
+	defer crypto.Wipe(key)
+	signer, err := ssh.ParsePrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SSH key: %v", err)
+	}
+	return signer, nil
+}
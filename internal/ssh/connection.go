@@ -0,0 +1,209 @@
+// internal/ssh/connection.go
+
+package ssh
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"sshManager/internal/models"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// ConnectionIdleTimeout to czas, po którym nieużywane połączenie współdzielone
+// jest zamykane
+const ConnectionIdleTimeout = 5 * time.Minute
+
+// connectionCheckTimeout ogranicza sprawdzanie, czy zapamiętane połączenie żyje
+const connectionCheckTimeout = 5 * time.Second
+
+// Connection przechowuje jedno połączenie SSH, współdzielone przez sesję powłoki
+// i transfer plików. Każdy z nich otwiera na nim własne kanały (NewSession,
+// sftp.NewClient), więc host uwierzytelnia nas (także kodem 2FA) tylko raz.
+// Połączenie do innego hosta zastępuje poprzednie.
+type Connection struct {
+	mutex   sync.Mutex
+	current *sharedConn
+}
+
+// sharedConn to połączenie z hostem (i z hostem pośredniczącym) wraz z licznikiem
+// klientów, którzy go używają
+type sharedConn struct {
+	owner      *Connection // nil - połączenie prywatne, zamykane po zwolnieniu
+	key        string      // Ustawienia hosta, z którymi nawiązano połączenie
+	verified   bool        // Klucze hostów sprawdzone w known_hosts (wymagane przez powłokę)
+	client     *ssh.Client
+	jumpClient *ssh.Client
+	users      int
+	idle       *time.Timer
+	closed     bool
+}
+
+// NewConnection tworzy pusty magazyn połączenia współdzielonego
+func NewConnection() *Connection {
+	return &Connection{}
+}
+
+// connectionKey opisuje ustawienia hosta, od których zależy połączenie; host
+// o innym adresie, loginie, uwierzytelnianiu lub algorytmach łączy się od nowa
+func connectionKey(host *models.Host) string {
+	return strings.Join([]string{
+		host.Login,
+		net.JoinHostPort(host.IP, host.Port),
+		host.JumpHost,
+		fmt.Sprint(host.GetAuthIDs()),
+		fmt.Sprint(host.Algorithms),
+	}, "|")
+}
+
+// acquire zwraca zapamiętane połączenie z hostem, jeśli nadal działa; przy
+// verified tylko takie, którego klucze hostów zostały zweryfikowane
+func (c *Connection) acquire(host *models.Host, verified bool) *sharedConn {
+	if c == nil {
+		return nil
+	}
+
+	c.mutex.Lock()
+	sc := c.current
+	if sc == nil || sc.closed || sc.key != connectionKey(host) || (verified && !sc.verified) {
+		c.mutex.Unlock()
+		return nil
+	}
+	sc.users++
+	if sc.idle != nil {
+		sc.idle.Stop()
+		sc.idle = nil
+	}
+	c.mutex.Unlock()
+
+	// Serwer mógł zamknąć bezczynne połączenie, zanim to zauważyliśmy
+	if !connectionAlive(sc.client) {
+		sc.discard()
+		return nil
+	}
+	return sc
+}
+
+// store zapamiętuje nowe połączenie z hostem (z jednym użytkownikiem) w miejsce
+// poprzedniego; bez magazynu połączenie jest prywatne
+func (c *Connection) store(host *models.Host, client, jumpClient *ssh.Client, verified bool) *sharedConn {
+	sc := &sharedConn{
+		owner:      c,
+		key:        connectionKey(host),
+		verified:   verified,
+		client:     client,
+		jumpClient: jumpClient,
+		users:      1,
+	}
+	if c == nil {
+		return sc
+	}
+
+	c.mutex.Lock()
+	if old := c.current; old != nil && old.users == 0 {
+		old.close()
+	}
+	c.current = sc
+	c.mutex.Unlock()
+
+	// Zerwane połączenie nie może zostać użyte ponownie
+	go func() {
+		client.Wait()
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		sc.closed = true
+		if c.current == sc {
+			c.current = nil
+		}
+		if sc.users == 0 {
+			sc.close()
+		}
+	}()
+	return sc
+}
+
+// Close zamyka zapamiętane połączenie (np. przy wyjściu z programu)
+func (c *Connection) Close() {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.current != nil {
+		c.current.close()
+		c.current = nil
+	}
+}
+
+// release kończy używanie połączenia; ostatni użytkownik zostawia je otwarte
+// na ConnectionIdleTimeout, chyba że zostało już zastąpione lub zerwane
+func (sc *sharedConn) release() {
+	c := sc.owner
+	if c == nil {
+		sc.close()
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	sc.users--
+	if sc.users > 0 {
+		return
+	}
+	if sc.closed || c.current != sc {
+		sc.close()
+		return
+	}
+	sc.idle = time.AfterFunc(ConnectionIdleTimeout, func() {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		if sc.users == 0 && c.current == sc {
+			sc.close()
+			c.current = nil
+		}
+	})
+}
+
+// discard zwalnia połączenie, które przestało działać, aby nikt go już nie użył
+func (sc *sharedConn) discard() {
+	if c := sc.owner; c != nil {
+		c.mutex.Lock()
+		sc.closed = true
+		if c.current == sc {
+			c.current = nil
+		}
+		c.mutex.Unlock()
+	}
+	sc.release()
+}
+
+// close zamyka połączenie z hostem i z hostem pośredniczącym
+func (sc *sharedConn) close() {
+	if sc.idle != nil {
+		sc.idle.Stop()
+		sc.idle = nil
+	}
+	sc.closed = true
+	sc.client.Close()
+	closeJumpClient(sc.jumpClient)
+}
+
+// connectionAlive sprawdza pakietem keepalive, czy połączenie odpowiada
+func connectionAlive(client *ssh.Client) bool {
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		return err == nil
+	case <-time.After(connectionCheckTimeout):
+		return false
+	}
+}
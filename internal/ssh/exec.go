@@ -0,0 +1,69 @@
+// internal/ssh/exec.go
+
+package ssh
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"sshManager/internal/models"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Dial łączy z hostem (także przez host pośredniczący) bez sesji interaktywnej
+// i bez przekierowań portów; służy do wykonywania pojedynczych poleceń przez RunCommand
+func (s *SSHClient) Dial(host *models.Host, authData []byte) error {
+	s.warnings = append(agentWarnings(host, authData), compressionWarnings(host)...)
+	s.banner = ""
+
+	jumpClient, err := connectJumpHost(host, s.resolveJumpHost, s.dialHost)
+	if err != nil {
+		return err
+	}
+
+	client, err := s.dialHost(host, authData, jumpClient)
+	if err != nil {
+		closeJumpClient(jumpClient)
+		return err
+	}
+
+	s.client = client
+	s.jumpClient = jumpClient
+	s.currentHost = host
+	s.lastHost = host
+	return nil
+}
+
+// RunCommand wykonuje polecenie na połączonym hoście (bez powłoki i PTY),
+// przekazując jego wyjście do stdout i stderr. Zwraca kod wyjścia polecenia;
+// błąd oznacza, że polecenia nie udało się wykonać.
+func (s *SSHClient) RunCommand(command string, stdout, stderr io.Writer) (int, error) {
+	if s.client == nil {
+		return -1, errors.New("not connected")
+	}
+	return runCommand(s.client, command, stdout, stderr)
+}
+
+// runCommand wykonuje polecenie w nowej sesji klienta i zwraca jego kod wyjścia
+func runCommand(client *ssh.Client, command string, stdout, stderr io.Writer) (int, error) {
+	session, err := client.NewSession()
+	if err != nil {
+		return -1, fmt.Errorf("failed to create SSH session: %v", err)
+	}
+	defer session.Close()
+
+	session.Stdout = stdout
+	session.Stderr = stderr
+
+	var exitErr *ssh.ExitError
+	switch err := session.Run(command); {
+	case err == nil:
+		return 0, nil
+	case errors.As(err, &exitErr):
+		return exitErr.ExitStatus(), nil
+	default:
+		return -1, fmt.Errorf("failed to execute command: %v", err)
+	}
+}
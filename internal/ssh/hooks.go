@@ -0,0 +1,91 @@
+// internal/ssh/hooks.go
+
+package ssh
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"sshManager/internal/models"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// preConnectTimeout ogranicza czas działania lokalnego polecenia przed połączeniem
+const preConnectTimeout = 2 * time.Minute
+
+// maxHookOutput to maksymalna długość wyjścia polecenia dołączanego do błędu
+const maxHookOutput = 200
+
+// RunPreConnectHook uruchamia lokalnie polecenie host.PreConnectCommand (np. włączenie
+// VPN) przez powłokę systemu. Błąd lub przekroczenie czasu przerywa połączenie.
+func RunPreConnectHook(host *models.Host) error {
+	command := strings.TrimSpace(host.PreConnectCommand)
+	if command == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), preConnectTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+
+	output, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("pre-connect command '%s' timed out after %v", command, preConnectTimeout)
+	}
+	if err != nil {
+		message := fmt.Sprintf("pre-connect command '%s' failed: %v", command, err)
+		if details := strings.TrimSpace(string(output)); details != "" {
+			if len(details) > maxHookOutput {
+				details = details[:maxHookOutput] + "..."
+			}
+			message += "\n" + details
+		}
+		return fmt.Errorf("%s", message)
+	}
+	return nil
+}
+
+// initCommandsInput zwraca polecenia startowe jako tekst wpisywany do powłoki
+func initCommandsInput(commands []string) string {
+	var input strings.Builder
+	for _, command := range commands {
+		if command = strings.TrimSpace(command); command != "" {
+			input.WriteString(command + "\n")
+		}
+	}
+	return input.String()
+}
+
+// sendEnv wysyła zmienne środowiskowe przed uruchomieniem powłoki. Serwery
+// przyjmują zwykle tylko zmienne wymienione w AcceptEnv, więc odrzucona
+// zmienna jest zgłaszana jako ostrzeżenie i nie przerywa połączenia.
+func sendEnv(session *ssh.Session, env map[string]string, warnings io.Writer) {
+	for _, name := range envNames(env) {
+		if err := session.Setenv(name, env[name]); err != nil {
+			fmt.Fprintf(warnings, "Warning: server rejected environment variable %s (check AcceptEnv in sshd_config)\r\n", name)
+		}
+	}
+}
+
+// envNames zwraca posortowane nazwy zmiennych, aby kolejność była powtarzalna
+func envNames(env map[string]string) []string {
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,80 @@
+// internal/ssh/jump_host.go
+
+package ssh
+
+import (
+	"fmt"
+	"sshManager/internal/crypto"
+	"sshManager/internal/models"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// JumpHostResolver zwraca konfigurację hosta pośredniczącego o podanej nazwie
+// oraz dane potrzebne do autoryzacji (ścieżkę klucza lub hasło); dane są
+// czyszczone po połączeniu z hostem pośredniczącym.
+type JumpHostResolver func(name string) (*models.Host, []byte, error)
+
+// hostDialer nawiązuje połączenie z hostem, opcjonalnie przez istniejącego klienta
+type hostDialer func(host *models.Host, authData []byte, via *ssh.Client) (*ssh.Client, error)
+
+// connectJumpHost łączy się z hostem pośredniczącym wskazanym w host.JumpHost.
+// Zwraca nil, jeśli host nie korzysta z jump hosta.
+func connectJumpHost(host *models.Host, resolve JumpHostResolver, dial hostDialer) (*ssh.Client, error) {
+	if host.JumpHost == "" {
+		return nil, nil
+	}
+
+	if resolve == nil {
+		return nil, fmt.Errorf("cannot resolve jump host '%s': host configuration not available", host.JumpHost)
+	}
+
+	jumpHost, authData, err := resolve(host.JumpHost)
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve jump host '%s': %v", host.JumpHost, err)
+	}
+	defer crypto.Wipe(authData)
+
+	// Obsługujemy tylko jeden poziom pośrednictwa
+	if jumpHost.JumpHost != "" {
+		return nil, fmt.Errorf("jump host '%s' cannot use another jump host", jumpHost.Name)
+	}
+
+	client, err := dial(jumpHost, authData, nil)
+	if err != nil {
+		// Weryfikację klucza przekazujemy bez zmian, aby UI mogło zapytać użytkownika
+		if _, ok := err.(*HostKeyVerificationRequired); ok {
+			return nil, err
+		}
+		return nil, fmt.Errorf("jump host '%s': %v", jumpHost.Name, err)
+	}
+
+	return client, nil
+}
+
+// dialThrough nawiązuje połączenie SSH bezpośrednio lub tunelując je przez via
+func dialThrough(via *ssh.Client, addr string, config *ssh.ClientConfig) (*ssh.Client, error) {
+	if via == nil {
+		return ssh.Dial("tcp", addr, config)
+	}
+
+	conn, err := via.Dial("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach %s through jump host: %v", addr, err)
+	}
+
+	c, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return ssh.NewClient(c, chans, reqs), nil
+}
+
+// closeJumpClient zamyka połączenie z hostem pośredniczącym, jeśli istnieje
+func closeJumpClient(jumpClient *ssh.Client) {
+	if jumpClient != nil {
+		jumpClient.Close()
+	}
+}
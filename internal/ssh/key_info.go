@@ -0,0 +1,54 @@
+// internal/ssh/key_info.go
+
+package ssh
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// KeyInfo opisuje klucz prywatny na liście kluczy
+type KeyInfo struct {
+	Type        string // Typ klucza, np. "ed25519" albo "rsa-4096"
+	Fingerprint string // Odcisk SHA256 klucza publicznego (pusty, gdy nieznany)
+	Encrypted   bool   // true, gdy klucz jest chroniony hasłem
+}
+
+// DescribePrivateKey zwraca typ i odcisk klucza prywatnego. Dla klucza
+// chronionego hasłem zwraca Encrypted; typ i odcisk są wtedy znane tylko
+// w formacie OpenSSH, który zapisuje klucz publiczny jawnie.
+func DescribePrivateKey(keyData []byte) (KeyInfo, error) {
+	signer, err := ssh.ParsePrivateKey(keyData)
+	if err == nil {
+		return describePublicKey(signer.PublicKey()), nil
+	}
+
+	var missing *ssh.PassphraseMissingError
+	if errors.As(err, &missing) {
+		info := KeyInfo{}
+		if missing.PublicKey != nil {
+			info = describePublicKey(missing.PublicKey)
+		}
+		info.Encrypted = true
+		return info, nil
+	}
+	return KeyInfo{}, fmt.Errorf("invalid private key: %v", err)
+}
+
+// describePublicKey buduje opis z klucza publicznego
+func describePublicKey(publicKey ssh.PublicKey) KeyInfo {
+	keyType := strings.TrimPrefix(publicKey.Type(), "ssh-")
+	if cryptoKey, ok := publicKey.(ssh.CryptoPublicKey); ok {
+		if rsaKey, ok := cryptoKey.CryptoPublicKey().(*rsa.PublicKey); ok {
+			keyType = fmt.Sprintf("rsa-%d", rsaKey.N.BitLen())
+		}
+	}
+	return KeyInfo{
+		Type:        keyType,
+		Fingerprint: ssh.FingerprintSHA256(publicKey),
+	}
+}
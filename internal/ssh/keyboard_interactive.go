@@ -0,0 +1,100 @@
+// internal/ssh/keyboard_interactive.go
+
+package ssh
+
+import (
+	"errors"
+	"sshManager/internal/models"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// ErrAuthPromptCancelled oznacza, że użytkownik anulował odpowiedź na pytanie serwera
+var ErrAuthPromptCancelled = errors.New("authentication cancelled")
+
+// AuthChallenge to jedna runda pytań serwera w trybie keyboard-interactive
+// (np. kod jednorazowy 2FA/OTP)
+type AuthChallenge struct {
+	Host        string   // Nazwa hosta (docelowego lub pośredniczącego)
+	Name        string   // Nazwa rundy podana przez serwer (często pusta)
+	Instruction string   // Instrukcja serwera (często pusta)
+	Questions   []string // Treść kolejnych pytań, np. "Verification code: "
+	Echos       []bool   // Czy odpowiedź może być widoczna podczas wpisywania
+}
+
+// AuthPrompter zwraca odpowiedzi użytkownika na pytania serwera, w kolejności
+// pytań. Jest wywoływany osobno dla każdej rundy; błąd przerywa autoryzację.
+type AuthPrompter func(challenge AuthChallenge) ([]string, error)
+
+// SetAuthPrompter ustawia funkcję odpowiadającą na pytania keyboard-interactive.
+// Bez niej obsługiwane są tylko pytania o hasło zapisane dla hosta.
+func (s *SSHClient) SetAuthPrompter(prompt AuthPrompter) {
+	s.authPrompter = prompt
+}
+
+// newKeyboardInteractiveMethod przygotowuje autoryzację keyboard-interactive.
+// Pytania o hasło (np. z PAM) dostają kolejne hasła zapisane dla hosta, a
+// pozostałe pytania trafiają do prompt.
+func newKeyboardInteractiveMethod(host *models.Host, authData []byte, prompt AuthPrompter) ssh.AuthMethod {
+	passwords := authPasswords(host, authData)
+
+	return ssh.KeyboardInteractive(func(name, instruction string, questions []string, echos []bool) ([]string, error) {
+		// Runda bez pytań zawiera tylko komunikat serwera
+		if len(questions) == 0 {
+			return nil, nil
+		}
+
+		if len(passwords) > 0 && isPasswordQuestion(questions, echos) {
+			password := passwords[0]
+			passwords = passwords[1:]
+			return []string{string(password)}, nil
+		}
+
+		if prompt == nil {
+			return nil, errors.New("server asked for keyboard-interactive input (e.g. a one-time code), which is not available here")
+		}
+		answers, err := prompt(AuthChallenge{
+			Host:        host.Name,
+			Name:        name,
+			Instruction: instruction,
+			Questions:   questions,
+			Echos:       echos,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(answers) != len(questions) {
+			return nil, ErrAuthPromptCancelled
+		}
+		return answers, nil
+	})
+}
+
+// isPasswordQuestion sprawdza, czy runda to pojedyncze, ukryte pytanie o hasło
+func isPasswordQuestion(questions []string, echos []bool) bool {
+	return len(questions) == 1 && !echos[0] &&
+		strings.Contains(strings.ToLower(questions[0]), "password")
+}
+
+// authPasswords zwraca hasła zapisane dla hosta, w kolejności z jego listy metod
+func authPasswords(host *models.Host, authData []byte) [][]byte {
+	if !isAuthListData(host, authData) {
+		if host.PasswordID >= 0 {
+			return [][]byte{authData}
+		}
+		return nil
+	}
+
+	entries, err := parseAuthList(authData)
+	if err != nil {
+		return nil
+	}
+	var passwords [][]byte
+	for _, entry := range entries {
+		if !entry.Key {
+			passwords = append(passwords, entry.Data)
+		}
+	}
+	return passwords
+}
@@ -0,0 +1,121 @@
+// internal/ssh/keygen.go
+
+package ssh
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Obsługiwane typy generowanych kluczy
+const (
+	KeyTypeEd25519 = "ed25519"
+	KeyTypeRSA4096 = "rsa-4096"
+)
+
+// KeyTypes zwraca typy kluczy w kolejności wyświetlania w formularzu
+func KeyTypes() []string {
+	return []string{KeyTypeEd25519, KeyTypeRSA4096}
+}
+
+// GenerateKeyPair tworzy nową parę kluczy i zwraca klucz prywatny w formacie
+// OpenSSH (PEM) oraz klucz publiczny w formacie authorized_keys
+func GenerateKeyPair(keyType string, comment string) (string, string, error) {
+	var privateKey crypto.PrivateKey
+	var publicKey crypto.PublicKey
+
+	switch keyType {
+	case KeyTypeEd25519:
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to generate ed25519 key: %v", err)
+		}
+		privateKey, publicKey = priv, pub
+	case KeyTypeRSA4096:
+		priv, err := rsa.GenerateKey(rand.Reader, 4096)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to generate RSA key: %v", err)
+		}
+		privateKey, publicKey = priv, &priv.PublicKey
+	default:
+		return "", "", fmt.Errorf("unsupported key type: %s", keyType)
+	}
+
+	block, err := ssh.MarshalPrivateKey(privateKey, comment)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode private key: %v", err)
+	}
+
+	sshPublicKey, err := ssh.NewPublicKey(publicKey)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode public key: %v", err)
+	}
+
+	authorizedKey := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPublicKey)))
+	if comment != "" {
+		authorizedKey += " " + comment
+	}
+
+	return string(pem.EncodeToMemory(block)), authorizedKey, nil
+}
+
+// PublicKeyForKeyFile zwraca klucz publiczny (format authorized_keys) dla
+// klucza prywatnego z pliku; najpierw sprawdza obok plik .pub
+func PublicKeyForKeyFile(keyPath string) (string, error) {
+	if pubData, err := os.ReadFile(keyPath + ".pub"); err == nil {
+		if _, _, _, _, err := ssh.ParseAuthorizedKey(pubData); err == nil {
+			return strings.TrimSpace(string(pubData)), nil
+		}
+	}
+
+	keyData, err := os.ReadFile(keyPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read key file: %v", err)
+	}
+
+	signer, err := ssh.ParsePrivateKey(keyData)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse private key: %v", err)
+	}
+
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(signer.PublicKey()))), nil
+}
+
+// sameAuthorizedKey porównuje typ i treść dwóch wpisów authorized_keys,
+// ignorując komentarze
+func sameAuthorizedKey(a, b string) bool {
+	fieldsA := strings.Fields(a)
+	fieldsB := strings.Fields(b)
+	if len(fieldsA) < 2 || len(fieldsB) < 2 {
+		return false
+	}
+	return fieldsA[0] == fieldsB[0] && fieldsA[1] == fieldsB[1]
+}
+
+// ValidatePrivateKey sprawdza, czy dane są poprawnym kluczem prywatnym, który
+// da się użyć przy połączeniu. Klucze chronione hasłem są odrzucane, chyba że
+// allowEncrypted - aplikacja nie pyta o hasło klucza, więc taki klucz działa
+// tylko przez ssh-agenta.
+func ValidatePrivateKey(keyData []byte, allowEncrypted bool) error {
+	_, err := ssh.ParsePrivateKey(keyData)
+	if err == nil {
+		return nil
+	}
+	var missing *ssh.PassphraseMissingError
+	if errors.As(err, &missing) {
+		if allowEncrypted {
+			return nil
+		}
+		return errors.New("key is protected by a passphrase; add it to ssh-agent and enable \"Use ssh-agent\"")
+	}
+	return fmt.Errorf("invalid private key: %v", err)
+}
@@ -0,0 +1,157 @@
+// internal/ssh/known_hosts.go
+
+package ssh
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"sshManager/internal/models"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// KnownHost to wpis z pliku known_hosts aplikacji
+type KnownHost struct {
+	Hosts       []string // Wzorce hostów, np. "[10.0.0.1]:22" i "10.0.0.1"
+	KeyType     string   // Typ klucza, np. "ssh-ed25519"
+	Fingerprint string   // Odcisk SHA256 klucza
+	Marker      string   // "@cert-authority" lub "@revoked" (zwykle puste)
+	line        string   // Oryginalna linia, używana przy usuwaniu wpisu
+}
+
+// KnownHostsPath zwraca ścieżkę do pliku known_hosts aplikacji
+func KnownHostsPath() (string, error) {
+	return getAppKnownHostsPath()
+}
+
+// LoadKnownHosts wczytuje wpisy z pliku known_hosts aplikacji. Brak pliku
+// oznacza pustą listę; linie, których nie da się sparsować, są pomijane.
+func LoadKnownHosts() ([]KnownHost, error) {
+	knownHostsPath, err := getAppKnownHostsPath()
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(knownHostsPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read known_hosts: %v", err)
+	}
+
+	var entries []KnownHost
+	scanner := bufio.NewScanner(strings.NewReader(string(content)))
+	for scanner.Scan() {
+		lineText := scanner.Text()
+		trimmed := strings.TrimSpace(lineText)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		marker, hosts, publicKey, _, _, err := ssh.ParseKnownHosts([]byte(trimmed))
+		if err != nil {
+			continue
+		}
+		entries = append(entries, KnownHost{
+			Hosts:       hosts,
+			KeyType:     publicKey.Type(),
+			Fingerprint: ssh.FingerprintSHA256(publicKey),
+			Marker:      marker,
+			line:        lineText,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read known_hosts: %v", err)
+	}
+	return entries, nil
+}
+
+// checkHostKey sprawdza klucz hosta w known_hosts aplikacji. Dla znanego klucza
+// zwraca nil, a w przeciwnym razie dane do weryfikacji przez użytkownika;
+// Changed oznacza, że host ma zapisany inny klucz (możliwy atak MITM).
+func checkHostKey(host *models.Host, hostname string, remote net.Addr, key ssh.PublicKey) *HostKeyVerificationRequired {
+	var knownKeys []string
+	if knownHostsPath, err := getAppKnownHostsPath(); err == nil {
+		if callback, err := knownhosts.New(knownHostsPath); err == nil {
+			err = callback(hostname, remote, key)
+			if err == nil {
+				return nil
+			}
+
+			// KeyError z niepustym Want oznacza, że host ma zapisany inny klucz
+			var keyErr *knownhosts.KeyError
+			if errors.As(err, &keyErr) {
+				for _, known := range keyErr.Want {
+					knownKeys = append(knownKeys, ssh.FingerprintSHA256(known.Key))
+				}
+			}
+		}
+	}
+
+	return &HostKeyVerificationRequired{
+		IP:          host.IP,
+		Port:        host.Port,
+		Fingerprint: ssh.FingerprintSHA256(key),
+		PublicKey:   key,
+		RawKey:      key.Marshal(),
+		KeyType:     key.Type(),
+		Changed:     len(knownKeys) > 0,
+		KnownKeys:   knownKeys,
+	}
+}
+
+// Matches sprawdza, czy wpis dotyczy hosta o podanym adresie i porcie
+func (k KnownHost) Matches(ip, port string) bool {
+	for _, pattern := range k.Hosts {
+		if pattern == fmt.Sprintf("[%s]:%s", ip, port) || (port == "22" && pattern == ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveKnownHost usuwa wpis z pliku known_hosts aplikacji, przepisując plik
+// tak jak saveHostKey. Pozostałe linie (także komentarze) zostają bez zmian.
+func RemoveKnownHost(entry KnownHost) error {
+	knownHostsPath, err := getAppKnownHostsPath()
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(knownHostsPath)
+	if err != nil {
+		return fmt.Errorf("failed to read known_hosts: %v", err)
+	}
+
+	var finalLines []string
+	removed := false
+	scanner := bufio.NewScanner(strings.NewReader(string(content)))
+	for scanner.Scan() {
+		lineText := scanner.Text()
+		if !removed && lineText == entry.line {
+			removed = true
+			continue
+		}
+		finalLines = append(finalLines, lineText)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read known_hosts: %v", err)
+	}
+	if !removed {
+		return errors.New("entry no longer exists in known_hosts")
+	}
+
+	// Zapisz plik
+	var newContent string
+	if len(finalLines) > 0 {
+		newContent = strings.Join(finalLines, "\n") + "\n"
+	}
+	return os.WriteFile(knownHostsPath, []byte(newContent), 0600)
+}
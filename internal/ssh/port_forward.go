@@ -0,0 +1,248 @@
+// internal/ssh/port_forward.go
+
+package ssh
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// defaultBindAddress to adres nasłuchu, gdy specyfikacja go nie podaje (jak w OpenSSH)
+const defaultBindAddress = "localhost"
+
+// ForwardSpec opisuje pojedyncze przekierowanie portu w formacie
+// [bind_address:]port:host:hostport (tak jak -L/-R w OpenSSH)
+type ForwardSpec struct {
+	BindAddress string
+	BindPort    string
+	HostAddress string
+	HostPort    string
+}
+
+// ParseForwardSpec parsuje specyfikację przekierowania, np. "8080:localhost:80",
+// "127.0.0.1:8080:localhost:80" albo "8080:[::1]:80" (adresy IPv6 w nawiasach,
+// jak w OpenSSH)
+func ParseForwardSpec(spec string) (ForwardSpec, error) {
+	parts, err := splitForwardSpec(strings.TrimSpace(spec))
+	if err != nil {
+		return ForwardSpec{}, fmt.Errorf("invalid forward '%s': %v", spec, err)
+	}
+
+	var f ForwardSpec
+	switch len(parts) {
+	case 3:
+		f = ForwardSpec{
+			BindAddress: defaultBindAddress,
+			BindPort:    parts[0],
+			HostAddress: parts[1],
+			HostPort:    parts[2],
+		}
+	case 4:
+		f = ForwardSpec{
+			BindAddress: parts[0],
+			BindPort:    parts[1],
+			HostAddress: parts[2],
+			HostPort:    parts[3],
+		}
+	default:
+		return ForwardSpec{}, fmt.Errorf("invalid forward '%s': expected [bind_address:]port:host:hostport", spec)
+	}
+
+	if f.BindAddress == "" {
+		f.BindAddress = defaultBindAddress
+	}
+	if f.HostAddress == "" {
+		return ForwardSpec{}, fmt.Errorf("invalid forward '%s': missing target host", spec)
+	}
+	for _, port := range []string{f.BindPort, f.HostPort} {
+		if num, err := strconv.Atoi(port); err != nil || num < 1 || num > 65535 {
+			return ForwardSpec{}, fmt.Errorf("invalid forward '%s': port '%s' must be between 1 and 65535", spec, port)
+		}
+	}
+
+	return f, nil
+}
+
+// splitForwardSpec dzieli specyfikację na pola rozdzielone dwukropkami,
+// pomijając dwukropki w adresach w nawiasach kwadratowych; nawiasy są usuwane
+func splitForwardSpec(spec string) ([]string, error) {
+	var parts []string
+	for {
+		var field string
+		if strings.HasPrefix(spec, "[") {
+			end := strings.Index(spec, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("missing ']' in address")
+			}
+			field, spec = spec[1:end], spec[end+1:]
+			if spec != "" && !strings.HasPrefix(spec, ":") {
+				return nil, fmt.Errorf("expected ':' after ']'")
+			}
+		} else {
+			end := strings.Index(spec, ":")
+			if end < 0 {
+				end = len(spec)
+			}
+			field, spec = spec[:end], spec[end:]
+			if strings.ContainsAny(field, "[]") {
+				return nil, fmt.Errorf("unexpected bracket in '%s'", field)
+			}
+		}
+		parts = append(parts, field)
+		if spec == "" {
+			return parts, nil
+		}
+		spec = spec[1:] // Dwukropek rozdzielający pola
+	}
+}
+
+// ParseForwardSpecs parsuje listę specyfikacji, zwracając pierwszy napotkany błąd
+func ParseForwardSpecs(specs []string) ([]ForwardSpec, error) {
+	var result []ForwardSpec
+	for _, spec := range specs {
+		f, err := ParseForwardSpec(spec)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, f)
+	}
+	return result, nil
+}
+
+func (f ForwardSpec) bindAddr() string {
+	return net.JoinHostPort(f.BindAddress, f.BindPort)
+}
+
+func (f ForwardSpec) targetAddr() string {
+	return net.JoinHostPort(f.HostAddress, f.HostPort)
+}
+
+// String zwraca czytelny opis przekierowania, np. "8080 → localhost:80"
+func (f ForwardSpec) String() string {
+	if f.BindAddress == defaultBindAddress {
+		return fmt.Sprintf("%s → %s", f.BindPort, f.targetAddr())
+	}
+	return fmt.Sprintf("%s → %s", f.bindAddr(), f.targetAddr())
+}
+
+// portForward to aktywne przekierowanie: akceptuje połączenia na listenerze
+// i łączy każde z nich z celem za pomocą funkcji dial
+type portForward struct {
+	spec     ForwardSpec
+	remote   bool // true dla przekierowań zdalnych (-R)
+	listener net.Listener
+	dial     func() (net.Conn, error)
+	wg       sync.WaitGroup
+}
+
+// startLocalForward nasłuchuje lokalnie i tuneluje połączenia przez klienta SSH
+func startLocalForward(client *ssh.Client, spec ForwardSpec) (*portForward, error) {
+	listener, err := net.Listen("tcp", spec.bindAddr())
+	if err != nil {
+		return nil, fmt.Errorf("local forward %s: %v", spec, err)
+	}
+
+	pf := &portForward{
+		spec:     spec,
+		listener: listener,
+		dial: func() (net.Conn, error) {
+			return client.Dial("tcp", spec.targetAddr())
+		},
+	}
+	pf.wg.Add(1)
+	go pf.acceptLoop()
+	return pf, nil
+}
+
+// startRemoteForward nasłuchuje po stronie serwera SSH i przekazuje połączenia
+// do lokalnego celu
+func startRemoteForward(client *ssh.Client, spec ForwardSpec) (*portForward, error) {
+	listener, err := client.Listen("tcp", spec.bindAddr())
+	if err != nil {
+		return nil, fmt.Errorf("remote forward %s: %v", spec, err)
+	}
+
+	pf := &portForward{
+		spec:     spec,
+		remote:   true,
+		listener: listener,
+		dial: func() (net.Conn, error) {
+			return net.Dial("tcp", spec.targetAddr())
+		},
+	}
+	pf.wg.Add(1)
+	go pf.acceptLoop()
+	return pf, nil
+}
+
+// startLocalForwards uruchamia wszystkie lokalne przekierowania; przy błędzie
+// zamyka już otwarte listenery, aby nie zostawiać zajętych portów
+func startLocalForwards(client *ssh.Client, specs []ForwardSpec) ([]*portForward, error) {
+	var forwards []*portForward
+	for _, spec := range specs {
+		pf, err := startLocalForward(client, spec)
+		if err != nil {
+			for _, f := range forwards {
+				f.Close()
+			}
+			return nil, err
+		}
+		forwards = append(forwards, pf)
+	}
+	return forwards, nil
+}
+
+// acceptLoop obsługuje przychodzące połączenia aż do zamknięcia listenera
+func (pf *portForward) acceptLoop() {
+	defer pf.wg.Done()
+	for {
+		conn, err := pf.listener.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			target, err := pf.dial()
+			if err != nil {
+				conn.Close()
+				return
+			}
+			pipeConns(conn, target)
+		}()
+	}
+}
+
+// Close zatrzymuje nasłuch; otwarte połączenia kończą się wraz z klientem SSH
+func (pf *portForward) Close() error {
+	err := pf.listener.Close()
+	pf.wg.Wait()
+	return err
+}
+
+// pipeConns kopiuje dane w obu kierunkach i zamyka oba połączenia po zakończeniu
+func pipeConns(a, b net.Conn) {
+	var once sync.Once
+	closeBoth := func() {
+		a.Close()
+		b.Close()
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		io.Copy(a, b)
+		once.Do(closeBoth)
+	}()
+	go func() {
+		defer wg.Done()
+		io.Copy(b, a)
+		once.Do(closeBoth)
+	}()
+	wg.Wait()
+}
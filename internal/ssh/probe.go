@@ -0,0 +1,58 @@
+// internal/ssh/probe.go
+
+package ssh
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+	"time"
+)
+
+// ProbeTimeout to czas na rozwiązanie nazwy i połączenie TCP przy teście hosta
+const ProbeTimeout = 5 * time.Second
+
+// ProbeResult opisuje wynik testu osiągalności hosta
+type ProbeResult struct {
+	Addresses []string      // Adresy, na które rozwiązano nazwę (puste, gdy podano adres IP)
+	Latency   time.Duration // Czas nawiązania połączenia TCP
+	Banner    string        // Identyfikator serwera SSH, np. "SSH-2.0-OpenSSH_9.6" (pusty, gdy serwer go nie wysłał)
+}
+
+// ProbeHost sprawdza, czy nazwa hosta się rozwiązuje i czy port przyjmuje
+// połączenia TCP; nie loguje się, tylko odczytuje identyfikator serwera SSH
+func ProbeHost(host, port string) (ProbeResult, error) {
+	var result ProbeResult
+	ctx, cancel := context.WithTimeout(context.Background(), ProbeTimeout)
+	defer cancel()
+
+	if _, err := netip.ParseAddr(host); err != nil {
+		addresses, err := net.DefaultResolver.LookupHost(ctx, host)
+		if err != nil {
+			return result, fmt.Errorf("cannot resolve %s: %v", host, err)
+		}
+		result.Addresses = addresses
+	}
+
+	address := net.JoinHostPort(host, port)
+	start := time.Now()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return result, fmt.Errorf("%s is unreachable: %v", address, err)
+	}
+	defer conn.Close()
+	result.Latency = time.Since(start)
+
+	// Serwer SSH przedstawia się pierwszą linią; jej brak nie jest błędem,
+	// bo port może być osiągalny, tylko obsługiwany przez inną usługę
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	line, _ := bufio.NewReaderSize(conn, 256).ReadString('\n')
+	if line = strings.TrimSpace(line); strings.HasPrefix(line, "SSH-") {
+		result.Banner = line
+	}
+	return result, nil
+}
@@ -0,0 +1,81 @@
+// internal/ssh/ratelimit.go
+
+package ssh
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// rateLimiter ogranicza prędkość transferów metodą kubełka z żetonami. Jeden
+// limiter jest wspólny dla wszystkich czytników połączenia, więc limit dotyczy
+// łącznej prędkości, także przy wysyłaniu i pobieraniu jednocześnie.
+type rateLimiter struct {
+	mutex  sync.Mutex
+	rate   int64   // Bajtów na sekundę, 0 oznacza brak limitu
+	tokens float64 // Dostępne bajty; ujemne to dług do odczekania
+	last   time.Time
+}
+
+// setRate ustawia limit w bajtach na sekundę (0 wyłącza limit)
+func (l *rateLimiter) setRate(bytesPerSecond int64) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.rate = max(bytesPerSecond, 0)
+	l.tokens = 0
+	l.last = time.Now()
+}
+
+// getRate zwraca limit w bajtach na sekundę
+func (l *rateLimiter) getRate() int64 {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.rate
+}
+
+// chunk ogranicza wielkość pojedynczego odczytu, aby przy małym limicie
+// transfer płynął równo zamiast skokami po 32 KB
+func (l *rateLimiter) chunk(size int) int {
+	rate := l.getRate()
+	if rate == 0 {
+		return size
+	}
+	return int(min(int64(size), max(rate/10, 512)))
+}
+
+// wait pobiera n bajtów z kubełka i czeka, jeśli limit został przekroczony;
+// anulowanie ctx przerywa czekanie
+func (l *rateLimiter) wait(ctx context.Context, n int) error {
+	l.mutex.Lock()
+	if l.rate == 0 {
+		l.mutex.Unlock()
+		return nil
+	}
+
+	// Uzupełniamy żetony za czas od ostatniego odczytu, najwyżej na sekundę z góry
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * float64(l.rate)
+	if l.tokens > float64(l.rate) {
+		l.tokens = float64(l.rate)
+	}
+	l.last = now
+	l.tokens -= float64(n)
+	delay := time.Duration(-l.tokens / float64(l.rate) * float64(time.Second))
+	l.mutex.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,89 @@
+// internal/ssh/reconnect.go
+
+package ssh
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"sshManager/internal/crypto"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// ErrConnectionLost oznacza, że sesja skończyła się bez statusu wyjścia powłoki,
+// czyli połączenie zostało zerwane (sieć, nieudany keepalive), a nie zamknięte
+// przez użytkownika
+var ErrConnectionLost = errors.New("connection lost")
+
+// keepAliveMaxMissed to liczba odstępów keepalive bez odpowiedzi serwera, po
+// której połączenie uznajemy za zerwane (jak ServerAliveCountMax w OpenSSH)
+const keepAliveMaxMissed = 3
+
+// sendKeepAlive wysyła pakiet keepalive i czeka na odpowiedź. Przy zerwanej
+// sieci serwer może nie odpowiedzieć wcale, a żądanie nie zwróci błędu, więc
+// brak odpowiedzi przez keepAliveMaxMissed odstępów też jest błędem.
+func (s *SSHSession) sendKeepAlive(client *ssh.Client) error {
+	reply := make(chan error, 1)
+	go func() {
+		_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+		reply <- err
+	}()
+
+	timeout := keepAliveMaxMissed * s.keepAlive
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-reply:
+		return err
+	case <-timer.C:
+		return fmt.Errorf("no reply from server for %v", timeout)
+	case <-s.stopChan:
+		return nil
+	}
+}
+
+// sessionEndError klasyfikuje błąd zwrócony przez Wait. Wyjście z powłoki (logout)
+// i przerwanie sesji sygnałem nie są błędami; brak statusu wyjścia to ErrConnectionLost.
+func sessionEndError(err error, interrupted bool) error {
+	if err == nil || interrupted {
+		return nil
+	}
+
+	var exitErr *ssh.ExitError
+	if !errors.As(err, &exitErr) {
+		return fmt.Errorf("%w: %v", ErrConnectionLost, err)
+	}
+
+	errStr := err.Error()
+	if errStr != "Process exited with status 1" &&
+		!strings.Contains(errStr, "exit status") &&
+		!strings.Contains(errStr, "signal: terminated") &&
+		!strings.Contains(errStr, "signal: interrupt") {
+		return fmt.Errorf("session ended with error: %v", err)
+	}
+	return nil
+}
+
+// Reconnect zamyka zerwane połączenie i nawiązuje je ponownie z hostem i danymi
+// autoryzacji ostatniego udanego połączenia; nowa sesja jest dostępna przez
+// Session(). Po nieudanej próbie można wywołać Reconnect ponownie.
+func (s *SSHClient) Reconnect() error {
+	host, authData := s.lastHost, s.authData
+	if host == nil {
+		return errors.New("no connection to restore")
+	}
+
+	// Disconnect czyści dane autoryzacji, więc przejmujemy je na czas próby
+	s.authData = nil
+	s.Disconnect()
+	if err := s.Connect(host, authData); err != nil {
+		s.authData = authData
+		return err
+	}
+	crypto.Wipe(authData)
+	return nil
+}
@@ -0,0 +1,178 @@
+// internal/ssh/remote_copy.go
+
+package ssh
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"sshManager/internal/utils"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// cpNotFound to kod wyjścia powłoki, gdy polecenie nie istnieje
+const cpNotFound = 127
+
+// CopyRemote kopiuje plik lub katalog do innej ścieżki na tym samym serwerze.
+// Kopia jest robiona po stronie serwera poleceniem cp; gdy serwer nie pozwala
+// uruchamiać poleceń (np. konta tylko z SFTP), dane są przesyłane przez SFTP
+// (tam i z powrotem, z postępem w progressChan). Jeśli dstPath jest istniejącym
+// katalogiem, kopia trafia do niego pod nazwą źródła. Istniejących plików nie
+// nadpisujemy. Zwraca ścieżkę utworzonej kopii.
+func (ft *FileTransfer) CopyRemote(ctx context.Context, srcPath, dstPath string, progressChan chan<- TransferProgress) (string, error) {
+	ft.mutex.Lock()
+	defer ft.mutex.Unlock()
+
+	if !ft.connected {
+		return "", fmt.Errorf("not connected")
+	}
+
+	srcPath = path.Clean(utils.ToSFTPPath(srcPath))
+	dstPath = path.Clean(utils.ToSFTPPath(dstPath))
+
+	stat, lstat := ft.shellStat, ft.shellLstat
+	if ft.sftpClient != nil {
+		stat, lstat = ft.sftpClient.Stat, ft.sftpClient.Lstat
+	}
+
+	srcInfo, err := stat(srcPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat %s: %v", srcPath, err)
+	}
+	if dstInfo, err := stat(dstPath); err == nil {
+		if !dstInfo.IsDir() {
+			return "", fmt.Errorf("%s already exists", dstPath)
+		}
+		dstPath = path.Join(dstPath, path.Base(srcPath))
+		if _, err := lstat(dstPath); err == nil {
+			return "", fmt.Errorf("%s already exists", dstPath)
+		}
+	}
+	if dstPath == srcPath || (srcInfo.IsDir() && strings.HasPrefix(dstPath, srcPath+"/")) {
+		return "", fmt.Errorf("cannot copy %s into itself", srcPath)
+	}
+
+	var stderr bytes.Buffer
+	command := "cp -Rp -- " + shellQuote(srcPath) + " " + shellQuote(dstPath)
+	code, err := runCommandContext(ctx, ft.sshClient, command, &stderr)
+	switch {
+	case ctx.Err() != nil:
+		return dstPath, ctx.Err()
+	case err == nil && code == 0:
+		return dstPath, nil
+	case err == nil && code != cpNotFound:
+		return dstPath, fmt.Errorf("cp failed: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	// Brak dostępu do poleceń - kopiujemy przez SFTP
+	if ft.sftpClient == nil {
+		return dstPath, fmt.Errorf("cp is not available on the server and SFTP is disabled")
+	}
+	return dstPath, ft.copyRemoteSFTP(ctx, srcPath, dstPath, srcInfo, progressChan)
+}
+
+// copyRemoteSFTP kopiuje plik lub katalog przez SFTP (wywoływane z blokadą ft.mutex)
+func (ft *FileTransfer) copyRemoteSFTP(ctx context.Context, srcPath, dstPath string, info os.FileInfo, progressChan chan<- TransferProgress) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		target, err := ft.sftpClient.ReadLink(srcPath)
+		if err != nil {
+			return fmt.Errorf("failed to read link %s: %v", srcPath, err)
+		}
+		return ft.sftpClient.Symlink(target, dstPath)
+
+	case info.IsDir():
+		if err := ft.sftpClient.Mkdir(dstPath); err != nil {
+			return fmt.Errorf("failed to create directory %s: %v", dstPath, err)
+		}
+		entries, err := ft.sftpClient.ReadDir(srcPath)
+		if err != nil {
+			return fmt.Errorf("failed to list %s: %v", srcPath, err)
+		}
+		for _, entry := range entries {
+			if entry.Name() == "." || entry.Name() == ".." {
+				continue
+			}
+			if err := ft.copyRemoteSFTP(ctx, path.Join(srcPath, entry.Name()), path.Join(dstPath, entry.Name()), entry, progressChan); err != nil {
+				return err
+			}
+		}
+		return ft.sftpClient.Chmod(dstPath, info.Mode().Perm())
+	}
+
+	src, err := ft.sftpClient.Open(srcPath)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %v", srcPath, err)
+	}
+	defer src.Close()
+
+	dst, err := ft.sftpClient.OpenFile(dstPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %v", dstPath, err)
+	}
+	defer dst.Close()
+
+	reader := &ProgressReader{
+		Reader:    src,
+		Total:     info.Size(),
+		FileName:  path.Base(srcPath),
+		StartTime: time.Now(),
+		Progress:  progressChan,
+		Ctx:       ctx,
+		limiter:   &ft.limiter,
+	}
+	if _, err := io.Copy(dst, reader); err != nil {
+		return fmt.Errorf("error while copying %s: %v", srcPath, err)
+	}
+	return ft.sftpClient.Chmod(dstPath, info.Mode().Perm())
+}
+
+// runCommandContext działa jak runCommand, ale anulowanie ctx przerywa polecenie
+func runCommandContext(ctx context.Context, client *ssh.Client, command string, stderr io.Writer) (int, error) {
+	session, err := client.NewSession()
+	if err != nil {
+		return -1, fmt.Errorf("failed to create SSH session: %v", err)
+	}
+	defer session.Close()
+
+	session.Stderr = stderr
+	if err := session.Start(command); err != nil {
+		return -1, fmt.Errorf("failed to execute command: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- session.Wait() }()
+
+	var exitErr *ssh.ExitError
+	select {
+	case <-ctx.Done():
+		session.Signal(ssh.SIGKILL)
+		return -1, ctx.Err()
+	case err := <-done:
+		switch {
+		case err == nil:
+			return 0, nil
+		case errors.As(err, &exitErr):
+			return exitErr.ExitStatus(), nil
+		default:
+			return -1, fmt.Errorf("failed to execute command: %v", err)
+		}
+	}
+}
+
+// shellQuote ujmuje tekst w apostrofy dla powłoki POSIX
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -6,6 +6,7 @@ package ssh
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"strings"
@@ -40,7 +41,14 @@ type SSHSession struct {
 	termHeight        int
 	keepAlive         time.Duration
 	stopChan          chan struct{}
+	stopOnce          sync.Once // Zamyka stopChan tylko raz (Close i koniec StartShell)
 	stateMutex        sync.RWMutex
+	onShellStarted    func()            // Wywoływana po uruchomieniu powłoki
+	initCommands      []string          // Polecenia wpisywane do powłoki zaraz po jej uruchomieniu
+	env               map[string]string // Zmienne środowiskowe wysyłane przed uruchomieniem powłoki
+	log               io.Writer         // Log sesji (opcjonalny), dostaje kopię wyjścia
+	interrupted       bool              // Sesja zamknięta sygnałem, a nie przez zerwanie połączenia
+	transferRequested bool              // Powłoka zamknięta klawiszem TransferKey; połączenie zostaje otwarte
 	originalTermState *term.State
 }
 
@@ -98,10 +106,22 @@ func (s *SSHSession) ConfigureTerminal(termType string) error {
 }
 
 func (s *SSHSession) StartShell() error {
-	// Konfiguracja strumieni we/wy
-	s.session.Stdin = s.stdin
-	s.session.Stdout = s.stdout
-	s.session.Stderr = s.stderr
+	// Close (np. po nieudanym keepalive) zeruje s.session, więc powłoka
+	// korzysta z własnej kopii
+	session := s.session
+
+	// Konfiguracja strumieni we/wy; polecenia startowe trafiają na wejście
+	// powłoki przed tym, co wpisze użytkownik
+	session.Stdin = s.shellInput()
+	if s.log != nil {
+		session.Stdout = io.MultiWriter(s.stdout, s.log)
+	} else {
+		session.Stdout = s.stdout
+	}
+	session.Stderr = s.stderr
+
+	// Zmienne środowiskowe hosta (serwer może część z nich odrzucić)
+	sendEnv(session, s.env, s.stderr)
 
 	// Zapisujemy oryginalny stan terminala
 	var err error
@@ -115,7 +135,7 @@ func (s *SSHSession) StartShell() error {
 
 	// Uruchomienie keepalive jeśli włączone
 	if s.keepAlive > 0 {
-		go s.keepAliveLoop()
+		go s.keepAliveLoop(s.client)
 	}
 
 	// Przejście w tryb raw dla terminala
@@ -126,7 +146,7 @@ func (s *SSHSession) StartShell() error {
 
 	cleanup := func() {
 		// Zatrzymujemy keepalive i sygnały
-		close(s.stopChan)
+		s.stop()
 
 		// Resetujemy stan sesji
 		s.setState(StateDisconnected)
@@ -142,27 +162,23 @@ func (s *SSHSession) StartShell() error {
 	defer cleanup()
 
 	// Uruchomienie powłoki
-	if err := s.session.Shell(); err != nil {
+	if err := session.Shell(); err != nil {
 		return fmt.Errorf("failed to start shell: %v", err)
 	}
 
 	s.setState(StateConnected)
 
-	// Czekanie na zakończenie sesji
-	if err := s.session.Wait(); err != nil {
-		errStr := err.Error()
-		if errStr != "Process exited with status 1" &&
-			!strings.Contains(errStr, "exit status") &&
-			!strings.Contains(errStr, "signal: terminated") &&
-			!strings.Contains(errStr, "signal: interrupt") {
-			return fmt.Errorf("session ended with error: %v", err)
-		}
+	if s.onShellStarted != nil {
+		s.onShellStarted()
 	}
 
+	// Czekanie na zakończenie sesji
+	err = s.shellEndError(session.Wait())
+
 	// Dodatkowe opóźnienie przed zakończeniem
 	time.Sleep(100 * time.Millisecond)
 
-	return nil
+	return err
 }
 
 // handleSignals obsługuje sygnały systemowe
@@ -181,6 +197,7 @@ func (s *SSHSession) handleSignals() {
 					s.setError(fmt.Errorf("failed to update terminal size: %v", err))
 				}
 			case syscall.SIGTERM, syscall.SIGINT:
+				s.markInterrupted()
 				s.Close()
 				return
 			}
@@ -215,15 +232,14 @@ func (s *SSHSession) updateTerminalSize() error {
 }
 
 // keepAliveLoop wysyła pakiety keepalive
-func (s *SSHSession) keepAliveLoop() {
+func (s *SSHSession) keepAliveLoop(client *ssh.Client) {
 	ticker := time.NewTicker(s.keepAlive)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ticker.C:
-			_, _, err := s.client.SendRequest("keepalive@openssh.com", true, nil)
-			if err != nil {
+			if err := s.sendKeepAlive(client); err != nil {
 				s.setError(fmt.Errorf("keepalive failed: %v", err))
 				s.Close()
 				return
@@ -237,12 +253,7 @@ func (s *SSHSession) keepAliveLoop() {
 // Close zamyka sesję
 func (s *SSHSession) Close() error {
 	// Zamknięcie kanału stopChan
-	select {
-	case <-s.stopChan:
-		// Kanał już zamknięty
-	default:
-		close(s.stopChan)
-	}
+	s.stop()
 
 	var errors []string
 
@@ -275,6 +286,21 @@ func (s *SSHSession) setState(state SessionState) {
 	s.state = state
 }
 
+// markInterrupted zapamiętuje, że sesja jest zamykana na żądanie (sygnał),
+// więc jej zakończenie nie jest traktowane jako zerwane połączenie
+func (s *SSHSession) markInterrupted() {
+	s.stateMutex.Lock()
+	defer s.stateMutex.Unlock()
+	s.interrupted = true
+}
+
+// wasInterrupted sprawdza, czy sesja została zamknięta na żądanie
+func (s *SSHSession) wasInterrupted() bool {
+	s.stateMutex.RLock()
+	defer s.stateMutex.RUnlock()
+	return s.interrupted
+}
+
 // setError ustawia błąd sesji
 func (s *SSHSession) setError(err error) {
 	s.stateMutex.Lock()
@@ -283,6 +309,35 @@ func (s *SSHSession) setError(err error) {
 	s.state = StateError
 }
 
+// SetKeepAlive ustawia odstęp między pakietami keepalive (0 wyłącza keepalive);
+// musi być wywołane przed StartShell
+func (s *SSHSession) SetKeepAlive(interval time.Duration) {
+	s.keepAlive = interval
+}
+
+// SetShellStartedHook ustawia funkcję wywoływaną po uruchomieniu powłoki
+func (s *SSHSession) SetShellStartedHook(fn func()) {
+	s.onShellStarted = fn
+}
+
+// SetLog ustawia writer, do którego trafia kopia wyjścia sesji (log sesji);
+// musi być wywołane przed StartShell
+func (s *SSHSession) SetLog(w io.Writer) {
+	s.log = w
+}
+
+// SetInitCommands ustawia polecenia wykonywane w powłoce zaraz po jej uruchomieniu;
+// musi być wywołane przed StartShell
+func (s *SSHSession) SetInitCommands(commands []string) {
+	s.initCommands = commands
+}
+
+// SetEnv ustawia zmienne środowiskowe wysyłane do serwera przed uruchomieniem
+// powłoki; musi być wywołane przed StartShell
+func (s *SSHSession) SetEnv(env map[string]string) {
+	s.env = env
+}
+
 // GetState zwraca aktualny stan sesji
 func (s *SSHSession) GetState() SessionState {
 	s.stateMutex.RLock()
@@ -0,0 +1,133 @@
+// internal/ssh/session_log.go
+
+package ssh
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"sshManager/internal/config"
+	"sshManager/internal/models"
+)
+
+// SessionLogDir to katalog (w katalogu konfiguracji) z logami sesji
+const SessionLogDir = "logs"
+
+// OpenSessionLog tworzy plik logu sesji z hostem: <config>/logs/<host>-<czas>.log.
+// Plik zaczyna się nagłówkiem z nazwą hosta i czasem rozpoczęcia.
+func OpenSessionLog(host *models.Host) (*os.File, error) {
+	configPath, err := config.GetDefaultConfigPath()
+	if err != nil {
+		return nil, err
+	}
+
+	logDir := filepath.Join(filepath.Dir(configPath), SessionLogDir)
+	if err := os.MkdirAll(logDir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %v", err)
+	}
+
+	now := time.Now()
+	name := fmt.Sprintf("%s-%s.log", sanitizeLogName(host.Name), now.Format("20060102-150405"))
+	file, err := os.OpenFile(filepath.Join(logDir, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session log: %v", err)
+	}
+
+	fmt.Fprintf(file, "# sshManager session log: %s (%s@%s), started %s\n",
+		host.Name, host.Login, net.JoinHostPort(host.IP, host.Port), now.Format(time.RFC3339))
+	return file, nil
+}
+
+// sanitizeLogName zamienia znaki niedozwolone w nazwach plików na "_"
+func sanitizeLogName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
+
+// Stany parsera sekwencji sterujących
+const (
+	escNone    = iota // Zwykły tekst
+	escStart          // Po znaku ESC
+	escCSI            // Wewnątrz ESC [ ... (kończy się bajtem 0x40-0x7E)
+	escString         // Wewnątrz OSC/DCS (kończy się BEL lub ESC \)
+	escStringE        // ESC wewnątrz OSC/DCS
+	escCharset        // ESC ( / ESC ) - jeszcze jeden bajt do pominięcia
+)
+
+// escapeStripper przepuszcza do logu tylko czytelny tekst: usuwa sekwencje
+// sterujące terminala (kolory, ruch kursora, tytuły okna) i znaki CR/BS/BEL.
+// Stan jest pamiętany między zapisami, bo sekwencja może zostać podzielona.
+type escapeStripper struct {
+	w     io.Writer
+	state int
+}
+
+// NewSessionLogWriter zwraca writer zapisujący wyjście sesji do logu;
+// bez keepEscapes sekwencje sterujące są usuwane
+func NewSessionLogWriter(w io.Writer, keepEscapes bool) io.Writer {
+	if keepEscapes {
+		return w
+	}
+	return &escapeStripper{w: w}
+}
+
+func (e *escapeStripper) Write(p []byte) (int, error) {
+	out := make([]byte, 0, len(p))
+	for _, b := range p {
+		switch e.state {
+		case escNone:
+			switch {
+			case b == 0x1b:
+				e.state = escStart
+			case b == '\n' || b == '\t' || (b >= 0x20 && b != 0x7f):
+				out = append(out, b)
+			}
+		case escStart:
+			switch b {
+			case '[':
+				e.state = escCSI
+			case ']', 'P', 'X', '^', '_':
+				e.state = escString
+			case '(', ')', '*', '+':
+				e.state = escCharset
+			default:
+				e.state = escNone
+			}
+		case escCSI:
+			if b >= 0x40 && b <= 0x7e {
+				e.state = escNone
+			}
+		case escString:
+			switch b {
+			case 0x07:
+				e.state = escNone
+			case 0x1b:
+				e.state = escStringE
+			}
+		case escStringE:
+			if b == '\\' {
+				e.state = escNone
+			} else {
+				e.state = escString
+			}
+		case escCharset:
+			e.state = escNone
+		}
+	}
+
+	if _, err := e.w.Write(out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,117 @@
+// internal/ssh/session_transfer.go
+
+package ssh
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+
+	"sshManager/internal/models"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// TransferKey to klawisz (Ctrl+]), który w trakcie sesji zamyka powłokę
+// i otwiera tryb transferu plików na tym samym połączeniu
+const TransferKey byte = 0x1d
+
+// TransferKeyName to opis TransferKey wyświetlany użytkownikowi
+const TransferKeyName = "Ctrl+]"
+
+// ErrTransferRequested zwraca StartShell, gdy użytkownik nacisnął TransferKey;
+// połączenie pozostaje otwarte i można je przekazać do FileTransfer.ConnectUsing
+var ErrTransferRequested = errors.New("file transfer requested")
+
+// transferKeyReader przepuszcza wejście powłoki do momentu naciśnięcia
+// TransferKey; potem zgłasza koniec danych i nie czyta już z terminala,
+// aby kolejne klawisze trafiły do interfejsu, a nie do zamkniętej sesji
+type transferKeyReader struct {
+	r       io.Reader
+	onPress func()
+	once    sync.Once
+	pressed bool
+}
+
+func (t *transferKeyReader) Read(p []byte) (int, error) {
+	if t.pressed {
+		return 0, io.EOF
+	}
+	n, err := t.r.Read(p)
+	if i := bytes.IndexByte(p[:n], TransferKey); i >= 0 {
+		t.pressed = true
+		t.once.Do(t.onPress)
+		return i, nil
+	}
+	return n, err
+}
+
+// shellInput buduje wejście powłoki: polecenia startowe, a po nich klawiatura
+// z obsługą TransferKey
+func (s *SSHSession) shellInput() io.Reader {
+	session := s.session
+	var stdin io.Reader = &transferKeyReader{
+		r: s.stdin,
+		onPress: func() {
+			s.stateMutex.Lock()
+			s.transferRequested = true
+			s.stateMutex.Unlock()
+			session.Close()
+		},
+	}
+	if input := initCommandsInput(s.initCommands); input != "" {
+		stdin = io.MultiReader(strings.NewReader(input), stdin)
+	}
+	return stdin
+}
+
+// stop zatrzymuje keepalive i obsługę sygnałów. Sesję zamykają zarówno
+// StartShell po zakończeniu powłoki, jak i Close (np. po nieudanym keepalive),
+// więc kanał jest zamykany tylko raz.
+func (s *SSHSession) stop() {
+	s.stopOnce.Do(func() { close(s.stopChan) })
+}
+
+// shellEndError klasyfikuje zakończenie powłoki, uwzględniając TransferKey
+func (s *SSHSession) shellEndError(err error) error {
+	s.stateMutex.RLock()
+	requested := s.transferRequested
+	s.stateMutex.RUnlock()
+	if requested {
+		return ErrTransferRequested
+	}
+	return sessionEndError(err, s.wasInterrupted())
+}
+
+// releaseClient zamyka sesję, ale pozostawia otwarte połączenie i je zwraca
+func (s *SSHSession) releaseClient() *ssh.Client {
+	client := s.client
+	s.client = nil
+	s.Close()
+	return client
+}
+
+// handOver oddaje używane połączenie z hostem nowemu właścicielowi; sesja
+// powłoki i przekierowania portów są zamykane, a klient zostaje rozłączony,
+// tak jak po Disconnect
+func (s *SSHClient) handOver() (*models.Host, *sharedConn, error) {
+	if s.shared == nil || s.currentHost == nil {
+		return nil, nil, errors.New("no open connection to hand over")
+	}
+
+	s.closeForwards()
+	if s.session != nil {
+		s.session.releaseClient()
+		s.session = nil
+	}
+
+	host, shared := s.currentHost, s.shared
+	s.shared = nil
+	s.client = nil
+	s.jumpClient = nil
+	s.currentHost = nil
+	s.forgetAuthData()
+	return host, shared, nil
+}
@@ -5,6 +5,7 @@ package ssh
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"strings"
@@ -12,6 +13,8 @@ import (
 	"syscall"
 	"time"
 
+	"sshManager/internal/models"
+
 	"github.com/containerd/console"
 	"golang.org/x/crypto/ssh"
 )
@@ -26,19 +29,26 @@ const (
 )
 
 type SSHSession struct {
-	client     *ssh.Client
-	session    *ssh.Session
-	state      SessionState
-	lastError  error
-	stdin      *os.File
-	stdout     *os.File
-	stderr     *os.File
-	termWidth  int
-	termHeight int
-	keepAlive  time.Duration
-	stopChan   chan struct{}
-	stateMutex sync.RWMutex
-	winConsole console.Console
+	client            *ssh.Client
+	session           *ssh.Session
+	state             SessionState
+	lastError         error
+	stdin             *os.File
+	stdout            *os.File
+	stderr            *os.File
+	termWidth         int
+	termHeight        int
+	keepAlive         time.Duration
+	stopChan          chan struct{}
+	stopOnce          sync.Once // Zamyka stopChan tylko raz (Close i koniec StartShell)
+	stateMutex        sync.RWMutex
+	onShellStarted    func()            // Wywoływana po uruchomieniu powłoki
+	initCommands      []string          // Polecenia wpisywane do powłoki zaraz po jej uruchomieniu
+	env               map[string]string // Zmienne środowiskowe wysyłane przed uruchomieniem powłoki
+	log               io.Writer         // Log sesji (opcjonalny), dostaje kopię wyjścia
+	interrupted       bool              // Sesja zamknięta sygnałem, a nie przez zerwanie połączenia
+	transferRequested bool              // Powłoka zamknięta klawiszem TransferKey; połączenie zostaje otwarte
+	winConsole        console.Console
 }
 
 func NewSSHSession(client *ssh.Client) (*SSHSession, error) {
@@ -97,7 +107,7 @@ func (s *SSHSession) ConfigureTerminal(termType string) error {
 	}
 
 	if termType == "" {
-		termType = "xterm-256color"
+		termType = models.DefaultTerminalType
 	}
 
 	if err := s.session.RequestPty(termType, s.termHeight, s.termWidth, modes); err != nil {
@@ -108,9 +118,21 @@ func (s *SSHSession) ConfigureTerminal(termType string) error {
 }
 
 func (s *SSHSession) StartShell() error {
-	s.session.Stdin = s.stdin
-	s.session.Stdout = s.stdout
-	s.session.Stderr = s.stderr
+	// Close (np. po nieudanym keepalive) zeruje s.session, więc powłoka
+	// korzysta z własnej kopii
+	session := s.session
+
+	// Polecenia startowe trafiają na wejście powłoki przed tym, co wpisze użytkownik
+	session.Stdin = s.shellInput()
+	if s.log != nil {
+		session.Stdout = io.MultiWriter(s.stdout, s.log)
+	} else {
+		session.Stdout = s.stdout
+	}
+	session.Stderr = s.stderr
+
+	// Zmienne środowiskowe hosta (serwer może część z nich odrzucić)
+	sendEnv(session, s.env, s.stderr)
 
 	// Zachowaj oryginalny stan konsoli
 	if err := s.winConsole.SetRaw(); err != nil {
@@ -120,11 +142,11 @@ func (s *SSHSession) StartShell() error {
 	go s.handleSignals()
 
 	if s.keepAlive > 0 {
-		go s.keepAliveLoop()
+		go s.keepAliveLoop(s.client)
 	}
 
 	cleanup := func() {
-		close(s.stopChan)
+		s.stop()
 		s.setState(StateDisconnected)
 
 		// Przywróć oryginalny stan konsoli
@@ -138,23 +160,17 @@ func (s *SSHSession) StartShell() error {
 	}
 	defer cleanup()
 
-	if err := s.session.Shell(); err != nil {
+	if err := session.Shell(); err != nil {
 		return fmt.Errorf("failed to start shell: %w", err)
 	}
 
 	s.setState(StateConnected)
 
-	if err := s.session.Wait(); err != nil {
-		errStr := err.Error()
-		if errStr != "Process exited with status 1" &&
-			!strings.Contains(errStr, "exit status") &&
-			!strings.Contains(errStr, "signal: terminated") &&
-			!strings.Contains(errStr, "signal: interrupt") {
-			return fmt.Errorf("session ended with error: %w", err)
-		}
+	if s.onShellStarted != nil {
+		s.onShellStarted()
 	}
 
-	return nil
+	return s.shellEndError(session.Wait())
 }
 
 func (s *SSHSession) handleSignals() {
@@ -197,6 +213,7 @@ func (s *SSHSession) handleSignals() {
 		select {
 		case sig := <-sigChan:
 			if sig == syscall.SIGTERM || sig == syscall.SIGINT {
+				s.markInterrupted()
 				s.Close()
 				return
 			}
@@ -206,15 +223,14 @@ func (s *SSHSession) handleSignals() {
 	}
 }
 
-func (s *SSHSession) keepAliveLoop() {
+func (s *SSHSession) keepAliveLoop(client *ssh.Client) {
 	ticker := time.NewTicker(s.keepAlive)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ticker.C:
-			_, _, err := s.client.SendRequest("keepalive@openssh.com", true, nil)
-			if err != nil {
+			if err := s.sendKeepAlive(client); err != nil {
 				s.setError(fmt.Errorf("keepalive failed: %w", err))
 				s.Close()
 				return
@@ -226,11 +242,7 @@ func (s *SSHSession) keepAliveLoop() {
 }
 
 func (s *SSHSession) Close() error {
-	select {
-	case <-s.stopChan:
-	default:
-		close(s.stopChan)
-	}
+	s.stop()
 
 	var errors []string
 
@@ -269,6 +281,21 @@ func (s *SSHSession) setState(state SessionState) {
 	s.state = state
 }
 
+// markInterrupted zapamiętuje, że sesja jest zamykana na żądanie (sygnał),
+// więc jej zakończenie nie jest traktowane jako zerwane połączenie
+func (s *SSHSession) markInterrupted() {
+	s.stateMutex.Lock()
+	defer s.stateMutex.Unlock()
+	s.interrupted = true
+}
+
+// wasInterrupted sprawdza, czy sesja została zamknięta na żądanie
+func (s *SSHSession) wasInterrupted() bool {
+	s.stateMutex.RLock()
+	defer s.stateMutex.RUnlock()
+	return s.interrupted
+}
+
 func (s *SSHSession) setError(err error) {
 	s.stateMutex.Lock()
 	defer s.stateMutex.Unlock()
@@ -276,6 +303,35 @@ func (s *SSHSession) setError(err error) {
 	s.state = StateError
 }
 
+// SetKeepAlive ustawia odstęp między pakietami keepalive (0 wyłącza keepalive);
+// musi być wywołane przed StartShell
+func (s *SSHSession) SetKeepAlive(interval time.Duration) {
+	s.keepAlive = interval
+}
+
+// SetShellStartedHook ustawia funkcję wywoływaną po uruchomieniu powłoki
+func (s *SSHSession) SetShellStartedHook(fn func()) {
+	s.onShellStarted = fn
+}
+
+// SetLog ustawia writer, do którego trafia kopia wyjścia sesji (log sesji);
+// musi być wywołane przed StartShell
+func (s *SSHSession) SetLog(w io.Writer) {
+	s.log = w
+}
+
+// SetInitCommands ustawia polecenia wykonywane w powłoce zaraz po jej uruchomieniu;
+// musi być wywołane przed StartShell
+func (s *SSHSession) SetInitCommands(commands []string) {
+	s.initCommands = commands
+}
+
+// SetEnv ustawia zmienne środowiskowe wysyłane do serwera przed uruchomieniem
+// powłoki; musi być wywołane przed StartShell
+func (s *SSHSession) SetEnv(env map[string]string) {
+	s.env = env
+}
+
 func (s *SSHSession) GetState() SessionState {
 	s.stateMutex.RLock()
 	defer s.stateMutex.RUnlock()
@@ -0,0 +1,324 @@
+// internal/ssh/shell_fs.go
+
+package ssh
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/sftp"
+)
+
+// Tryb ograniczony: serwer nie udostępnia podsystemu SFTP (np. stare routery
+// albo serwery z wyłączonym "Subsystem sftp"). Pliki przesyłamy wtedy przez
+// SCP, a listowanie katalogów i pozostałe operacje wykonujemy poleceniami
+// powłoki. Wszystkie funkcje shell* są wywoływane z blokadą ft.mutex.
+
+// Limited zwraca true, gdy połączenie działa bez SFTP (tylko SCP i polecenia powłoki)
+func (ft *FileTransfer) Limited() bool {
+	ft.mutex.Lock()
+	defer ft.mutex.Unlock()
+	return ft.connected && ft.sftpClient == nil
+}
+
+// errLimitedMode zwracają operacje, których nie da się wykonać bez SFTP
+var errLimitedMode = errors.New("not available in limited mode (the server does not support SFTP)")
+
+// shellRun wykonuje polecenie na serwerze i zwraca jego wyjście; niezerowy kod
+// wyjścia jest błędem z treścią stderr
+func (ft *FileTransfer) shellRun(command string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	code, err := runCommand(ft.sshClient, command, &stdout, &stderr)
+	if err != nil {
+		return "", err
+	}
+	if code != 0 {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = fmt.Sprintf("command failed: exit status %d", code)
+		}
+		return stdout.String(), errors.New(message)
+	}
+	return stdout.String(), nil
+}
+
+// lsCommand buduje wywołanie ls z czasem w sekundach (GNU ls); inne wersje ls
+// nie znają --time-style, więc wtedy używamy zwykłego formatu daty
+func lsCommand(flags, target string) string {
+	quoted := shellQuote(target)
+	return fmt.Sprintf("LC_ALL=C ls %s --time-style=+%%s -- %s 2>/dev/null || LC_ALL=C ls %s -- %s",
+		flags, quoted, flags, quoted)
+}
+
+// shellReadDir listuje katalog przez ls (symlinki bez rozwiązywania, jak sftp ReadDir)
+func (ft *FileTransfer) shellReadDir(dir string) ([]os.FileInfo, error) {
+	output, err := ft.shellRun(lsCommand("-lAn", dir))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var entries []os.FileInfo
+	for _, line := range strings.Split(output, "\n") {
+		if info, ok := parseLsLine(line, now); ok && info.name != "." && info.name != ".." {
+			entries = append(entries, info)
+		}
+	}
+	return entries, nil
+}
+
+// shellStat zwraca informacje o ścieżce, rozwiązując symlinki (jak Stat)
+func (ft *FileTransfer) shellStat(target string) (os.FileInfo, error) {
+	return ft.shellStatFlags(target, "-ldnL")
+}
+
+// shellLstat zwraca informacje o samym symlinku (jak Lstat)
+func (ft *FileTransfer) shellLstat(target string) (os.FileInfo, error) {
+	return ft.shellStatFlags(target, "-ldn")
+}
+
+// shellStatFlags wykonuje ls -ld z dodatkowymi flagami dla jednej ścieżki
+func (ft *FileTransfer) shellStatFlags(target, flags string) (os.FileInfo, error) {
+	output, err := ft.shellRun(lsCommand(flags, target))
+	if err != nil {
+		if strings.Contains(err.Error(), "No such file") {
+			return nil, &os.PathError{Op: "stat", Path: target, Err: os.ErrNotExist}
+		}
+		return nil, err
+	}
+
+	info, ok := parseLsLine(strings.TrimRight(output, "\n"), time.Now())
+	if !ok {
+		return nil, fmt.Errorf("unexpected ls output for %s", target)
+	}
+	info.name = path.Base(target)
+	return info, nil
+}
+
+// shellReadFile odczytuje plik przez cat, odrzucając pliki większe niż maxSize
+func (ft *FileTransfer) shellReadFile(target string, maxSize int64) ([]byte, error) {
+	info, err := ft.shellStat(target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat remote file: %v", err)
+	}
+	if info.Size() > maxSize {
+		return nil, fmt.Errorf("file is too large (%d bytes, limit %d)", info.Size(), maxSize)
+	}
+
+	data, err := ft.shellRun("cat -- " + shellQuote(target))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read remote file: %v", err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("file is too large (limit %d bytes)", maxSize)
+	}
+	return []byte(data), nil
+}
+
+// shellDiskSpace odczytuje wolne i całkowite miejsce z df (w blokach 1 KiB)
+func (ft *FileTransfer) shellDiskSpace(target string) (free, total uint64, err error) {
+	output, err := ft.shellRun("LC_ALL=C df -Pk -- " + shellQuote(target))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Pierwsza linia to nagłówek, dane są w ostatniej
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(lines) < 2 || len(fields) < 4 {
+		return 0, 0, fmt.Errorf("unexpected df output")
+	}
+	total, err = strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("unexpected df output: %v", err)
+	}
+	free, err = strconv.ParseUint(fields[3], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("unexpected df output: %v", err)
+	}
+	return free * 1024, total * 1024, nil
+}
+
+// shellChmodMode zamienia os.FileMode na ósemkowe uprawnienia dla chmod
+func shellChmodMode(mode os.FileMode) string {
+	bits := uint32(mode.Perm())
+	if mode&os.ModeSetuid != 0 {
+		bits |= 04000
+	}
+	if mode&os.ModeSetgid != 0 {
+		bits |= 02000
+	}
+	if mode&os.ModeSticky != 0 {
+		bits |= 01000
+	}
+	return fmt.Sprintf("%04o", bits)
+}
+
+// lsFileInfo to os.FileInfo zbudowane z linii "ls -ln"; Sys() zwraca
+// *sftp.FileStat z UID/GID, tak jak informacje z SFTP
+type lsFileInfo struct {
+	name    string
+	size    int64
+	mode    os.FileMode
+	modTime time.Time
+	stat    *sftp.FileStat
+}
+
+func (fi *lsFileInfo) Name() string       { return fi.name }
+func (fi *lsFileInfo) Size() int64        { return fi.size }
+func (fi *lsFileInfo) Mode() os.FileMode  { return fi.mode }
+func (fi *lsFileInfo) ModTime() time.Time { return fi.modTime }
+func (fi *lsFileInfo) IsDir() bool        { return fi.mode.IsDir() }
+func (fi *lsFileInfo) Sys() interface{}   { return fi.stat }
+
+// parseLsLine parsuje linię "ls -ln", np.
+//
+//	-rw-r--r-- 1 1000 1000 220 Jan  2 15:04 .bashrc
+//	lrwxrwxrwx 1 0 0 7 1700000000 bin -> usr/bin
+//
+// Data jest epoką (GNU ls z --time-style=+%s) albo w formacie "Jan 2 15:04"
+// lub "Jan 2 2006". Urządzenia mają "major, minor" zamiast rozmiaru.
+func parseLsLine(line string, now time.Time) (*lsFileInfo, bool) {
+	perms, rest := lsToken(line)
+	if len(perms) < 10 || strings.HasPrefix(line, "total ") {
+		return nil, false
+	}
+	mode, ok := parseLsMode(perms[:10])
+	if !ok {
+		return nil, false
+	}
+
+	_, rest = lsToken(rest) // Liczba dowiązań
+	uidField, rest := lsToken(rest)
+	gidField, rest := lsToken(rest)
+	sizeField, rest := lsToken(rest)
+	if strings.HasSuffix(sizeField, ",") {
+		// Urządzenie: pomijamy numer minor, rozmiar jest zerowy
+		_, rest = lsToken(rest)
+		sizeField = "0"
+	}
+	uid, errUID := strconv.ParseUint(uidField, 10, 32)
+	gid, errGID := strconv.ParseUint(gidField, 10, 32)
+	size, errSize := strconv.ParseInt(sizeField, 10, 64)
+	if errUID != nil || errGID != nil || errSize != nil {
+		return nil, false
+	}
+
+	var modTime time.Time
+	first, rest := lsToken(rest)
+	if epoch, err := strconv.ParseInt(first, 10, 64); err == nil {
+		modTime = time.Unix(epoch, 0)
+	} else {
+		day, afterDay := lsToken(rest)
+		clock, afterClock := lsToken(afterDay)
+		rest = afterClock
+		if modTime, ok = parseLsDate(first, day, clock, now); !ok {
+			return nil, false
+		}
+	}
+
+	// Nazwę od daty oddziela dokładnie jedna spacja (wyrównanie jest przed polami)
+	if !strings.HasPrefix(rest, " ") || len(rest) < 2 {
+		return nil, false
+	}
+	name := rest[1:]
+	if mode&os.ModeSymlink != 0 {
+		if i := strings.Index(name, " -> "); i >= 0 {
+			name = name[:i]
+		}
+	}
+
+	return &lsFileInfo{
+		name:    name,
+		size:    size,
+		mode:    mode,
+		modTime: modTime,
+		stat: &sftp.FileStat{
+			Size:  uint64(size),
+			Mtime: uint32(modTime.Unix()),
+			UID:   uint32(uid),
+			GID:   uint32(gid),
+		},
+	}, true
+}
+
+// lsToken zwraca pierwsze pole linii i resztę zaczynającą się od odstępu za nim
+func lsToken(s string) (string, string) {
+	s = strings.TrimLeft(s, " ")
+	if i := strings.IndexByte(s, ' '); i >= 0 {
+		return s[:i], s[i:]
+	}
+	return s, ""
+}
+
+// parseLsDate parsuje datę ls bez roku (ostatnie pół roku) albo bez godziny
+func parseLsDate(month, day, clock string, now time.Time) (time.Time, bool) {
+	if strings.Contains(clock, ":") {
+		t, err := time.ParseInLocation("Jan 2 15:04 2006",
+			fmt.Sprintf("%s %s %s %d", month, day, clock, now.Year()), time.Local)
+		if err != nil {
+			return time.Time{}, false
+		}
+		// Data bez roku nie leży w przyszłości - to poprzedni rok
+		if t.After(now.Add(24 * time.Hour)) {
+			t = t.AddDate(-1, 0, 0)
+		}
+		return t, true
+	}
+
+	t, err := time.ParseInLocation("Jan 2 2006", fmt.Sprintf("%s %s %s", month, day, clock), time.Local)
+	return t, err == nil
+}
+
+// parseLsMode zamienia np. "drwxr-sr-t" na os.FileMode
+func parseLsMode(s string) (os.FileMode, bool) {
+	var mode os.FileMode
+	switch s[0] {
+	case '-':
+	case 'd':
+		mode |= os.ModeDir
+	case 'l':
+		mode |= os.ModeSymlink
+	case 'c':
+		mode |= os.ModeDevice | os.ModeCharDevice
+	case 'b':
+		mode |= os.ModeDevice
+	case 'p':
+		mode |= os.ModeNamedPipe
+	case 's':
+		mode |= os.ModeSocket
+	default:
+		return 0, false
+	}
+
+	// Bity specjalne są zapisane w miejscu "x" każdej z trzech grup
+	special := []os.FileMode{os.ModeSetuid, os.ModeSetgid, os.ModeSticky}
+	for group := 0; group < 3; group++ {
+		r, w, x := s[1+group*3], s[2+group*3], s[3+group*3]
+		shift := uint(6 - group*3)
+		if r == 'r' {
+			mode |= 4 << shift
+		}
+		if w == 'w' {
+			mode |= 2 << shift
+		}
+		switch x {
+		case 'x':
+			mode |= 1 << shift
+		case 's', 't':
+			mode |= 1<<shift | special[group]
+		case 'S', 'T':
+			mode |= special[group]
+		case '-':
+		default:
+			return 0, false
+		}
+	}
+	return mode, true
+}
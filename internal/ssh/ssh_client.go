@@ -2,11 +2,13 @@ package ssh
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"net"
 	"os"
 	"path/filepath"
 	"sshManager/internal/config"
+	"sshManager/internal/crypto"
 	"sshManager/internal/models"
 	"strings"
 	"time"
@@ -16,9 +18,21 @@ import (
 )
 
 type SSHClient struct {
-	currentHost *models.Host
-	passwords   []models.Password
-	session     *SSHSession
+	currentHost     *models.Host
+	passwords       []models.Password
+	session         *SSHSession
+	client          *ssh.Client       // Połączenie z hostem (zamykane razem z sesją, jeśli ją utworzono)
+	jumpClient      *ssh.Client       // Połączenie z hostem pośredniczącym (jeśli używany)
+	connection      *Connection       // Połączenie współdzielone z transferem plików (opcjonalne)
+	shared          *sharedConn       // Używane połączenie; nil dla połączeń z Dial
+	resolveJumpHost JumpHostResolver  // Wyszukiwanie hostów pośredniczących po nazwie
+	forwards        []*portForward    // Aktywne przekierowania portów
+	warnings        []string          // Ostrzeżenia z ostatniego połączenia
+	lastHost        *models.Host      // Host ostatniego udanego połączenia (do Reconnect)
+	authData        []byte            // Kopia danych autoryzacji połączenia (do Reconnect), czyszczona przy Disconnect
+	authPrompter    AuthPrompter      // Odpowiedzi na pytania keyboard-interactive (np. 2FA)
+	banner          string            // Baner serwera wysłany przed autoryzacją (ostatnie połączenie)
+	algorithms      models.Algorithms // Globalne nadpisania algorytmów (z konfiguracji)
 }
 
 type HostKeyVerificationRequired struct {
@@ -26,8 +40,10 @@ type HostKeyVerificationRequired struct {
 	Port        string
 	Fingerprint string
 	PublicKey   ssh.PublicKey
-	RawKey      []byte // Dodane - surowe dane klucza
-	KeyType     string // Dodane - typ klucza
+	RawKey      []byte   // Dodane - surowe dane klucza
+	KeyType     string   // Dodane - typ klucza
+	Changed     bool     // Host ma w known_hosts inny klucz (możliwy atak MITM)
+	KnownKeys   []string // Odciski kluczy zapisanych w known_hosts (gdy Changed)
 }
 
 const (
@@ -67,9 +83,10 @@ func saveHostKey(host *models.Host, publicKey ssh.PublicKey) error {
 		return fmt.Errorf("failed to create directory: %v", err)
 	}
 
-	// Format hosta
+	// Format hosta; knownhosts.Line zapisuje "[adres]:port" (albo sam adres dla
+	// portu 22), a adresy IPv6 bez nawiasów, tak jak OpenSSH
 	hostPatterns := []string{
-		fmt.Sprintf("[%s]:%s", host.IP, host.Port),
+		net.JoinHostPort(host.IP, host.Port),
 		host.IP,
 	}
 
@@ -92,14 +109,7 @@ func saveHostKey(host *models.Host, publicKey ssh.PublicKey) error {
 	scanner := bufio.NewScanner(strings.NewReader(string(content)))
 	for scanner.Scan() {
 		lineText := scanner.Text()
-		shouldKeep := true
-		for _, pattern := range hostPatterns {
-			if strings.Contains(lineText, pattern) {
-				shouldKeep = false
-				break
-			}
-		}
-		if shouldKeep {
+		if !knownHostsLineMatches(lineText, hostPatterns) {
 			finalLines = append(finalLines, lineText)
 		}
 	}
@@ -112,6 +122,46 @@ func saveHostKey(host *models.Host, publicKey ssh.PublicKey) error {
 	return os.WriteFile(knownHostsPath, content, 0600)
 }
 
+// knownHostsLineMatches sprawdza, czy linia known_hosts dotyczy któregoś z adresów.
+// Porównujemy całe wzorce hostów, więc 10.0.0.1 nie pasuje do 10.0.0.12,
+// a 2001:db8::1 do 2001:db8::10
+func knownHostsLineMatches(line string, addresses []string) bool {
+	fields := strings.Fields(line)
+	if len(fields) > 0 && strings.HasPrefix(fields[0], "@") {
+		fields = fields[1:] // Znacznik @cert-authority lub @revoked
+	}
+	if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+		return false
+	}
+	for _, pattern := range strings.Split(fields[0], ",") {
+		for _, address := range addresses {
+			if pattern == knownhosts.Normalize(address) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// newAuthMethod przygotowuje metodę autoryzacji: klucz SSH (PasswordID < 0) lub hasło
+func newAuthMethod(host *models.Host, authData []byte) (ssh.AuthMethod, error) {
+	if isAgentAuthData(host, authData) {
+		return newAgentAuthMethod(host, authData)
+	}
+
+	if host.PasswordID < 0 {
+		// Obsługa klucza SSH
+		signer, err := loadKeySigner(string(authData))
+		if err != nil {
+			return nil, err
+		}
+		return ssh.PublicKeys(signer), nil
+	}
+
+	// Obsługa hasła
+	return passwordMethod(authData), nil
+}
+
 func NewSSHClient(passwords []models.Password) *SSHClient {
 	return &SSHClient{
 		passwords: passwords,
@@ -120,6 +170,9 @@ func NewSSHClient(passwords []models.Password) *SSHClient {
 }
 
 func (e *HostKeyVerificationRequired) Error() string {
+	if e.Changed {
+		return fmt.Sprintf("host key of %s has changed (possible man-in-the-middle attack)", net.JoinHostPort(e.IP, e.Port))
+	}
 	return "host key verification required"
 }
 
@@ -138,7 +191,7 @@ func GetHostKeyFingerprint(host *models.Host) (string, error) {
 		Timeout: 2 * time.Second,
 	}
 
-	conn, err := ssh.Dial("tcp", fmt.Sprintf("%s:%s", host.IP, host.Port), config)
+	conn, err := ssh.Dial("tcp", net.JoinHostPort(host.IP, host.Port), config)
 	if err != nil && result != "" {
 		return result, nil
 	}
@@ -153,134 +206,171 @@ func GetHostKeyFingerprint(host *models.Host) (string, error) {
 	return result, nil
 }
 
-func (s *SSHClient) Connect(host *models.Host, authData string) error {
-	// Konfiguracja autoryzacji
-	var authMethod ssh.AuthMethod
-	if host.PasswordID < 0 {
-		// Obsługa klucza SSH
-		key, err := os.ReadFile(authData)
+// Connect łączy się z hostem i otwiera sesję powłoki. Dane autoryzacji pozostają
+// własnością wywołującego, który może je wyczyścić po powrocie; do Reconnect
+// klient zachowuje własną kopię.
+func (s *SSHClient) Connect(host *models.Host, authData []byte) error {
+	// Błędy w specyfikacji przekierowań zgłaszamy przed nawiązaniem połączenia
+	localForwards, err := ParseForwardSpecs(host.LocalForwards)
+	if err != nil {
+		return err
+	}
+	if _, err := ParseForwardSpecs(host.RemoteForwards); err != nil {
+		return err
+	}
+
+	s.warnings = append(agentWarnings(host, authData), compressionWarnings(host)...)
+	s.banner = ""
+
+	// Otwarte już połączenie z hostem (np. z transferu plików) nie wymaga
+	// ponownego logowania; powłoka używa tylko połączeń ze sprawdzonym kluczem
+	shared := s.connection.acquire(host, true)
+	if shared == nil {
+		// Połączenie przez host pośredniczący (bastion), jeśli został skonfigurowany
+		jumpClient, err := connectJumpHost(host, s.resolveJumpHost, s.dialHost)
 		if err != nil {
-			return fmt.Errorf("failed to read SSH key: %v", err)
+			return err
 		}
-		signer, err := ssh.ParsePrivateKey(key)
+
+		// Próba nawiązania połączenia
+		client, err := s.dialHost(host, authData, jumpClient)
 		if err != nil {
-			return fmt.Errorf("failed to parse SSH key: %v", err)
+			closeJumpClient(jumpClient)
+			return err
 		}
-		authMethod = ssh.PublicKeys(signer)
-	} else {
-		// Obsługa hasła
-		authMethod = ssh.Password(authData)
+		shared = s.connection.store(host, client, jumpClient, true)
 	}
 
-	// Pobranie ścieżki do known_hosts
-	knownHostsPath, err := getAppKnownHostsPath()
+	// Utworzenie nowej sesji
+	session, err := NewSSHSession(shared.client)
+	if err != nil {
+		shared.discard()
+		return fmt.Errorf("failed to create session: %v", err)
+	}
+
+	// Uruchomienie lokalnych przekierowań portów na czas trwania sesji
+	forwards, err := startLocalForwards(shared.client, localForwards)
+	if err != nil {
+		session.releaseClient()
+		shared.release()
+		return err
+	}
+
+	s.session = session
+	s.client = shared.client
+	s.forwards = forwards
+	s.jumpClient = shared.jumpClient
+	s.shared = shared
+	s.currentHost = host
+	s.lastHost = host
+	s.rememberAuthData(authData)
+	return nil
+}
+
+// rememberAuthData zachowuje kopię danych autoryzacji do Reconnect w miejsce
+// poprzedniej, którą czyści
+func (s *SSHClient) rememberAuthData(authData []byte) {
+	kept := bytes.Clone(authData)
+	crypto.Wipe(s.authData)
+	s.authData = kept
+}
+
+// forgetAuthData czyści zachowane dane autoryzacji
+func (s *SSHClient) forgetAuthData() {
+	crypto.Wipe(s.authData)
+	s.authData = nil
+}
+
+// dialHost nawiązuje połączenie SSH z hostem, weryfikując jego klucz w known_hosts.
+// Jeśli podano via, połączenie jest tunelowane przez wskazanego klienta (jump host).
+func (s *SSHClient) dialHost(host *models.Host, authData []byte, via *ssh.Client) (*ssh.Client, error) {
+	// Konfiguracja autoryzacji; keyboard-interactive (np. 2FA) jest proponowany
+	// jako ostatni, gdy serwer nie przyjmie klucza ani hasła albo ich wymaga
+	authMethods, err := newAuthMethods(host, authData)
 	if err != nil {
-		return fmt.Errorf("failed to get known_hosts path: %v", err)
+		return nil, err
+	}
+	authMethods = append(authMethods, newKeyboardInteractiveMethod(host, authData, s.authPrompter))
+
+	// Bez pliku known_hosts nie da się zweryfikować klucza hosta
+	if _, err := getAppKnownHostsPath(); err != nil {
+		return nil, fmt.Errorf("failed to get known_hosts path: %v", err)
 	}
 
 	var verificationRequired *HostKeyVerificationRequired
+	algorithms := resolveAlgorithms(s.algorithms, host)
 
 	config := &ssh.ClientConfig{
 		User: host.Login,
-		Auth: []ssh.AuthMethod{authMethod},
+		Auth: authMethods,
+		// Baner hosta docelowego zastępuje ewentualny baner hosta pośredniczącego
+		BannerCallback: func(message string) error {
+			s.banner = message
+			return nil
+		},
 		HostKeyCallback: func(hostname string, remote net.Addr, key ssh.PublicKey) error {
-			// Próbuj standardowej weryfikacji najpierw
-			hostKeyCallback, err := knownhosts.New(knownHostsPath)
-			if err == nil {
-				err = hostKeyCallback(hostname, remote, key)
-				if err == nil {
-					return nil // Klucz jest znany i poprawny
-				}
-			}
-
-			// Jeśli klucz nie jest znany lub wystąpił błąd, zapisz informacje do weryfikacji
-			verificationRequired = &HostKeyVerificationRequired{
-				IP:          host.IP,
-				Port:        host.Port,
-				Fingerprint: ssh.FingerprintSHA256(key),
-				PublicKey:   key,
-				RawKey:      key.Marshal(),
-				KeyType:     key.Type(),
+			// Jeśli klucz nie jest znany lub się zmienił, zapisz informacje do weryfikacji
+			verificationRequired = checkHostKey(host, hostname, remote, key)
+			if verificationRequired == nil {
+				return nil // Klucz jest znany i poprawny
 			}
 			return verificationRequired
 		},
-		Timeout: 3 * time.Second,
-		// Kompletna lista obsługiwanych algorytmów
-		HostKeyAlgorithms: []string{
-			KeyAlgoECDSA256,
-			KeyAlgoECDSA384,
-			KeyAlgoECDSA521,
-			KeyAlgoED25519,
-			KeyAlgoRSA,
-			KeyAlgoRSASHA2256,
-			KeyAlgoRSASHA2512,
-		},
-		// Dodajemy konfigurację cipherów i KEX
+		Timeout: host.GetConnectTimeout(),
+		// Algorytmy domyślne z nadpisaniami globalnymi i hosta
+		HostKeyAlgorithms: algorithms.HostKeyAlgorithms,
 		Config: ssh.Config{
-			Ciphers: []string{
-				"aes128-gcm@openssh.com",
-				"aes256-gcm@openssh.com",
-				"chacha20-poly1305@openssh.com",
-				"aes128-ctr",
-				"aes192-ctr",
-				"aes256-ctr",
-			},
-			KeyExchanges: []string{
-				"curve25519-sha256@libssh.org",
-				"ecdh-sha2-nistp256",
-				"ecdh-sha2-nistp384",
-				"ecdh-sha2-nistp521",
-				"diffie-hellman-group14-sha256",
-				"diffie-hellman-group16-sha512",
-			},
+			Ciphers:      algorithms.Ciphers,
+			KeyExchanges: algorithms.KeyExchanges,
 		},
 	}
 
-	// Próba nawiązania połączenia
-	client, err := ssh.Dial("tcp", fmt.Sprintf("%s:%s", host.IP, host.Port), config)
+	client, err := dialThrough(via, net.JoinHostPort(host.IP, host.Port), config)
 	if err != nil {
 		// Jeśli wymagana jest weryfikacja klucza hosta
 		if verificationRequired != nil {
-			return verificationRequired
+			return nil, verificationRequired
 		}
 
 		// Szczegółowa diagnostyka błędów
 		switch {
 		case strings.Contains(err.Error(), "no common algorithm"):
-			return fmt.Errorf("SSH handshake failed: no compatible algorithms found.\n"+
+			return nil, fmt.Errorf("SSH handshake failed: no compatible algorithms found.\n"+
 				"Server offered different algorithms than what we support.\n"+
+				"Legacy algorithms can be enabled in the host's Algorithms field, e.g. kex=+diffie-hellman-group1-sha1.\n"+
 				"Original error: %v", err)
 		case strings.Contains(err.Error(), "connection refused"):
-			return fmt.Errorf("connection refused: the SSH server is not accepting connections on %s:%s", host.IP, host.Port)
+			return nil, fmt.Errorf("connection refused: the SSH server is not accepting connections on %s", net.JoinHostPort(host.IP, host.Port))
 		case strings.Contains(err.Error(), "i/o timeout"):
-			return fmt.Errorf("connection timed out: could not reach %s:%s within %v", host.IP, host.Port, config.Timeout)
+			return nil, fmt.Errorf("connection timed out: could not reach %s within %v", net.JoinHostPort(host.IP, host.Port), config.Timeout)
 		case strings.Contains(err.Error(), "unable to authenticate"):
-			return fmt.Errorf("authentication failed: invalid credentials for user %s", host.Login)
+			return nil, fmt.Errorf("authentication failed: invalid credentials for user %s", host.Login)
 		case strings.Contains(err.Error(), "handshake failed"):
-			return fmt.Errorf("SSH handshake failed: %v\nPlease check if the server supports modern SSH protocols", err)
+			return nil, fmt.Errorf("SSH handshake failed: %v\nPlease check if the server supports modern SSH protocols", err)
 		default:
-			return fmt.Errorf("failed to establish SSH connection: %v", err)
+			return nil, fmt.Errorf("failed to establish SSH connection: %v", err)
 		}
 	}
 
-	// Utworzenie nowej sesji
-	session, err := NewSSHSession(client)
-	if err != nil {
-		client.Close()
-		return fmt.Errorf("failed to create session: %v", err)
-	}
-
-	s.session = session
-	s.currentHost = host
-	return nil
+	return client, nil
 }
 
-func (s *SSHClient) ConnectWithAcceptedKey(host *models.Host, authData string) error {
+// ConnectWithAcceptedKey zapisuje zaakceptowany klucz hosta i ponawia połączenie.
+// Przy połączeniu przez jump host może zwrócić kolejny błąd HostKeyVerificationRequired
+// dla drugiego etapu - każdy klucz musi zostać potwierdzony osobno.
+func (s *SSHClient) ConnectWithAcceptedKey(host *models.Host, authData []byte) error {
 	// Najpierw próbujemy połączenia, aby uzyskać klucz publiczny
 	err := s.Connect(host, authData)
 	if verificationErr, ok := err.(*HostKeyVerificationRequired); ok {
-		// Zapisujemy nowy klucz hosta do known_hosts
-		if err := saveHostKey(host, verificationErr.PublicKey); err != nil {
+		// Zmieniony klucz musi zostać najpierw usunięty z known_hosts przez użytkownika
+		if verificationErr.Changed {
+			return fmt.Errorf("host key of %s has changed; remove the old key from known hosts first",
+				net.JoinHostPort(verificationErr.IP, verificationErr.Port))
+		}
+
+		// Zapisujemy nowy klucz hosta (docelowego lub pośredniczącego) do known_hosts
+		verifiedHost := &models.Host{IP: verificationErr.IP, Port: verificationErr.Port}
+		if err := saveHostKey(verifiedHost, verificationErr.PublicKey); err != nil {
 			return fmt.Errorf("failed to save host key: %v", err)
 		}
 		// Ponowna próba połączenia
@@ -297,11 +387,36 @@ func (s *SSHClient) IsConnected() bool {
 }
 
 func (s *SSHClient) Disconnect() {
+	s.closeForwards()
 	if s.session != nil {
-		s.session.Close()
+		s.session.releaseClient()
 		s.session = nil
 	}
+	if s.shared != nil {
+		// Połączenie współdzielone zamyka Connection, gdy nikt go już nie używa
+		s.shared.release()
+		s.shared = nil
+	} else {
+		if s.client != nil {
+			s.client.Close() // Połączenie bez sesji (Dial)
+		}
+		closeJumpClient(s.jumpClient)
+	}
+	s.client = nil
+	s.jumpClient = nil
 	s.currentHost = nil
+	s.forgetAuthData()
+}
+
+// SetConnection ustawia połączenie współdzielone z transferem plików; Connect
+// używa go, zamiast łączyć się ponownie z tym samym hostem
+func (s *SSHClient) SetConnection(connection *Connection) {
+	s.connection = connection
+}
+
+// SetJumpHostResolver ustawia funkcję wyszukującą hosty pośredniczące po nazwie
+func (s *SSHClient) SetJumpHostResolver(resolver JumpHostResolver) {
+	s.resolveJumpHost = resolver
 }
 
 func (s *SSHClient) GetCurrentHost() *models.Host {
@@ -315,3 +430,69 @@ func (s *SSHClient) GetPasswords() []models.Password {
 func (c *SSHClient) Session() *SSHSession {
 	return c.session
 }
+
+// ActiveForwards zwraca opisy aktywnych przekierowań portów
+func (s *SSHClient) ActiveForwards() []string {
+	var result []string
+	for _, f := range s.forwards {
+		if f.remote {
+			result = append(result, "R "+f.spec.String())
+		} else {
+			result = append(result, f.spec.String())
+		}
+	}
+	return result
+}
+
+// StartRemoteForwards uruchamia zdalne przekierowania portów hosta (-R).
+// Błąd jednego przekierowania nie przerywa sesji - błędy są zwracane,
+// aby wywołujący mógł je zgłosić użytkownikowi.
+func (s *SSHClient) StartRemoteForwards() []error {
+	if s.session == nil || s.currentHost == nil {
+		return nil
+	}
+
+	var errs []error
+	for _, spec := range s.currentHost.RemoteForwards {
+		f, err := ParseForwardSpec(spec)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		pf, err := startRemoteForward(s.session.client, f)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		s.forwards = append(s.forwards, pf)
+	}
+	return errs
+}
+
+// Warnings zwraca ostrzeżenia z ostatniego połączenia (np. brak ssh-agenta)
+func (s *SSHClient) Warnings() []string {
+	return s.warnings
+}
+
+// Banner zwraca baner (np. informację prawną) wysłany przez serwer przed
+// autoryzacją w ostatnim połączeniu; pusty, jeśli serwer go nie wysłał
+func (s *SSHClient) Banner() string {
+	return s.banner
+}
+
+// compressionWarnings zgłasza, że żądana kompresja nie zostanie użyta -
+// biblioteka golang.org/x/crypto/ssh negocjuje wyłącznie "none"
+func compressionWarnings(host *models.Host) []string {
+	if !host.Compression {
+		return nil
+	}
+	return []string{fmt.Sprintf("compression requested for %s is not supported by the SSH library, connecting without it", host.Name)}
+}
+
+// closeForwards zamyka wszystkie aktywne przekierowania portów
+func (s *SSHClient) closeForwards() {
+	for _, f := range s.forwards {
+		f.Close()
+	}
+	s.forwards = nil
+}
@@ -3,11 +3,14 @@
 package ssh
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
+	"net"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -23,13 +26,22 @@ import (
 
 // FileTransfer represents a file transfer session
 type FileTransfer struct {
-	sshClient   *ssh.Client
-	scpClient   scp.Client
-	sftpClient  *sftp.Client
-	currentHost *models.Host
-	cipher      *crypto.Cipher
-	connected   bool
-	mutex       sync.Mutex
+	sshClient       *ssh.Client
+	jumpClient      *ssh.Client // Connection to the jump host, if one is used
+	connection      *Connection // Connection shared with the shell client (optional)
+	shared          *sharedConn // Connection in use; closed by its Connection when unused
+	unverifiedKey   bool        // A host key of the last dial was not found in known_hosts
+	scpClient       scp.Client
+	sftpClient      *sftp.Client
+	currentHost     *models.Host
+	cipher          *crypto.Cipher
+	connected       bool
+	mutex           sync.Mutex
+	resolveJumpHost JumpHostResolver
+	warnings        []string          // Warnings from the last connection attempt
+	algorithms      models.Algorithms // Global algorithm overrides from the configuration
+	preserve        bool              // Copy the source's permissions and modification time to transferred files
+	limiter         rateLimiter       // Speed limit shared by all transfers of the connection
 }
 
 // TransferProgress represents the progress of a file transfer
@@ -38,6 +50,13 @@ type TransferProgress struct {
 	TotalBytes       int64
 	TransferredBytes int64
 	StartTime        time.Time
+	Resumed          bool    // True when the transfer continues a partial destination file
+	ResumeOffset     int64   // Bytes already present at the destination when resuming
+	Speed            float64 // Transfer rate in bytes per second, averaged over recent samples
+	BatchTotalBytes  int64   // Size of the whole batch when copying several files (0 for a single file)
+	BatchDoneBytes   int64   // Bytes of the batch transferred so far, including the current file
+	BatchTotalFiles  int     // Number of files in the batch
+	BatchDoneFiles   int     // Number of batch files already completed
 }
 
 // NewFileTransfer creates a new instance of FileTransfer
@@ -45,11 +64,15 @@ func NewFileTransfer(cipher *crypto.Cipher) *FileTransfer {
 	return &FileTransfer{
 		cipher:    cipher,
 		connected: false,
+		preserve:  true,
 	}
 }
 
-// Connect establishes an SSH, SCP, and SFTP connection
-func (ft *FileTransfer) Connect(host *models.Host, authData string) error {
+// Connect establishes an SSH, SCP, and SFTP connection. Servers without the
+// SFTP subsystem are still usable in limited mode: files are copied over SCP
+// and everything else runs as shell commands (see shell_fs.go). authData is
+// not kept, so the caller may wipe it once Connect returns.
+func (ft *FileTransfer) Connect(host *models.Host, authData []byte) error {
 	ft.mutex.Lock()
 	defer ft.mutex.Unlock()
 
@@ -57,52 +80,80 @@ func (ft *FileTransfer) Connect(host *models.Host, authData string) error {
 		return nil
 	}
 
-	var authMethod ssh.AuthMethod
-	if host.PasswordID < 0 {
-		// Using SSH key authentication
-		key, err := os.ReadFile(authData)
-		if err != nil {
-			return fmt.Errorf("failed to read SSH key: %v", err)
-		}
-		signer, err := ssh.ParsePrivateKey(key)
-		if err != nil {
-			return fmt.Errorf("failed to parse SSH key: %v", err)
-		}
-		authMethod = ssh.PublicKeys(signer)
-	} else {
-		// Using password authentication
-		authMethod = ssh.Password(authData)
+	ft.warnings = append(agentWarnings(host, authData), compressionWarnings(host)...)
+
+	// Reuse the connection of a shell session to the same host, if it is still open
+	if shared := ft.connection.acquire(host, false); shared != nil {
+		return ft.attach(host, shared)
 	}
 
-	config := &ssh.ClientConfig{
-		User:            host.Login,
-		Auth:            []ssh.AuthMethod{authMethod},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
-		Timeout:         30 * time.Second,
+	// Connect through the jump host first, if one is configured
+	ft.unverifiedKey = false
+	jumpClient, err := connectJumpHost(host, ft.resolveJumpHost, ft.dialTransferHost)
+	if err != nil {
+		return err
 	}
 
-	addr := fmt.Sprintf("%s:%s", host.IP, host.Port)
-	sshClient, err := ssh.Dial("tcp", addr, config)
+	sshClient, err := ft.dialTransferHost(host, authData, jumpClient)
 	if err != nil {
-		return fmt.Errorf("failed to connect: %v", err)
+		closeJumpClient(jumpClient)
+		return err
 	}
 
+	// A shell may reuse the connection only when every host key is known
+	return ft.attach(host, ft.connection.store(host, sshClient, jumpClient, !ft.unverifiedKey))
+}
+
+// SetConnection sets the connection shared with the shell client; Connect
+// reuses it instead of dialing the same host again
+func (ft *FileTransfer) SetConnection(connection *Connection) {
+	ft.connection = connection
+}
+
+// ConnectUsing takes over the connection of a shell client whose session was
+// left with TransferKey, so the transfer does not dial and authenticate again.
+// The shell client is disconnected; the connection (and its jump host) now
+// belongs to the FileTransfer and is closed by Disconnect.
+func (ft *FileTransfer) ConnectUsing(client *SSHClient) error {
+	ft.mutex.Lock()
+	defer ft.mutex.Unlock()
+
+	if ft.connected {
+		return fmt.Errorf("file transfer is already connected")
+	}
+
+	host, shared, err := client.handOver()
+	if err != nil {
+		return err
+	}
+
+	ft.warnings = nil
+	return ft.attach(host, shared)
+}
+
+// attach opens the SCP and SFTP clients over an established SSH connection.
+// On failure the connection is released.
+func (ft *FileTransfer) attach(host *models.Host, shared *sharedConn) error {
+	sshClient := shared.client
+
 	// Create SCP client using existing SSH connection
 	scpClient, err := scp.NewClientBySSH(sshClient)
 	if err != nil {
-		sshClient.Close()
+		shared.release()
 		return fmt.Errorf("failed to create SCP client: %v", err)
 	}
 
 	// Create SFTP client for directory operations
 	sftpClient, err := sftp.NewClient(sshClient)
 	if err != nil {
-		scpClient.Close()
-		sshClient.Close()
-		return fmt.Errorf("failed to create SFTP client: %v", err)
+		sftpClient = nil
+		ft.warnings = append(ft.warnings, fmt.Sprintf(
+			"SFTP is not available (%v); limited mode: SCP transfers and shell commands only", err))
 	}
 
 	ft.sshClient = sshClient
+	ft.jumpClient = shared.jumpClient
+	ft.shared = shared
 	ft.scpClient = scpClient
 	ft.sftpClient = sftpClient
 	ft.currentHost = host
@@ -111,6 +162,89 @@ func (ft *FileTransfer) Connect(host *models.Host, authData string) error {
 	return nil
 }
 
+// dialTransferHost opens the SSH connection used for file transfers,
+// optionally tunnelled through an already connected jump host
+func (ft *FileTransfer) dialTransferHost(host *models.Host, authData []byte, via *ssh.Client) (*ssh.Client, error) {
+	authMethods, err := newAuthMethods(host, authData)
+	if err != nil {
+		return nil, err
+	}
+	// Transfers have no prompt, so only password questions can be answered
+	authMethods = append(authMethods, newKeyboardInteractiveMethod(host, authData, nil))
+
+	// Same algorithm preferences as the shell connection
+	algorithms := resolveAlgorithms(ft.algorithms, host)
+	var keyChanged *HostKeyVerificationRequired
+	config := &ssh.ClientConfig{
+		User: host.Login,
+		Auth: authMethods,
+		// Unknown keys are accepted, but then the shell will not reuse the
+		// connection. A key that differs from the one in known_hosts is
+		// rejected before any credentials are sent.
+		HostKeyCallback: func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			verification := checkHostKey(host, hostname, remote, key)
+			if verification == nil {
+				return nil
+			}
+			if verification.Changed {
+				keyChanged = verification
+				return verification
+			}
+			ft.unverifiedKey = true
+			return nil
+		},
+		Timeout:           host.GetConnectTimeout(),
+		HostKeyAlgorithms: algorithms.HostKeyAlgorithms,
+		Config: ssh.Config{
+			Ciphers:      algorithms.Ciphers,
+			KeyExchanges: algorithms.KeyExchanges,
+		},
+	}
+
+	addr := net.JoinHostPort(host.IP, host.Port)
+	sshClient, err := dialThrough(via, addr, config)
+	if err != nil {
+		// Passed on unchanged (also from the jump host), so the UI can warn about it
+		if keyChanged != nil {
+			return nil, keyChanged
+		}
+		return nil, fmt.Errorf("failed to connect to %s: %v", addr, err)
+	}
+
+	return sshClient, nil
+}
+
+// Warnings returns warnings from the last connection attempt (e.g. missing ssh-agent)
+func (ft *FileTransfer) Warnings() []string {
+	ft.mutex.Lock()
+	defer ft.mutex.Unlock()
+	return ft.warnings
+}
+
+// SetJumpHostResolver sets the function used to look up jump hosts by name
+func (ft *FileTransfer) SetJumpHostResolver(resolver JumpHostResolver) {
+	ft.resolveJumpHost = resolver
+}
+
+// SetPreserveAttributes sets whether UploadFile and DownloadFile copy the
+// source's permissions and modification time to the destination (on by default)
+func (ft *FileTransfer) SetPreserveAttributes(preserve bool) {
+	ft.mutex.Lock()
+	defer ft.mutex.Unlock()
+	ft.preserve = preserve
+}
+
+// SetRateLimit limits the combined speed of all transfers to kbPerSecond
+// kilobytes per second; 0 removes the limit. It also applies to a running transfer.
+func (ft *FileTransfer) SetRateLimit(kbPerSecond int) {
+	ft.limiter.setRate(int64(kbPerSecond) * 1024)
+}
+
+// RateLimit returns the transfer speed limit in kilobytes per second (0 if unlimited)
+func (ft *FileTransfer) RateLimit() int {
+	return int(ft.limiter.getRate() / 1024)
+}
+
 // Disconnect closes the SCP, SFTP, and SSH connections
 func (ft *FileTransfer) Disconnect() error {
 	ft.mutex.Lock()
@@ -129,13 +263,13 @@ func (ft *FileTransfer) Disconnect() error {
 		ft.sftpClient = nil
 	}
 
-	// Close the SSH client
-	if ft.sshClient != nil {
-		if err := ft.sshClient.Close(); err != nil {
-			errors = append(errors, fmt.Sprintf("SSH client close error: %v", err))
-		}
-		ft.sshClient = nil
+	// Release the SSH connection; it is closed once the shell no longer uses it either
+	if ft.shared != nil {
+		ft.shared.release()
+		ft.shared = nil
 	}
+	ft.sshClient = nil
+	ft.jumpClient = nil
 
 	ft.connected = false
 	ft.currentHost = nil
@@ -173,6 +307,9 @@ func (ft *FileTransfer) ListRemoteFiles(path string) ([]os.FileInfo, error) {
 		return nil, fmt.Errorf("not connected")
 	}
 
+	if ft.sftpClient == nil {
+		return ft.shellReadDir(utils.ToSFTPPath(path))
+	}
 	return ft.sftpClient.ReadDir(path)
 }
 
@@ -185,6 +322,9 @@ func (ft *FileTransfer) GetRemoteFileInfo(path string) (os.FileInfo, error) {
 		return nil, fmt.Errorf("not connected")
 	}
 
+	if ft.sftpClient == nil {
+		return ft.shellStat(utils.ToSFTPPath(path))
+	}
 	return ft.sftpClient.Stat(path)
 }
 
@@ -197,9 +337,36 @@ func (ft *FileTransfer) CreateRemoteDirectory(path string) error {
 		return fmt.Errorf("not connected")
 	}
 
+	if ft.sftpClient == nil {
+		_, err := ft.shellRun("mkdir -p -- " + shellQuote(utils.ToSFTPPath(path)))
+		return err
+	}
 	return ft.sftpClient.MkdirAll(path)
 }
 
+// CreateRemoteFile creates an empty file on the remote server; an existing
+// file is never truncated
+func (ft *FileTransfer) CreateRemoteFile(path string) error {
+	ft.mutex.Lock()
+	defer ft.mutex.Unlock()
+
+	if !ft.connected {
+		return fmt.Errorf("not connected")
+	}
+
+	if ft.sftpClient == nil {
+		// noclobber (set -C) makes the redirection fail for existing files
+		_, err := ft.shellRun("set -C; : > " + shellQuote(utils.ToSFTPPath(path)))
+		return err
+	}
+
+	file, err := ft.sftpClient.OpenFile(utils.ToSFTPPath(path), os.O_WRONLY|os.O_CREATE|os.O_EXCL)
+	if err != nil {
+		return err
+	}
+	return file.Close()
+}
+
 // RemoveRemoteFile removes a file or directory on the remote server
 func (ft *FileTransfer) RemoveRemoteFile(path string) error {
 	ft.mutex.Lock()
@@ -209,14 +376,20 @@ func (ft *FileTransfer) RemoveRemoteFile(path string) error {
 		return fmt.Errorf("not connected")
 	}
 
+	if ft.sftpClient == nil {
+		// rm -r removes a symlink itself, never the directory it points to
+		_, err := ft.shellRun("rm -rf -- " + shellQuote(utils.ToSFTPPath(path)))
+		return err
+	}
+
 	// First, try to remove as a file
 	err := ft.sftpClient.Remove(path)
 	if err == nil {
 		return nil
 	}
 
-	// If it fails, check if it's a directory
-	info, err := ft.sftpClient.Stat(path)
+	// If it fails, check if it's a directory (Lstat, so a symlink is never followed)
+	info, err := ft.sftpClient.Lstat(path)
 	if err != nil {
 		return fmt.Errorf("failed to get file info: %v", err)
 	}
@@ -241,9 +414,158 @@ func (ft *FileTransfer) RenameRemoteFile(oldPath, newPath string) error {
 	oldPath = utils.ToSFTPPath(oldPath)
 	newPath = utils.ToSFTPPath(newPath)
 
+	if ft.sftpClient == nil {
+		_, err := ft.shellRun("mv -- " + shellQuote(oldPath) + " " + shellQuote(newPath))
+		return err
+	}
 	return ft.sftpClient.Rename(oldPath, newPath)
 }
 
+// RemoteDiskSpace returns the space available to the user and the total size
+// of the remote filesystem holding path. It needs the statvfs@openssh.com
+// extension, so other servers return an error. In limited mode it uses df.
+func (ft *FileTransfer) RemoteDiskSpace(path string) (free, total uint64, err error) {
+	ft.mutex.Lock()
+	defer ft.mutex.Unlock()
+
+	if !ft.connected {
+		return 0, 0, fmt.Errorf("not connected")
+	}
+
+	if ft.sftpClient == nil {
+		return ft.shellDiskSpace(utils.ToSFTPPath(path))
+	}
+
+	stat, err := ft.sftpClient.StatVFS(utils.ToSFTPPath(path))
+	if err != nil {
+		return 0, 0, err
+	}
+	return stat.Bavail * stat.Frsize, stat.Blocks * stat.Frsize, nil
+}
+
+// ReadRemoteLink returns the target of a remote symbolic link
+func (ft *FileTransfer) ReadRemoteLink(path string) (string, error) {
+	ft.mutex.Lock()
+	defer ft.mutex.Unlock()
+
+	if !ft.connected {
+		return "", fmt.Errorf("not connected")
+	}
+
+	if ft.sftpClient == nil {
+		target, err := ft.shellRun("readlink -- " + shellQuote(utils.ToSFTPPath(path)))
+		return strings.TrimSuffix(target, "\n"), err
+	}
+	return ft.sftpClient.ReadLink(utils.ToSFTPPath(path))
+}
+
+// ChmodRemote changes the permission bits of a remote file or directory
+func (ft *FileTransfer) ChmodRemote(path string, mode os.FileMode) error {
+	ft.mutex.Lock()
+	defer ft.mutex.Unlock()
+
+	if !ft.connected {
+		return fmt.Errorf("not connected")
+	}
+
+	if ft.sftpClient == nil {
+		_, err := ft.shellRun("chmod " + shellChmodMode(mode) + " -- " + shellQuote(utils.ToSFTPPath(path)))
+		return err
+	}
+	return ft.sftpClient.Chmod(utils.ToSFTPPath(path), mode)
+}
+
+// ReadRemoteFile returns the contents of a remote file, refusing files larger than maxSize bytes
+func (ft *FileTransfer) ReadRemoteFile(path string, maxSize int64) ([]byte, error) {
+	ft.mutex.Lock()
+	defer ft.mutex.Unlock()
+
+	if !ft.connected {
+		return nil, fmt.Errorf("not connected")
+	}
+
+	path = utils.ToSFTPPath(path)
+	if ft.sftpClient == nil {
+		return ft.shellReadFile(path, maxSize)
+	}
+
+	file, err := ft.sftpClient.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open remote file: %v", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat remote file: %v", err)
+	}
+	if info.Size() > maxSize {
+		return nil, fmt.Errorf("file is too large (%d bytes, limit %d)", info.Size(), maxSize)
+	}
+
+	// Read at most maxSize+1 bytes in case the file grew after Stat
+	data, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read remote file: %v", err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("file is too large (limit %d bytes)", maxSize)
+	}
+	return data, nil
+}
+
+// RemoteOwnerNames maps user and group IDs to names using /etc/passwd and
+// /etc/group on the remote server. Files that cannot be read yield empty maps,
+// so callers can fall back to numeric IDs.
+func (ft *FileTransfer) RemoteOwnerNames() (users map[uint32]string, groups map[uint32]string) {
+	ft.mutex.Lock()
+	defer ft.mutex.Unlock()
+
+	if !ft.connected {
+		return map[uint32]string{}, map[uint32]string{}
+	}
+
+	return ft.readIDNames("/etc/passwd"), ft.readIDNames("/etc/group")
+}
+
+// readIDNames parses "name:x:id:..." lines from a remote passwd/group file
+func (ft *FileTransfer) readIDNames(path string) map[uint32]string {
+	names := make(map[uint32]string)
+
+	data, err := ft.readIDFile(path)
+	if err != nil {
+		return names
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Split(line, ":")
+		if len(fields) < 3 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if id, err := strconv.ParseUint(fields[2], 10, 32); err == nil {
+			if _, exists := names[uint32(id)]; !exists {
+				names[uint32(id)] = fields[0]
+			}
+		}
+	}
+	return names
+}
+
+// readIDFile reads a passwd/group file over SFTP or, in limited mode, with cat
+func (ft *FileTransfer) readIDFile(path string) ([]byte, error) {
+	if ft.sftpClient == nil {
+		data, err := ft.shellRun("cat -- " + shellQuote(path))
+		return []byte(data), err
+	}
+
+	file, err := ft.sftpClient.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
+
 // GetRemoteHomeDir returns the home directory on the remote server
 func (ft *FileTransfer) GetRemoteHomeDir() (string, error) {
 	ft.mutex.Lock()
@@ -253,21 +575,123 @@ func (ft *FileTransfer) GetRemoteHomeDir() (string, error) {
 		return "", fmt.Errorf("not connected")
 	}
 
-	session, err := ft.sshClient.NewSession()
+	var output bytes.Buffer
+	code, err := runCommand(ft.sshClient, "echo $HOME", &output, io.Discard)
 	if err != nil {
-		return "", fmt.Errorf("failed to create SSH session: %v", err)
+		return "", err
+	}
+	if code != 0 {
+		return "", fmt.Errorf("failed to execute command: exit status %d", code)
 	}
-	defer session.Close()
 
-	output, err := session.Output("echo $HOME")
+	return strings.TrimSpace(output.String()), nil
+}
+
+// InstallPublicKey appends publicKey to ~/.ssh/authorized_keys on the remote
+// server, creating ~/.ssh (0700) and the file (0600) when they are missing.
+// It returns false without changing anything if the key is already installed.
+func (ft *FileTransfer) InstallPublicKey(publicKey string) (bool, error) {
+	homeDir, err := ft.GetRemoteHomeDir()
 	if err != nil {
-		return "", fmt.Errorf("failed to execute command: %v", err)
+		return false, fmt.Errorf("failed to get remote home directory: %v", err)
+	}
+
+	ft.mutex.Lock()
+	defer ft.mutex.Unlock()
+
+	if !ft.connected {
+		return false, fmt.Errorf("not connected")
+	}
+	if ft.sftpClient == nil {
+		return false, errLimitedMode
+	}
+
+	sshDir := strings.TrimSuffix(homeDir, "/") + "/.ssh"
+	authorizedKeys := sshDir + "/authorized_keys"
+
+	// Create ~/.ssh if needed
+	if _, err := ft.sftpClient.Stat(sshDir); err != nil {
+		if !os.IsNotExist(err) {
+			return false, fmt.Errorf("failed to check %s: %v", sshDir, err)
+		}
+		if err := ft.sftpClient.Mkdir(sshDir); err != nil {
+			return false, fmt.Errorf("failed to create %s: %v", sshDir, err)
+		}
+		if err := ft.sftpClient.Chmod(sshDir, 0700); err != nil {
+			return false, fmt.Errorf("failed to set permissions on %s: %v", sshDir, err)
+		}
+	}
+
+	// Read existing entries to skip keys that are already installed
+	var existing string
+	created := false
+	file, err := ft.sftpClient.Open(authorizedKeys)
+	switch {
+	case err == nil:
+		data, readErr := io.ReadAll(file)
+		file.Close()
+		if readErr != nil {
+			return false, fmt.Errorf("failed to read %s: %v", authorizedKeys, readErr)
+		}
+		existing = string(data)
+	case os.IsNotExist(err):
+		created = true
+	default:
+		return false, fmt.Errorf("failed to open %s: %v", authorizedKeys, err)
 	}
 
-	return strings.TrimSpace(string(output)), nil
+	for _, line := range strings.Split(existing, "\n") {
+		if sameAuthorizedKey(line, publicKey) {
+			return false, nil
+		}
+	}
+
+	file, err = ft.sftpClient.OpenFile(authorizedKeys, os.O_WRONLY|os.O_CREATE|os.O_APPEND)
+	if err != nil {
+		return false, fmt.Errorf("failed to open %s for writing: %v", authorizedKeys, err)
+	}
+	defer file.Close()
+
+	if created {
+		if err := ft.sftpClient.Chmod(authorizedKeys, 0600); err != nil {
+			return false, fmt.Errorf("failed to set permissions on %s: %v", authorizedKeys, err)
+		}
+	}
+
+	entry := strings.TrimSpace(publicKey) + "\n"
+	if existing != "" && !strings.HasSuffix(existing, "\n") {
+		entry = "\n" + entry
+	}
+	if _, err := file.Write([]byte(entry)); err != nil {
+		return false, fmt.Errorf("failed to write %s: %v", authorizedKeys, err)
+	}
+
+	return true, nil
+}
+
+// UploadFile copies a local file to the server. Cancelling ctx aborts the copy
+// and leaves the partial remote file in place, so a later upload resumes it
+func (ft *FileTransfer) UploadFile(ctx context.Context, localPath, remotePath string, progressChan chan<- TransferProgress) error {
+	return ft.uploadFile(ctx, localPath, remotePath, progressChan, true, true)
+}
+
+// OverwriteRemoteFile uploads a local file in place of a remote file the user
+// chose to overwrite. Unlike UploadFile it never resumes, because the existing
+// file is other content, not a partial copy.
+func (ft *FileTransfer) OverwriteRemoteFile(ctx context.Context, localPath, remotePath string, progressChan chan<- TransferProgress) error {
+	return ft.uploadFile(ctx, localPath, remotePath, progressChan, false, true)
 }
 
-func (ft *FileTransfer) UploadFile(localPath, remotePath string, progressChan chan<- TransferProgress) error {
+// ReplaceRemoteFile uploads a local file over an existing remote file. Unlike
+// UploadFile it never resumes: a shorter remote file is an older version of the
+// content, not a partial copy of it.
+func (ft *FileTransfer) ReplaceRemoteFile(ctx context.Context, localPath, remotePath string) error {
+	return ft.uploadFile(ctx, localPath, remotePath, nil, false, false)
+}
+
+// uploadFile implements UploadFile; resume enables continuing a partial upload
+// and preserve copies the local attributes to the remote file
+func (ft *FileTransfer) uploadFile(ctx context.Context, localPath, remotePath string, progressChan chan<- TransferProgress, resume, preserve bool) error {
 	ft.mutex.Lock()
 	if !ft.connected {
 		ft.mutex.Unlock()
@@ -291,15 +715,25 @@ func (ft *FileTransfer) UploadFile(localPath, remotePath string, progressChan ch
 		return fmt.Errorf("failed to stat local file: %v", err)
 	}
 
+	// Resume a partial upload over SFTP if the remote file is a shorter prefix
+	// (SCP alone can only send whole files)
+	if resume && !ft.Limited() {
+		if remoteInfo, err := ft.GetRemoteFileInfo(remotePath); err == nil && !remoteInfo.IsDir() {
+			if offset := resumeOffset(fileInfo.Size(), remoteInfo.Size()); offset > 0 && ft.remotePartialCopy(remotePath, localFile, offset) {
+				if err := ft.resumeUpload(ctx, localFile, fileInfo.Size(), remotePath, offset, progressChan); err != nil {
+					return err
+				}
+				return ft.preserveRemoteAttributes(remotePath, fileInfo)
+			}
+		}
+	}
+
 	// Set permissions (convert to string in octal)
 	perm := fmt.Sprintf("%#o", fileInfo.Mode().Perm())
 
 	// Start time for progress
 	startTime := time.Now()
 
-	// Prepare context
-	ctx := context.Background()
-
 	// Define PassThru function for progress reporting
 	// Use filepath.Base for the local path to get proper filename
 	passThru := func(r io.Reader, total int64) io.Reader {
@@ -309,6 +743,8 @@ func (ft *FileTransfer) UploadFile(localPath, remotePath string, progressChan ch
 			FileName:  filepath.Base(localPath),
 			StartTime: startTime,
 			Progress:  progressChan,
+			Ctx:       ctx,
+			limiter:   &ft.limiter,
 		}
 	}
 
@@ -318,10 +754,85 @@ func (ft *FileTransfer) UploadFile(localPath, remotePath string, progressChan ch
 		return fmt.Errorf("error while uploading file: %v", err)
 	}
 
+	// ReplaceRemoteFile keeps the attributes of the remote file
+	if !preserve {
+		return nil
+	}
+	return ft.preserveRemoteAttributes(remotePath, fileInfo)
+}
+
+// preserveRemoteAttributes copies the local file's permissions and modification
+// time to the uploaded remote file, unless preservation is turned off. SCP sets
+// the mode only when it creates the file, so it is applied again here.
+func (ft *FileTransfer) preserveRemoteAttributes(remotePath string, local os.FileInfo) error {
+	ft.mutex.Lock()
+	defer ft.mutex.Unlock()
+
+	if !ft.connected {
+		return fmt.Errorf("not connected")
+	}
+	if !ft.preserve {
+		return nil
+	}
+
+	if ft.sftpClient == nil {
+		_, err := ft.shellRun(fmt.Sprintf("chmod %s -- %s && TZ=UTC0 touch -m -t %s -- %s",
+			shellChmodMode(local.Mode()), shellQuote(remotePath),
+			local.ModTime().UTC().Format("200601021504.05"), shellQuote(remotePath)))
+		if err != nil {
+			return fmt.Errorf("uploaded, but failed to preserve attributes: %v", err)
+		}
+		return nil
+	}
+
+	if err := ft.sftpClient.Chmod(remotePath, local.Mode().Perm()); err != nil {
+		return fmt.Errorf("uploaded, but failed to preserve permissions: %v", err)
+	}
+	if err := ft.sftpClient.Chtimes(remotePath, time.Now(), local.ModTime()); err != nil {
+		return fmt.Errorf("uploaded, but failed to preserve modification time: %v", err)
+	}
 	return nil
 }
 
-func (ft *FileTransfer) DownloadFile(remotePath, localPath string, progressChan chan<- TransferProgress) error {
+// preserveLocalAttributes copies the remote file's permissions and modification
+// time to the downloaded local file, unless preservation is turned off
+func (ft *FileTransfer) preserveLocalAttributes(localPath, remotePath string) error {
+	ft.mutex.Lock()
+	preserve := ft.preserve
+	ft.mutex.Unlock()
+	if !preserve {
+		return nil
+	}
+
+	remote, err := ft.GetRemoteFileInfo(remotePath)
+	if err != nil {
+		return fmt.Errorf("downloaded, but failed to read remote attributes: %v", err)
+	}
+
+	if err := os.Chmod(localPath, remote.Mode().Perm()); err != nil {
+		return fmt.Errorf("downloaded, but failed to preserve permissions: %v", err)
+	}
+	if err := os.Chtimes(localPath, time.Now(), remote.ModTime()); err != nil {
+		return fmt.Errorf("downloaded, but failed to preserve modification time: %v", err)
+	}
+	return nil
+}
+
+// DownloadFile copies a remote file to the local disk. Cancelling ctx aborts the
+// copy and leaves the partial local file in place, so a later download resumes it
+func (ft *FileTransfer) DownloadFile(ctx context.Context, remotePath, localPath string, progressChan chan<- TransferProgress) error {
+	return ft.downloadFile(ctx, remotePath, localPath, progressChan, true)
+}
+
+// OverwriteLocalFile downloads a remote file in place of a local file the user
+// chose to overwrite. Unlike DownloadFile it never resumes, because the
+// existing file is other content, not a partial copy.
+func (ft *FileTransfer) OverwriteLocalFile(ctx context.Context, remotePath, localPath string, progressChan chan<- TransferProgress) error {
+	return ft.downloadFile(ctx, remotePath, localPath, progressChan, false)
+}
+
+// downloadFile implements DownloadFile; resume enables continuing a partial download
+func (ft *FileTransfer) downloadFile(ctx context.Context, remotePath, localPath string, progressChan chan<- TransferProgress, resume bool) error {
 	ft.mutex.Lock()
 	if !ft.connected {
 		ft.mutex.Unlock()
@@ -339,6 +850,20 @@ func (ft *FileTransfer) DownloadFile(remotePath, localPath string, progressChan
 		return fmt.Errorf("failed to create target directory: %v", err)
 	}
 
+	// Resume a partial download over SFTP if the local file is a shorter prefix
+	if localInfo, err := os.Stat(localPath); resume && err == nil && !localInfo.IsDir() && !ft.Limited() {
+		remoteInfo, err := ft.GetRemoteFileInfo(remotePath)
+		if err != nil {
+			return fmt.Errorf("failed to stat remote file: %v", err)
+		}
+		if offset := resumeOffset(remoteInfo.Size(), localInfo.Size()); offset > 0 && ft.localPartialCopy(remotePath, localPath, offset) {
+			if err := ft.resumeDownload(ctx, remotePath, remoteInfo.Size(), localPath, offset, progressChan); err != nil {
+				return err
+			}
+			return ft.preserveLocalAttributes(localPath, remotePath)
+		}
+	}
+
 	// Open local file for writing with proper permissions
 	localFile, err := os.OpenFile(localPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
 	if err != nil {
@@ -349,9 +874,6 @@ func (ft *FileTransfer) DownloadFile(remotePath, localPath string, progressChan
 	// Start time for progress
 	startTime := time.Now()
 
-	// Prepare context
-	ctx := context.Background()
-
 	// Define PassThru function for progress reporting
 	// Use filepath.Base with the remote path to get proper filename
 	passThru := func(r io.Reader, total int64) io.Reader {
@@ -361,6 +883,8 @@ func (ft *FileTransfer) DownloadFile(remotePath, localPath string, progressChan
 			FileName:  filepath.Base(remotePath),
 			StartTime: startTime,
 			Progress:  progressChan,
+			Ctx:       ctx,
+			limiter:   &ft.limiter,
 		}
 	}
 
@@ -369,6 +893,190 @@ func (ft *FileTransfer) DownloadFile(remotePath, localPath string, progressChan
 	if err != nil {
 		return fmt.Errorf("error while downloading file: %v", err)
 	}
+	if err := localFile.Close(); err != nil {
+		return fmt.Errorf("failed to close local file: %v", err)
+	}
+
+	return ft.preserveLocalAttributes(localPath, remotePath)
+}
+
+// resumeOffset returns the offset to resume from when the destination may hold
+// a partial copy of the source (non-empty and shorter), or 0 for a full
+// transfer. The content is checked separately with isPartialCopy.
+func resumeOffset(sourceSize, destSize int64) int64 {
+	if destSize > 0 && destSize < sourceSize {
+		return destSize
+	}
+	return 0
+}
+
+// resumeCheckSize is how much of the start and of the end of a partial copy is
+// compared with the source before the transfer is resumed
+const resumeCheckSize = 64 * 1024
+
+// isPartialCopy reports whether the first offset bytes of dst match src,
+// comparing the first and the last resumeCheckSize bytes of that range. A
+// shorter destination that differs (e.g. an older version of the file in a
+// directory copied over an existing one) must be replaced, not appended to.
+func isPartialCopy(src, dst io.ReaderAt, offset int64) bool {
+	n := min(int64(resumeCheckSize), offset)
+	srcBlock := make([]byte, n)
+	dstBlock := make([]byte, n)
+	for _, start := range []int64{0, offset - n} {
+		if _, err := io.ReadFull(io.NewSectionReader(src, start, n), srcBlock); err != nil {
+			return false
+		}
+		if _, err := io.ReadFull(io.NewSectionReader(dst, start, n), dstBlock); err != nil {
+			return false
+		}
+		if !bytes.Equal(srcBlock, dstBlock) {
+			return false
+		}
+	}
+	return true
+}
+
+// remotePartialCopy reports whether the remote file is a partial copy of
+// localFile up to offset (see isPartialCopy)
+func (ft *FileTransfer) remotePartialCopy(remotePath string, localFile *os.File, offset int64) bool {
+	ft.mutex.Lock()
+	defer ft.mutex.Unlock()
+
+	if !ft.connected || ft.sftpClient == nil {
+		return false
+	}
+	remoteFile, err := ft.sftpClient.Open(remotePath)
+	if err != nil {
+		return false
+	}
+	defer remoteFile.Close()
+	return isPartialCopy(localFile, remoteFile, offset)
+}
+
+// localPartialCopy reports whether the local file is a partial copy of the
+// remote file up to offset (see isPartialCopy)
+func (ft *FileTransfer) localPartialCopy(remotePath, localPath string, offset int64) bool {
+	localFile, err := os.Open(localPath)
+	if err != nil {
+		return false
+	}
+	defer localFile.Close()
+
+	ft.mutex.Lock()
+	defer ft.mutex.Unlock()
+
+	if !ft.connected || ft.sftpClient == nil {
+		return false
+	}
+	remoteFile, err := ft.sftpClient.Open(remotePath)
+	if err != nil {
+		return false
+	}
+	defer remoteFile.Close()
+	return isPartialCopy(remoteFile, localFile, offset)
+}
+
+// resumeUpload appends the remainder of localFile to a partial remote file
+// starting at offset, using SFTP ranged writes
+func (ft *FileTransfer) resumeUpload(ctx context.Context, localFile *os.File, size int64, remotePath string, offset int64, progressChan chan<- TransferProgress) error {
+	ft.mutex.Lock()
+	defer ft.mutex.Unlock()
+
+	if !ft.connected {
+		return fmt.Errorf("not connected")
+	}
+
+	remoteFile, err := ft.sftpClient.OpenFile(remotePath, os.O_WRONLY)
+	if err != nil {
+		return fmt.Errorf("failed to open remote file for resume: %v", err)
+	}
+	defer remoteFile.Close()
+
+	if _, err := remoteFile.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("remote does not support resuming at offset %d: %v", offset, err)
+	}
+	if _, err := localFile.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek local file: %v", err)
+	}
+
+	reader := &ProgressReader{
+		Reader:       localFile,
+		Total:        size,
+		Transferred:  offset,
+		FileName:     filepath.Base(localFile.Name()),
+		StartTime:    time.Now(),
+		Progress:     progressChan,
+		Resumed:      true,
+		ResumeOffset: offset,
+		Ctx:          ctx,
+		limiter:      &ft.limiter,
+	}
+	if _, err := io.Copy(remoteFile, reader); err != nil {
+		return fmt.Errorf("error while resuming upload: %v", err)
+	}
+
+	// Verify that the remote file is now complete
+	info, err := remoteFile.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to verify uploaded file: %v", err)
+	}
+	if info.Size() != size {
+		return fmt.Errorf("resumed upload size mismatch: remote has %d bytes, expected %d", info.Size(), size)
+	}
+
+	return nil
+}
+
+// resumeDownload appends the remainder of a remote file to a partial local
+// file starting at offset, using SFTP ranged reads
+func (ft *FileTransfer) resumeDownload(ctx context.Context, remotePath string, size int64, localPath string, offset int64, progressChan chan<- TransferProgress) error {
+	ft.mutex.Lock()
+	defer ft.mutex.Unlock()
+
+	if !ft.connected {
+		return fmt.Errorf("not connected")
+	}
+
+	remoteFile, err := ft.sftpClient.Open(remotePath)
+	if err != nil {
+		return fmt.Errorf("failed to open remote file for resume: %v", err)
+	}
+	defer remoteFile.Close()
+
+	if _, err := remoteFile.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("remote does not support resuming at offset %d: %v", offset, err)
+	}
+
+	localFile, err := os.OpenFile(localPath, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open local file for resume: %v", err)
+	}
+	defer localFile.Close()
+
+	reader := &ProgressReader{
+		Reader:       remoteFile,
+		Total:        size,
+		Transferred:  offset,
+		FileName:     filepath.Base(remotePath),
+		StartTime:    time.Now(),
+		Progress:     progressChan,
+		Resumed:      true,
+		ResumeOffset: offset,
+		Ctx:          ctx,
+		limiter:      &ft.limiter,
+	}
+	if _, err := io.Copy(localFile, reader); err != nil {
+		return fmt.Errorf("error while resuming download: %v", err)
+	}
+
+	// Verify that the local file is now complete
+	info, err := localFile.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to verify downloaded file: %v", err)
+	}
+	if info.Size() != size {
+		return fmt.Errorf("resumed download size mismatch: local file has %d bytes, expected %d", info.Size(), size)
+	}
 
 	return nil
 }
@@ -382,6 +1090,11 @@ func (ft *FileTransfer) RemoveRemoteDirectoryRecursive(path string) error {
 		return fmt.Errorf("not connected")
 	}
 
+	if ft.sftpClient == nil {
+		_, err := ft.shellRun("rm -rf -- " + shellQuote(utils.ToSFTPPath(path)))
+		return err
+	}
+
 	entries, err := ft.sftpClient.ReadDir(path)
 	if err != nil {
 		return fmt.Errorf("failed to list remote directory: %v", err)
@@ -404,7 +1117,7 @@ func (ft *FileTransfer) RemoveRemoteDirectoryRecursive(path string) error {
 }
 
 // UploadDirectory uploads an entire directory to the server
-func (ft *FileTransfer) UploadDirectory(localPath, remotePath string, progressChan chan<- TransferProgress) error {
+func (ft *FileTransfer) UploadDirectory(ctx context.Context, localPath, remotePath string, progressChan chan<- TransferProgress) error {
 	ft.mutex.Lock()
 	if !ft.connected {
 		ft.mutex.Unlock()
@@ -434,7 +1147,7 @@ func (ft *FileTransfer) UploadDirectory(localPath, remotePath string, progressCh
 			return ft.CreateRemoteDirectory(remotePathFull)
 		}
 
-		return ft.UploadFile(path, remotePathFull, progressChan)
+		return ft.UploadFile(ctx, path, remotePathFull, progressChan)
 	})
 
 	if err != nil {
@@ -445,7 +1158,7 @@ func (ft *FileTransfer) UploadDirectory(localPath, remotePath string, progressCh
 }
 
 // DownloadDirectory downloads a directory from the server
-func (ft *FileTransfer) DownloadDirectory(remotePath, localPath string, progressChan chan<- TransferProgress) error {
+func (ft *FileTransfer) DownloadDirectory(ctx context.Context, remotePath, localPath string, progressChan chan<- TransferProgress) error {
 	ft.mutex.Lock()
 	if !ft.connected {
 		ft.mutex.Unlock()
@@ -459,7 +1172,7 @@ func (ft *FileTransfer) DownloadDirectory(remotePath, localPath string, progress
 	}
 
 	// Get list of files
-	entries, err := ft.sftpClient.ReadDir(remotePath)
+	entries, err := ft.ListRemoteFiles(remotePath)
 	if err != nil {
 		return fmt.Errorf("failed to list remote directory: %v", err)
 	}
@@ -470,11 +1183,11 @@ func (ft *FileTransfer) DownloadDirectory(remotePath, localPath string, progress
 		localDstPath := filepath.Join(localPath, entry.Name())
 
 		if entry.IsDir() {
-			if err := ft.DownloadDirectory(remoteSrcPath, localDstPath, progressChan); err != nil {
+			if err := ft.DownloadDirectory(ctx, remoteSrcPath, localDstPath, progressChan); err != nil {
 				return err
 			}
 		} else {
-			if err := ft.DownloadFile(remoteSrcPath, localDstPath, progressChan); err != nil {
+			if err := ft.DownloadFile(ctx, remoteSrcPath, localDstPath, progressChan); err != nil {
 				return err
 			}
 		}
@@ -492,11 +1205,44 @@ type ProgressReader struct {
 	StartTime      time.Time
 	Progress       chan<- TransferProgress
 	LastReportTime time.Time
+	Resumed        bool            // Transferred started at the resume offset
+	ResumeOffset   int64           // Offset the resumed transfer started from
+	Ctx            context.Context // Aborts reading once cancelled (optional)
+
+	samples []progressSample // Recent progress reports used to smooth the speed
+	limiter *rateLimiter     // Transfer speed limit (optional)
+}
+
+// progressSample records how many bytes were transferred at a point in time.
+type progressSample struct {
+	time  time.Time
+	bytes int64
 }
 
+// speedSamples is the number of recent reports the transfer speed is averaged over.
+const speedSamples = 5
+
 func (pr *ProgressReader) Read(p []byte) (n int, err error) {
+	if pr.Ctx != nil {
+		if err := pr.Ctx.Err(); err != nil {
+			return 0, err
+		}
+	}
+
+	if len(pr.samples) == 0 {
+		pr.samples = append(pr.samples, progressSample{time: time.Now(), bytes: pr.Transferred})
+	}
+
+	if pr.limiter != nil {
+		p = p[:pr.limiter.chunk(len(p))]
+	}
 	n, err = pr.Reader.Read(p)
 	pr.Transferred += int64(n)
+	if pr.limiter != nil && n > 0 {
+		if waitErr := pr.limiter.wait(pr.Ctx, n); waitErr != nil && err == nil {
+			err = waitErr
+		}
+	}
 
 	// Report progress every second or when done
 	now := time.Now()
@@ -507,6 +1253,9 @@ func (pr *ProgressReader) Read(p []byte) (n int, err error) {
 			TotalBytes:       pr.Total,
 			TransferredBytes: pr.Transferred,
 			StartTime:        pr.StartTime,
+			Resumed:          pr.Resumed,
+			ResumeOffset:     pr.ResumeOffset,
+			Speed:            pr.speed(now),
 		}
 		if pr.Progress != nil {
 			select {
@@ -518,3 +1267,19 @@ func (pr *ProgressReader) Read(p []byte) (n int, err error) {
 
 	return n, err
 }
+
+// speed records a new sample and returns the transfer rate over the
+// last speedSamples reports, which keeps the displayed value from jumping.
+func (pr *ProgressReader) speed(now time.Time) float64 {
+	pr.samples = append(pr.samples, progressSample{time: now, bytes: pr.Transferred})
+	if len(pr.samples) > speedSamples+1 {
+		pr.samples = pr.samples[len(pr.samples)-speedSamples-1:]
+	}
+
+	oldest := pr.samples[0]
+	elapsed := now.Sub(oldest.time).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(pr.Transferred-oldest.bytes) / elapsed
+}
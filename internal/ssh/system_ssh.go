@@ -0,0 +1,75 @@
+// internal/ssh/system_ssh.go
+
+package ssh
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"sshManager/internal/models"
+)
+
+// SystemSSHCommand buduje polecenie systemowego klienta ssh dla hosta z opcją
+// UseSystemSSH. identityFiles to ścieżki kluczy hosta, a jumpHost (może być nil)
+// jest przekazywany przez -J. Hasła i pytania serwera obsługuje sam ssh.
+func SystemSSHCommand(host *models.Host, identityFiles []string, jumpHost *models.Host) (*exec.Cmd, error) {
+	binary, err := exec.LookPath("ssh")
+	if err != nil {
+		return nil, fmt.Errorf("system ssh binary not found: %v", err)
+	}
+
+	cmd := exec.Command(binary, systemSSHArgs(host, identityFiles, jumpHost)...)
+	// ssh przekazuje TERM do zdalnego PTY
+	cmd.Env = append(os.Environ(), "TERM="+host.GetTerminalType())
+	return cmd, nil
+}
+
+// systemSSHArgs zamienia ustawienia hosta na argumenty OpenSSH
+func systemSSHArgs(host *models.Host, identityFiles []string, jumpHost *models.Host) []string {
+	var args []string
+	if host.Port != "" {
+		args = append(args, "-p", host.Port)
+	}
+	if host.Login != "" {
+		args = append(args, "-l", host.Login)
+	}
+	for _, identityFile := range identityFiles {
+		args = append(args, "-i", identityFile)
+	}
+
+	// Ten sam known_hosts co klient wbudowany, aby klucze hostów były wspólne
+	if knownHostsPath, err := getAppKnownHostsPath(); err == nil {
+		args = append(args, "-o", "UserKnownHostsFile="+knownHostsPath)
+	}
+	if host.ConnectTimeout > 0 {
+		args = append(args, "-o", fmt.Sprintf("ConnectTimeout=%d", host.ConnectTimeout))
+	}
+	args = append(args, "-o", fmt.Sprintf("ServerAliveInterval=%d", int(host.GetKeepAliveInterval().Seconds())))
+	if host.Compression {
+		args = append(args, "-C")
+	}
+
+	// SetEnv wymaga OpenSSH 7.8; serwer i tak przyjmie tylko zmienne z AcceptEnv.
+	// Wartość w cudzysłowie, żeby ssh nie dzielił jej na spacjach.
+	for _, name := range envNames(host.Env) {
+		args = append(args, "-o", fmt.Sprintf("SetEnv=%s=%q", name, host.Env[name]))
+	}
+
+	for _, spec := range host.LocalForwards {
+		args = append(args, "-L", spec)
+	}
+	for _, spec := range host.RemoteForwards {
+		args = append(args, "-R", spec)
+	}
+
+	if jumpHost != nil {
+		jump := net.JoinHostPort(jumpHost.IP, jumpHost.Port)
+		if jumpHost.Login != "" {
+			jump = jumpHost.Login + "@" + jump
+		}
+		args = append(args, "-J", jump)
+	}
+
+	return append(args, host.IP)
+}
@@ -0,0 +1,72 @@
+// internal/sync/api_url.go
+
+package sync
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// ApiURLEnv to zmienna środowiskowa z adresem własnego serwera synchronizacji;
+// ma pierwszeństwo przed polem api_url w konfiguracji
+const ApiURLEnv = "SSHM_API_URL"
+
+var (
+	apiBaseURL = ApiBaseURL // Adres API używany przez SyncWithAPI i PushToAPI
+	apiURLErr  error        // Błąd niepoprawnego adresu; blokuje synchronizację
+)
+
+// SetAPIBaseURL ustawia adres API z konfiguracji albo ze zmiennej SSHM_API_URL.
+// Pusty adres oznacza domyślny ApiBaseURL. Niepoprawny adres blokuje
+// synchronizację, aby dane nie trafiły na domyślny serwer przez pomyłkę.
+func SetAPIBaseURL(configured string) error {
+	raw := strings.TrimSpace(configured)
+	if env := strings.TrimSpace(os.Getenv(ApiURLEnv)); env != "" {
+		raw = env
+	}
+	if raw == "" {
+		apiBaseURL, apiURLErr = ApiBaseURL, nil
+		return nil
+	}
+
+	base, err := NormalizeAPIURL(raw)
+	if err != nil {
+		apiBaseURL, apiURLErr = "", err
+		return err
+	}
+	apiBaseURL, apiURLErr = base, nil
+	return nil
+}
+
+// NormalizeAPIURL sprawdza adres API (http lub https z nazwą hosta, bez
+// parametrów) i dopisuje końcowy "/", do którego doklejane są endpointy
+func NormalizeAPIURL(raw string) (string, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid API URL %q: %v", raw, err)
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return "", fmt.Errorf("invalid API URL %q: scheme must be http or https", raw)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("invalid API URL %q: missing host", raw)
+	}
+	if u.User != nil || u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("invalid API URL %q: credentials, query and fragment are not allowed", raw)
+	}
+	if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+		u.RawPath = ""
+	}
+	return u.String(), nil
+}
+
+// endpointURL zwraca pełny adres endpointu API
+func endpointURL(endpoint string) (string, error) {
+	if apiURLErr != nil {
+		return "", apiURLErr
+	}
+	return apiBaseURL + endpoint, nil
+}
@@ -0,0 +1,224 @@
+// internal/sync/backup.go
+
+package sync
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"sshManager/internal/utils"
+)
+
+const (
+	// BackupsDirName to katalog (obok pliku konfiguracji) z kopiami sprzed synchronizacji
+	BackupsDirName = "backups"
+
+	// MaxBackups to liczba przechowywanych kopii; starsze są usuwane
+	MaxBackups = 5
+
+	// backupKeysDir to podkatalog kopii z plikami kluczy
+	backupKeysDir = "keys"
+
+	// backupTimeFormat to format nazwy katalogu kopii; sortuje się chronologicznie
+	backupTimeFormat = "20060102-150405.000000000"
+)
+
+// Backup opisuje jedną kopię konfiguracji i kluczy
+type Backup struct {
+	Name       string    // Nazwa katalogu kopii
+	Path       string    // Pełna ścieżka katalogu kopii
+	ConfigFile string    // Ścieżka pliku konfiguracji w kopii
+	Time       time.Time // Czas utworzenia kopii
+	Hosts      int       // Liczba hostów w kopii
+	Valid      bool      // false, gdy plik konfiguracji w kopii jest nieczytelny
+}
+
+// backupsDir zwraca katalog kopii dla pliku konfiguracji
+func backupsDir(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), BackupsDirName)
+}
+
+// CreateBackup zapisuje kopię pliku konfiguracji i kluczy w nowym katalogu
+// backups/<czas> i usuwa kopie starsze niż ostatnie MaxBackups
+func CreateBackup(configPath, keysDir string) (Backup, error) {
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		return Backup{}, fmt.Errorf("error reading config file: %v", err)
+	}
+
+	now := time.Now()
+	name := now.Format(backupTimeFormat)
+	dir := filepath.Join(backupsDir(configPath), name)
+	if err := os.MkdirAll(filepath.Join(dir, backupKeysDir), 0700); err != nil {
+		return Backup{}, fmt.Errorf("error creating backup directory: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, filepath.Base(configPath)), content, 0600); err != nil {
+		return Backup{}, fmt.Errorf("error creating backup file: %v", err)
+	}
+	if err := copyKeyFiles(keysDir, filepath.Join(dir, backupKeysDir)); err != nil {
+		os.RemoveAll(dir)
+		return Backup{}, err
+	}
+
+	if err := pruneBackups(configPath); err != nil {
+		return Backup{}, err
+	}
+	return readBackup(configPath, name, now), nil
+}
+
+// ListBackups zwraca dostępne kopie, od najnowszej
+func ListBackups(configPath string) ([]Backup, error) {
+	names, err := backupNames(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	backups := make([]Backup, 0, len(names))
+	for i := len(names) - 1; i >= 0; i-- {
+		created, _ := time.ParseInLocation(backupTimeFormat, names[i], time.Local)
+		backups = append(backups, readBackup(configPath, names[i], created))
+	}
+	return backups, nil
+}
+
+// RestoreBackup zastępuje plik konfiguracji i klucze zawartością kopii.
+// Kopia z nieczytelnym plikiem konfiguracji jest odrzucana.
+func RestoreBackup(configPath, keysDir string, backup Backup) error {
+	content, err := os.ReadFile(backup.ConfigFile)
+	if err != nil {
+		return fmt.Errorf("error reading backup %s: %v", backup.Name, err)
+	}
+	if !json.Valid(content) {
+		return fmt.Errorf("backup %s is corrupt", backup.Name)
+	}
+
+	if err := utils.WriteFileAtomic(configPath, content, 0600); err != nil {
+		return fmt.Errorf("error restoring config from backup: %v", err)
+	}
+
+	// Klucze z kopii zastępują wszystkie obecne pliki kluczy
+	if err := os.MkdirAll(keysDir, 0700); err != nil {
+		return fmt.Errorf("error creating keys directory: %v", err)
+	}
+	entries, err := os.ReadDir(keysDir)
+	if err != nil {
+		return fmt.Errorf("error reading keys directory: %v", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) == ".old" {
+			continue
+		}
+		if err := os.Remove(filepath.Join(keysDir, entry.Name())); err != nil {
+			return fmt.Errorf("error removing key file %s: %v", entry.Name(), err)
+		}
+	}
+	if err := copyKeyFiles(filepath.Join(backup.Path, backupKeysDir), keysDir); err != nil {
+		return fmt.Errorf("error restoring keys from backup: %v", err)
+	}
+	return nil
+}
+
+// RestoreFromBackup przywraca najnowszą czytelną kopię, np. gdy danych z API
+// nie udało się zapisać; uszkodzone kopie są pomijane
+func RestoreFromBackup(configPath, keysDir string) error {
+	backups, err := ListBackups(configPath)
+	if err != nil {
+		return err
+	}
+	for _, backup := range backups {
+		if backup.Valid {
+			return RestoreBackup(configPath, keysDir, backup)
+		}
+	}
+	return errors.New("no usable backup found")
+}
+
+// readBackup opisuje kopię o podanej nazwie
+func readBackup(configPath, name string, created time.Time) Backup {
+	path := filepath.Join(backupsDir(configPath), name)
+	backup := Backup{
+		Name:       name,
+		Path:       path,
+		ConfigFile: filepath.Join(path, filepath.Base(configPath)),
+		Time:       created,
+	}
+
+	var content struct {
+		Hosts []json.RawMessage `json:"hosts"`
+	}
+	data, err := os.ReadFile(backup.ConfigFile)
+	if err == nil && json.Unmarshal(data, &content) == nil {
+		backup.Hosts = len(content.Hosts)
+		backup.Valid = true
+	}
+	return backup
+}
+
+// backupNames zwraca nazwy katalogów kopii, od najstarszej
+func backupNames(configPath string) ([]string, error) {
+	entries, err := os.ReadDir(backupsDir(configPath))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading backups directory: %v", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := time.Parse(backupTimeFormat, entry.Name()); err == nil {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// pruneBackups usuwa najstarsze kopie ponad MaxBackups
+func pruneBackups(configPath string) error {
+	names, err := backupNames(configPath)
+	if err != nil {
+		return err
+	}
+	for len(names) > MaxBackups {
+		if err := os.RemoveAll(filepath.Join(backupsDir(configPath), names[0])); err != nil {
+			return fmt.Errorf("error removing old backup %s: %v", names[0], err)
+		}
+		names = names[1:]
+	}
+	return nil
+}
+
+// copyKeyFiles kopiuje pliki kluczy (bez podkatalogów i starych kopii .old)
+func copyKeyFiles(srcDir, dstDir string) error {
+	entries, err := os.ReadDir(srcDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("error reading keys directory: %v", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) == ".old" {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(srcDir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("error reading key file %s: %v", entry.Name(), err)
+		}
+		if err := os.WriteFile(filepath.Join(dstDir, entry.Name()), content, KeyFilePerms); err != nil {
+			return fmt.Errorf("error creating key backup %s: %v", entry.Name(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+// internal/sync/http.go
+
+package sync
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	apiTimeout       = 30 * time.Second // Limit czasu jednego zapytania do API
+	apiAttempts      = 3                // Liczba prób przy błędach przejściowych
+	apiRetryDelay    = time.Second      // Opóźnienie przed drugą próbą, potem podwajane
+	maxErrorBodySize = 200              // Tyle znaków odpowiedzi trafia do komunikatu błędu
+)
+
+// apiClient ma limit czasu, aby zawieszony serwer nie blokował startu aplikacji
+var apiClient = &http.Client{Timeout: apiTimeout}
+
+// APIError to odpowiedź API z kodem innym niż 200
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	switch e.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Sprintf("API key rejected (status %d): %s", e.StatusCode, e.Body)
+	default:
+		return fmt.Sprintf("API returned status code %d: %s", e.StatusCode, e.Body)
+	}
+}
+
+// isTransient określa czy warto ponowić zapytanie: błędy sieci i 5xx tak,
+// 4xx (np. zły klucz API) nie
+func isTransient(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500
+	}
+	return true
+}
+
+// doAPIRequest wysyła zapytanie do API z kluczem i zwraca treść odpowiedzi 200.
+// Błędy przejściowe są ponawiane z wykładniczo rosnącym opóźnieniem.
+func doAPIRequest(method, endpoint, apiKey string, body []byte) ([]byte, error) {
+	delay := apiRetryDelay
+	var lastErr error
+	for attempt := 1; attempt <= apiAttempts; attempt++ {
+		respBody, err := doAPIRequestOnce(method, endpoint, apiKey, body)
+		if err == nil {
+			return respBody, nil
+		}
+		lastErr = err
+		if !isTransient(err) {
+			return nil, err
+		}
+		if attempt < apiAttempts {
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+	return nil, fmt.Errorf("%v (gave up after %d attempts)", lastErr, apiAttempts)
+}
+
+// doAPIRequestOnce wykonuje jedno zapytanie do API
+func doAPIRequestOnce(method, endpoint, apiKey string, body []byte) ([]byte, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, endpoint, reader)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+
+	req.Header.Set("X-Api-Key", apiKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := apiClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: shortenBody(respBody)}
+	}
+	return respBody, nil
+}
+
+// shortenBody skraca treść odpowiedzi (np. stronę HTML z błędem) do komunikatu
+func shortenBody(body []byte) string {
+	text := []rune(strings.Join(strings.Fields(string(body)), " "))
+	if len(text) > maxErrorBodySize {
+		return string(text[:maxErrorBodySize]) + "..."
+	}
+	return string(text)
+}
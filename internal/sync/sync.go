@@ -1,15 +1,13 @@
 package sync
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"path/filepath"
 	"sshManager/internal/crypto"
 	"sshManager/internal/models"
+	"sshManager/internal/utils"
 	"strconv"
 	"strings"
 	"time"
@@ -17,7 +15,7 @@ import (
 )
 
 const (
-	ApiBaseURL   = "https://sshm.io/api/v1/"
+	ApiBaseURL   = "https://sshm.io/api/v1/" // Domyślny adres API (zob. SetAPIBaseURL)
 	KeyFilePerms = 0600
 )
 
@@ -53,72 +51,16 @@ func BackupConfigFile(configPath string) error {
 	return nil
 }
 
-func BackupKeys(keysDir string) error {
-	// Sprawdź czy katalog kluczy istnieje
-	if _, err := os.Stat(keysDir); os.IsNotExist(err) {
-		return nil
-	}
-
-	entries, err := os.ReadDir(keysDir)
-	if err != nil {
-		return fmt.Errorf("error reading keys directory: %v", err)
-	}
-
-	// Najpierw usuń stare backupy (.old)
-	for _, entry := range entries {
-		if filepath.Ext(entry.Name()) == ".old" {
-			oldPath := filepath.Join(keysDir, entry.Name())
-			if err := os.Remove(oldPath); err != nil {
-				return fmt.Errorf("error removing old backup %s: %v", entry.Name(), err)
-			}
-		}
-	}
-
-	// Teraz twórz nowe backupy
-	for _, entry := range entries {
-		if entry.IsDir() || filepath.Ext(entry.Name()) == ".old" {
-			continue
-		}
-
-		originalPath := filepath.Join(keysDir, entry.Name())
-		backupPath := originalPath + ".old"
-
-		content, err := os.ReadFile(originalPath)
-		if err != nil {
-			return fmt.Errorf("error reading key file %s: %v", entry.Name(), err)
-		}
-
-		if err := os.WriteFile(backupPath, content, 0600); err != nil {
-			return fmt.Errorf("error creating key backup %s: %v", entry.Name(), err)
-		}
-	}
-
-	return nil
-}
-
 // SyncWithAPI synchronizuje dane z API
 func SyncWithAPI(apiKey string) (*SyncResponse, error) {
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", ApiBaseURL+"/sync", nil)
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %v", err)
-	}
-
-	req.Header.Set("X-Api-Key", apiKey)
-
-	resp, err := client.Do(req)
+	endpoint, err := endpointURL("sync")
 	if err != nil {
-		return nil, fmt.Errorf("error making request: %v", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := doAPIRequest("GET", endpoint, apiKey, nil)
 	if err != nil {
-		return nil, fmt.Errorf("error reading response: %v", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status code %d: %s", resp.StatusCode, body)
+		return nil, err
 	}
 
 	var syncResp SyncResponse
@@ -132,16 +74,15 @@ func SyncWithAPI(apiKey string) (*SyncResponse, error) {
 func SaveAPIData(configPath, keysDir string, data SyncData, cipher *crypto.Cipher) error {
 	fmt.Printf("Starting SaveAPIData - config: %s, keys dir: %s\n", configPath, keysDir)
 
-	// Przygotuj strukturę danych do lokalnego zapisu
-	config := struct {
-		Hosts     []models.Host     `json:"hosts"`
-		Passwords []models.Password `json:"passwords"`
-		Keys      []models.Key      `json:"keys"`
-	}{
-		Hosts:     make([]models.Host, 0),
-		Passwords: make([]models.Password, 0),
-		Keys:      make([]models.Key, 0),
-	}
+	// API przechowuje tylko hosty, hasła i klucze; wszystko inne to ustawienia
+	// lokalne. Zaczynamy od istniejącego pliku i podmieniamy tylko dane z API,
+	// więc każde pole models.Config spoza nich przetrwa synchronizację.
+	local := loadLocalState(configPath)
+	config := local.Config
+	config.Hosts = make([]models.Host, 0)
+	config.Passwords = make([]models.Password, 0)
+	config.Keys = make([]models.Key, 0)
+	config.LastSync = time.Now()
 
 	// Przetwarzanie hostów
 	for _, h := range data.Hosts {
@@ -161,6 +102,16 @@ func SaveAPIData(configPath, keysDir string, data SyncData, cipher *crypto.Ciphe
 			return fmt.Errorf("failed to decrypt description: %v", err)
 		}
 
+		// Notatki mogą zawierać poufne informacje, więc są szyfrowane jak opis;
+		// starsze dane z API ich nie mają
+		var notes string
+		if encryptedNotes := getStringValue(hostMap, "notes"); encryptedNotes != "" {
+			notes, err = cipher.Decrypt(encryptedNotes)
+			if err != nil {
+				return fmt.Errorf("failed to decrypt notes: %v", err)
+			}
+		}
+
 		login, err := cipher.Decrypt(getStringValue(hostMap, "login"))
 		if err != nil {
 			return fmt.Errorf("failed to decrypt login: %v", err)
@@ -178,12 +129,42 @@ func SaveAPIData(configPath, keysDir string, data SyncData, cipher *crypto.Ciphe
 
 		// Tworzenie obiektu hosta z odszyfrowanymi danymi
 		host := models.Host{
-			Name:        name,
-			Description: description,
-			Login:       login,
-			IP:          ip,
-			Port:        port,
-			PasswordID:  getIntValue(hostMap, "password_id"),
+			Name:              name,
+			Description:       description,
+			Notes:             notes,
+			Login:             login,
+			IP:                ip,
+			Port:              port,
+			PasswordID:        getIntValue(hostMap, "password_id"),
+			TerminalType:      getStringValue(hostMap, "terminal_type"),
+			Compression:       getBoolValue(hostMap, "compression"),
+			LogSession:        getBoolValue(hostMap, "log_session"),
+			UseSystemSSH:      getBoolValue(hostMap, "use_system_ssh"),
+			Group:             getStringValue(hostMap, "group"),
+			Environment:       getStringValue(hostMap, "environment"),
+			Tags:              getStringSliceValue(hostMap, "tags"),
+			JumpHost:          getStringValue(hostMap, "jump_host"),
+			LocalForwards:     getStringSliceValue(hostMap, "local_forwards"),
+			RemoteForwards:    getStringSliceValue(hostMap, "remote_forwards"),
+			ConnectTimeout:    getIntValue(hostMap, "connect_timeout"),
+			KeepAliveInterval: getIntValue(hostMap, "keep_alive_interval"),
+			ReconnectAttempts: getIntValue(hostMap, "reconnect_attempts"),
+			InitCommands:      getStringSliceValue(hostMap, "init_commands"),
+			Env:               getStringMapValue(hostMap, "env"),
+			RemoteDir:         getStringValue(hostMap, "remote_dir"),
+			Algorithms: models.Algorithms{
+				HostKeyAlgorithms: getStringSliceValue(hostMap, "host_key_algorithms"),
+				Ciphers:           getStringSliceValue(hostMap, "ciphers"),
+				KeyExchanges:      getStringSliceValue(hostMap, "key_exchanges"),
+			},
+		}
+		host.SetAuthIDs(getIntSliceValue(hostMap, "auth_ids"))
+		if previous, ok := local.host(name); ok {
+			host.LastConnected = previous.LastConnected
+			host.ConnectCount = previous.ConnectCount
+			// Polecenia lokalne nie są synchronizowane, aby dane z API nie mogły
+			// uruchamiać poleceń na tym komputerze
+			host.PreConnectCommand = previous.PreConnectCommand
 		}
 		config.Hosts = append(config.Hosts, host)
 	}
@@ -210,9 +191,11 @@ func SaveAPIData(configPath, keysDir string, data SyncData, cipher *crypto.Ciphe
 		}
 
 		key := models.Key{
+			ID:          getIntValue(keyMap, "id"),
 			Description: getStringValue(keyMap, "description"),
 			Path:        getStringValue(keyMap, "path"),
 			KeyData:     getStringValue(keyMap, "key_data"),
+			UseAgent:    getBoolValue(keyMap, "use_agent"),
 		}
 		config.Keys = append(config.Keys, key)
 	}
@@ -223,7 +206,7 @@ func SaveAPIData(configPath, keysDir string, data SyncData, cipher *crypto.Ciphe
 		return fmt.Errorf("error marshaling config data: %v", err)
 	}
 
-	if err := os.WriteFile(configPath, jsonData, 0600); err != nil {
+	if err := utils.WriteFileAtomic(configPath, jsonData, 0600); err != nil {
 		return fmt.Errorf("error saving config file: %v", err)
 	}
 
@@ -286,7 +269,7 @@ func SaveAPIData(configPath, keysDir string, data SyncData, cipher *crypto.Ciphe
 		// Próba zapisu z powtórzeniami
 		var writeErr error
 		for attempts := 0; attempts < 3; attempts++ {
-			if err := os.WriteFile(keyPath, []byte(keyContent), KeyFilePerms); err != nil {
+			if err := utils.WriteFileAtomic(keyPath, []byte(keyContent), KeyFilePerms); err != nil {
 				writeErr = err
 				time.Sleep(100 * time.Millisecond)
 				continue
@@ -316,42 +299,45 @@ func SaveAPIData(configPath, keysDir string, data SyncData, cipher *crypto.Ciphe
 	return nil
 }
 
-// Funkcja pomocnicza do sanityzacji nazw plików
-func sanitizeFilename(filename string) string {
-	return strings.Map(func(r rune) rune {
-		if unicode.IsLetter(r) || unicode.IsNumber(r) || r == '-' || r == '_' {
-			return r
-		}
-		return '_'
-	}, filename)
+// localState to istniejący lokalny plik konfiguracji; jego ustawienia lokalne
+// i lokalne dane hostów przenosimy do konfiguracji pobranej z API
+type localState struct {
+	models.Config
 }
 
-func RestoreFromBackup(configPath, keysDir string) error {
-	// Przywróć plik konfiguracyjny
-	backupConfigPath := configPath + ".old"
-	if _, err := os.Stat(backupConfigPath); err == nil {
-		if err := os.Rename(backupConfigPath, configPath); err != nil {
-			return fmt.Errorf("error restoring config from backup: %v", err)
+// host zwraca lokalną wersję hosta o podanej nazwie (ze statystykami połączeń
+// i poleceniem uruchamianym przed połączeniem)
+func (s localState) host(name string) (models.Host, bool) {
+	for _, host := range s.Hosts {
+		if host.Name == name {
+			return host, true
 		}
 	}
+	return models.Host{}, false
+}
 
-	// Przywróć klucze
-	entries, err := os.ReadDir(keysDir)
+// loadLocalState odczytuje lokalne dane z istniejącego pliku konfiguracji;
+// nie są one synchronizowane, więc muszą przetrwać pobranie danych z API
+func loadLocalState(configPath string) localState {
+	var local localState
+	data, err := os.ReadFile(configPath)
 	if err != nil {
-		return fmt.Errorf("error reading keys directory: %v", err)
+		return local
 	}
-
-	for _, entry := range entries {
-		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".old" {
-			oldPath := filepath.Join(keysDir, entry.Name())
-			newPath := oldPath[:len(oldPath)-4] // usuń '.old'
-			if err := os.Rename(oldPath, newPath); err != nil {
-				return fmt.Errorf("error restoring key %s from backup: %v", entry.Name(), err)
-			}
-		}
+	if err := json.Unmarshal(data, &local); err != nil {
+		return localState{}
 	}
+	return local
+}
 
-	return nil
+// Funkcja pomocnicza do sanityzacji nazw plików
+func sanitizeFilename(filename string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, filename)
 }
 
 // Uproszczona funkcja do pobierania wartości string z mapy
@@ -373,6 +359,65 @@ func getStringValue(m map[string]interface{}, key string) string {
 	return ""
 }
 
+// Uproszczona funkcja do pobierania wartości bool z mapy
+func getBoolValue(m map[string]interface{}, key string) bool {
+	switch v := m[key].(type) {
+	case bool:
+		return v
+	case string:
+		b, _ := strconv.ParseBool(v)
+		return b
+	case float64:
+		return v != 0
+	}
+	return false
+}
+
+// Uproszczona funkcja do pobierania listy stringów z mapy
+func getStringSliceValue(m map[string]interface{}, key string) []string {
+	values, ok := m[key].([]interface{})
+	if !ok {
+		return nil
+	}
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// Uproszczona funkcja do pobierania mapy stringów z mapy
+func getStringMapValue(m map[string]interface{}, key string) map[string]string {
+	values, ok := m[key].(map[string]interface{})
+	if !ok || len(values) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(values))
+	for k, v := range values {
+		if s, ok := v.(string); ok {
+			result[k] = s
+		}
+	}
+	return result
+}
+
+// Uproszczona funkcja do pobierania listy liczb z mapy
+func getIntSliceValue(m map[string]interface{}, key string) []int {
+	values, ok := m[key].([]interface{})
+	if !ok {
+		return nil
+	}
+	result := make([]int, 0, len(values))
+	for _, v := range values {
+		if f, ok := v.(float64); ok {
+			result = append(result, int(f))
+		}
+	}
+	return result
+}
+
 // Uproszczona funkcja do pobierania wartości int z mapy
 func getIntValue(m map[string]interface{}, key string) int {
 	if val, ok := m[key]; ok {
@@ -435,6 +480,14 @@ func PushToAPI(apiKey string, configPath, keysDir string, cipher *crypto.Cipher)
 			return fmt.Errorf("error encrypting description: %v", err)
 		}
 
+		var encryptedNotes string
+		if host.Notes != "" {
+			encryptedNotes, err = cipher.Encrypt(host.Notes)
+			if err != nil {
+				return fmt.Errorf("error encrypting notes: %v", err)
+			}
+		}
+
 		encryptedLogin, err := cipher.Encrypt(host.Login)
 		if err != nil {
 			return fmt.Errorf("error encrypting login: %v", err)
@@ -452,15 +505,34 @@ func PushToAPI(apiKey string, configPath, keysDir string, cipher *crypto.Cipher)
 
 		// Przygotowanie mapy z zaszyfrowanymi danymi
 		hostData := map[string]interface{}{
-			"name":          encryptedName,
-			"description":   encryptedDescription,
-			"login":         encryptedLogin,
-			"ip":            encryptedIP,
-			"port":          encryptedPort,
-			"password_id":   host.PasswordID,
-			"terminal_type": host.TerminalType,
-			"keep_alive":    host.KeepAlive,
-			"compression":   host.Compression,
+			"name":                encryptedName,
+			"description":         encryptedDescription,
+			"notes":               encryptedNotes,
+			"login":               encryptedLogin,
+			"ip":                  encryptedIP,
+			"port":                encryptedPort,
+			"password_id":         host.PasswordID,
+			"auth_ids":            host.AuthIDs,
+			"terminal_type":       host.TerminalType,
+			"keep_alive":          host.KeepAlive,
+			"compression":         host.Compression,
+			"log_session":         host.LogSession,
+			"use_system_ssh":      host.UseSystemSSH,
+			"group":               host.Group,
+			"environment":         host.Environment,
+			"tags":                host.Tags,
+			"jump_host":           host.JumpHost,
+			"local_forwards":      host.LocalForwards,
+			"remote_forwards":     host.RemoteForwards,
+			"connect_timeout":     host.ConnectTimeout,
+			"keep_alive_interval": host.KeepAliveInterval,
+			"reconnect_attempts":  host.ReconnectAttempts,
+			"init_commands":       host.InitCommands,
+			"env":                 host.Env,
+			"remote_dir":          host.RemoteDir,
+			"host_key_algorithms": host.HostKeyAlgorithms,
+			"ciphers":             host.Ciphers,
+			"key_exchanges":       host.KeyExchanges,
 		}
 		payload.Data.Hosts = append(payload.Data.Hosts, hostData)
 	}
@@ -477,9 +549,11 @@ func PushToAPI(apiKey string, configPath, keysDir string, cipher *crypto.Cipher)
 	// Przygotowanie kluczy do wysyłki
 	for _, key := range localData.Keys {
 		keyData := map[string]interface{}{
+			"id":          key.ID,
 			"description": key.Description,
 			"key_data":    key.KeyData,
 			"path":        key.Path,
+			"use_agent":   key.UseAgent,
 		}
 		payload.Data.Keys = append(payload.Data.Keys, keyData)
 	}
@@ -490,30 +564,13 @@ func PushToAPI(apiKey string, configPath, keysDir string, cipher *crypto.Cipher)
 		return fmt.Errorf("error preparing data for API: %v", err)
 	}
 
-	// Przygotowanie i wykonanie requestu HTTP
-	client := &http.Client{}
-	req, err := http.NewRequest("POST", ApiBaseURL+"sync", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("error creating request: %v", err)
-	}
-
-	req.Header.Set("X-Api-Key", apiKey)
-	req.Header.Set("Content-Type", "application/json")
-
-	// Wykonanie zapytania
-	resp, err := client.Do(req)
+	// Wysłanie danych (z ponowieniami przy błędach przejściowych)
+	endpoint, err := endpointURL("sync")
 	if err != nil {
-		return fmt.Errorf("error making request: %v", err)
-	}
-	defer resp.Body.Close()
-
-	// Sprawdzenie odpowiedzi
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API returned error status %d: %s", resp.StatusCode, body)
+		return err
 	}
-
-	return nil
+	_, err = doAPIRequest("POST", endpoint, apiKey, jsonData)
+	return err
 }
 
 func normalizeKeyContent(content string) string {
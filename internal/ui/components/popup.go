@@ -19,6 +19,24 @@ const (
 	PopupMessage
 	PopupKeyEdit
 	PopupSessionEnded
+	PopupSelectKey
+	PopupOverwrite
+	PopupChmod
+	PopupBookmarks
+	PopupGoTo
+	PopupConfirmConnect
+	PopupSelectTheme
+	PopupHostKeyChanged
+	PopupAuthPrompt
+	PopupBanner
+	PopupSelectBackup
+	PopupConfirmRestore
+	PopupNewFile
+	PopupRemoteCopy
+	PopupRateLimit
+	PopupConfirmCopy
+	PopupSelectTag
+	PopupSelectProfile
 )
 
 type Popup struct {
@@ -50,10 +68,16 @@ func NewPopup(popupType PopupType, title, message string, width, height, screenW
 }
 
 func (p *Popup) Render() string {
+	// Ostrzeżenie o zmienionym kluczu hosta ma czerwoną ramkę
+	borderColor := ui.Border
+	if p.Type == PopupHostKeyChanged {
+		borderColor = ui.Error
+	}
+
 	// Style dla popupu
 	popupStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(ui.Border).
+		BorderForeground(borderColor).
 		Padding(1, 2).
 		Width(p.Width).
 		Height(p.Height)
@@ -69,17 +93,42 @@ func (p *Popup) Render() string {
 	content.WriteString(p.Message + "\n")
 
 	// Dodaj pole input dla promptów wymagających wprowadzenia tekstu
-	if p.Type == PopupRename || p.Type == PopupMkdir {
+	if p.Type == PopupRename || p.Type == PopupMkdir || p.Type == PopupChmod || p.Type == PopupGoTo ||
+		p.Type == PopupAuthPrompt || p.Type == PopupNewFile || p.Type == PopupRemoteCopy || p.Type == PopupRateLimit {
 		content.WriteString("\n" + p.Input.View())
 	}
 
 	// Dodaj informację o klawiszach
 	var keys string
 	switch p.Type {
-	case PopupDelete, PopupHostKey:
+	case PopupDelete, PopupHostKey, PopupConfirmConnect, PopupConfirmRestore:
 		keys = "y - Yes, n - No"
 	case PopupMessage:
 		keys = "ESC/ENTER - Close"
+	case PopupOverwrite:
+		keys = "ESC - Cancel copy"
+	case PopupSelectKey:
+		keys = "↑/↓ - Select, ENTER - Install, ESC - Cancel"
+	case PopupSelectTheme:
+		keys = "↑/↓ - Preview, ENTER - Apply, ESC - Cancel"
+	case PopupSelectTag:
+		keys = "↑/↓ - Select, ENTER - Filter, ESC - Cancel"
+	case PopupSelectProfile:
+		keys = "↑/↓ - Select, ENTER - Switch, ESC - Cancel"
+	case PopupHostKeyChanged:
+		keys = "K - Open known hosts, ESC - Cancel"
+	case PopupBanner:
+		keys = "ENTER - Continue, ESC - Disconnect"
+	case PopupSelectBackup:
+		keys = "↑/↓ - Select, ENTER - Restore, ESC - Cancel"
+	case PopupBookmarks:
+		keys = "↑/↓ - Select, ENTER - Go, d - Delete, ESC - Cancel"
+	case PopupGoTo:
+		keys = "ENTER - Go, TAB - Complete, ESC - Cancel"
+	case PopupRemoteCopy:
+		keys = "ENTER - Copy, ESC - Cancel"
+	case PopupConfirmCopy:
+		keys = "y/ENTER - Copy, n/ESC - Cancel"
 	default:
 		keys = "ENTER - Confirm, ESC - Cancel"
 	}
@@ -0,0 +1,116 @@
+// internal/ui/keymap.go
+
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// KeyMap definiuje klawisze nawigacji wspólne dla wszystkich widoków.
+// Widoki nie sprawdzają klawiszy strzałek bezpośrednio, tylko tłumaczą
+// wciśnięty klawisz przez VerticalKey/NavKey, dzięki czemu w/s, j/k i h/l
+// działają wszędzie tak samo.
+type KeyMap struct {
+	Up    key.Binding
+	Down  key.Binding
+	Left  key.Binding
+	Right key.Binding
+}
+
+// DefaultKeyMap zwraca domyślne ustawienia klawiszy
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Up:    newNavBinding("up", "up", "w", "k"),
+		Down:  newNavBinding("down", "down", "s", "j"),
+		Left:  newNavBinding("left", "left", "h"),
+		Right: newNavBinding("right", "right", "l"),
+	}
+}
+
+// newNavBinding tworzy skrót z opisem pomocy zbudowanym z jego klawiszy,
+// dzięki czemu ekran pomocy zawsze pokazuje faktycznie przypisane klawisze
+func newNavBinding(name string, keys ...string) key.Binding {
+	return key.NewBinding(
+		key.WithKeys(keys...),
+		key.WithHelp(KeyLabel(keys...), name),
+	)
+}
+
+// arrowLabels to symbole strzałek wyświetlane zamiast nazw klawiszy
+var arrowLabels = map[string]string{"up": "↑", "down": "↓", "left": "←", "right": "→"}
+
+// KeyLabel zapisuje klawisze w formie do wyświetlenia, np. "↑/w/k" albo "ctrl+↑"
+func KeyLabel(keys ...string) string {
+	labels := make([]string, len(keys))
+	for i, k := range keys {
+		// Strzałką zastępujemy tylko nazwę klawisza, bez modyfikatorów (ctrl+, alt+)
+		modifiers, name := "", k
+		if i := strings.LastIndex(k, "+"); i > 0 && i < len(k)-1 {
+			modifiers, name = k[:i+1], k[i+1:]
+		}
+		if arrow, ok := arrowLabels[name]; ok {
+			name = arrow
+		}
+		labels[i] = modifiers + name
+	}
+	return strings.Join(labels, "/")
+}
+
+// bindings zwraca skróty według nazw używanych w pliku konfiguracji
+func (k *KeyMap) bindings() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"up":    &k.Up,
+		"down":  &k.Down,
+		"left":  &k.Left,
+		"right": &k.Right,
+	}
+}
+
+// Apply nadpisuje klawisze ustawieniami z pliku konfiguracji (key_bindings).
+// Nieznane nazwy i puste listy są pomijane i zgłaszane w zwróconym błędzie.
+func (k *KeyMap) Apply(overrides map[string][]string) error {
+	bindings := k.bindings()
+	var invalid []string
+	for name, keys := range overrides {
+		binding, ok := bindings[name]
+		if !ok || len(keys) == 0 {
+			invalid = append(invalid, name)
+			continue
+		}
+		*binding = newNavBinding(name, keys...)
+	}
+	if len(invalid) > 0 {
+		sort.Strings(invalid)
+		return fmt.Errorf("invalid key bindings ignored: %s", strings.Join(invalid, ", "))
+	}
+	return nil
+}
+
+// VerticalKey zwraca "up" albo "down", gdy klawisz jest przypisany do ruchu
+// w górę lub w dół; inne klawisze zwraca bez zmian (msg.String())
+func (k KeyMap) VerticalKey(msg tea.KeyMsg) string {
+	switch {
+	case key.Matches(msg, k.Up):
+		return "up"
+	case key.Matches(msg, k.Down):
+		return "down"
+	}
+	return msg.String()
+}
+
+// NavKey działa jak VerticalKey, ale tłumaczy także ruch w lewo i w prawo
+// ("left"/"right"); do użycia tylko tam, gdzie h/l nie mają innej funkcji
+func (k KeyMap) NavKey(msg tea.KeyMsg) string {
+	switch {
+	case key.Matches(msg, k.Left):
+		return "left"
+	case key.Matches(msg, k.Right):
+		return "right"
+	}
+	return k.VerticalKey(msg)
+}
@@ -1,6 +1,7 @@
 package messages
 
 type PasswordEnteredMsg string
+type PasswordRejectedMsg string
 
 type HostKeyVerificationMsg struct {
 	IP          string
@@ -15,5 +16,8 @@ type ApiKeyEnteredMsg struct {
 
 type HostKeyResponseMsg bool
 type ReloadAppMsg struct{}
+
+// SwitchProfileMsg restartuje aplikację z innym profilem konfiguracji
+type SwitchProfileMsg string
 type ShellExitedMsg struct{}
 type SessionEndedMsg struct{}
@@ -3,75 +3,23 @@
 package ui
 
 import (
+	"crypto/subtle"
 	"fmt"
 	"os"
+	"os/exec"
+	"slices"
 	"sshManager/internal/config"
 	"sshManager/internal/crypto"
 	"sshManager/internal/models"
 	"sshManager/internal/ssh"
+	"sync"
 
-	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"golang.org/x/term"
 )
 
-// KeyMap definiuje skróty klawiszowe
-type KeyMap struct {
-	Up       key.Binding
-	Down     key.Binding
-	Enter    key.Binding
-	Back     key.Binding
-	Quit     key.Binding
-	Edit     key.Binding
-	Connect  key.Binding
-	Transfer key.Binding
-	Refresh  key.Binding
-}
-
-// DefaultKeyMap zwraca domyślne ustawienia klawiszy
-func DefaultKeyMap() KeyMap {
-	return KeyMap{
-		Up: key.NewBinding(
-			key.WithKeys("up", "w"),
-			key.WithHelp("↑/w", "up"),
-		),
-		Down: key.NewBinding(
-			key.WithKeys("down", "s"),
-			key.WithHelp("↓/s", "down"),
-		),
-		Enter: key.NewBinding(
-			key.WithKeys("enter"),
-			key.WithHelp("enter", "select"),
-		),
-		Back: key.NewBinding(
-			key.WithKeys("esc"),
-			key.WithHelp("esc", "back"),
-		),
-		Quit: key.NewBinding(
-			key.WithKeys("q", "ctrl+c"),
-			key.WithHelp("q", "quit"),
-		),
-		Edit: key.NewBinding(
-			key.WithKeys("e"),
-			key.WithHelp("e", "edit"),
-		),
-		Connect: key.NewBinding(
-			key.WithKeys("c"),
-			key.WithHelp("c", "connect"),
-		),
-		Transfer: key.NewBinding(
-			key.WithKeys("t"),
-			key.WithHelp("t", "transfer"),
-		),
-		Refresh: key.NewBinding(
-			key.WithKeys("r"),
-			key.WithHelp("r", "refresh"),
-		),
-	}
-}
-
 // Status reprezentuje stan aplikacji
 type Status struct {
 	Message string
@@ -98,6 +46,7 @@ type Model struct {
 	activeView     View
 	sshClient      *ssh.SSHClient // tylko dla trybu SSH
 	transfer       *ssh.FileTransfer
+	connection     *ssh.Connection // Połączenie z hostem wspólne dla powłoki i transferu
 	hosts          []models.Host
 	passwords      []models.Password
 	selectedHost   *models.Host
@@ -114,7 +63,16 @@ type Model struct {
 	terminalHeight int
 	selectedItems  map[string]bool // mapa przechowująca zaznaczone elementy (klucz: ścieżka pliku)
 	localMode      bool            // true jeśli pracujemy bez synchronizacji
+	syncErr        error           // Błąd synchronizacji przy starcie, pokazywany w głównym widoku
+	pushRequested  bool            // Zapisano zmiany, które należy wysłać do API w tle
+	pushErr        error           // Błąd ostatniego wysyłania zmian do API
+	lastDeleted    *deletedItem    // Ostatnio usunięty element do cofnięcia (Ctrl+Z)
 
+	// mutex chroni stan, którego używają także gorutyny widoków (np. transfer
+	// plików w tle): hosts, passwords, selectedHost, transfer, cipher, selectedItems
+	// i lastDeleted; wskaźnik, bo metody tea.Model kopiują Model. Listy hostów
+	// i haseł są podmieniane w całości, a gettery zwracają ich kopie
+	mutex *sync.RWMutex
 }
 
 // Init implementuje tea.Model
@@ -163,7 +121,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		newListModel, cmd := m.hostList.Update(msg)
 		m.hostList = newListModel
 		if item, ok := m.hostList.SelectedItem().(HostItem); ok {
-			m.selectedHost = &item.host
+			m.SetSelectedHost(&item.host)
 		}
 		return m, cmd
 	case ViewPasswordList:
@@ -222,6 +180,8 @@ func NewModel() *Model {
 		terminalWidth:  width,  // Dodane
 		terminalHeight: height, // Dodane
 		selectedItems:  make(map[string]bool),
+		connection:     ssh.NewConnection(),
+		mutex:          &sync.RWMutex{},
 	}
 
 	// Wczytaj zapisaną konfigurację
@@ -229,6 +189,18 @@ func NewModel() *Model {
 		m.SetStatus(fmt.Sprintf("Warning: %v", err), true)
 	}
 
+	// Przywróć zapisany motyw; nieznana nazwa zostawia motyw domyślny
+	if theme := configManager.GetTheme(); theme != "" {
+		if err := SetThemeByName(theme); err != nil {
+			m.SetStatus(fmt.Sprintf("Warning: %v", err), true)
+		}
+	}
+
+	// Własne klawisze nawigacji z pliku konfiguracji
+	if err := m.keys.Apply(configManager.GetKeyBindings()); err != nil {
+		m.SetStatus(fmt.Sprintf("Warning: %v", err), true)
+	}
+
 	// Załaduj dane do modelu
 	m.hosts = configManager.GetHosts()
 	m.passwords = configManager.GetPasswords()
@@ -238,10 +210,36 @@ func NewModel() *Model {
 	return &m // Zwracamy wskaźnik do m
 }
 
+// CycleTheme przełącza na następny motyw i zapisuje wybór w lokalnej konfiguracji
+func (m *Model) CycleTheme() {
+	SwitchTheme()
+	m.saveTheme()
+}
+
+// SelectTheme ustawia motyw o podanej nazwie i zapisuje wybór w lokalnej konfiguracji
+func (m *Model) SelectTheme(name string) error {
+	if err := SetThemeByName(name); err != nil {
+		return err
+	}
+	m.saveTheme()
+	return nil
+}
+
+// saveTheme zapisuje aktualny motyw w lokalnej konfiguracji
+func (m *Model) saveTheme() {
+	m.config.SetTheme(CurrentThemeName())
+	if err := m.config.SaveLocal(); err != nil {
+		m.SetStatus(fmt.Sprintf("Warning: failed to save theme: %v", err), true)
+	}
+}
+
+// SaveConfig zapisuje konfigurację lokalnie; wysłanie zmian do API jest
+// zlecane osobno i odbywa się w tle
 func (m *Model) SaveConfig() interface{} {
 	if err := m.config.Save(); err != nil {
 		return fmt.Errorf("nie udało się zapisać konfiguracji: %v", err)
 	}
+	m.RequestPush()
 	return nil
 }
 
@@ -260,19 +258,23 @@ func initializeList(title string) list.Model {
 // UpdateLists aktualizuje listy hostów i haseł
 func (m *Model) UpdateLists() {
 	// Pobierz aktualne dane z konfiguracji
-	m.hosts = m.config.GetHosts()
-	m.passwords = m.config.GetPasswords()
+	hosts := m.config.GetHosts()
+	passwords := m.config.GetPasswords()
+	m.mutex.Lock()
+	m.hosts = hosts
+	m.passwords = passwords
+	m.mutex.Unlock()
 
 	// Aktualizacja listy hostów
 	var hostItems []list.Item
-	for _, h := range m.hosts {
+	for _, h := range hosts {
 		hostItems = append(hostItems, HostItem{host: h})
 	}
 	m.hostList.SetItems(hostItems)
 
 	// Aktualizacja listy haseł
 	var passwordItems []list.Item
-	for _, p := range m.passwords {
+	for _, p := range passwords {
 		passwordItems = append(passwordItems, PasswordItem{password: p})
 	}
 	m.passwordList.SetItems(passwordItems)
@@ -309,52 +311,94 @@ func (m *Model) ClearStatus() {
 	m.status = Status{}
 }
 
-func (m *Model) ConnectToHost(host *models.Host, password string) interface{} {
+func (m *Model) ConnectToHost(host *models.Host, authData []byte) interface{} {
 	// Jeśli istnieje poprzednie połączenie, zamknij je
 	if m.sshClient != nil {
 		m.DisconnectHost()
 	}
 
 	// Utwórz nowego klienta SSH
-	m.sshClient = ssh.NewSSHClient(m.passwords)
+	m.sshClient = m.NewSSHClient()
 
 	// Nawiąż połączenie
-	err := m.sshClient.Connect(host, password)
+	err := m.sshClient.Connect(host, authData)
 	if err != nil {
 		return fmt.Errorf("failed to connect: %v", err)
 	}
 
+	// Utwórz nowy obiekt transferu plików; użyje tego samego połączenia
+	m.mutex.Lock()
 	m.selectedHost = host
-
-	// Utwórz nowy obiekt transferu plików (poprawione wywołanie)
-	m.transfer = ssh.NewFileTransfer(m.cipher)
+	m.transfer = m.newTransfer()
+	m.mutex.Unlock()
 
 	return nil
 }
 
+// NewSSHClient tworzy klienta SSH powłoki, który dzieli połączenie z hostem
+// z transferem plików (GetTransfer)
+func (m *Model) NewSSHClient() *ssh.SSHClient {
+	client := ssh.NewSSHClient(m.GetPasswords())
+	client.SetJumpHostResolver(m.ResolveJumpHost)
+	client.SetDefaultAlgorithms(m.config.GetAlgorithms())
+	client.SetConnection(m.connection)
+	return client
+}
+
+// newTransfer tworzy transfer plików z ustawieniami z konfiguracji; wywołujący
+// trzyma mutex
+func (m *Model) newTransfer() *ssh.FileTransfer {
+	transfer := ssh.NewFileTransfer(m.cipher)
+	transfer.SetJumpHostResolver(m.ResolveJumpHost)
+	transfer.SetDefaultAlgorithms(m.config.GetAlgorithms())
+	transfer.SetPreserveAttributes(m.config.GetPreserveAttributes())
+	transfer.SetRateLimit(m.config.GetRateLimit())
+	transfer.SetConnection(m.connection)
+	return transfer
+}
+
+// CloseConnection zamyka połączenie współdzielone przez powłokę i transfer
+// (przy wyjściu z programu)
+func (m *Model) CloseConnection() {
+	m.connection.Close()
+}
+
 func (m *Model) DisconnectHost() interface{} {
-	if m.transfer != nil {
-		if err := m.transfer.Disconnect(); // Używamy Disconnect zamiast Close
+	// Rozłączanie może chwilę potrwać, więc nie trzymamy przy nim blokady
+	m.mutex.RLock()
+	transfer := m.transfer
+	m.mutex.RUnlock()
+	if transfer != nil {
+		if err := transfer.Disconnect(); // Używamy Disconnect zamiast Close
 		err != nil {
 			return fmt.Errorf("error disconnecting transfer: %v", err)
 		}
-		m.transfer = nil
 	}
 	if m.sshClient != nil {
 		m.sshClient.Disconnect()
 		m.sshClient = nil
 	}
+
+	m.mutex.Lock()
+	if m.transfer == transfer {
+		m.transfer = nil
+	}
 	m.selectedHost = nil
+	m.mutex.Unlock()
 	return nil
 }
 
 // GetSelectedHost zwraca aktualnie wybrany host
 func (m *Model) GetSelectedHost() *models.Host {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
 	return m.selectedHost
 }
 
 // SetSelectedHost ustawia wybrany host
 func (m *Model) SetSelectedHost(host *models.Host) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	m.selectedHost = host
 }
 
@@ -364,12 +408,164 @@ func (m *Model) IsConnected() bool {
 }
 
 func (m *Model) GetTransfer() *ssh.FileTransfer {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	if m.transfer == nil {
-		m.transfer = ssh.NewFileTransfer(m.cipher)
+		m.transfer = m.newTransfer()
 	}
 	return m.transfer
 }
 
+// GetHostAuthData zwraca dane autoryzacji hosta. Dla jednej metody są to dane
+// z authDataFor; dla kilku metod (AuthIDs) - lista zbudowana przez
+// ssh.MultiAuthData, z pominięciem metod, których nie da się przygotować.
+// Dane mogą zawierać odszyfrowane hasła, więc po połączeniu należy je
+// wyczyścić przez crypto.Wipe.
+func (m *Model) GetHostAuthData(host *models.Host) ([]byte, error) {
+	ids := host.GetAuthIDs()
+	if len(ids) == 1 {
+		return m.authDataFor(ids[0])
+	}
+
+	var entries []ssh.AuthEntry
+	var firstErr error
+	for _, id := range ids {
+		data, err := m.authDataFor(id)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		entries = append(entries, ssh.AuthEntry{Key: id < 0, Data: data})
+	}
+	if len(entries) == 0 {
+		return nil, firstErr
+	}
+	authData := ssh.MultiAuthData(entries)
+	for _, entry := range entries {
+		crypto.Wipe(entry.Data)
+	}
+	return authData, nil
+}
+
+// authDataFor zwraca dane autoryzacji jednej metody: ścieżkę klucza SSH
+// (dla ujemnego id), dane ssh-agenta albo odszyfrowane hasło
+func (m *Model) authDataFor(id int) ([]byte, error) {
+	if id < 0 {
+		key, ok := models.FindKeyByAuthID(m.config.GetKeys(), id)
+		if !ok {
+			return nil, fmt.Errorf("invalid key ID")
+		}
+
+		if key.UseAgent {
+			// Ścieżka klucza (jeśli jest) służy jako zapasowa, gdy agent jest niedostępny
+			fallback, _ := key.GetKeyPath()
+			return ssh.AgentAuthData(fallback), nil
+		}
+
+		keyPath, err := key.GetKeyPath()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get key path: %v", err)
+		}
+		return []byte(keyPath), nil
+	}
+
+	passwords := m.config.GetPasswords()
+	if id >= len(passwords) {
+		return nil, fmt.Errorf("invalid password ID")
+	}
+	decrypted, err := passwords[id].GetDecryptedBytes(m.GetCipher())
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt password: %v", err)
+	}
+	return decrypted, nil
+}
+
+// SystemSSHCommand przygotowuje polecenie systemowego ssh dla hosta z opcją
+// UseSystemSSH, z plikami jego kluczy i hostem pośredniczącym
+func (m *Model) SystemSSHCommand(host *models.Host) (*exec.Cmd, error) {
+	var jumpHost *models.Host
+	if host.JumpHost != "" {
+		found, _, err := m.config.FindHostByName(host.JumpHost)
+		if err != nil {
+			return nil, fmt.Errorf("cannot resolve jump host '%s': %v", host.JumpHost, err)
+		}
+		jumpHost = &found
+	}
+
+	// Klucze przekazujemy jako -i; hasło ssh zapyta sam
+	var identityFiles []string
+	for _, id := range host.GetAuthIDs() {
+		key, ok := models.FindKeyByAuthID(m.config.GetKeys(), id)
+		if !ok {
+			continue
+		}
+		if keyPath, err := key.GetKeyPath(); err == nil {
+			identityFiles = append(identityFiles, keyPath)
+		}
+	}
+
+	return ssh.SystemSSHCommand(host, identityFiles, jumpHost)
+}
+
+// ResolveJumpHost odnajduje host pośredniczący po nazwie i przygotowuje
+// jego dane autoryzacji (implementuje ssh.JumpHostResolver)
+func (m *Model) ResolveJumpHost(name string) (*models.Host, []byte, error) {
+	host, _, err := m.config.FindHostByName(name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("host '%s' not found in configuration", name)
+	}
+
+	authData, err := m.GetHostAuthData(&host)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get credentials for '%s': %v", name, err)
+	}
+
+	return &host, authData, nil
+}
+
+// InstallKeyOnHost loguje się na host jego własnymi danymi autoryzacji i dopisuje
+// klucz publiczny wybranego klucza do ~/.ssh/authorized_keys (jak ssh-copy-id).
+// Zwraca false, jeśli klucz był już zainstalowany.
+func (m *Model) InstallKeyOnHost(host *models.Host, keyIndex int) (bool, error) {
+	keys := m.config.GetKeys()
+	if keyIndex < 0 || keyIndex >= len(keys) {
+		return false, fmt.Errorf("invalid key ID")
+	}
+
+	keyPath, err := keys[keyIndex].GetKeyPath()
+	if err != nil {
+		return false, fmt.Errorf("key '%s' has no key file: %v", keys[keyIndex].Description, err)
+	}
+
+	publicKey, err := ssh.PublicKeyForKeyFile(keyPath)
+	if err != nil {
+		return false, err
+	}
+
+	authData, err := m.GetHostAuthData(host)
+	if err != nil {
+		return false, fmt.Errorf("failed to get credentials: %v", err)
+	}
+	defer crypto.Wipe(authData)
+
+	if err := ssh.RunPreConnectHook(host); err != nil {
+		return false, err
+	}
+
+	// Osobne połączenie SFTP, aby nie naruszać sesji widoku transferu
+	transfer := ssh.NewFileTransfer(m.GetCipher())
+	transfer.SetJumpHostResolver(m.ResolveJumpHost)
+	transfer.SetDefaultAlgorithms(m.config.GetAlgorithms())
+	if err := transfer.Connect(host, authData); err != nil {
+		return false, fmt.Errorf("failed to connect: %v", err)
+	}
+	defer transfer.Disconnect()
+
+	return transfer.InstallPublicKey(publicKey)
+}
+
 // SetActiveView switch view and initialize if needed
 func (m *Model) SetActiveView(view View) {
 	m.activeView = view
@@ -402,19 +598,29 @@ func (m *Model) AddHost(host *models.Host) interface{} {
 	m.config.AddHost(*host)
 
 	// Zaktualizuj lokalną listę hostów
-	m.hosts = m.config.GetHosts()
+	hosts := m.config.GetHosts()
+	m.mutex.Lock()
+	m.hosts = hosts
+	m.mutex.Unlock()
 	return nil
 }
 
-// UpdateHost aktualizuje istniejącego hosta
+// UpdateHost aktualizuje istniejącego hosta w konfiguracji (zmiana trafia do
+// kolejki synchronizacji) i w lokalnej liście
 func (m *Model) UpdateHost(oldName string, host *models.Host) interface{} {
-	for i, h := range m.hosts {
-		if h.Name == oldName {
-			m.hosts[i] = *host
-			return nil
-		}
+	_, index, err := m.config.FindHostByName(oldName)
+	if err != nil {
+		return fmt.Errorf("nie znaleziono hosta %s", oldName)
+	}
+	if err := m.config.UpdateHost(index, *host); err != nil {
+		return err
 	}
-	return fmt.Errorf("nie znaleziono hosta %s", oldName)
+
+	hosts := m.config.GetHosts()
+	m.mutex.Lock()
+	m.hosts = hosts
+	m.mutex.Unlock()
+	return nil
 }
 
 // AddPassword dodaje nowe hasło
@@ -433,48 +639,96 @@ func (m *Model) AddPassword(password *models.Password) error {
 	if err := m.config.Save(); err != nil {
 		return fmt.Errorf("nie udało się zapisać konfiguracji: %v", err)
 	}
+	m.RequestPush()
 
 	// Aktualizuj lokalną listę haseł
-	m.passwords = m.config.GetPasswords()
+	passwords := m.config.GetPasswords()
+	m.mutex.Lock()
+	m.passwords = passwords
+	m.mutex.Unlock()
 	return nil
 }
 
-// UpdatePassword aktualizuje istniejące hasło
+// UpdatePassword aktualizuje istniejące hasło w konfiguracji (zmiana trafia
+// do kolejki synchronizacji) i w lokalnej liście
 func (m *Model) UpdatePassword(oldDesc string, password *models.Password) error {
-	for i, p := range m.passwords {
-		if p.Description == oldDesc {
-			m.passwords[i] = *password
-			return nil
+	for i, p := range m.config.GetPasswords() {
+		if p.Description != oldDesc {
+			continue
+		}
+		if err := m.config.UpdatePassword(i, *password); err != nil {
+			return err
 		}
+
+		passwords := m.config.GetPasswords()
+		m.mutex.Lock()
+		m.passwords = passwords
+		m.mutex.Unlock()
+		return nil
 	}
 	return fmt.Errorf("nie znaleziono hasła %s", oldDesc)
 }
 
-// GetHosts zwraca listę hostów
+// GetHosts zwraca kopię listy hostów
 func (m *Model) GetHosts() []models.Host {
-	return m.hosts
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return slices.Clone(m.hosts)
 }
 
-// GetPasswords zwraca listę haseł
+// GetPasswords zwraca kopię listy haseł
 func (m *Model) GetPasswords() []models.Password {
-	return m.passwords
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return slices.Clone(m.passwords)
+}
+
+// FindReusedPassword zwraca opis zapisanego hasła o tej samej treści co plain
+// (porównując odszyfrowane wartości); hasło o opisie exclude jest pomijane
+func (m *Model) FindReusedPassword(plain, exclude string) (string, bool) {
+	cipher := m.GetCipher()
+	if plain == "" || cipher == nil {
+		return "", false
+	}
+	for _, password := range m.config.GetPasswords() {
+		if password.Description == exclude {
+			continue
+		}
+		decrypted, err := password.GetDecryptedBytes(cipher)
+		if err != nil {
+			continue
+		}
+		same := subtle.ConstantTimeCompare(decrypted, []byte(plain)) == 1
+		crypto.Wipe(decrypted)
+		if same {
+			return password.Description, true
+		}
+	}
+	return "", false
 }
 
 // Dodaj w internal/ui/models.go
 
-// GetPasswordByIndex zwraca hasło o danym indeksie
+// GetPasswordByIndex zwraca kopię hasła o danym indeksie
 func (m *Model) GetPasswordByIndex(index int) *models.Password {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
 	if index >= 0 && index < len(m.passwords) {
-		return &m.passwords[index]
+		password := m.passwords[index]
+		return &password
 	}
 	return nil
 }
 
 func (m *Model) SetCipher(cipher *crypto.Cipher) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	m.cipher = cipher
 }
 
 func (m *Model) GetCipher() *crypto.Cipher {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
 	return m.cipher
 }
 
@@ -487,13 +741,12 @@ func (m *Model) DeleteHost(name string) interface{} {
 			if err := m.config.DeleteHost(i); err != nil {
 				return fmt.Errorf("nie można usunąć hosta: %v", err)
 			}
-			// Usuń z lokalnej listy
-			for j, host := range m.hosts {
-				if host.Name == name {
-					m.hosts = append(m.hosts[:j], m.hosts[j+1:]...)
-					break
-				}
-			}
+			m.setLastDeleted(&deletedItem{host: &h})
+			// Podmień lokalną listę na nową, bez usuniętego hosta
+			hosts := m.config.GetHosts()
+			m.mutex.Lock()
+			m.hosts = hosts
+			m.mutex.Unlock()
 			return nil
 		}
 	}
@@ -517,23 +770,23 @@ func (m *Model) DeletePassword(description string) interface{} {
 
 	// Sprawdź czy hasło nie jest używane przez żadnego hosta
 	for _, h := range m.config.GetHosts() {
-		if h.PasswordID == passwordIndex {
+		if h.UsesAuth(passwordIndex) {
 			return fmt.Errorf("hasło jest używane przez hosta %s", h.Name)
 		}
 	}
 
 	// Usuń hasło z konfiguracji
+	deleted := m.config.GetPasswords()[passwordIndex]
 	if err := m.config.DeletePassword(passwordIndex); err != nil {
 		return fmt.Errorf("nie można usunąć hasła: %v", err)
 	}
+	m.setLastDeleted(&deletedItem{password: &deleted})
 
-	// Usuń z lokalnej listy
-	for i, p := range m.passwords {
-		if p.Description == description {
-			m.passwords = append(m.passwords[:i], m.passwords[i+1:]...)
-			break
-		}
-	}
+	// Podmień lokalną listę na nową, bez usuniętego hasła
+	passwords := m.config.GetPasswords()
+	m.mutex.Lock()
+	m.passwords = passwords
+	m.mutex.Unlock()
 
 	return nil
 }
@@ -543,6 +796,8 @@ func (m *Model) GetActiveView() View {
 }
 
 func (m *Model) SetTransfer(transfer *ssh.FileTransfer) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	m.transfer = transfer
 }
 
@@ -575,13 +830,31 @@ func (m *Model) UpdateWindowSize(width, height int) {
 }
 
 func (m *Model) ToggleSelection(path string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	if m.selectedItems == nil {
 		m.selectedItems = make(map[string]bool)
 	}
 	m.selectedItems[path] = !m.selectedItems[path]
 }
 
+// SetSelection zaznacza lub odznacza element
+func (m *Model) SetSelection(path string, selected bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.selectedItems == nil {
+		m.selectedItems = make(map[string]bool)
+	}
+	if selected {
+		m.selectedItems[path] = true
+	} else {
+		delete(m.selectedItems, path)
+	}
+}
+
 func (m *Model) IsSelected(path string) bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
 	if m.selectedItems == nil {
 		return false
 	}
@@ -589,10 +862,14 @@ func (m *Model) IsSelected(path string) bool {
 }
 
 func (m *Model) ClearSelection() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	m.selectedItems = make(map[string]bool)
 }
 
 func (m *Model) GetSelectedPaths() []string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
 	var paths []string
 	for path, isSelected := range m.selectedItems {
 		if isSelected {
@@ -661,9 +938,11 @@ func (m *Model) DeleteKey(description string) error {
 	}
 
 	// Deleguj usuwanie do config.Manager
+	deleted := keys[keyIndex]
 	if err := m.config.DeleteKey(keyIndex); err != nil {
 		return fmt.Errorf("failed to delete key '%s': %v", description, err)
 	}
+	m.rememberDeletedKey(deleted)
 
 	return nil
 }
@@ -675,6 +954,45 @@ func (m *Model) IsLocalMode() bool {
 	return m.localMode
 }
 
+// SetSyncError zapamiętuje błąd synchronizacji, który przełączył aplikację w tryb lokalny
+func (m *Model) SetSyncError(err error) {
+	m.syncErr = err
+}
+
+// TakeSyncError zwraca zapamiętany błąd synchronizacji i czyści go, aby został pokazany raz
+func (m *Model) TakeSyncError() error {
+	err := m.syncErr
+	m.syncErr = nil
+	return err
+}
+
+// RequestPush zleca wysłanie zapisanych zmian do API w tle
+func (m *Model) RequestPush() {
+	m.pushRequested = true
+}
+
+// TakePushRequest zwraca, czy zlecono wysłanie zmian do API, i kasuje zlecenie
+func (m *Model) TakePushRequest() bool {
+	requested := m.pushRequested
+	m.pushRequested = false
+	return requested
+}
+
+// SetPushError zapamiętuje błąd wysyłania zmian do API (nil po udanym wysłaniu)
+func (m *Model) SetPushError(err error) {
+	m.pushErr = err
+}
+
+// GetPushError zwraca błąd ostatniego wysyłania zmian do API
+func (m *Model) GetPushError() error {
+	return m.pushErr
+}
+
+// Keys zwraca klawisze nawigacji wspólne dla widoków
+func (m *Model) Keys() KeyMap {
+	return m.keys
+}
+
 func (m *Model) GetConfig() *config.Manager {
 	return m.config
 }
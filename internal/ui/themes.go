@@ -1,8 +1,17 @@
 package ui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"fmt"
+	"sshManager/internal/models"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
 
 type Theme struct {
+	// Nazwa motywu zapisywana w konfiguracji i podawana we fladze --theme
+	Name string
+
 	// Podstawowe kolory
 	Subtle    lipgloss.Color
 	Highlight lipgloss.Color
@@ -43,6 +52,8 @@ var (
 	themes = []Theme{
 		{
 			// Domyślny motyw (obecny)
+			Name: "default",
+
 			Subtle:    lipgloss.Color("#6C7086"),
 			Highlight: lipgloss.Color("#7DC4E4"),
 			Special:   lipgloss.Color("#FF9E64"),
@@ -74,6 +85,8 @@ var (
 		},
 		{
 			// Dracula Classic - motyw inspirowany klasycznym schematem kolorów Dracula
+			Name: "dracula-classic",
+
 			Subtle:    lipgloss.Color("#6272A4"), // Delikatny fioletowy
 			Highlight: lipgloss.Color("#8BE9FD"), // Jasny cyan
 			Special:   lipgloss.Color("#FF79C6"), // Różowy
@@ -105,6 +118,8 @@ var (
 		},
 		{
 			// Dracula Night - motyw z ciemniejszymi odcieniami inspirowanymi Dracula Theme
+			Name: "dracula-night",
+
 			Subtle:    lipgloss.Color("#44475A"), // Ciemny szary z fioletowym odcieniem
 			Highlight: lipgloss.Color("#BD93F9"), // Jasny fioletowy
 			Special:   lipgloss.Color("#FFB86C"), // Pomarańczowy
@@ -136,6 +151,8 @@ var (
 		},
 		{
 			// VSCodeDark - inspirowany domyślnym motywem VS Code Dark+
+			Name: "vscode-dark",
+
 			Subtle:    lipgloss.Color("#808080"), // Szary z VS Code
 			Highlight: lipgloss.Color("#569CD6"), // Niebieski VS Code
 			Special:   lipgloss.Color("#4EC9B0"), // Turkusowy VS Code
@@ -167,6 +184,8 @@ var (
 		},
 		{
 			// DraculaClassic - dokładnie bazujący na palecie Dracula
+			Name: "dracula",
+
 			Subtle:    lipgloss.Color("#6272a4"), // Comment
 			Highlight: lipgloss.Color("#8be9fd"), // Cyan
 			Special:   lipgloss.Color("#50fa7b"), // Green
@@ -198,6 +217,8 @@ var (
 		},
 		{
 			// Molokai - inspirowany klasyczną paletą Molokai
+			Name: "molokai",
+
 			Subtle:    lipgloss.Color("#808080"), // Szary
 			Highlight: lipgloss.Color("#66D9EF"), // Jasnoniebieski molokai
 			Special:   lipgloss.Color("#A6E22E"), // Limonkowy molokai
@@ -229,6 +250,8 @@ var (
 		},
 		{
 			// CyberNeon - inspirowany cyberpunkiem z neonowymi akcentami
+			Name: "cyber-neon",
+
 			Subtle:    lipgloss.Color("#8B9BB4"), // Jaśniejszy niebieskoszary
 			Highlight: lipgloss.Color("#FF2A6D"), // Neonowy różowy
 			Special:   lipgloss.Color("#05FFA1"), // Jaskrawy cybernetyczny zielony
@@ -260,6 +283,8 @@ var (
 		},
 		{
 			// AtomicDark - inspirowany edytorem Atom i nowoczesnym UI
+			Name: "atomic-dark",
+
 			Subtle:    lipgloss.Color("#ABB2BF"), // Jasny szary
 			Highlight: lipgloss.Color("#61AFEF"), // Jasny niebieski
 			Special:   lipgloss.Color("#98C379"), // Zielony atom
@@ -291,6 +316,8 @@ var (
 		},
 		{
 			// DraculaPro - inspirowany popularnym motywem Dracula
+			Name: "dracula-pro",
+
 			Subtle:    lipgloss.Color("#BFBFBF"), // Jasny szary
 			Highlight: lipgloss.Color("#BD93F9"), // Fioletowy dracula
 			Special:   lipgloss.Color("#50FA7B"), // Zielony dracula
@@ -322,6 +349,8 @@ var (
 		},
 		{
 			// Ciemny motyw
+			Name: "dark",
+
 			Subtle:    lipgloss.Color("#515671"),
 			Highlight: lipgloss.Color("#89DCEB"),
 			Special:   lipgloss.Color("#FAB387"),
@@ -353,6 +382,8 @@ var (
 		},
 		{
 			// Aurora - motyw inspirowany zorzą polarną
+			Name: "aurora",
+
 			Subtle:    lipgloss.Color("#6272A4"), // Delikatny szaro-niebieski
 			Highlight: lipgloss.Color("#61AFEF"), // Jasny niebieski
 			Special:   lipgloss.Color("#FF79C6"), // Intensywny różowy
@@ -384,6 +415,8 @@ var (
 		},
 		{
 			// Cyberpunk - motyw inspirowany futurystycznymi neonami
+			Name: "cyberpunk",
+
 			Subtle:    lipgloss.Color("#2F2B6D"),
 			Highlight: lipgloss.Color("#FF00FF"), // Neonowy fiolet
 			Special:   lipgloss.Color("#00FFFF"), // Cyan
@@ -415,6 +448,8 @@ var (
 		},
 		{
 			// NeonGreen - motyw z dominującym intensywnym zielonym
+			Name: "neon-green",
+
 			Subtle:    lipgloss.Color("#CCCCCC"), // Jaśniejszy dla lepszej widoczności
 			Highlight: lipgloss.Color("#39FF14"), // Neonowy zielony
 			Special:   lipgloss.Color("#00FF7F"), // Spring Green
@@ -446,6 +481,8 @@ var (
 		},
 		{
 			// RetroOrange - motyw z ciepłym, retro pomarańczowym akcentem
+			Name: "retro-orange",
+
 			Subtle:    lipgloss.Color("#D0D0D0"), // Znacznie jaśniejszy dla lepszej widoczności
 			Highlight: lipgloss.Color("#FFA500"), // Pomarańczowy
 			Special:   lipgloss.Color("#FF8C00"), // Dark Orange
@@ -477,6 +514,8 @@ var (
 		},
 		{
 			// ElectricBlue - motyw z wyrazistym elektrycznym niebieskim
+			Name: "electric-blue",
+
 			Subtle:    lipgloss.Color("#C8C8C8"), // Jaśniejszy dla lepszej widoczności
 			Highlight: lipgloss.Color("#00FFFF"), // Electric Blue
 			Special:   lipgloss.Color("#1E90FF"), // Dodger Blue
@@ -509,6 +548,39 @@ var (
 	}
 )
 
+// environmentColors to kolory środowisk hostów; są wspólne dla wszystkich
+// motywów, aby produkcja zawsze wyróżniała się na czerwono
+var environmentColors = map[string]lipgloss.Color{
+	models.EnvironmentProduction:  lipgloss.Color("#FF5555"), // Czerwony
+	models.EnvironmentStaging:     lipgloss.Color("#FFB86C"), // Pomarańczowy
+	models.EnvironmentTest:        lipgloss.Color("#F1FA8C"), // Żółty
+	models.EnvironmentDevelopment: lipgloss.Color("#50FA7B"), // Zielony
+}
+
+// EnvironmentStyle zwraca styl nazwy hosta z danego środowiska;
+// nieznane środowiska używają zwykłego HostStyle
+func EnvironmentStyle(environment string) lipgloss.Style {
+	if color, ok := environmentColors[models.NormalizeEnvironment(environment)]; ok {
+		return HostStyle.Foreground(color)
+	}
+	return HostStyle
+}
+
+// EnvironmentBadge renderuje etykietę środowiska, np. " PROD "
+func EnvironmentBadge(environment string) string {
+	environment = models.NormalizeEnvironment(environment)
+	color, ok := environmentColors[environment]
+	if !ok {
+		color = Subtle
+	}
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#000000")).
+		Background(color).
+		Bold(true).
+		Padding(0, 1).
+		Render(strings.ToUpper(environment))
+}
+
 // SwitchTheme przełącza na następny motyw i aktualizuje wszystkie style
 func SwitchTheme() {
 	currentThemeIndex = (currentThemeIndex + 1) % len(themes)
@@ -516,6 +588,33 @@ func SwitchTheme() {
 	updateStyles(currentTheme)
 }
 
+// ThemeNames zwraca nazwy dostępnych motywów w kolejności przełączania
+func ThemeNames() []string {
+	names := make([]string, len(themes))
+	for i, theme := range themes {
+		names[i] = theme.Name
+	}
+	return names
+}
+
+// CurrentThemeName zwraca nazwę aktualnego motywu
+func CurrentThemeName() string {
+	return themes[currentThemeIndex].Name
+}
+
+// SetThemeByName ustawia motyw o podanej nazwie (bez rozróżniania wielkości liter)
+// i aktualizuje wszystkie style
+func SetThemeByName(name string) error {
+	for i, theme := range themes {
+		if strings.EqualFold(theme.Name, strings.TrimSpace(name)) {
+			currentThemeIndex = i
+			updateStyles(theme)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown theme '%s' (available: %s)", name, strings.Join(ThemeNames(), ", "))
+}
+
 func updateStyles(theme Theme) {
 	// Aktualizacja podstawowych kolorów
 	Subtle = theme.Subtle
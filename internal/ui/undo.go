@@ -0,0 +1,88 @@
+// internal/ui/undo.go
+
+package ui
+
+import (
+	"errors"
+	"fmt"
+
+	"sshManager/internal/models"
+)
+
+// deletedItem przechowuje kopię ostatnio usuniętego elementu do cofnięcia
+// (Ctrl+Z). Pamiętamy tylko jedno usunięcie i tylko w pamięci, więc bufor
+// jest wspólny dla widoku głównego i widoku edycji.
+type deletedItem struct {
+	host     *models.Host
+	password *models.Password
+	key      *models.Key
+}
+
+// CanUndoDelete sprawdza, czy jest usunięcie do cofnięcia
+func (m *Model) CanUndoDelete() bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.lastDeleted != nil
+}
+
+// setLastDeleted zapamiętuje element do cofnięcia (nil czyści bufor)
+func (m *Model) setLastDeleted(item *deletedItem) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.lastDeleted = item
+}
+
+// UndoDelete przywraca ostatnio usunięty host, hasło albo klucz i zapisuje
+// konfigurację. Zwraca opis przywróconego elementu do paska statusu.
+func (m *Model) UndoDelete() (string, error) {
+	m.mutex.RLock()
+	item := m.lastDeleted
+	m.mutex.RUnlock()
+	if item == nil {
+		return "", errors.New("nothing to undo")
+	}
+
+	var restored string
+	switch {
+	case item.host != nil:
+		if err := m.AddHost(item.host); err != nil {
+			return "", fmt.Errorf("cannot restore host: %v", err)
+		}
+		restored = "host " + item.host.Name
+
+	case item.password != nil:
+		if err := m.AddPassword(item.password); err != nil {
+			return "", fmt.Errorf("cannot restore password: %v", err)
+		}
+		restored = "password " + item.password.Description
+
+	case item.key != nil:
+		// Plik klucza przechowywanego lokalnie został usunięty razem z kluczem,
+		// więc AddKey zapisze go ponownie z odszyfrowanych danych
+		if item.key.IsLocal() && item.key.RawKeyData == "" {
+			return "", errors.New("cannot restore key: key data is not available")
+		}
+		if err := m.AddKey(item.key); err != nil {
+			return "", fmt.Errorf("cannot restore key: %v", err)
+		}
+		restored = "key " + item.key.Description
+	}
+
+	if err := m.SaveConfig(); err != nil {
+		return "", fmt.Errorf("%v", err)
+	}
+	m.setLastDeleted(nil)
+	m.UpdateLists()
+	return restored, nil
+}
+
+// rememberDeletedKey zapamiętuje klucz do cofnięcia; dane klucza
+// przechowywanego lokalnie odszyfrowujemy od razu, bo jego plik zostanie usunięty
+func (m *Model) rememberDeletedKey(key models.Key) {
+	if cipher := m.GetCipher(); key.IsLocal() && key.RawKeyData == "" && cipher != nil {
+		if raw, err := key.GetKeyData(cipher); err == nil {
+			key.RawKeyData = raw
+		}
+	}
+	m.setLastDeleted(&deletedItem{key: &key})
+}
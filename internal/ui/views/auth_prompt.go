@@ -0,0 +1,178 @@
+// internal/ui/views/auth_prompt.go
+
+package views
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"sshManager/internal/crypto"
+	"sshManager/internal/ssh"
+	"sshManager/internal/ui/components"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// errConnectTimedOut oznacza przekroczenie limitu czasu połączenia
+var errConnectTimedOut = errors.New("connection timed out")
+
+// authPromptMsg przekazuje do UI rundę pytań keyboard-interactive (np. kod 2FA)
+type authPromptMsg struct {
+	challenge ssh.AuthChallenge
+	reply     chan<- []string // Odpowiedzi dla serwera; nil anuluje autoryzację
+	prompts   <-chan authPromptMsg
+}
+
+// authPromptState to odpowiedzi zbierane dla bieżącej rundy pytań
+type authPromptState struct {
+	challenge ssh.AuthChallenge
+	answers   []string
+	reply     chan<- []string
+}
+
+// runConnect wywołuje connect w tle i czeka na wynik najwyżej timeout (0 = bez
+// limitu). Pytania keyboard-interactive trafiają do UI przez prompts, a czas
+// oczekiwania na odpowiedź użytkownika nie wlicza się do limitu. Kanał prompts
+// jest zamykany po zakończeniu connect.
+//
+// connect dostaje własną kopię authData, czyszczoną dopiero po jego
+// zakończeniu: po przekroczeniu limitu połączenie trwa dalej w tle i może
+// jeszcze odczytać hasło, choć wywołujący wyczyścił już swoje dane.
+func runConnect(sshClient *ssh.SSHClient, prompts chan authPromptMsg, timeout time.Duration, authData []byte, connect func(authData []byte) error) error {
+	var expired <-chan time.Time
+	pause := func(bool) {}
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+		pause = func(waiting bool) {
+			if waiting {
+				timer.Stop()
+			} else {
+				timer.Reset(timeout)
+			}
+		}
+	}
+	sshClient.SetAuthPrompter(newAuthPrompter(prompts, pause))
+
+	data := bytes.Clone(authData)
+	done := make(chan error, 1)
+	go func() {
+		err := connect(data)
+		crypto.Wipe(data)
+		done <- err
+		close(prompts)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-expired:
+		return fmt.Errorf("%w after %v", errConnectTimedOut, timeout)
+	}
+}
+
+// newAuthPrompter tworzy ssh.AuthPrompter, który przekazuje pytania serwera do
+// UI i czeka na odpowiedzi; pause(true) wstrzymuje limit czasu połączenia
+func newAuthPrompter(prompts chan authPromptMsg, pause func(bool)) ssh.AuthPrompter {
+	return func(challenge ssh.AuthChallenge) ([]string, error) {
+		// Bufor pozwala UI odpowiedzieć bez blokowania, nawet gdy połączenie już zerwano
+		reply := make(chan []string, 1)
+
+		pause(true)
+		defer pause(false)
+		prompts <- authPromptMsg{challenge: challenge, reply: reply, prompts: prompts}
+		answers := <-reply
+		if answers == nil {
+			return nil, ssh.ErrAuthPromptCancelled
+		}
+		return answers, nil
+	}
+}
+
+// waitForAuthPrompt czeka na kolejną rundę pytań; po zamknięciu kanału kończy się bez wiadomości
+func waitForAuthPrompt(prompts <-chan authPromptMsg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-prompts
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+// showAuthPrompt pokazuje popup z kolejnym pytaniem bieżącej rundy
+func (v *mainView) showAuthPrompt() {
+	challenge := v.authPrompt.challenge
+	index := len(v.authPrompt.answers)
+
+	title := "Authentication"
+	if challenge.Host != "" {
+		title = fmt.Sprintf("Authentication: %s", challenge.Host)
+	}
+
+	var message strings.Builder
+	if challenge.Name != "" {
+		message.WriteString(challenge.Name + "\n")
+	}
+	if challenge.Instruction != "" {
+		message.WriteString(strings.TrimSpace(challenge.Instruction) + "\n")
+	}
+	if len(challenge.Questions) > 1 {
+		message.WriteString(fmt.Sprintf("(%d of %d) ", index+1, len(challenge.Questions)))
+	}
+	message.WriteString(strings.TrimSpace(challenge.Questions[index]))
+
+	v.popup = components.NewPopup(
+		components.PopupAuthPrompt,
+		title,
+		message.String(),
+		60,
+		9,
+		v.width,
+		v.height,
+	)
+	v.popup.Input.Placeholder = ""
+	v.popup.Input.CharLimit = 256
+	if !challenge.Echos[index] {
+		v.popup.Input.EchoMode = textinput.EchoPassword
+	}
+}
+
+// handleAuthPromptPopup zbiera odpowiedzi na pytania serwera; ESC anuluje autoryzację
+func (v *mainView) handleAuthPromptPopup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		v.cancelAuthPrompt()
+		v.popup = nil
+		return v, nil
+
+	case "enter":
+		v.authPrompt.answers = append(v.authPrompt.answers, v.popup.Input.Value())
+		if len(v.authPrompt.answers) < len(v.authPrompt.challenge.Questions) {
+			v.showAuthPrompt()
+			return v, nil
+		}
+		v.authPrompt.reply <- v.authPrompt.answers
+		v.authPrompt = nil
+		v.popup = nil
+		return v, nil
+	}
+
+	var cmd tea.Cmd
+	v.popup.Input, cmd = v.popup.Input.Update(msg)
+	return v, cmd
+}
+
+// cancelAuthPrompt odrzuca oczekujące pytanie serwera, jeśli jest
+func (v *mainView) cancelAuthPrompt() {
+	if v.authPrompt == nil {
+		return
+	}
+	v.authPrompt.reply <- nil
+	v.authPrompt = nil
+}
@@ -0,0 +1,225 @@
+// internal/ui/views/backup_picker.go
+
+package views
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"sshManager/internal/sync"
+	"sshManager/internal/ui"
+	"sshManager/internal/ui/components"
+	"sshManager/internal/ui/messages"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// backupRestoredMsg przychodzi po przywróceniu kopii (wysyłanej do API po restarcie)
+type backupRestoredMsg struct {
+	backup sync.Backup
+	err    error
+}
+
+// handleRestoreBackup otwiera wybór kopii konfiguracji zrobionych przed synchronizacją
+func (v *mainView) handleRestoreBackup() (tea.Model, tea.Cmd) {
+	backups, err := v.model.GetConfig().ListBackups()
+	if err != nil {
+		v.errMsg = fmt.Sprintf("Failed to list backups: %v", err)
+		return v, nil
+	}
+	if len(backups) == 0 {
+		v.errMsg = "No backups found"
+		return v, nil
+	}
+
+	v.restore.backups = backups
+	v.restore.index = 0
+	v.showBackupSelectPopup()
+	return v, nil
+}
+
+// backupLabel opisuje kopię na liście, np. "2026-10-16 14:03:12  12 hosts"
+func backupLabel(backup sync.Backup) string {
+	label := backup.Time.Format("2006-01-02 15:04:05")
+	if !backup.Valid {
+		return label + "  (corrupt)"
+	}
+	if backup.Hosts == 1 {
+		return label + "  1 host"
+	}
+	return fmt.Sprintf("%s  %d hosts", label, backup.Hosts)
+}
+
+// showBackupSelectPopup (re)buduje popup z listą kopii
+func (v *mainView) showBackupSelectPopup() {
+	var message strings.Builder
+	message.WriteString("Restore configuration and keys from:\n\n")
+	for i, backup := range v.restore.backups {
+		label := fmt.Sprintf("%s  (%s)", backupLabel(backup), formatTimeAgo(backup.Time, time.Now()))
+		if i == v.restore.index {
+			message.WriteString(ui.SelectedItemStyle.Render("> "+label) + "\n")
+		} else {
+			message.WriteString("  " + label + "\n")
+		}
+	}
+
+	v.popup = components.NewPopup(
+		components.PopupSelectBackup,
+		"Restore Backup",
+		message.String(),
+		70,
+		len(v.restore.backups)+8,
+		v.width,
+		v.height,
+	)
+}
+
+// handleBackupSelectPopup obsługuje klawisze w popupie wyboru kopii
+func (v *mainView) handleBackupSelectPopup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	backups := v.restore.backups
+
+	switch v.model.Keys().VerticalKey(msg) {
+	case "esc":
+		v.popup = nil
+	case "up":
+		v.restore.index = (v.restore.index + len(backups) - 1) % len(backups)
+		v.showBackupSelectPopup()
+	case "down":
+		v.restore.index = (v.restore.index + 1) % len(backups)
+		v.showBackupSelectPopup()
+	case "enter":
+		backup := backups[v.restore.index]
+		if !backup.Valid {
+			v.errMsg = fmt.Sprintf("Backup from %s is corrupt; choose another one",
+				backup.Time.Format("2006-01-02 15:04:05"))
+			v.popup = nil
+			return v, nil
+		}
+		v.showConfirmRestorePopup(backup)
+	}
+	return v, nil
+}
+
+// showConfirmRestorePopup pokazuje, co zmieni przywrócenie kopii; nic nie jest
+// nadpisywane przed potwierdzeniem
+func (v *mainView) showConfirmRestorePopup(backup sync.Backup) {
+	diff, err := v.model.GetConfig().DiffBackup(backup)
+	if err != nil {
+		v.errMsg = fmt.Sprintf("Cannot read backup: %v", err)
+		v.popup = nil
+		return
+	}
+
+	var message strings.Builder
+	message.WriteString(fmt.Sprintf("Backup from %s:\n\n", backup.Time.Format("2006-01-02 15:04:05")))
+	if diff.IsEmpty() {
+		message.WriteString("The backup matches the current configuration.\n")
+	} else {
+		writeDiffLine(&message, "Hosts", diff.HostsBefore, diff.HostsAfter, diff.AddedHosts, diff.RemovedHosts, diff.ChangedHosts)
+		writeDiffLine(&message, "Passwords", diff.PasswordsBefore, diff.PasswordsAfter, diff.AddedPasswords, diff.RemovedPasswords, nil)
+		writeDiffLine(&message, "Keys", diff.KeysBefore, diff.KeysAfter, diff.AddedKeys, diff.RemovedKeys, nil)
+	}
+	if v.model.GetConfig().HasApiKey() {
+		message.WriteString("\nThe restored configuration will also be pushed to the API.\n")
+	}
+	message.WriteString("\nRestore this backup?")
+
+	v.popup = components.NewPopup(
+		components.PopupConfirmRestore,
+		"Restore Backup",
+		message.String(),
+		70,
+		strings.Count(message.String(), "\n")+7,
+		v.width,
+		v.height,
+	)
+}
+
+// maxDiffNames ogranicza liczbę nazw wypisanych w jednej linii podsumowania
+const maxDiffNames = 5
+
+// writeDiffLine dopisuje podsumowanie zmian jednego rodzaju danych, np.
+// "Hosts: 12 -> 9" i pod spodem "3 removed: a, b, c"
+func writeDiffLine(message *strings.Builder, label string, before, after int, added, removed, changed []string) {
+	line := fmt.Sprintf("%s: %d -> %d", label, before, after)
+	var details []string
+	for _, group := range []struct {
+		verb  string
+		names []string
+	}{
+		{"removed", removed},
+		{"added", added},
+		{"changed", changed},
+	} {
+		if len(group.names) > 0 {
+			details = append(details, fmt.Sprintf("%d %s: %s", len(group.names), group.verb, joinNames(group.names)))
+		}
+	}
+	if len(details) > 0 {
+		line += "\n  " + strings.Join(details, "\n  ")
+	}
+	message.WriteString(line + "\n")
+}
+
+// joinNames łączy nazwy, skracając długie listy
+func joinNames(names []string) string {
+	if len(names) <= maxDiffNames {
+		return strings.Join(names, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(names[:maxDiffNames], ", "), len(names)-maxDiffNames)
+}
+
+// handleConfirmRestorePopup przywraca kopię dopiero po potwierdzeniu
+func (v *mainView) handleConfirmRestorePopup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		backup := v.restore.backups[v.restore.index]
+		v.popup = components.NewPopup(
+			components.PopupMessage,
+			"Restore Backup",
+			"Restoring backup...",
+			50,
+			7,
+			v.width,
+			v.height,
+		)
+		cfg := v.model.GetConfig()
+		return v, func() tea.Msg {
+			return backupRestoredMsg{backup: backup, err: cfg.RestoreBackup(backup)}
+		}
+	case "n", "N", "esc":
+		// Powrót do listy kopii
+		v.showBackupSelectPopup()
+	}
+	return v, nil
+}
+
+// handleBackupRestored po przywróceniu kopii restartuje aplikację, aby wczytała nowe dane
+func (v *mainView) handleBackupRestored(msg backupRestoredMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		v.popup = components.NewPopup(
+			components.PopupMessage,
+			"Error",
+			fmt.Sprintf("Failed to restore backup: %v", msg.err),
+			60,
+			8,
+			v.width,
+			v.height,
+		)
+		return v, nil
+	}
+
+	v.popup = components.NewPopup(
+		components.PopupMessage,
+		"Success",
+		"Backup restored. Restarting...",
+		50,
+		7,
+		v.width,
+		v.height,
+	)
+	return v, func() tea.Msg {
+		return messages.ReloadAppMsg{}
+	}
+}
@@ -0,0 +1,91 @@
+// internal/ui/views/banner.go
+
+package views
+
+import (
+	"strings"
+	"unicode"
+
+	"sshManager/internal/ui/components"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxBannerLines ogranicza długość baneru pokazywanego w popupie
+const maxBannerLines = 20
+
+// sanitizeBanner usuwa z baneru serwera znaki sterujące (np. sekwencje
+// escape), które mogłyby zepsuć widok, i skraca zbyt długi tekst
+func sanitizeBanner(banner string) string {
+	banner = strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' {
+			return r
+		}
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, banner)
+
+	lines := strings.Split(strings.TrimSpace(banner), "\n")
+	if len(lines) > maxBannerLines {
+		lines = append(lines[:maxBannerLines], "...")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// showBannerPopup pokazuje baner serwera; sesja startuje dopiero po ENTER
+func (v *mainView) showBannerPopup(banner string) {
+	title := "Server Banner"
+	if host := v.model.GetSelectedHost(); host != nil {
+		title = "Server Banner: " + host.Name
+	}
+	v.popup = components.NewPopup(
+		components.PopupBanner,
+		title,
+		banner,
+		70,
+		min(strings.Count(banner, "\n")+7, maxBannerLines+7),
+		v.width,
+		v.height,
+	)
+}
+
+// handleBannerPopup po ENTER wchodzi do powłoki, a po ESC rozłącza się
+func (v *mainView) handleBannerPopup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		return v.startShell()
+	case "esc":
+		if sshClient := v.model.GetSSHClient(); sshClient != nil {
+			sshClient.Disconnect()
+			v.model.SetSSHClient(nil)
+		}
+		v.popup = components.NewPopup(
+			components.PopupMessage,
+			"SSH",
+			"Connection cancelled",
+			50,
+			7,
+			v.width,
+			v.height,
+		)
+	}
+	return v, nil
+}
+
+// startShell kończy pętlę TUI, aby main.go mogło wykonać ConfigureTerminal() i StartShell()
+func (v *mainView) startShell() (tea.Model, tea.Cmd) {
+	v.recordConnection(v.model.GetSelectedHost())
+	v.connecting = true
+	v.popup = components.NewPopup(
+		components.PopupMessage,
+		"SSH",
+		"Connecting...",
+		50,
+		7,
+		v.width,
+		v.height,
+	)
+	return v, tea.Quit
+}
@@ -0,0 +1,333 @@
+// internal/ui/views/broadcast.go
+
+package views
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"sshManager/internal/crypto"
+	"sshManager/internal/models"
+	"sshManager/internal/ssh"
+	"sshManager/internal/ui"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	broadcastConcurrency = 8         // Ile hostów łączy się jednocześnie
+	maxBroadcastOutput   = 64 * 1024 // Limit zapamiętanego wyjścia jednego hosta
+)
+
+// broadcastState przechowuje stan wykonywania polecenia na kilku hostach:
+// najpierw pole polecenia, potem raport uzupełniany w miarę nadchodzenia wyników
+type broadcastState struct {
+	id       int // Numer uruchomienia; wyniki poprzednich uruchomień są pomijane
+	hosts    []models.Host
+	input    textinput.Model
+	command  string // Uruchomione polecenie (puste, dopóki wpisujemy)
+	results  []broadcastResult
+	pending  int
+	viewport viewport.Model
+}
+
+// broadcastResult to wynik polecenia na jednym hoście
+type broadcastResult struct {
+	done     bool
+	exitCode int
+	output   string
+	err      error // Połączenie albo uruchomienie polecenia nie powiodło się
+}
+
+// broadcastResultMsg niesie wynik polecenia na hoście o indeksie index
+type broadcastResultMsg struct {
+	id     int
+	index  int
+	result broadcastResult
+}
+
+// broadcastRuns numeruje uruchomienia (widok główny jest tworzony na nowo)
+var broadcastRuns int
+
+// toggleHostMark zaznacza lub odznacza host do wykonania polecenia; zaznaczenia
+// trzymamy w tej samej mapie co zaznaczone pliki, kluczem jest nazwa hosta
+func (v *mainView) toggleHostMark() {
+	if hosts := v.visibleHosts(); len(hosts) > 0 {
+		v.model.ToggleSelection(hosts[v.selectedIndex].Name)
+		v.moveSelection(1)
+	}
+}
+
+// markedHosts zwraca zaznaczone hosty w kolejności listy
+func (v *mainView) markedHosts() []models.Host {
+	var marked []models.Host
+	for _, host := range v.sortHosts(v.hosts) {
+		if v.model.IsSelected(host.Name) {
+			marked = append(marked, host)
+		}
+	}
+	return marked
+}
+
+// openBroadcast otwiera pole polecenia dla zaznaczonych hostów albo,
+// gdy żaden nie jest zaznaczony, dla hosta pod kursorem
+func (v *mainView) openBroadcast() tea.Cmd {
+	hosts := v.markedHosts()
+	if len(hosts) == 0 {
+		visible := v.visibleHosts()
+		if len(visible) == 0 {
+			return nil
+		}
+		hosts = []models.Host{visible[v.selectedIndex]}
+	}
+
+	input := textinput.New()
+	input.Placeholder = "e.g. uptime"
+	input.CharLimit = 1024
+	input.Width = max(v.width-20, 20)
+
+	v.broadcast = &broadcastState{hosts: hosts, input: input}
+	return v.broadcast.input.Focus()
+}
+
+// handleBroadcastKey obsługuje klawisze pola polecenia i raportu
+func (v *mainView) handleBroadcastKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	b := v.broadcast
+
+	// Wpisywanie polecenia
+	if b.command == "" {
+		switch msg.String() {
+		case "esc":
+			v.broadcast = nil
+			return v, nil
+		case "enter":
+			command := strings.TrimSpace(b.input.Value())
+			if command == "" {
+				return v, nil
+			}
+			return v, v.startBroadcast(command)
+		}
+		var cmd tea.Cmd
+		b.input, cmd = b.input.Update(msg)
+		return v, cmd
+	}
+
+	// Raport; zamknięcie w trakcie porzuca wyniki, które jeszcze nie dotarły
+	switch v.model.Keys().VerticalKey(msg) {
+	case "esc", "q":
+		v.broadcast = nil
+		return v, nil
+	case "up":
+		b.viewport.LineUp(1)
+		return v, nil
+	case "down":
+		b.viewport.LineDown(1)
+		return v, nil
+	}
+	var cmd tea.Cmd
+	b.viewport, cmd = b.viewport.Update(msg)
+	return v, cmd
+}
+
+// startBroadcast uruchamia polecenie na wszystkich hostach jednocześnie
+// (najwyżej broadcastConcurrency połączeń naraz)
+func (v *mainView) startBroadcast(command string) tea.Cmd {
+	b := v.broadcast
+	broadcastRuns++
+	b.id = broadcastRuns
+	b.command = command
+	b.results = make([]broadcastResult, len(b.hosts))
+	b.pending = len(b.hosts)
+	b.input.Blur()
+
+	width, height := v.broadcastSize()
+	b.viewport = viewport.New(width, height)
+	b.viewport.SetContent(v.broadcastReport())
+
+	slots := make(chan struct{}, broadcastConcurrency)
+	cmds := make([]tea.Cmd, len(b.hosts))
+	for i, host := range b.hosts {
+		id, index := b.id, i
+		cmds[i] = func() tea.Msg {
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			return broadcastResultMsg{id: id, index: index, result: v.runOnHost(host, command)}
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// runOnHost łączy się z hostem, wykonuje polecenie i zbiera jego wyjście
+// (stdout i stderr razem, w kolejności nadejścia)
+func (v *mainView) runOnHost(host models.Host, command string) broadcastResult {
+	authData, err := v.model.GetHostAuthData(&host)
+	if err != nil {
+		return broadcastResult{done: true, err: fmt.Errorf("cannot prepare credentials: %v", err)}
+	}
+	defer crypto.Wipe(authData)
+	if err := ssh.RunPreConnectHook(&host); err != nil {
+		return broadcastResult{done: true, err: fmt.Errorf("connection aborted: %v", err)}
+	}
+
+	sshClient := ssh.NewSSHClient(v.model.GetPasswords())
+	sshClient.SetJumpHostResolver(v.model.ResolveJumpHost)
+	sshClient.SetDefaultAlgorithms(v.model.GetConfig().GetAlgorithms())
+	if err := sshClient.Dial(&host, authData); err != nil {
+		// Nowe klucze hostów potwierdzamy tylko przy zwykłym połączeniu
+		var verificationRequired *ssh.HostKeyVerificationRequired
+		if errors.As(err, &verificationRequired) && !verificationRequired.Changed {
+			err = errors.New("host key is not known yet; connect once from the host list to verify it")
+		}
+		return broadcastResult{done: true, err: err}
+	}
+	defer sshClient.Disconnect()
+
+	output := &limitedBuffer{limit: maxBroadcastOutput}
+	code, err := sshClient.RunCommand(command, output, output)
+	return broadcastResult{done: true, exitCode: code, output: output.String(), err: err}
+}
+
+// handleBroadcastResult zapisuje wynik hosta i odświeża raport
+func (v *mainView) handleBroadcastResult(msg broadcastResultMsg) (tea.Model, tea.Cmd) {
+	b := v.broadcast
+	if b == nil || b.id != msg.id {
+		return v, nil
+	}
+	b.results[msg.index] = msg.result
+	b.pending--
+	b.viewport.SetContent(v.broadcastReport())
+	return v, nil
+}
+
+// broadcastReport buduje treść raportu: nagłówek z wynikiem każdego hosta i jego wyjście
+func (v *mainView) broadcastReport() string {
+	b := v.broadcast
+	var report strings.Builder
+	for i, host := range b.hosts {
+		result := b.results[i]
+
+		var status string
+		switch {
+		case !result.done:
+			status = ui.DescriptionStyle.Render("running...")
+		case result.err != nil:
+			status = ui.ErrorStyle.Render("ERROR")
+		case result.exitCode != 0:
+			status = ui.ErrorStyle.Render(fmt.Sprintf("FAILED (exit %d)", result.exitCode))
+		default:
+			status = ui.SuccessStyle.Render("OK (exit 0)")
+		}
+		header := fmt.Sprintf("── %s (%s@%s) ", host.Name, host.Login, host.IP)
+		report.WriteString(ui.LabelStyle.Render(header) + status + "\n")
+
+		if result.err != nil {
+			report.WriteString(ui.ErrorStyle.Render(result.err.Error()) + "\n")
+		}
+		if output := cleanOutput(result.output); output != "" {
+			report.WriteString(output + "\n")
+		}
+		report.WriteString("\n")
+	}
+	return report.String()
+}
+
+// broadcastSummary zlicza wyniki, np. "3 ok, 1 failed, 2 running"
+func (v *mainView) broadcastSummary() string {
+	ok, failed := 0, 0
+	for _, result := range v.broadcast.results {
+		switch {
+		case !result.done:
+		case result.err == nil && result.exitCode == 0:
+			ok++
+		default:
+			failed++
+		}
+	}
+	summary := fmt.Sprintf("%d ok, %d failed", ok, failed)
+	if v.broadcast.pending > 0 {
+		summary += fmt.Sprintf(", %d running", v.broadcast.pending)
+	}
+	return summary
+}
+
+// renderBroadcast rysuje pole polecenia albo raport w miejscu paneli (jak ekran pomocy)
+func (v *mainView) renderBroadcast() string {
+	b := v.broadcast
+	var content strings.Builder
+
+	names := make([]string, len(b.hosts))
+	for i, host := range b.hosts {
+		names[i] = host.Name
+	}
+
+	if b.command == "" {
+		content.WriteString(ui.TitleStyle.Render(fmt.Sprintf("Run command on %d host(s)", len(b.hosts))) + "\n\n")
+		content.WriteString(ui.DescriptionStyle.Render(truncateText(strings.Join(names, ", "), max(v.width-12, 20))) + "\n\n")
+		content.WriteString(ui.LabelStyle.Render("Command:") + "\n")
+		content.WriteString(b.input.View() + "\n\n")
+		content.WriteString(ui.DescriptionStyle.Render("ENTER run, ESC cancel"))
+	} else {
+		title := fmt.Sprintf("%s on %d host(s): %s", b.command, len(b.hosts), v.broadcastSummary())
+		content.WriteString(ui.TitleStyle.Render(truncateText(title, b.viewport.Width)) + "\n")
+		content.WriteString(b.viewport.View() + "\n")
+		content.WriteString(ui.DescriptionStyle.Render(fmt.Sprintf(
+			"%3.0f%%  ↑↓ scroll, PgUp/PgDn page, ESC/q close",
+			b.viewport.ScrollPercent()*100)))
+	}
+
+	return lipgloss.Place(
+		v.width,
+		v.height,
+		lipgloss.Center,
+		lipgloss.Center,
+		ui.WindowStyle.Render(content.String()),
+	)
+}
+
+// broadcastSize zwraca wymiary raportu (bez ramki, tytułu i podpowiedzi)
+func (v *mainView) broadcastSize() (int, int) {
+	return max(v.width-8, 20), max(v.height-8, 5)
+}
+
+// cleanOutput przygotowuje wyjście polecenia do wyświetlenia w raporcie
+func cleanOutput(output string) string {
+	output = strings.ReplaceAll(output, "\r\n", "\n")
+	output = strings.ReplaceAll(output, "\r", "")
+	output = strings.ReplaceAll(output, "\t", "    ")
+	return strings.TrimRight(output, "\n")
+}
+
+// limitedBuffer zbiera wyjście polecenia do limitu; stdout i stderr są
+// zapisywane z osobnych gorutyn, więc zapis jest chroniony muteksem
+type limitedBuffer struct {
+	mu        sync.Mutex
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if room := b.limit - b.buf.Len(); len(p) > room {
+		b.buf.Write(p[:max(room, 0)])
+		b.truncated = true
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *limitedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.truncated {
+		return b.buf.String() + "\n... (output truncated)"
+	}
+	return b.buf.String()
+}
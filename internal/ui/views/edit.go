@@ -4,7 +4,10 @@ package views
 
 import (
 	"fmt"
+	"net/netip"
+	"sshManager/internal/config"
 	"sshManager/internal/models"
+	"sshManager/internal/ssh"
 	"sshManager/internal/ui"
 	"strconv"
 	"strings"
@@ -26,6 +29,19 @@ const (
 	modeKeyList // Nowy tryb dla listy kluczy
 )
 
+// hostFieldCount to liczba pól w formularzu hosta
+const hostFieldCount = 20
+
+// hostNotesField to indeks pola notatek (za przełącznikami formularza hosta)
+const hostNotesField = hostFieldCount + 3
+
+// keyGeneratedMsg niesie wynik generowania pary kluczy w tle
+type keyGeneratedMsg struct {
+	privateKey string
+	publicKey  string
+	err        error
+}
+
 type editView struct {
 	model                 *ui.Model
 	activeField           int
@@ -33,6 +49,15 @@ type editView struct {
 	editingHost           bool
 	inputs                []textinput.Model
 	keyTextarea           textarea.Model // Dodajemy pole na textarea
+	keyUseAgent           bool           // Przełącznik "Use ssh-agent" w formularzu klucza
+	keyTypeIndex          int            // Wybrany typ klucza do wygenerowania (indeks w ssh.KeyTypes())
+	generatedPublicKey    string         // Klucz publiczny ostatnio wygenerowanej pary
+	generatingKey         bool
+	hostCompression       bool           // Przełącznik "Compression" w formularzu hosta (pole za polami tekstowymi)
+	hostLogSession        bool           // Przełącznik "Log session" w formularzu hosta (pole za kompresją)
+	hostSystemSSH         bool           // Przełącznik "Use system ssh" w formularzu hosta (pole za logowaniem sesji)
+	hostNotes             textarea.Model // Notatki hosta (wielowierszowe, ostatnie pole formularza)
+	testingHost           bool           // Trwa test połączenia z adresem z formularza (CTRL+T)
 	currentHost           *models.Host
 	currentPassword       *models.Password
 	errorMsg              string
@@ -48,13 +73,18 @@ type editView struct {
 	height                int
 	currentKey            *models.Key
 	keys                  []models.Key
-	authTypePasswords     bool // true jeśli aktywna jest lista haseł, false jeśli lista kluczy
+	authTypePasswords     bool              // true jeśli aktywna jest lista haseł, false jeśli lista kluczy
+	authMarks             []int             // Metody zaznaczone spacją (kodowane jak PasswordID), w kolejności prób
+	reveal                passwordReveal    // Hasło odsłonięte na chwilę na liście lub w formularzu
+	notice                string            // Komunikat o powodzeniu (np. skopiowanie hasła)
+	keyBrowser            *keyBrowser       // Otwarta przeglądarka pliku klucza (nil gdy zamknięta)
+	keyDetailsCache       map[string]string // Typ i odcisk kluczy z listy (klucz: ścieżka i dane klucza)
 }
 
 func NewEditView(model *ui.Model) *editView {
 	v := &editView{
 		model:                 model,
-		inputs:                make([]textinput.Model, 6), // Name, Description, Login, IP, Port, Password
+		inputs:                make([]textinput.Model, hostFieldCount), // Name, Description, Login, IP, Port, Group, Jump host, Local/Remote forwards, Timeout, Keepalive, TERM, Environment, Init/Pre-connect commands, Reconnect attempts, Algorithms, Env variables, Remote dir, Tags
 		width:                 model.GetTerminalWidth(),
 		height:                model.GetTerminalHeight(),
 		mode:                  modeNormal,
@@ -91,8 +121,40 @@ func NewEditView(model *ui.Model) *editView {
 		case 4:
 			t.Placeholder = "Port"
 		case 5:
-			t.Placeholder = "Password"
-			t.EchoMode = textinput.EchoPassword
+			t.Placeholder = "Group"
+		case 6:
+			t.Placeholder = "Jump host"
+		case 7:
+			t.Placeholder = "Local forwards"
+			t.CharLimit = 256
+		case 8:
+			t.Placeholder = "Remote forwards"
+			t.CharLimit = 256
+		case 9:
+			t.Placeholder = "Connect timeout"
+		case 10:
+			t.Placeholder = "Keepalive interval"
+		case 11:
+			t.Placeholder = "Terminal type"
+		case 12:
+			t.Placeholder = "Environment"
+		case 13:
+			t.Placeholder = "Init commands"
+			t.CharLimit = 256
+		case 14:
+			t.Placeholder = "Pre-connect command"
+			t.CharLimit = 256
+		case 15:
+			t.Placeholder = "Reconnect attempts"
+		case 16:
+			t.Placeholder = "Algorithms"
+			t.CharLimit = 256
+		case 18:
+			t.Placeholder = "Remote directory"
+			t.CharLimit = 256
+		case 19:
+			t.Placeholder = "Tags"
+			t.CharLimit = 256
 		}
 		v.inputs[i] = t
 	}
@@ -131,6 +193,8 @@ func (v *editView) View() string {
 
 	if v.errorMsg != "" {
 		content += "\n" + ui.ErrorStyle.Render(v.errorMsg)
+	} else if v.notice != "" {
+		content += "\n" + ui.SuccessStyle.Render(v.notice)
 	}
 
 	finalContent := ui.WindowStyle.
@@ -165,6 +229,7 @@ func (v *editView) resetState() {
 	v.passwordList = make([]models.Password, 0)
 	v.selectedItemIndex = 0
 	v.selectedPasswordIndex = 0
+	v.authMarks = nil
 
 	// Reset all inputs
 	for i := range v.inputs {
@@ -203,10 +268,10 @@ func (v *editView) renderAuthSelection(width int) string {
 			prefix := "  "
 			if v.authTypePasswords && i == v.selectedPasswordIndex {
 				prefix = "> "
-				line := fmt.Sprintf("%-*s", listWidth-1, prefix+pwd.Description)
+				line := fmt.Sprintf("%-*s", listWidth-1, prefix+v.authMarkLabel(i)+pwd.Description)
 				content.WriteString(ui.SelectedItemStyle.Render(line) + "\n")
 			} else {
-				line := fmt.Sprintf("%-*s", listWidth-1, prefix+pwd.Description)
+				line := fmt.Sprintf("%-*s", listWidth-1, prefix+v.authMarkLabel(i)+pwd.Description)
 				content.WriteString(line + "\n")
 			}
 		}
@@ -227,18 +292,23 @@ func (v *editView) renderAuthSelection(width int) string {
 			prefix := "  "
 			if !v.authTypePasswords && i == v.selectedPasswordIndex {
 				prefix = "> "
-				line := fmt.Sprintf("%-*s", listWidth-1, prefix+key.Description)
+				line := fmt.Sprintf("%-*s", listWidth-1, prefix+v.authMarkLabel(key.AuthID())+key.Description)
 				content.WriteString(ui.SelectedItemStyle.Render(line) + "\n")
 			} else {
-				line := fmt.Sprintf("%-*s", listWidth-1, prefix+key.Description)
+				line := fmt.Sprintf("%-*s", listWidth-1, prefix+v.authMarkLabel(key.AuthID())+key.Description)
 				content.WriteString(line + "\n")
 			}
 		}
 	}
 
+	if len(v.authMarks) > 0 {
+		content.WriteString("\n" + ui.DescriptionStyle.Render("Marked methods are offered to the server in the numbered order") + "\n")
+	}
+
 	content.WriteString("\n" + v.renderControls(
 		Control{"↑↓", "Navigate"},
 		Control{"Tab", "Switch section"},
+		Control{"SPACE", "Mark/unmark"},
 		Control{"ENTER", "Select"},
 		Control{"ESC", "Cancel"},
 	))
@@ -246,6 +316,51 @@ func (v *editView) renderAuthSelection(width int) string {
 	return content.String()
 }
 
+// authMarkLabel zwraca numer metody na liście zaznaczonych (lub odstęp)
+func (v *editView) authMarkLabel(id int) string {
+	if len(v.authMarks) == 0 {
+		return ""
+	}
+	for i, mark := range v.authMarks {
+		if mark == id {
+			return fmt.Sprintf("[%d] ", i+1)
+		}
+	}
+	return "    "
+}
+
+// cursorAuthID zwraca metodę wskazaną kursorem, kodowaną jak PasswordID
+func (v *editView) cursorAuthID() (int, bool) {
+	if v.authTypePasswords {
+		if v.selectedPasswordIndex >= len(v.passwordList) {
+			return 0, false
+		}
+		// Dla haseł używamy indeksu dodatniego
+		return v.selectedPasswordIndex, true
+	}
+	if v.selectedPasswordIndex >= len(v.keys) {
+		return 0, false
+	}
+	// Klucze mają stałe identyfikatory, kodowane liczbą ujemną
+	return v.keys[v.selectedPasswordIndex].AuthID(), true
+}
+
+// toggleAuthMark dodaje metodę spod kursora na koniec listy zaznaczonych
+// albo ją z tej listy usuwa
+func (v *editView) toggleAuthMark() {
+	id, ok := v.cursorAuthID()
+	if !ok {
+		return
+	}
+	for i, mark := range v.authMarks {
+		if mark == id {
+			v.authMarks = append(v.authMarks[:i], v.authMarks[i+1:]...)
+			return
+		}
+	}
+	v.authMarks = append(v.authMarks, id)
+}
+
 func (v *editView) renderPasswordList(width int) string {
 	var content strings.Builder
 	var items []struct {
@@ -260,13 +375,23 @@ func (v *editView) renderPasswordList(width int) string {
 		if len(v.keys) == 0 {
 			content.WriteString(ui.DescriptionStyle.Render("No SSH keys available. Press 'a' to add a new key.") + "\n")
 		} else {
-			// Przygotuj listę kluczy
+			// Przygotuj listę kluczy; typ i odcisk wyrównujemy do najdłuższego opisu
+			descriptions := make([]string, len(v.keys))
+			descriptionWidth := 0
 			for i, key := range v.keys {
+				descriptions[i] = key.Description
+				if key.UseAgent {
+					descriptions[i] += " [agent]"
+				}
+				descriptionWidth = max(descriptionWidth, lipgloss.Width(descriptions[i]))
+			}
+			for i, key := range v.keys {
+				description := fmt.Sprintf("%-*s  %s", descriptionWidth, descriptions[i], v.keyDetails(key))
 				items = append(items, struct {
 					description string
 					isSelected  bool
 				}{
-					description: key.Description,
+					description: description,
 					isSelected:  i == v.selectedItemIndex,
 				})
 			}
@@ -278,11 +403,15 @@ func (v *editView) renderPasswordList(width int) string {
 		} else {
 			// Przygotuj listę haseł
 			for i, pass := range v.passwords {
+				description := pass.Description
+				if v.reveal.value != "" && i == v.reveal.index {
+					description += ": " + v.reveal.value
+				}
 				items = append(items, struct {
 					description string
 					isSelected  bool
 				}{
-					description: pass.Description,
+					description: description,
 					isSelected:  i == v.selectedItemIndex,
 				})
 			}
@@ -303,12 +432,19 @@ func (v *editView) renderPasswordList(width int) string {
 	}
 
 	// Wspólne kontrolki dla obu trybów
-	content.WriteString("\n" + v.renderControls(
-		Control{"a", "Add"},
-		Control{"e", "Edit"},
-		Control{"d", "Delete"},
-		Control{"ESC", "Back"},
-	))
+	controls := []Control{
+		{"a", "Add"},
+		{"e", "Edit"},
+		{"d", "Delete"},
+	}
+	if v.mode == modePasswordList {
+		controls = append(controls, Control{"v", "Reveal"}, Control{"y", "Copy"})
+	}
+	if v.model.CanUndoDelete() {
+		controls = append(controls, Control{"CTRL+Z", "Undo delete"})
+	}
+	controls = append(controls, Control{"ESC", "Back"})
+	content.WriteString("\n" + v.renderControls(controls...))
 
 	return content.String()
 }
@@ -357,7 +493,11 @@ func (v *editView) renderPasswordEdit(width int) string {
 		if i == v.activeField {
 			inputStyle = ui.SelectedItemStyle.Width(inputWidth)
 		}
-		content.WriteString(inputStyle.Render(input.View()) + "\n\n")
+		content.WriteString(inputStyle.Render(input.View()) + "\n")
+		if i == 1 {
+			content.WriteString(v.renderPasswordFeedback(input.Value()))
+		}
+		content.WriteString("\n")
 	}
 
 	// Dodanie kontroli na dole widoku
@@ -365,11 +505,41 @@ func (v *editView) renderPasswordEdit(width int) string {
 		Control{"ENTER", "Save"},
 		Control{"ESC", "Cancel"},
 		Control{"↑/↓", "Navigate"},
+		Control{"CTRL+R", "Reveal"},
+		Control{"CTRL+Y", "Copy"},
 	))
 
 	return content.String()
 }
 
+// renderPasswordFeedback pokazuje na bieżąco siłę wpisywanego hasła i ostrzega,
+// gdy jest już zapisane pod innym opisem; to tylko podpowiedź, nie blokuje zapisu
+func (v *editView) renderPasswordFeedback(password string) string {
+	if password == "" {
+		return ""
+	}
+
+	strength := models.EstimatePasswordStrength(password)
+	style := ui.SuccessStyle
+	switch strength.Label {
+	case "very weak", "weak":
+		style = ui.ErrorStyle
+	case "fair":
+		style = ui.DescriptionStyle
+	}
+	feedback := style.Render(fmt.Sprintf("Strength: %s (~%.0f bits, %d of 4 character classes)",
+		strength.Label, strength.Entropy, strength.Classes)) + "\n"
+
+	exclude := ""
+	if v.currentPassword != nil {
+		exclude = v.currentPassword.Description
+	}
+	if reused, ok := v.model.FindReusedPassword(password, exclude); ok {
+		feedback += ui.ErrorStyle.Render(fmt.Sprintf("Warning: same as stored password '%s'", reused)) + "\n"
+	}
+	return feedback
+}
+
 func (v *editView) renderHostEdit(width int) string {
 	var content strings.Builder
 
@@ -390,10 +560,25 @@ func (v *editView) renderHostEdit(width int) string {
 		"Login:",
 		"IP/Host:",
 		"Port:",
+		"Group (optional):",
+		"Jump Host (optional):",
+		"Local Forwards (optional, comma separated):",
+		"Remote Forwards (optional, comma separated):",
+		"Connect Timeout (seconds, 0 = default):",
+		"Keepalive Interval (seconds, 0 = default):",
+		"Terminal Type (optional, TERM):",
+		"Environment (optional, e.g. prod, staging, test, dev):",
+		"Init Commands (optional, run after login, separated by ;):",
+		"Pre-connect Command (optional, run locally before connecting):",
+		"Reconnect Attempts (0 = off, when the connection drops):",
+		"Algorithms (optional, for legacy servers):",
+		"Environment Variables (optional, NAME=value, comma separated):",
+		"Remote Directory (optional, where file transfer starts):",
+		"Tags (optional, comma separated, e.g. k8s, db, eu-west):",
 	}
 
 	// Renderowanie pól wejściowych
-	for i, input := range v.inputs[:5] {
+	for i, input := range v.inputs[:hostFieldCount] {
 		content.WriteString(ui.LabelStyle.Render(labels[i]) + "\n")
 
 		inputStyle := ui.InputStyle.Width(inputWidth)
@@ -403,12 +588,66 @@ func (v *editView) renderHostEdit(width int) string {
 		content.WriteString(inputStyle.Render(input.View()) + "\n\n")
 	}
 
+	// Przełącznik kompresji
+	checkbox := "[ ] Compression"
+	if v.hostCompression {
+		checkbox = "[x] Compression"
+	}
+	checkboxStyle := ui.InputStyle.Width(inputWidth)
+	if v.activeField == hostFieldCount {
+		checkboxStyle = ui.SelectedItemStyle.Width(inputWidth)
+	}
+	content.WriteString(checkboxStyle.Render(checkbox) + "\n\n")
+
+	// Przełącznik logowania sesji
+	checkbox = "[ ] Log session to file"
+	if v.hostLogSession {
+		checkbox = "[x] Log session to file"
+	}
+	checkboxStyle = ui.InputStyle.Width(inputWidth)
+	if v.activeField == hostFieldCount+1 {
+		checkboxStyle = ui.SelectedItemStyle.Width(inputWidth)
+	}
+	content.WriteString(checkboxStyle.Render(checkbox) + "\n\n")
+
+	// Przełącznik zewnętrznego klienta ssh
+	checkbox = "[ ] Use system ssh binary"
+	if v.hostSystemSSH {
+		checkbox = "[x] Use system ssh binary"
+	}
+	checkboxStyle = ui.InputStyle.Width(inputWidth)
+	if v.activeField == hostFieldCount+2 {
+		checkboxStyle = ui.SelectedItemStyle.Width(inputWidth)
+	}
+	content.WriteString(checkboxStyle.Render(checkbox) + "\n\n")
+
+	// Notatki hosta
+	content.WriteString(ui.LabelStyle.Render("Notes (optional, ENTER adds a line, TAB moves on):") + "\n")
+	v.hostNotes.SetWidth(inputWidth)
+	v.hostNotes.SetHeight(5)
+	notesStyle := ui.InputStyle
+	if v.activeField == hostNotesField {
+		notesStyle = ui.SelectedItemStyle
+		if !v.hostNotes.Focused() {
+			v.hostNotes.Focus()
+		}
+	} else {
+		v.hostNotes.Blur()
+	}
+	content.WriteString(notesStyle.Render(v.hostNotes.View()) + "\n\n")
+
 	// Dodanie kontroli na dole widoku
-	content.WriteString(v.renderControls(
-		Control{"ENTER", "Save"},
-		Control{"ESC", "Cancel"},
-		Control{"↑/↓", "Navigate"},
-	))
+	controls := []Control{
+		{"ENTER", "Save"},
+		{"ESC", "Cancel"},
+		{"↑/↓", "Navigate"},
+		{"SPACE", "Toggle option"},
+		{"CTRL+T", "Test connection"},
+	}
+	if v.currentHost == nil {
+		controls = append(controls, Control{"CTRL+O", "Import ~/.ssh/config"})
+	}
+	content.WriteString(v.renderControls(controls...))
 
 	return content.String()
 }
@@ -417,17 +656,41 @@ func (v *editView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
+	case keyGeneratedMsg:
+		v.generatingKey = false
+		if msg.err != nil {
+			v.errorMsg = msg.err.Error()
+			return v, nil
+		}
+		v.errorMsg = ""
+		v.inputs[1].SetValue("") // Wygenerowany klucz zapisujemy jako dane, nie ścieżkę
+		v.keyTextarea.SetValue(msg.privateKey)
+		v.generatedPublicKey = msg.publicKey
+		return v, nil
+
 	case tea.WindowSizeMsg:
 		v.width = msg.Width
 		v.height = msg.Height
 		v.model.UpdateWindowSize(msg.Width, msg.Height)
 		return v, nil
 
+	case passwordHideMsg:
+		v.handlePasswordHide(msg)
+		return v, nil
+
+	case hostProbeMsg:
+		v.finishHostProbe(msg)
+		return v, nil
+
 	case tea.KeyMsg:
+		v.notice = ""
+		if v.keyBrowser != nil {
+			return v.handleKeyBrowserKey(msg)
+		}
 		if v.mode == modePasswordList || v.mode == modeKeyList {
-			switch msg.String() {
+			switch key := v.model.Keys().VerticalKey(msg); key {
 			case "tab", "shift+tab", "up", "down":
-				return v.handleNavigationKey(msg.String())
+				return v.handleNavigationKey(key)
 			}
 		}
 
@@ -444,6 +707,11 @@ func (v *editView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return v, cmd
 
 			case "enter":
+				// W polu notatek Enter zaczyna nową linię
+				if v.editingHost && v.activeField == hostNotesField {
+					v.hostNotes, cmd = v.hostNotes.Update(msg)
+					return v, cmd
+				}
 				model, cmd := v.handleEnterKey()
 				if _, ok := model.(*editView); !ok {
 					return model, cmd
@@ -451,21 +719,103 @@ func (v *editView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return v, cmd
 
 			case "tab", "shift+tab", "up", "down":
+				// Strzałki przesuwają kursor w notatkach, a z pierwszej i ostatniej linii przechodzą do innych pól
+				if v.editingHost && v.activeField == hostNotesField && v.notesCursorMoves(msg.String()) {
+					v.hostNotes, cmd = v.hostNotes.Update(msg)
+					return v, cmd
+				}
 				return v.handleNavigationKey(msg.String())
 
+			case "ctrl+g":
+				if v.mode == modeKeyEdit {
+					return v, v.generateKeyPair()
+				}
+				return v, nil
+
+			case "ctrl+f":
+				if v.mode == modeKeyEdit {
+					v.openKeyBrowser()
+				}
+				return v, nil
+
+			case "ctrl+t":
+				if v.editingHost && v.mode == modeNormal {
+					return v, v.testHostConnection()
+				}
+				return v, nil
+
+			case "ctrl+o":
+				if v.editingHost && v.currentHost == nil {
+					return v.importSSHConfig()
+				}
+				return v, nil
+
+			case "ctrl+r":
+				if !v.editingHost && v.mode == modeNormal {
+					return v, v.toggleFormReveal()
+				}
+				return v, nil
+
+			case "ctrl+y":
+				if !v.editingHost && v.mode == modeNormal {
+					v.copyPassword(true)
+				}
+				return v, nil
+
 			default:
 				// Obsługa textarea dla trybu edycji klucza
 				if v.mode == modeKeyEdit && v.activeField == 2 {
 					v.keyTextarea, cmd = v.keyTextarea.Update(msg)
 					return v, cmd
 				}
+				// Przełącznik ssh-agenta zmieniamy spacją
+				if v.mode == modeKeyEdit && v.activeField == 3 {
+					if msg.String() == " " {
+						v.keyUseAgent = !v.keyUseAgent
+					}
+					return v, nil
+				}
+				// Notatki hosta
+				if v.editingHost && v.activeField == hostNotesField {
+					v.hostNotes, cmd = v.hostNotes.Update(msg)
+					return v, cmd
+				}
+				// Przełączniki kompresji, logowania sesji i systemowego ssh w formularzu hosta
+				if v.editingHost && v.activeField >= hostFieldCount {
+					if msg.String() == " " {
+						switch v.activeField {
+						case hostFieldCount:
+							v.hostCompression = !v.hostCompression
+						case hostFieldCount + 1:
+							v.hostLogSession = !v.hostLogSession
+						default:
+							v.hostSystemSSH = !v.hostSystemSSH
+						}
+					}
+					return v, nil
+				}
+				// Wybór typu generowanego klucza
+				if v.mode == modeKeyEdit && v.activeField == 4 {
+					switch v.model.Keys().NavKey(msg) {
+					case " ", "right":
+						v.keyTypeIndex = (v.keyTypeIndex + 1) % len(ssh.KeyTypes())
+					case "left":
+						v.keyTypeIndex = (v.keyTypeIndex + len(ssh.KeyTypes()) - 1) % len(ssh.KeyTypes())
+					}
+					return v, nil
+				}
+				// Pole portu przyjmuje tylko cyfry
+				if v.editingHost && v.activeField == 4 && !isPortInput(msg) {
+					v.errorMsg = "port must be a number"
+					return v, nil
+				}
 				// Standardowa obsługa dla innych pól
 				v.inputs[v.activeField], cmd = v.inputs[v.activeField].Update(msg)
 				return v, cmd
 			}
 		}
 		// Obsługuj klawisze w normalnym trybie
-		switch msg.String() {
+		switch key := v.model.Keys().VerticalKey(msg); key {
 		case "esc":
 			model, cmd := v.handleEscapeKey()
 			if _, ok := model.(*editView); !ok {
@@ -474,7 +824,31 @@ func (v *editView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return v, cmd
 
 		case "tab", "shift+tab", "up", "down":
-			return v.handleNavigationKey(msg.String())
+			return v.handleNavigationKey(key)
+
+		case " ":
+			if v.mode == modeSelectPassword {
+				v.toggleAuthMark()
+			}
+			return v, nil
+
+		case "ctrl+z":
+			if v.mode == modePasswordList || v.mode == modeKeyList {
+				v.undoDelete()
+			}
+			return v, nil
+
+		case "v":
+			if v.mode == modePasswordList && len(v.passwords) > 0 {
+				return v, v.revealListPassword()
+			}
+			return v, nil
+
+		case "y":
+			if v.mode == modePasswordList && len(v.passwords) > 0 {
+				v.copyPassword(false)
+			}
+			return v, nil
 
 		case "enter":
 			model, cmd := v.handleEnterKey()
@@ -582,6 +956,21 @@ func (v *editView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 // internal/ui/views/edit.go
+// undoDelete przywraca ostatnio usunięty element (również host usunięty
+// w widoku głównym) i odświeża listy haseł i kluczy
+func (v *editView) undoDelete() {
+	restored, err := v.model.UndoDelete()
+	if err != nil {
+		v.errorMsg = err.Error()
+		return
+	}
+	v.errorMsg = ""
+	v.deleteConfirmation = false
+	v.passwords = v.model.GetPasswords()
+	v.keys = v.model.GetKeys()
+	v.model.SetStatus(fmt.Sprintf("Restored %s", restored), false)
+}
+
 func (v *editView) handleEscapeKey() (tea.Model, tea.Cmd) {
 	switch v.mode {
 	case modeSelectPassword:
@@ -708,9 +1097,9 @@ func (v *editView) navigateFields(key string) {
 	var maxFields int
 	switch {
 	case v.editingHost:
-		maxFields = 5 // For host editing
+		maxFields = hostNotesField + 1 // For host editing (text fields + compression, session log and system ssh toggles + notes)
 	case v.mode == modeKeyEdit:
-		maxFields = 3 // For key editing
+		maxFields = 5 // For key editing (description, path, key data, ssh-agent, key type)
 	default:
 		maxFields = 2 // For password editing
 	}
@@ -732,6 +1121,18 @@ func (v *editView) navigateFields(key string) {
 	}
 }
 
+// notesCursorMoves sprawdza, czy strzałka przesunie kursor wewnątrz notatek
+// (zamiast przejść do sąsiedniego pola formularza)
+func (v *editView) notesCursorMoves(key string) bool {
+	switch key {
+	case "up":
+		return v.hostNotes.Line() > 0
+	case "down":
+		return v.hostNotes.Line() < v.hostNotes.LineCount()-1
+	}
+	return false
+}
+
 func (v *editView) handleActionKey(key string) (tea.Model, tea.Cmd) {
 	switch v.mode {
 	case modePasswordList:
@@ -846,14 +1247,14 @@ func (v *editView) handleSave() (tea.Model, tea.Cmd) {
 			return v, nil
 		}
 
-		if path == "" && keyData == "" {
+		if path == "" && keyData == "" && !v.keyUseAgent {
 			v.errorMsg = "either path or key data must be provided"
 			return v, nil
 		}
 
 		// Dodatkowa walidacja dla klucza SSH
 		if keyData != "" {
-			if !strings.Contains(keyData, "-----BEGIN") || !strings.Contains(keyData, "-----END") {
+			if !isPEMKey(keyData) {
 				v.errorMsg = "invalid SSH key format"
 				return v, nil
 			}
@@ -869,11 +1270,18 @@ func (v *editView) handleSave() (tea.Model, tea.Cmd) {
 			}
 		}
 
+		// Parsujemy klucz już przy zapisie, żeby błąd nie wyszedł dopiero przy połączeniu
+		if err := v.validateKeyMaterial(path, keyData); err != nil {
+			v.errorMsg = err.Error()
+			return v, nil
+		}
+
 		// Create new key
 		key, err := models.NewKey(
 			description,
 			path,
 			keyData,
+			v.keyUseAgent,
 			v.model.GetCipher(),
 		)
 		if err != nil {
@@ -948,29 +1356,59 @@ func (v *editView) validateAndSaveHost() (tea.Model, tea.Cmd) {
 		return v, nil
 	}
 
-	// Zainicjalizuj tymczasowego hosta
-	v.tmpHost = &models.Host{
-		Name:        v.inputs[0].Value(),
-		Description: v.inputs[1].Value(),
-		Login:       v.inputs[2].Value(),
-		IP:          v.inputs[3].Value(),
-		Port:        v.inputs[4].Value(),
+	// Zainicjalizuj tymczasowego hosta (przy edycji zachowujemy pozostałe ustawienia)
+	v.tmpHost = &models.Host{}
+	if v.currentHost != nil {
+		host := *v.currentHost
+		v.tmpHost = &host
 	}
-
-	// Przejdź do trybu wyboru hasła
+	v.tmpHost.Name = strings.TrimSpace(v.inputs[0].Value())
+	v.tmpHost.Description = strings.TrimSpace(v.inputs[1].Value())
+	v.tmpHost.Login = strings.TrimSpace(v.inputs[2].Value())
+	v.tmpHost.IP = models.NormalizeAddress(v.inputs[3].Value())
+	v.tmpHost.Port = hostPort(v.inputs[4].Value())
+	v.tmpHost.Group = strings.TrimSpace(v.inputs[5].Value())
+	v.tmpHost.JumpHost = strings.TrimSpace(v.inputs[6].Value())
+	v.tmpHost.LocalForwards = splitList(v.inputs[7].Value())
+	v.tmpHost.RemoteForwards = splitList(v.inputs[8].Value())
+	v.tmpHost.ConnectTimeout, _ = parseSeconds(v.inputs[9].Value(), maxConnectTimeout)
+	v.tmpHost.KeepAliveInterval, _ = parseSeconds(v.inputs[10].Value(), maxKeepAliveInterval)
+	v.tmpHost.TerminalType = strings.TrimSpace(v.inputs[11].Value())
+	v.tmpHost.Environment = models.NormalizeEnvironment(v.inputs[12].Value())
+	v.tmpHost.InitCommands = splitCommands(v.inputs[13].Value())
+	v.tmpHost.PreConnectCommand = strings.TrimSpace(v.inputs[14].Value())
+	v.tmpHost.ReconnectAttempts, _ = parseReconnectAttempts(v.inputs[15].Value())
+	v.tmpHost.Algorithms, _ = models.ParseAlgorithms(v.inputs[16].Value())
+	v.tmpHost.Env, _ = models.ParseEnv(v.inputs[17].Value())
+	v.tmpHost.RemoteDir = strings.TrimSpace(v.inputs[18].Value())
+	v.tmpHost.Tags = models.NormalizeTags(splitList(v.inputs[19].Value()))
+	v.tmpHost.Compression = v.hostCompression
+	v.tmpHost.LogSession = v.hostLogSession
+	v.tmpHost.UseSystemSSH = v.hostSystemSSH
+	v.tmpHost.Notes = strings.TrimSpace(v.hostNotes.Value())
+
+	// Przejdź do trybu wyboru hasła; kilka metod hosta pozostaje zaznaczonych
 	v.mode = modeSelectPassword
 	v.passwordList = passwords
 	v.selectedPasswordIndex = 0
+	v.authMarks = nil
+	if len(v.tmpHost.AuthIDs) > 1 {
+		v.authMarks = append([]int(nil), v.tmpHost.AuthIDs...)
+	}
 	return v, nil
 }
 
 func (v *editView) saveHostWithPassword() (tea.Model, tea.Cmd) {
-	if v.authTypePasswords {
-		// Dla haseł używamy indeksu dodatniego
-		v.tmpHost.PasswordID = v.selectedPasswordIndex
+	// Zaznaczone metody mają pierwszeństwo przed pozycją kursora
+	if len(v.authMarks) > 0 {
+		v.tmpHost.SetAuthIDs(v.authMarks)
 	} else {
-		// Dla kluczy używamy indeksu ujemnego
-		v.tmpHost.PasswordID = -(v.selectedPasswordIndex + 1) // +1 żeby uniknąć problemu z zerem
+		id, ok := v.cursorAuthID()
+		if !ok {
+			v.errorMsg = "Please select an authentication method"
+			return v, nil
+		}
+		v.tmpHost.SetAuthIDs([]int{id})
 	}
 
 	// Aktualizacja lub dodanie hosta
@@ -1004,6 +1442,41 @@ func (v *editView) saveHostWithPassword() (tea.Model, tea.Cmd) {
 	return NewMainView(v.model), nil
 }
 
+// importSSHConfig importuje hosty z ~/.ssh/config i wraca do widoku głównego
+func (v *editView) importSSHConfig() (tea.Model, tea.Cmd) {
+	path, err := config.DefaultSSHConfigPath()
+	if err != nil {
+		v.errorMsg = err.Error()
+		return v, nil
+	}
+
+	result, err := v.model.GetConfig().ImportSSHConfig(path)
+	if err != nil {
+		v.errorMsg = fmt.Sprintf("Import failed: %v", err)
+		return v, nil
+	}
+
+	if len(result.Imported) > 0 {
+		if err := v.model.SaveConfig(); err != nil {
+			v.errorMsg = fmt.Sprintf("Failed to save configuration: %v", err)
+			return v, nil
+		}
+	}
+
+	status := fmt.Sprintf("Imported %d host(s) from %s", len(result.Imported), path)
+	if len(result.Skipped) > 0 {
+		status += fmt.Sprintf(", skipped %d existing", len(result.Skipped))
+	}
+
+	v.mode = modeNormal
+	v.editing = false
+	v.resetState()
+	v.model.SetStatus(status, false)
+
+	v.model.SetActiveView(ui.ViewMain)
+	return NewMainView(v.model), nil
+}
+
 func (v *editView) initializeHostInputs() {
 	// Reset all inputs first
 	for i := range v.inputs {
@@ -1018,6 +1491,38 @@ func (v *editView) initializeHostInputs() {
 		v.inputs[2].SetValue(v.currentHost.Login)
 		v.inputs[3].SetValue(v.currentHost.IP)
 		v.inputs[4].SetValue(v.currentHost.Port)
+		v.inputs[5].SetValue(v.currentHost.Group)
+		v.inputs[6].SetValue(v.currentHost.JumpHost)
+		v.inputs[7].SetValue(strings.Join(v.currentHost.LocalForwards, ", "))
+		v.inputs[8].SetValue(strings.Join(v.currentHost.RemoteForwards, ", "))
+		if v.currentHost.ConnectTimeout > 0 {
+			v.inputs[9].SetValue(strconv.Itoa(v.currentHost.ConnectTimeout))
+		}
+		if v.currentHost.KeepAliveInterval > 0 {
+			v.inputs[10].SetValue(strconv.Itoa(v.currentHost.KeepAliveInterval))
+		}
+		v.inputs[11].SetValue(v.currentHost.TerminalType)
+		v.inputs[12].SetValue(v.currentHost.Environment)
+		v.inputs[13].SetValue(strings.Join(v.currentHost.InitCommands, "; "))
+		v.inputs[14].SetValue(v.currentHost.PreConnectCommand)
+		if v.currentHost.ReconnectAttempts > 0 {
+			v.inputs[15].SetValue(strconv.Itoa(v.currentHost.ReconnectAttempts))
+		}
+		v.inputs[16].SetValue(v.currentHost.Algorithms.String())
+		v.inputs[17].SetValue(models.FormatEnv(v.currentHost.Env))
+		v.inputs[18].SetValue(v.currentHost.RemoteDir)
+		v.inputs[19].SetValue(strings.Join(v.currentHost.Tags, ", "))
+	}
+	v.hostCompression = v.currentHost != nil && v.currentHost.Compression
+	v.hostLogSession = v.currentHost != nil && v.currentHost.LogSession
+	v.hostSystemSSH = v.currentHost != nil && v.currentHost.UseSystemSSH
+
+	v.hostNotes = textarea.New()
+	v.hostNotes.Placeholder = "e.g. runbook links, sudo reminders, maintenance windows"
+	v.hostNotes.ShowLineNumbers = false
+	v.hostNotes.CharLimit = 4096
+	if v.currentHost != nil {
+		v.hostNotes.SetValue(v.currentHost.Notes)
 	}
 
 	// Configure field properties
@@ -1026,7 +1531,23 @@ func (v *editView) initializeHostInputs() {
 	v.inputs[1].EchoMode = textinput.EchoNormal
 	v.inputs[2].Placeholder = "Username"
 	v.inputs[3].Placeholder = "IP address or hostname"
-	v.inputs[4].Placeholder = "Port number"
+	v.inputs[4].Placeholder = fmt.Sprintf("Port number (empty for %s)", models.DefaultPort)
+	v.inputs[4].CharLimit = 5
+	v.inputs[5].Placeholder = "Group name (empty for Ungrouped)"
+	v.inputs[6].Placeholder = "Name of a bastion host (empty for direct connection)"
+	v.inputs[7].Placeholder = "e.g. 8080:localhost:80, 5432:db.internal:5432"
+	v.inputs[8].Placeholder = "e.g. 9000:localhost:3000 (remote port:local target)"
+	v.inputs[9].Placeholder = fmt.Sprintf("Empty for default (%v)", models.DefaultConnectTimeout)
+	v.inputs[10].Placeholder = fmt.Sprintf("Empty for default (%v)", models.DefaultKeepAliveInterval)
+	v.inputs[11].Placeholder = fmt.Sprintf("e.g. vt100 (empty for %s)", models.DefaultTerminalType)
+	v.inputs[12].Placeholder = "prod hosts ask for confirmation before connecting"
+	v.inputs[13].Placeholder = "e.g. cd /srv; tmux attach"
+	v.inputs[14].Placeholder = "e.g. wg-quick up office (a failure aborts the connection)"
+	v.inputs[15].Placeholder = fmt.Sprintf("Empty for no reconnect (max %d)", maxReconnectAttempts)
+	v.inputs[16].Placeholder = "e.g. kex=+diffie-hellman-group1-sha1 ciphers=+aes128-cbc hostkeys=+ssh-dss"
+	v.inputs[17].Placeholder = "e.g. LANG=en_US.UTF-8, EDITOR=vim (the server's AcceptEnv decides)"
+	v.inputs[18].Placeholder = "e.g. /var/www or ~/projects (empty for the home directory)"
+	v.inputs[19].Placeholder = "Labels across groups; press # in the host list to filter by tag"
 
 	// Focus the first field
 	v.activeField = 0
@@ -1050,43 +1571,179 @@ func (v *editView) initializePasswordInputs() {
 	v.inputs[0].Placeholder = "Password description"
 	v.inputs[1].Placeholder = "Enter password"
 	v.inputs[1].EchoMode = textinput.EchoPassword
+	v.reveal.form = false
 
 	// Focus the first field
 	v.activeField = 0
 	v.inputs[0].Focus()
 }
 
-// Helper function to check if a field contains only digits
-func isNumeric(s string) bool {
-	num, err := strconv.Atoi(s)
-	if err != nil {
-		return false
+// validateAddress sprawdza adres hosta: dwukropek oznacza adres IPv6 (podany
+// bez portu), a spacje nie występują ani w adresach, ani w nazwach hostów
+func validateAddress(address string) error {
+	if strings.ContainsAny(address, " \t") {
+		return fmt.Errorf("IP/hostname '%s' must not contain spaces", address)
+	}
+	if _, err := netip.ParseAddr(address); strings.Contains(address, ":") && err != nil {
+		return fmt.Errorf("'%s' is not a valid IPv6 address (enter the port in the Port field)", address)
+	}
+	return nil
+}
+
+// hostPort zwraca port z formularza; puste pole oznacza domyślny port SSH
+func hostPort(value string) string {
+	if port := strings.TrimSpace(value); port != "" {
+		return port
+	}
+	return models.DefaultPort
+}
+
+// isPortInput sprawdza, czy wpisywane znaki mogą należeć do numeru portu;
+// litery odrzucamy od razu, zamiast czekać z błędem na zapis
+func isPortInput(msg tea.KeyMsg) bool {
+	if msg.Type != tea.KeyRunes {
+		return true
+	}
+	for _, r := range msg.Runes {
+		if r < '0' || r > '9' {
+			return false
+		}
 	}
-	// Sprawdź czy numer portu jest w prawidłowym zakresie (1-65535)
-	return num > 0 && num <= 65535
+	return true
 }
 
 // Helper function to validate host fields
 func (v *editView) validateHostFields() error {
-	if v.inputs[0].Value() == "" {
+	name := strings.TrimSpace(v.inputs[0].Value())
+	if name == "" {
 		return fmt.Errorf("host name is required")
 	}
-	if v.inputs[2].Value() == "" {
+	if strings.TrimSpace(v.inputs[2].Value()) == "" {
 		return fmt.Errorf("login is required")
 	}
-	if v.inputs[3].Value() == "" {
+	address := models.NormalizeAddress(v.inputs[3].Value())
+	if address == "" {
 		return fmt.Errorf("IP/hostname is required")
 	}
-	if !isNumeric(v.inputs[4].Value()) {
+	if err := validateAddress(address); err != nil {
+		return err
+	}
+	port, err := strconv.Atoi(hostPort(v.inputs[4].Value()))
+	if err != nil {
 		return fmt.Errorf("port must be a valid number")
 	}
-	port, _ := strconv.Atoi(v.inputs[4].Value())
 	if port < 1 || port > 65535 {
 		return fmt.Errorf("port must be between 1 and 65535")
 	}
+	if jumpHost := strings.TrimSpace(v.inputs[6].Value()); jumpHost != "" {
+		if jumpHost == name {
+			return fmt.Errorf("host cannot be its own jump host")
+		}
+		if _, _, err := v.model.GetConfig().FindHostByName(jumpHost); err != nil {
+			return fmt.Errorf("jump host '%s' not found", jumpHost)
+		}
+	}
+	if _, err := ssh.ParseForwardSpecs(splitList(v.inputs[7].Value())); err != nil {
+		return err
+	}
+	if _, err := ssh.ParseForwardSpecs(splitList(v.inputs[8].Value())); err != nil {
+		return err
+	}
+	if _, err := parseSeconds(v.inputs[9].Value(), maxConnectTimeout); err != nil {
+		return fmt.Errorf("connect timeout %v", err)
+	}
+	if _, err := parseSeconds(v.inputs[10].Value(), maxKeepAliveInterval); err != nil {
+		return fmt.Errorf("keepalive interval %v", err)
+	}
+	if termType := strings.TrimSpace(v.inputs[11].Value()); termType != "" && !isValidTermType(termType) {
+		return fmt.Errorf("terminal type '%s' is not a valid TERM value (e.g. xterm-256color, vt100)", termType)
+	}
+	if _, err := parseReconnectAttempts(v.inputs[15].Value()); err != nil {
+		return err
+	}
+	if _, err := models.ParseAlgorithms(v.inputs[16].Value()); err != nil {
+		return err
+	}
+	if _, err := models.ParseEnv(v.inputs[17].Value()); err != nil {
+		return err
+	}
 	return nil
 }
 
+// isValidTermType sprawdza, czy wartość wygląda jak nazwa terminala z terminfo
+// (litery, cyfry oraz znaki - . + _, zaczyna się literą lub cyfrą)
+func isValidTermType(termType string) bool {
+	if len(termType) > 32 {
+		return false
+	}
+	for i, r := range termType {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case i > 0 && strings.ContainsRune("-.+_", r):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Górne limity pól liczonych w sekundach
+const (
+	maxConnectTimeout    = 600
+	maxKeepAliveInterval = 3600
+)
+
+// parseSeconds parsuje opcjonalną liczbę sekund z zakresu 0..max (puste pole = 0)
+func parseSeconds(value string, max int) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 || seconds > max {
+		return 0, fmt.Errorf("must be a number of seconds between 0 and %d", max)
+	}
+	return seconds, nil
+}
+
+// maxReconnectAttempts to górny limit prób ponownego połączenia po zerwaniu sesji
+const maxReconnectAttempts = 10
+
+// parseReconnectAttempts parsuje opcjonalną liczbę prób ponownego połączenia (puste pole = 0)
+func parseReconnectAttempts(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	attempts, err := strconv.Atoi(value)
+	if err != nil || attempts < 0 || attempts > maxReconnectAttempts {
+		return 0, fmt.Errorf("reconnect attempts must be a number between 0 and %d", maxReconnectAttempts)
+	}
+	return attempts, nil
+}
+
+// splitList dzieli wartość pola na elementy rozdzielone przecinkami, pomijając puste
+func splitList(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// splitCommands dzieli polecenia rozdzielone średnikami, pomijając puste
+func splitCommands(value string) []string {
+	var result []string
+	for _, command := range strings.Split(value, ";") {
+		if command = strings.TrimSpace(command); command != "" {
+			result = append(result, command)
+		}
+	}
+	return result
+}
+
 // Helper function to validate password fields
 func (v *editView) validatePasswordFields() error {
 	if v.inputs[0].Value() == "" {
@@ -1121,10 +1778,16 @@ func (v *editView) initializeKeyInputs() {
 	v.keyTextarea.Placeholder = "Paste SSH key here (optional)"
 	v.keyTextarea.ShowLineNumbers = false
 	v.keyTextarea.CharLimit = 4096
+	v.keyUseAgent = false
+	v.keyTypeIndex = 0
+	v.generatedPublicKey = ""
+	v.generatingKey = false
+	v.keyBrowser = nil
 
 	// Jeśli edytujemy istniejący klucz
 	if v.currentKey != nil {
 		v.inputs[0].SetValue(v.currentKey.Description)
+		v.keyUseAgent = v.currentKey.UseAgent
 		path, err := v.currentKey.GetKeyPath()
 		if err == nil {
 			v.inputs[1].SetValue(path)
@@ -1137,6 +1800,10 @@ func (v *editView) initializeKeyInputs() {
 }
 
 func (v *editView) renderKeyEdit(width int) string {
+	if v.keyBrowser != nil {
+		return v.renderKeyBrowser()
+	}
+
 	var content strings.Builder
 
 	// Tytuł
@@ -1181,16 +1848,77 @@ func (v *editView) renderKeyEdit(width int) string {
 	}
 	content.WriteString(textareaStyle.Render(v.keyTextarea.View()) + "\n\n")
 
+	// Przełącznik ssh-agenta
+	checkbox := "[ ] Use ssh-agent"
+	if v.keyUseAgent {
+		checkbox = "[x] Use ssh-agent"
+	}
+	checkboxStyle := ui.InputStyle.Width(inputWidth)
+	if v.activeField == 3 {
+		checkboxStyle = ui.SelectedItemStyle.Width(inputWidth)
+	}
+	content.WriteString(checkboxStyle.Render(checkbox) + "\n\n")
+
+	// Typ klucza dla akcji Generate
+	content.WriteString(ui.LabelStyle.Render("Generate Key Type:") + "\n")
+	var keyTypes []string
+	for i, keyType := range ssh.KeyTypes() {
+		if i == v.keyTypeIndex {
+			keyTypes = append(keyTypes, "("+keyType+")")
+		} else {
+			keyTypes = append(keyTypes, " "+keyType+" ")
+		}
+	}
+	keyTypeStyle := ui.InputStyle.Width(inputWidth)
+	if v.activeField == 4 {
+		keyTypeStyle = ui.SelectedItemStyle.Width(inputWidth)
+	}
+	content.WriteString(keyTypeStyle.Render(strings.Join(keyTypes, "  ")) + "\n\n")
+
+	if v.generatingKey {
+		content.WriteString(ui.DescriptionStyle.Render("Generating key pair...") + "\n\n")
+	} else if v.generatedPublicKey != "" {
+		// Klucz publiczny do skopiowania na serwer (authorized_keys)
+		content.WriteString(ui.LabelStyle.Render("Public Key (add to ~/.ssh/authorized_keys):") + "\n")
+		content.WriteString(lipgloss.NewStyle().Width(inputWidth).Render(v.generatedPublicKey) + "\n\n")
+	}
+
 	// Dodanie informacji pomocniczej
 	content.WriteString(ui.DescriptionStyle.Render(
-		"Note: Provide either Key Path or Key Data, not both\n\n"))
+		"Note: Provide either Key Path or Key Data, not both\n" +
+			"With ssh-agent enabled, the key path/data is only used when the agent is unavailable\n\n"))
 
 	// Dodanie kontroli na dole widoku
 	content.WriteString(v.renderControls(
 		Control{"ENTER", "Save"},
 		Control{"ESC", "Cancel"},
 		Control{"↑/↓", "Navigate"},
+		Control{"SPACE", "Toggle agent"},
+		Control{"Ctrl+G", "Generate"},
+		Control{"Ctrl+F", "Load file"},
 	))
 
 	return content.String()
 }
+
+// generateKeyPair generuje w tle nową parę kluczy wybranego typu; klucz
+// prywatny trafia do pola Key Data i jest zapisywany jak wklejony klucz
+func (v *editView) generateKeyPair() tea.Cmd {
+	if v.generatingKey {
+		return nil
+	}
+
+	keyType := ssh.KeyTypes()[v.keyTypeIndex]
+	comment := strings.TrimSpace(v.inputs[0].Value())
+	v.generatingKey = true
+	v.errorMsg = ""
+
+	return func() tea.Msg {
+		privateKey, publicKey, err := ssh.GenerateKeyPair(keyType, comment)
+		return keyGeneratedMsg{
+			privateKey: privateKey,
+			publicKey:  publicKey,
+			err:        err,
+		}
+	}
+}
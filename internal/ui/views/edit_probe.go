@@ -0,0 +1,86 @@
+// internal/ui/views/edit_probe.go
+
+package views
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"sshManager/internal/models"
+	"sshManager/internal/ssh"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// hostProbeMsg niesie wynik testu połączenia z formularza hosta
+type hostProbeMsg struct {
+	address  string
+	jumpHost string
+	result   ssh.ProbeResult
+	err      error
+}
+
+// testHostConnection sprawdza w tle, czy adres i port z formularza są osiągalne
+// (CTRL+T); literówki w adresie wychodzą na jaw przed pierwszym połączeniem
+func (v *editView) testHostConnection() tea.Cmd {
+	if v.testingHost {
+		return nil
+	}
+
+	host := models.NormalizeAddress(v.inputs[3].Value())
+	if host == "" {
+		v.errorMsg = "IP/hostname is required"
+		return nil
+	}
+	port := hostPort(v.inputs[4].Value())
+	if number, err := strconv.Atoi(port); err != nil || number < 1 || number > 65535 {
+		v.errorMsg = "port must be between 1 and 65535"
+		return nil
+	}
+
+	address := net.JoinHostPort(host, port)
+	jumpHost := strings.TrimSpace(v.inputs[6].Value())
+	v.testingHost = true
+	v.errorMsg = ""
+	v.notice = fmt.Sprintf("Testing connection to %s...", address)
+
+	return func() tea.Msg {
+		result, err := ssh.ProbeHost(host, port)
+		return hostProbeMsg{address: address, jumpHost: jumpHost, result: result, err: err}
+	}
+}
+
+// finishHostProbe pokazuje wynik testu połączenia pod formularzem
+func (v *editView) finishHostProbe(msg hostProbeMsg) {
+	v.testingHost = false
+
+	// Host za jump hostem zwykle nie jest osiągalny wprost, więc to tylko informacja
+	var via string
+	if msg.jumpHost != "" {
+		via = fmt.Sprintf(" (tested directly; connections go through jump host '%s')", msg.jumpHost)
+	}
+
+	if msg.err != nil {
+		v.notice = ""
+		v.errorMsg = msg.err.Error() + via
+		return
+	}
+
+	latency := "<1 ms"
+	if ms := msg.result.Latency.Milliseconds(); ms > 0 {
+		latency = fmt.Sprintf("%d ms", ms)
+	}
+	details := []string{latency}
+	if len(msg.result.Addresses) > 0 {
+		details = append(details, "resolved to "+strings.Join(msg.result.Addresses, ", "))
+	}
+	if msg.result.Banner != "" {
+		details = append(details, msg.result.Banner)
+	} else {
+		details = append(details, "no SSH banner received")
+	}
+	v.errorMsg = ""
+	v.notice = fmt.Sprintf("%s is reachable (%s)%s", msg.address, strings.Join(details, ", "), via)
+}
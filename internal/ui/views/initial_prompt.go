@@ -14,6 +14,7 @@ import (
 type initialPromptModel struct {
 	password      []rune
 	configPath    string
+	notice        string // Komunikat nad polem klucza (np. o zablokowaniu aplikacji)
 	errorMessage  string
 	width, height int
 }
@@ -46,6 +47,18 @@ func NewInitialPromptModel(configPath string) *initialPromptModel {
 	}
 }
 
+// NewLockPromptModel tworzy ekran blokady: to samo pytanie o klucz szyfrowania,
+// z komunikatem wyjaśniającym, dlaczego trzeba go podać ponownie
+func NewLockPromptModel(configPath, notice string, width, height int) *initialPromptModel {
+	return &initialPromptModel{
+		password:   []rune{},
+		configPath: configPath,
+		notice:     notice,
+		width:      width,
+		height:     height,
+	}
+}
+
 func (m *initialPromptModel) Init() tea.Cmd {
 	return nil
 }
@@ -57,6 +70,12 @@ func (m *initialPromptModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		return m, nil
 
+	case messages.PasswordRejectedMsg:
+		// Błędny klucz - czyścimy pole i pytamy ponownie
+		m.password = []rune{}
+		m.errorMessage = string(msg)
+		return m, nil
+
 	case tea.KeyMsg:
 		switch msg.Type {
 		case tea.KeyRunes:
@@ -125,14 +144,12 @@ func (m *initialPromptModel) View() string {
 	maskedPassword := strings.Repeat("*", len(m.password))
 
 	// Połączenie wszystkich elementów
-	content := lipgloss.JoinVertical(
-		lipgloss.Center,
-		asciiArtRendered,
-		"",
-		configInfo,
-		"",
-		passwordPrompt+maskedPassword,
-	)
+	lines := []string{asciiArtRendered, "", configInfo, ""}
+	if m.notice != "" {
+		lines = append(lines, promptStyle.Render(m.notice), "")
+	}
+	lines = append(lines, passwordPrompt+maskedPassword)
+	content := lipgloss.JoinVertical(lipgloss.Center, lines...)
 
 	// Dodanie komunikatu o błędzie, jeśli istnieje
 	if m.errorMessage != "" {
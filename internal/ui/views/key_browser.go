@@ -0,0 +1,153 @@
+// internal/ui/views/key_browser.go
+
+package views
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sshManager/internal/ssh"
+	"sshManager/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// keyBrowser to przeglądarka lokalnych plików do wczytania klucza prywatnego
+// w formularzu klucza. Korzysta z lokalnego panelu widoku transferu, więc
+// lista plików wygląda i sortuje się tak samo jak tam.
+type keyBrowser struct {
+	files *transferView
+}
+
+// newKeyBrowser otwiera przeglądarkę w ~/.ssh (albo w katalogu domowym, gdy ~/.ssh nie istnieje)
+func newKeyBrowser(model *ui.Model) (*keyBrowser, error) {
+	start := filepath.Join(getHomeDir(), ".ssh")
+	if info, err := os.Stat(start); err != nil || !info.IsDir() {
+		start = getHomeDir()
+	}
+
+	files := &transferView{
+		model:      model,
+		showHidden: true, // Klucze często leżą w ukrytych katalogach
+		localPanel: Panel{path: start, active: true},
+	}
+	if err := files.updateLocalPanel(); err != nil {
+		return nil, err
+	}
+	return &keyBrowser{files: files}, nil
+}
+
+// isPEMKey sprawdza, czy dane wyglądają na klucz w formacie PEM
+func isPEMKey(keyData string) bool {
+	return strings.Contains(keyData, "-----BEGIN") && strings.Contains(keyData, "-----END")
+}
+
+// validateKeyMaterial parsuje klucz z pola Key Data albo z pliku podanego w Key Path
+func (v *editView) validateKeyMaterial(path, keyData string) error {
+	if keyData != "" {
+		return ssh.ValidatePrivateKey([]byte(keyData), v.keyUseAgent)
+	}
+	if path == "" {
+		return nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("cannot read key file: %v", err)
+	}
+	if err := ssh.ValidatePrivateKey(content, v.keyUseAgent); err != nil {
+		return fmt.Errorf("%s: %v", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// openKeyBrowser otwiera wybór pliku klucza (CTRL+F w formularzu klucza)
+func (v *editView) openKeyBrowser() {
+	browser, err := newKeyBrowser(v.model)
+	if err != nil {
+		v.errorMsg = fmt.Sprintf("Failed to open file browser: %v", err)
+		return
+	}
+	v.errorMsg = ""
+	v.keyBrowser = browser
+}
+
+// handleKeyBrowserKey obsługuje klawisze w przeglądarce plików klucza
+func (v *editView) handleKeyBrowserKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	files := v.keyBrowser.files
+	panel := &files.localPanel
+
+	switch v.model.Keys().VerticalKey(msg) {
+	case "esc":
+		v.keyBrowser = nil
+	case "up":
+		files.navigatePanel(panel, -1)
+	case "down":
+		files.navigatePanel(panel, 1)
+	case "backspace":
+		if err := files.changeDirectory(panel, filepath.Dir(panel.path)); err != nil {
+			v.errorMsg = err.Error()
+		}
+	case "enter":
+		if len(panel.entries) == 0 {
+			return v, nil
+		}
+		entry := panel.entries[panel.selectedIndex]
+		if entry.isDir {
+			if err := files.enterDirectory(panel); err != nil {
+				v.errorMsg = err.Error()
+			}
+			return v, nil
+		}
+		v.loadKeyFile(filepath.Join(panel.path, entry.name), entry.size)
+	}
+	return v, nil
+}
+
+// loadKeyFile wczytuje wybrany plik do pola Key Data; plik, który nie jest
+// kluczem PEM albo nie mieści się w polu, jest odrzucany, a przeglądarka
+// zostaje otwarta
+func (v *editView) loadKeyFile(path string, size int64) {
+	if size > int64(v.keyTextarea.CharLimit) {
+		v.errorMsg = fmt.Sprintf("%s is too large to be a private key", filepath.Base(path))
+		return
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		v.errorMsg = fmt.Sprintf("Failed to read key file: %v", err)
+		return
+	}
+	keyData := string(content)
+	if !isPEMKey(keyData) {
+		v.errorMsg = fmt.Sprintf("%s is not a PEM private key", filepath.Base(path))
+		return
+	}
+
+	// Klucz zapisujemy jako dane, więc ścieżka nie może być jednocześnie ustawiona
+	v.inputs[1].SetValue("")
+	v.keyTextarea.SetValue(keyData)
+	v.generatedPublicKey = ""
+	v.keyBrowser = nil
+	v.errorMsg = ""
+	v.notice = fmt.Sprintf("Key loaded from %s", path)
+}
+
+// renderKeyBrowser rysuje przeglądarkę plików w miejscu formularza klucza
+func (v *editView) renderKeyBrowser() string {
+	var content strings.Builder
+	content.WriteString(ui.TitleStyle.Render("Select Private Key File") + "\n\n")
+
+	// Panel ma taki sam rozmiar jak w widoku transferu
+	files := v.keyBrowser.files
+	files.width = v.width
+	content.WriteString(files.renderPanel(&files.localPanel) + "\n\n")
+
+	content.WriteString(v.renderControls(
+		Control{"ENTER", "Open/Load"},
+		Control{"BACKSPACE", "Up"},
+		Control{"↑/↓", "Navigate"},
+		Control{"ESC", "Cancel"},
+	))
+	return content.String()
+}
@@ -0,0 +1,65 @@
+// internal/ui/views/key_details.go
+
+package views
+
+import (
+	"os"
+
+	"sshManager/internal/models"
+	"sshManager/internal/ssh"
+)
+
+// keyDetails zwraca typ i odcisk klucza do listy kluczy, np.
+// "ed25519  SHA256:abc...". Lista jest rysowana przy każdym odświeżeniu,
+// a parsowanie kluczy RSA jest kosztowne, więc wyniki trzymamy w pamięci.
+func (v *editView) keyDetails(key models.Key) string {
+	cacheKey := key.Path + "\x00" + key.KeyData
+	if details, ok := v.keyDetailsCache[cacheKey]; ok {
+		return details
+	}
+
+	details := v.describeKey(key)
+	if v.keyDetailsCache == nil {
+		v.keyDetailsCache = make(map[string]string)
+	}
+	v.keyDetailsCache[cacheKey] = details
+	return details
+}
+
+// describeKey wczytuje klucz (z zaszyfrowanych danych albo z pliku) i opisuje go;
+// błąd dotyczy tylko tego klucza, a nie całej listy
+func (v *editView) describeKey(key models.Key) string {
+	var keyData []byte
+	switch {
+	case key.KeyData != "":
+		if v.model.GetCipher() == nil {
+			return "locked"
+		}
+		data, err := key.GetKeyData(v.model.GetCipher())
+		if err != nil {
+			return "cannot decrypt"
+		}
+		keyData = []byte(data)
+	case key.Path != "":
+		data, err := os.ReadFile(key.Path)
+		if err != nil {
+			return "cannot read file"
+		}
+		keyData = data
+	default:
+		return "agent only"
+	}
+
+	info, err := ssh.DescribePrivateKey(keyData)
+	if err != nil {
+		return "invalid key"
+	}
+	if info.Encrypted && info.Fingerprint == "" {
+		return "encrypted"
+	}
+	details := info.Type + "  " + info.Fingerprint
+	if info.Encrypted {
+		details += "  (encrypted)"
+	}
+	return details
+}
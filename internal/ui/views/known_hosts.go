@@ -0,0 +1,216 @@
+// internal/ui/views/known_hosts.go
+
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"sshManager/internal/ssh"
+	"sshManager/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// knownHostsView pokazuje wpisy z pliku known_hosts aplikacji i pozwala usunąć
+// klucz hosta, np. po przeinstalowaniu serwera
+type knownHostsView struct {
+	model              *ui.Model
+	entries            []ssh.KnownHost
+	selectedIndex      int
+	deleteConfirmation bool
+	errorMsg           string
+	status             string
+	width, height      int
+}
+
+// NewKnownHostsView tworzy widok wpisów known_hosts
+func NewKnownHostsView(model *ui.Model) *knownHostsView {
+	v := &knownHostsView{
+		model:  model,
+		width:  model.GetTerminalWidth(),
+		height: model.GetTerminalHeight(),
+	}
+	v.reload()
+	return v
+}
+
+// reload ponownie wczytuje plik known_hosts, zachowując pozycję kursora
+func (v *knownHostsView) reload() {
+	entries, err := ssh.LoadKnownHosts()
+	if err != nil {
+		v.errorMsg = err.Error()
+	}
+	v.entries = entries
+	if v.selectedIndex >= len(v.entries) {
+		v.selectedIndex = max(len(v.entries)-1, 0)
+	}
+}
+
+// selectEntry zaznacza pierwszy wpis dotyczący hosta o podanym adresie i porcie
+func (v *knownHostsView) selectEntry(ip, port string) {
+	for i, entry := range v.entries {
+		if entry.Matches(ip, port) {
+			v.selectedIndex = i
+			return
+		}
+	}
+}
+
+func (v *knownHostsView) Init() tea.Cmd {
+	return nil
+}
+
+func (v *knownHostsView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		v.width = msg.Width
+		v.height = msg.Height
+		v.model.UpdateWindowSize(msg.Width, msg.Height)
+		return v, nil
+
+	case tea.KeyMsg:
+		// Każdy klawisz poza 'd' anuluje potwierdzenie usunięcia
+		if msg.String() != "d" {
+			v.deleteConfirmation = false
+		}
+
+		switch v.model.Keys().VerticalKey(msg) {
+		case "esc", "q":
+			mainView := NewMainView(v.model)
+			return mainView, mainView.Init()
+		case "up":
+			if v.selectedIndex > 0 {
+				v.selectedIndex--
+			}
+		case "down":
+			if v.selectedIndex < len(v.entries)-1 {
+				v.selectedIndex++
+			}
+		case "r":
+			v.errorMsg = ""
+			v.status = ""
+			v.reload()
+		case "d", "delete":
+			return v.handleDelete()
+		}
+	}
+	return v, nil
+}
+
+// handleDelete usuwa zaznaczony wpis po ponownym naciśnięciu 'd'
+func (v *knownHostsView) handleDelete() (tea.Model, tea.Cmd) {
+	if len(v.entries) == 0 {
+		return v, nil
+	}
+	if !v.deleteConfirmation {
+		v.errorMsg = "Press 'd' again to confirm deletion"
+		v.deleteConfirmation = true
+		return v, nil
+	}
+
+	v.deleteConfirmation = false
+	entry := v.entries[v.selectedIndex]
+	if err := ssh.RemoveKnownHost(entry); err != nil {
+		v.errorMsg = fmt.Sprintf("Failed to remove host key: %v", err)
+		return v, nil
+	}
+
+	v.errorMsg = ""
+	v.status = fmt.Sprintf("Removed %s key of %s; it will be verified again on the next connection",
+		entry.KeyType, strings.Join(entry.Hosts, ", "))
+	v.reload()
+	return v, nil
+}
+
+// hostNames zwraca nazwy skonfigurowanych hostów, których dotyczy wpis
+func (v *knownHostsView) hostNames(entry ssh.KnownHost) []string {
+	var names []string
+	for _, host := range v.model.GetConfig().GetHosts() {
+		if entry.Matches(host.IP, host.Port) {
+			names = append(names, host.Name)
+		}
+	}
+	return names
+}
+
+func (v *knownHostsView) View() string {
+	contentWidth := min(v.width-40, 160)
+	listWidth := contentWidth - 4
+
+	var content strings.Builder
+	content.WriteString(ui.TitleStyle.Render("Known Hosts") + "\n")
+	if path, err := ssh.KnownHostsPath(); err == nil {
+		content.WriteString(ui.DescriptionStyle.Render(path) + "\n")
+	}
+	content.WriteString("\n")
+
+	if len(v.entries) == 0 {
+		content.WriteString(ui.DescriptionStyle.Render("No known host keys. Keys are added when you accept them on the first connection.") + "\n")
+	}
+
+	// Lista przewijana tak, aby zaznaczony wpis był zawsze widoczny
+	visible := max(v.height-16, 3)
+	start := 0
+	if v.selectedIndex >= visible {
+		start = v.selectedIndex - visible + 1
+	}
+	end := min(start+visible, len(v.entries))
+
+	for i := start; i < end; i++ {
+		entry := v.entries[i]
+		description := strings.Join(entry.Hosts, ", ")
+		if names := v.hostNames(entry); len(names) > 0 {
+			description += " (" + strings.Join(names, ", ") + ")"
+		}
+		if entry.Marker != "" {
+			description = entry.Marker + " " + description
+		}
+		description = fmt.Sprintf("%s  %s %s", description, entry.KeyType, entry.Fingerprint)
+		if len(description) > listWidth-3 {
+			description = description[:listWidth-6] + "..."
+		}
+
+		if i == v.selectedIndex {
+			line := fmt.Sprintf("%-*s", listWidth-1, "> "+description)
+			content.WriteString(ui.SelectedItemStyle.Render(line) + "\n")
+		} else {
+			line := fmt.Sprintf("%-*s", listWidth-1, "  "+description)
+			content.WriteString(line + "\n")
+		}
+	}
+	if len(v.entries) > visible {
+		content.WriteString(ui.DescriptionStyle.Render(fmt.Sprintf("%d-%d of %d", start+1, end, len(v.entries))) + "\n")
+	}
+
+	controls := []Control{{"d", "Delete"}, {"r", "Reload"}, {"ESC", "Back"}}
+	content.WriteString("\n")
+	for i, ctrl := range controls {
+		if i > 0 {
+			content.WriteString("    ")
+		}
+		content.WriteString(ui.ButtonStyle.Render(ctrl.key) + " - " + ctrl.description)
+	}
+
+	if v.status != "" {
+		content.WriteString("\n\n" + ui.SuccessStyle.Render(v.status))
+	}
+	if v.errorMsg != "" {
+		content.WriteString("\n\n" + ui.ErrorStyle.Render(v.errorMsg))
+	}
+
+	finalContent := ui.WindowStyle.
+		Width(contentWidth).
+		Render(content.String())
+
+	return lipgloss.Place(
+		v.width,
+		v.height,
+		lipgloss.Center,
+		lipgloss.Center,
+		finalContent,
+		lipgloss.WithWhitespaceChars(""),
+		lipgloss.WithWhitespaceForeground(lipgloss.Color("0")),
+	)
+}
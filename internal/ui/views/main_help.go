@@ -0,0 +1,113 @@
+// internal/ui/views/main_help.go
+
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"sshManager/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// mainShortcut opisuje skrót widoku głównego. Z tej samej listy budowana jest
+// tabela poleceń pod listą hostów i ekran pomocy (F1/?), więc oba zawsze
+// pokazują te same klawisze.
+type mainShortcut struct {
+	keys   []string // Klawisze (nazwy jak w tea.KeyMsg, sekwencje ESC jako "ESC+4")
+	column string   // Kolumna tabeli poleceń; pusta - skrót tylko na ekranie pomocy
+	help   string   // Opis na ekranie pomocy
+}
+
+// mainShortcuts zwraca skróty widoku głównego; klawisze nawigacji pochodzą z KeyMap
+func mainShortcuts(keys ui.KeyMap) []mainShortcut {
+	return []mainShortcut{
+		{[]string{"enter", "c"}, "Connect", "Connect to the selected host (or double-click it)"},
+		{keys.Up.Keys(), "Navigate", "Move the selection up"},
+		{keys.Down.Keys(), "Navigate", "Move the selection down"},
+		{[]string{"/"}, "Filter", "Filter hosts (ESC clears the filter)"},
+		{[]string{"#"}, "Tag", "Show only hosts with a tag (ESC shows all hosts)"},
+		{[]string{"g"}, "Fold Group", "Fold the group of the selected host"},
+		{[]string{"G"}, "Fold Group", "Unfold all groups"},
+		{[]string{"o"}, "Sort/Move", "Change the host sort order"},
+		{[]string{"ctrl+up", "ctrl+down"}, "Sort/Move", "Move the selected host up/down"},
+		{[]string{"[", "]"}, "", "Scroll the notes of the selected host"},
+		{[]string{"e", "f4", "ESC+4"}, "Edit Host", "Edit the selected host"},
+		{[]string{"h"}, "Add Host", "Add a new host"},
+		{[]string{"p"}, "Pass", "Manage passwords"},
+		{[]string{"t"}, "Transfer", "Transfer files to/from the selected host"},
+		{[]string{"ctrl+]"}, "", "In a shell session: close the shell and transfer files over the same connection"},
+		{[]string{"d", "f8", "ESC+8"}, "Delete Host", "Delete the selected host (after confirmation; dd confirms at once)"},
+		{[]string{"ctrl+k"}, "Keys/Known", "Manage SSH keys"},
+		{[]string{"K"}, "Keys/Known", "Manage known host keys"},
+		{[]string{"I"}, "Install Key", "Install a public key on the selected host"},
+		{[]string{"x"}, "Mark/Run", "Mark or unmark the selected host"},
+		{[]string{"X"}, "Mark/Run", "Run a command on the marked hosts (or the selected host)"},
+		{[]string{"space"}, "Theme", "Switch to the next color theme"},
+		{[]string{"T"}, "Theme", "Pick a color theme with preview"},
+		{[]string{"S"}, "Sync", "Sync with the API now"},
+		{[]string{"P"}, "", "Switch to another configuration profile"},
+		{[]string{"ctrl+r"}, "", "Restore configuration from a sync backup"},
+		{[]string{"ctrl+z"}, "", "Undo the last deleted host, password or key"},
+		{[]string{"f1", "?"}, "Help", "Show this help"},
+		{[]string{"q", "ctrl+c"}, "Quit", "Quit sshManager"},
+	}
+}
+
+// shortcutTable zwraca nagłówki i klawisze tabeli poleceń; skróty z tą samą
+// kolumną są łączone, a "ctrl+" skracane do "^"
+func shortcutTable(shortcuts []mainShortcut) (headers, cells []string) {
+	column := make(map[string]int)
+	for _, shortcut := range shortcuts {
+		if shortcut.column == "" {
+			continue
+		}
+		label := strings.ReplaceAll(ui.KeyLabel(shortcut.keys...), "ctrl+", "^")
+		if i, ok := column[shortcut.column]; ok {
+			cells[i] += "/" + label
+			continue
+		}
+		column[shortcut.column] = len(headers)
+		headers = append(headers, shortcut.column)
+		cells = append(cells, label)
+	}
+	return headers, cells
+}
+
+// renderHelp rysuje ekran pomocy w stylu pomocy widoku transferu
+func (v *mainView) renderHelp() string {
+	shortcuts := mainShortcuts(v.model.Keys())
+
+	width := 0
+	for _, shortcut := range shortcuts {
+		width = max(width, lipgloss.Width(ui.KeyLabel(shortcut.keys...)))
+	}
+
+	var help strings.Builder
+	help.WriteString("\n Main View Help\n --------------\n")
+	for _, shortcut := range shortcuts {
+		label := ui.KeyLabel(shortcut.keys...)
+		padding := strings.Repeat(" ", width-lipgloss.Width(label))
+		help.WriteString(fmt.Sprintf(" %s%s  - %s\n", label, padding, shortcut.help))
+	}
+	help.WriteString("\n Press ESC, F1 or ? to close\n")
+
+	return lipgloss.Place(
+		v.width,
+		v.height,
+		lipgloss.Center,
+		lipgloss.Center,
+		ui.WindowStyle.Render(ui.DescriptionStyle.Render(help.String())),
+	)
+}
+
+// handleHelpKey zamyka ekran pomocy; pozostałe klawisze są ignorowane
+func (v *mainView) handleHelpKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q", "f1", "?":
+		v.showHelp = false
+	}
+	return v, nil
+}
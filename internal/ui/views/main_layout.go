@@ -0,0 +1,223 @@
+// internal/ui/views/main_layout.go
+
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"sshManager/internal/models"
+	"sshManager/internal/ui"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+)
+
+// Układ widoku głównego. Szerokości paneli wyliczamy z szerokości terminala,
+// tak jak w widoku transferu; na wąskich terminalach panele są rysowane
+// jeden pod drugim.
+const (
+	defaultPanelWidth   = 45  // Szerokość panelu, zanim znamy rozmiar terminala
+	minPanelWidth       = 30  // Najwęższy panel w układzie obok siebie
+	maxMainContentWidth = 160 // Powyżej tej szerokości nie rozciągamy paneli
+	windowFrameWidth    = 6   // Ramka (2) i poziomy padding (4) WindowStyle
+	panelSeparator      = "  +  "
+)
+
+// mainLayout opisuje rozmieszczenie paneli widoku głównego
+type mainLayout struct {
+	panelWidth int  // Szerokość panelu bez ramki (jak w PanelStyle.Width)
+	stacked    bool // Panele jeden pod drugim zamiast obok siebie
+}
+
+// layout wyznacza układ paneli dla bieżącej szerokości terminala
+func (v *mainView) layout() mainLayout {
+	if v.width <= 0 {
+		return mainLayout{panelWidth: defaultPanelWidth}
+	}
+
+	inner := min(v.width-windowFrameWidth, maxMainContentWidth)
+	sideBySide := (inner-lipgloss.Width(panelSeparator))/2 - 2 // 2 to ramka panelu
+	if sideBySide >= minPanelWidth {
+		return mainLayout{panelWidth: sideBySide}
+	}
+	return mainLayout{panelWidth: max(inner-2, 10), stacked: true}
+}
+
+// contentWidth zwraca szerokość obu paneli razem z ramkami i separatorem
+func (l mainLayout) contentWidth() int {
+	if l.stacked {
+		return l.panelWidth + 2
+	}
+	return 2*(l.panelWidth+2) + lipgloss.Width(panelSeparator)
+}
+
+// joinPanels łączy panel hostów i szczegółów zgodnie z układem
+func (l mainLayout) joinPanels(left, right string) string {
+	if l.stacked {
+		return lipgloss.JoinVertical(lipgloss.Left, left, right)
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Left, left, panelSeparator, right)
+}
+
+// truncateText skraca tekst do width kolumn, kończąc go wielokropkiem
+func truncateText(text string, width int) string {
+	if lipgloss.Width(text) <= width {
+		return text
+	}
+	if width <= 0 {
+		return ""
+	}
+
+	var truncated strings.Builder
+	used := 0
+	for _, r := range text {
+		w := lipgloss.Width(string(r))
+		if used+w > width-1 {
+			break
+		}
+		truncated.WriteRune(r)
+		used += w
+	}
+	return truncated.String() + "…"
+}
+
+// renderCommandTables rysuje tabelę poleceń; kolumny, które nie mieszczą się
+// w szerokości width, przechodzą do kolejnej tabeli poniżej
+func renderCommandTables(headers, cells []string, width int) string {
+	styleFunc := func(row, col int) lipgloss.Style {
+		switch {
+		case row == -1: // Nagłówki
+			return lipgloss.NewStyle().
+				Padding(0, 1).
+				Foreground(ui.Subtle).
+				Align(lipgloss.Center)
+		default: // Skróty
+			return lipgloss.NewStyle().
+				Padding(0, 1).
+				Foreground(ui.Special)
+		}
+	}
+	render := func(headers, cells []string) string {
+		return table.New().
+			Border(lipgloss.NormalBorder()).
+			BorderStyle(lipgloss.NewStyle().Foreground(ui.StatusBar)).
+			StyleFunc(styleFunc).
+			Headers(headers...).
+			Row(cells...).
+			Render()
+	}
+
+	var tables []string
+	start, used := 0, 1 // Lewa krawędź tabeli
+	for i := range headers {
+		// Kolumna z paddingiem i prawą krawędzią
+		column := max(lipgloss.Width(headers[i]), lipgloss.Width(cells[i])) + 3
+		if i > start && used+column > width {
+			tables = append(tables, render(headers[start:i], cells[start:i]))
+			start, used = i, 1
+		}
+		used += column
+	}
+	tables = append(tables, render(headers[start:], cells[start:]))
+
+	return lipgloss.JoinVertical(lipgloss.Left, tables...)
+}
+
+// hostListRow to jedna linia listy hostów: nagłówek grupy albo host
+type hostListRow struct {
+	header       bool
+	group        string
+	host         models.Host
+	visibleIndex int // Indeks hosta w visibleHosts (dla nagłówków -1)
+}
+
+// hostListRows układa listę hostów w linie, tak jak są rysowane w panelu
+func (v *mainView) hostListRows() []hostListRow {
+	hosts := v.filteredHosts()
+	grouped := v.groupedView()
+
+	var rows []hostListRow
+	visibleIndex := 0
+	for i, host := range hosts {
+		// Nagłówek grupy przed pierwszym hostem z danej grupy
+		group := hostGroupName(host)
+		if grouped && (i == 0 || hostGroupName(hosts[i-1]) != group) {
+			rows = append(rows, hostListRow{header: true, group: group, visibleIndex: -1})
+		}
+		if grouped && v.collapsed[group] {
+			continue
+		}
+		rows = append(rows, hostListRow{group: group, host: host, visibleIndex: visibleIndex})
+		visibleIndex++
+	}
+	return rows
+}
+
+// hostListHeight zwraca liczbę linii listy hostów mieszczących się na ekranie
+// (0 - bez ograniczenia, zanim znamy rozmiar terminala)
+func (v *mainView) hostListHeight() int {
+	if v.height <= 0 {
+		return 0
+	}
+
+	// Ramka i padding okna (4), tytuł z pustą linią (2), pusta linia pod
+	// panelami i na końcu (2), ramka panelu (2), tytuł panelu z pustą linią (2)
+	// oraz stopka listy (1)
+	reserved := 13 + lipgloss.Height(v.renderStatusBar())
+	if v.filtering || v.filter != "" {
+		reserved++
+	}
+	if v.layout().stacked {
+		reserved += lipgloss.Height(v.renderDetailsPanel())
+	}
+	return max(v.height-reserved, 3)
+}
+
+// scrollHostList przesuwa listę hostów tak, żeby zaznaczony host był
+// widoczny razem z nagłówkiem swojej grupy
+func (v *mainView) scrollHostList(rows []hostListRow, height int) {
+	if height <= 0 || len(rows) <= height {
+		v.hostScroll = 0
+		return
+	}
+
+	selected := 0
+	for i, row := range rows {
+		if row.visibleIndex == v.selectedIndex {
+			selected = i
+			break
+		}
+	}
+	top := selected
+	if top > 0 && rows[top-1].header {
+		top--
+	}
+
+	if top < v.hostScroll {
+		v.hostScroll = top
+	} else if selected >= v.hostScroll+height {
+		v.hostScroll = selected - height + 1
+	}
+	// Po powiększeniu okna nie zostawiamy pustego miejsca pod listą
+	v.hostScroll = max(min(v.hostScroll, len(rows)-height), 0)
+}
+
+// hostListFooter opisuje widoczny fragment listy, np. "Showing 11-30 of 42 hosts"
+func (v *mainView) hostListFooter(visible []hostListRow) string {
+	first, last := -1, -1
+	for _, row := range visible {
+		if row.header {
+			continue
+		}
+		if first == -1 {
+			first = row.visibleIndex
+		}
+		last = row.visibleIndex
+	}
+	total := len(v.visibleHosts())
+	if first == -1 {
+		return fmt.Sprintf("\n  %d hosts", total)
+	}
+	return fmt.Sprintf("\n  Showing %d-%d of %d hosts", first+1, last+1, total)
+}
@@ -0,0 +1,71 @@
+// internal/ui/views/main_notes.go
+
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"sshManager/internal/models"
+	"sshManager/internal/ui"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// notesHeight to liczba linii notatek widocznych naraz w panelu szczegółów;
+// dłuższe notatki przewijamy klawiszami [ i ]
+const notesHeight = 6
+
+// notesLines zawija notatki hosta do szerokości panelu szczegółów
+func (v *mainView) notesLines(host models.Host) []string {
+	if host.Notes == "" {
+		return nil
+	}
+	width := max(v.layout().panelWidth-4, 10) // Wcięcie i margines panelu
+	lines := strings.Split(lipgloss.NewStyle().Width(width).Render(host.Notes), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return lines
+}
+
+// notesOffset zwraca pierwszą widoczną linię notatek; przewinięcie dotyczy
+// tylko hosta, dla którego je ustawiono, więc inny host zaczyna od początku
+func (v *mainView) notesOffset(host models.Host, lines int) int {
+	if host.Name != v.notesHost {
+		return 0
+	}
+	return max(min(v.notesScroll, lines-notesHeight), 0)
+}
+
+// scrollNotes przewija notatki zaznaczonego hosta o delta linii
+func (v *mainView) scrollNotes(delta int) {
+	hosts := v.visibleHosts()
+	if len(hosts) == 0 {
+		return
+	}
+	host := hosts[v.selectedIndex]
+	lines := len(v.notesLines(host))
+	v.notesScroll = max(min(v.notesOffset(host, lines)+delta, lines-notesHeight), 0)
+	v.notesHost = host.Name
+}
+
+// renderNotes rysuje sekcję notatek panelu szczegółów (pustą, gdy host ich nie ma)
+func (v *mainView) renderNotes(host models.Host) string {
+	lines := v.notesLines(host)
+	if len(lines) == 0 {
+		return ""
+	}
+
+	var content strings.Builder
+	content.WriteString("\n\n  " + ui.LabelStyle.Render("Notes:"))
+	offset := v.notesOffset(host, len(lines))
+	if len(lines) > notesHeight {
+		content.WriteString(" " + ui.DescriptionStyle.Render(
+			fmt.Sprintf("(%d-%d of %d, [ ] to scroll)", offset+1, offset+notesHeight, len(lines))))
+	}
+	for _, line := range lines[offset:min(offset+notesHeight, len(lines))] {
+		content.WriteString("\n  " + ui.Infotext.Render(line))
+	}
+	return content.String()
+}
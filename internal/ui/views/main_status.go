@@ -0,0 +1,37 @@
+// internal/ui/views/main_status.go
+
+package views
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// statusTimeout to czas, po którym komunikat statusu znika z paska
+const statusTimeout = 4 * time.Second
+
+// statusExpiredMsg kasuje komunikat statusu o numerze id. Nowszy komunikat
+// ma inny numer, więc nie zostanie skasowany przez timer poprzedniego.
+type statusExpiredMsg struct {
+	id int
+}
+
+// setStatus pokazuje komunikat statusu i zwraca komendę, która go skasuje.
+// Błędy (errMsg) nie wygasają - znikają po ESC albo przy kolejnej akcji.
+func (v *mainView) setStatus(status string) tea.Cmd {
+	v.status = status
+	v.statusID++
+	id := v.statusID
+	return tea.Tick(statusTimeout, func(time.Time) tea.Msg {
+		return statusExpiredMsg{id: id}
+	})
+}
+
+// handleStatusExpired kasuje komunikat, jeśli nie został już zastąpiony
+func (v *mainView) handleStatusExpired(msg statusExpiredMsg) (tea.Model, tea.Cmd) {
+	if msg.id == v.statusID {
+		v.status = ""
+	}
+	return v, nil
+}
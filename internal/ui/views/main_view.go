@@ -3,9 +3,12 @@
 package views
 
 import (
+	"errors"
 	"fmt"
-	"path/filepath"
+	"net"
+	"sort"
 	"sshManager/internal/config"
+	"sshManager/internal/crypto"
 	"sshManager/internal/models"
 	"sshManager/internal/sync"
 	"sshManager/internal/ui"
@@ -16,9 +19,11 @@ import (
 
 	"sshManager/internal/ssh"
 
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/charmbracelet/lipgloss/table"
 )
 
 type mainView struct {
@@ -37,12 +42,49 @@ type mainView struct {
 	waitingForKeyConfirmation bool
 	hostKeyFingerprint        string
 	pendingConnection         struct {
-		host     *models.Host
-		password string
+		host *models.Host // Host czekający na potwierdzenie klucza; dane autoryzacji przygotowujemy ponownie
 	}
-	popup *components.Popup // Dodane nowe pole
+	popup       *components.Popup // Dodane nowe pole
+	filterInput textinput.Model   // Pole wyszukiwania hostów
+	filtering   bool              // true gdy pole filtra przyjmuje znaki
+	filter      string            // Aktualny filtr listy hostów
+	collapsed   map[string]bool   // Zwinięte grupy hostów
+	installKey  struct {          // Stan wyboru klucza do instalacji na hoście
+		host  models.Host
+		index int
+	}
+	hostKeyChanged struct { // Host, którego klucz się zmienił (do otwarcia known hosts)
+		ip   string
+		port string
+	}
+	themePicker struct { // Stan wyboru motywu z podglądem
+		list     list.Model
+		original string // Motyw przywracany po ESC
+	}
+	authPrompt  *authPromptState // Pytania keyboard-interactive (np. 2FA) w trakcie łączenia
+	syncing     bool             // true w trakcie ręcznej synchronizacji z API
+	syncSpinner spinner.Model    // Wskaźnik trwającej synchronizacji
+	restore     struct {         // Stan wyboru kopii do przywrócenia
+		backups []sync.Backup
+		index   int
+	}
+	hostRows      map[int]int     // Linia panelu hostów -> indeks hosta w visibleHosts (do obsługi myszy)
+	lastClick     mouseClick      // Ostatnie kliknięcie hosta (wykrywanie dwukliku)
+	showHelp      bool            // true gdy otwarty jest ekran pomocy (F1/?)
+	pendingDelete string          // Host czekający na potwierdzenie usunięcia
+	hostScroll    int             // Pierwsza widoczna linia listy hostów
+	notesScroll   int             // Pierwsza widoczna linia notatek hosta notesHost
+	notesHost     string          // Host, którego notatki przewinięto
+	tagFilter     string          // Tag, do którego ograniczono listę hostów (pusty gdy brak)
+	tagPicker     list.Model      // Lista tagów w popupie wyboru tagu
+	profilePicker list.Model      // Lista profili w popupie wyboru profilu
+	statusID      int             // Numer bieżącego komunikatu statusu (do jego wygaśnięcia)
+	broadcast     *broadcastState // Polecenie wykonywane na zaznaczonych hostach (nil gdy zamknięte)
 }
 
+// ungroupedLabel to nazwa grupy dla hostów bez przypisanej grupy
+const ungroupedLabel = "Ungrouped"
+
 type connectError string
 type errMsg string
 
@@ -50,6 +92,19 @@ type hostKeyVerificationMsg struct {
 	IP          string
 	Port        string
 	Fingerprint string
+	Changed     bool     // Klucz różni się od zapisanego w known_hosts
+	KnownKeys   []string // Odciski zapisanych kluczy (gdy Changed)
+}
+
+// newHostKeyVerificationMsg tworzy wiadomość o weryfikacji klucza na podstawie błędu połączenia
+func newHostKeyVerificationMsg(err *ssh.HostKeyVerificationRequired) hostKeyVerificationMsg {
+	return hostKeyVerificationMsg{
+		IP:          err.IP,
+		Port:        err.Port,
+		Fingerprint: err.Fingerprint,
+		Changed:     err.Changed,
+		KnownKeys:   err.KnownKeys,
+	}
 }
 
 type connectSuccessMsg struct{}
@@ -58,12 +113,25 @@ type connectFinishedMsg struct {
 	err error
 }
 
+// keyInstalledMsg niesie wynik instalacji klucza publicznego na hoście
+type keyInstalledMsg struct {
+	host      string
+	key       string
+	installed bool
+	err       error
+}
+
 func (e connectError) Error() string {
 	return string(e)
 }
 
 func NewMainView(model *ui.Model) *mainView {
-	return &mainView{
+	filterInput := textinput.New()
+	filterInput.Placeholder = "Filter hosts..."
+	filterInput.Prompt = "/ "
+	filterInput.CharLimit = 64
+
+	v := &mainView{
 		model:        model,
 		showHostList: true,
 		hosts:        model.GetHosts(),
@@ -72,8 +140,337 @@ func NewMainView(model *ui.Model) *mainView {
 		height:       model.GetTerminalHeight(), // Dodane
 
 		// Inicjalizacja popupów na nil
-		popup: nil,
+		popup:       nil,
+		filterInput: filterInput,
+		collapsed:   make(map[string]bool),
+	}
+
+	// Lista zaczyna od hosta użytego ostatnio (także po powrocie z sesji lub transferu)
+	if last := model.GetConfig().GetLastHost(); last != "" {
+		v.selectHost(last)
+	}
+
+	// Błąd synchronizacji przy starcie pokazujemy zamiast cichego przejścia w tryb lokalny
+	if err := model.TakeSyncError(); err != nil {
+		v.popup = components.NewPopup(
+			components.PopupMessage,
+			"Sync Error",
+			fmt.Sprintf("%v\n\nWorking in local mode. Press S to retry.", err),
+			70,
+			10,
+			v.width,
+			v.height,
+		)
+	}
+	return v
+}
+
+// hostGroupName zwraca nazwę grupy hosta, puste grupy trafiają do "Ungrouped"
+func hostGroupName(host models.Host) string {
+	if host.Group == "" {
+		return ungroupedLabel
+	}
+	return host.Group
+}
+
+// sortHostsByGroup sortuje kopię listy hostów po grupie, a następnie po nazwie.
+// Hosty bez grupy zawsze lądują na końcu listy.
+func sortHostsByGroup(hosts []models.Host) []models.Host {
+	sorted := make([]models.Host, len(hosts))
+	copy(sorted, hosts)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		gi, gj := sorted[i].Group, sorted[j].Group
+		if gi != gj {
+			if gi == "" || gj == "" {
+				return gj == ""
+			}
+			return strings.ToLower(gi) < strings.ToLower(gj)
+		}
+		return strings.ToLower(sorted[i].Name) < strings.ToLower(sorted[j].Name)
+	})
+	return sorted
+}
+
+// hostSortOrders to kolejność przełączania sortowania klawiszem 'o'
+var hostSortOrders = []string{
+	models.HostSortGroup,
+	models.HostSortName,
+	models.HostSortRecent,
+	models.HostSortManual,
+}
+
+// hostSortLabels to opisy sortowania wyświetlane w nagłówku listy
+var hostSortLabels = map[string]string{
+	models.HostSortGroup:  "by group",
+	models.HostSortName:   "by name",
+	models.HostSortRecent: "recent first",
+	models.HostSortManual: "manual order",
+}
+
+// groupedView zwraca true, gdy lista jest podzielona na grupy
+func (v *mainView) groupedView() bool {
+	return v.model.GetConfig().GetHostSort() == models.HostSortGroup
+}
+
+// sortHosts sortuje kopię listy hostów według porządku zapisanego w konfiguracji
+func (v *mainView) sortHosts(hosts []models.Host) []models.Host {
+	sorted := make([]models.Host, len(hosts))
+	copy(sorted, hosts)
+
+	switch v.model.GetConfig().GetHostSort() {
+	case models.HostSortName:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return strings.ToLower(sorted[i].Name) < strings.ToLower(sorted[j].Name)
+		})
+	case models.HostSortRecent:
+		// Hosty, z którymi jeszcze się nie łączono, trafiają na koniec w kolejności alfabetycznej
+		sort.SliceStable(sorted, func(i, j int) bool {
+			if !sorted[i].LastConnected.Equal(sorted[j].LastConnected) {
+				return sorted[i].LastConnected.After(sorted[j].LastConnected)
+			}
+			return strings.ToLower(sorted[i].Name) < strings.ToLower(sorted[j].Name)
+		})
+	case models.HostSortManual:
+		// Kolejność z konfiguracji
+	default:
+		return sortHostsByGroup(sorted)
+	}
+	return sorted
+}
+
+// cycleHostSort przełącza sortowanie listy hostów i zapisuje wybór w konfiguracji
+func (v *mainView) cycleHostSort() tea.Cmd {
+	var selected string
+	if hosts := v.visibleHosts(); len(hosts) > 0 {
+		selected = hosts[v.selectedIndex].Name
+	}
+
+	current := v.model.GetConfig().GetHostSort()
+	next := hostSortOrders[0]
+	for i, order := range hostSortOrders {
+		if order == current {
+			next = hostSortOrders[(i+1)%len(hostSortOrders)]
+			break
+		}
+	}
+	v.model.GetConfig().SetHostSort(next)
+	if err := v.model.GetConfig().SaveLocal(); err != nil {
+		v.errMsg = fmt.Sprintf("Failed to save configuration: %v", err)
+		return nil
+	}
+
+	v.selectHost(selected)
+	v.errMsg = ""
+	return v.setStatus("Hosts sorted " + hostSortLabels[next])
+}
+
+// selectHost zaznacza hosta o podanej nazwie, jeśli jest widoczny
+func (v *mainView) selectHost(name string) {
+	v.selectedIndex = 0
+	for i, host := range v.visibleHosts() {
+		if host.Name == name {
+			v.selectedIndex = i
+			return
+		}
+	}
+}
+
+// moveSelectedHost przesuwa zaznaczonego hosta w górę (-1) lub w dół (1)
+// listy w konfiguracji; działa tylko przy ręcznej kolejności
+func (v *mainView) moveSelectedHost(direction int) {
+	if v.model.GetConfig().GetHostSort() != models.HostSortManual {
+		v.errMsg = "Switch to manual order (o) to move hosts"
+		return
+	}
+
+	hosts := v.visibleHosts()
+	target := v.selectedIndex + direction
+	if len(hosts) == 0 || target < 0 || target >= len(hosts) {
+		return
 	}
+
+	// Przy aktywnym filtrze sąsiad na liście nie musi być sąsiadem w konfiguracji
+	cfg := v.model.GetConfig()
+	_, from, err := cfg.FindHostByName(hosts[v.selectedIndex].Name)
+	if err != nil {
+		v.errMsg = err.Error()
+		return
+	}
+	_, to, err := cfg.FindHostByName(hosts[target].Name)
+	if err != nil {
+		v.errMsg = err.Error()
+		return
+	}
+	if err := cfg.MoveHost(from, to-from); err != nil {
+		v.errMsg = err.Error()
+		return
+	}
+	if err := v.model.SaveConfig(); err != nil {
+		v.errMsg = fmt.Sprintf("Failed to save configuration: %v", err)
+		return
+	}
+
+	v.hosts = v.model.GetHosts()
+	v.selectedIndex = target
+	v.errMsg = ""
+	v.status = ""
+}
+
+// recordConnection zapisuje czas połączenia z hostem i zwiększa licznik połączeń;
+// wywoływane dopiero po udanym nawiązaniu połączenia SSH
+func (v *mainView) recordConnection(host *models.Host) {
+	if host == nil {
+		return
+	}
+	if err := v.model.GetConfig().MarkHostConnected(host.Name, time.Now()); err != nil {
+		return
+	}
+	v.hosts = v.model.GetHosts()
+	if err := v.model.GetConfig().SaveLocal(); err != nil {
+		v.errMsg = fmt.Sprintf("Failed to save configuration: %v", err)
+	}
+}
+
+// rememberHost zapisuje hosta jako ostatnio używanego, aby był zaznaczony po
+// ponownym uruchomieniu programu
+func (v *mainView) rememberHost(name string) {
+	cfg := v.model.GetConfig()
+	if cfg.GetLastHost() == name {
+		return
+	}
+	cfg.SetLastHost(name)
+	if err := cfg.SaveLocal(); err != nil {
+		v.errMsg = fmt.Sprintf("Failed to save configuration: %v", err)
+	}
+}
+
+// filteredHosts zwraca hosty z wybranym tagiem pasujące do aktualnego filtra
+// (bez rozróżniania wielkości liter, po nazwie, opisie, loginie, adresie, grupie i tagach)
+func (v *mainView) filteredHosts() []models.Host {
+	if v.filter == "" && v.tagFilter == "" {
+		return v.sortHosts(v.hosts)
+	}
+
+	query := strings.ToLower(v.filter)
+	var result []models.Host
+	for _, host := range v.hosts {
+		if v.tagFilter != "" && !host.HasTag(v.tagFilter) {
+			continue
+		}
+		if strings.Contains(strings.ToLower(host.Name), query) ||
+			strings.Contains(strings.ToLower(host.Description), query) ||
+			strings.Contains(strings.ToLower(host.Login), query) ||
+			strings.Contains(strings.ToLower(host.IP), query) ||
+			strings.Contains(strings.ToLower(host.Group), query) ||
+			strings.Contains(strings.ToLower(strings.Join(host.Tags, " ")), query) {
+			result = append(result, host)
+		}
+	}
+	return v.sortHosts(result)
+}
+
+// visibleHosts zwraca przefiltrowane hosty z pominięciem zwiniętych grup.
+// selectedIndex zawsze wskazuje pozycję na tej liście.
+func (v *mainView) visibleHosts() []models.Host {
+	grouped := v.groupedView()
+	var result []models.Host
+	for _, host := range v.filteredHosts() {
+		if !grouped || !v.collapsed[hostGroupName(host)] {
+			result = append(result, host)
+		}
+	}
+	return result
+}
+
+// toggleSelectedGroup zwija lub rozwija grupę zaznaczonego hosta
+func (v *mainView) toggleSelectedGroup() {
+	hosts := v.visibleHosts()
+	if len(hosts) == 0 {
+		return
+	}
+	group := hostGroupName(hosts[v.selectedIndex])
+
+	// Zaznaczenie przechodzi na pierwszy host za zwiniętą grupą
+	for i, host := range hosts {
+		if hostGroupName(host) == group {
+			v.selectedIndex = i
+			break
+		}
+	}
+	v.collapsed[group] = true
+	v.clampSelection()
+}
+
+// expandAllGroups rozwija wszystkie zwinięte grupy
+func (v *mainView) expandAllGroups() {
+	var selected string
+	if hosts := v.visibleHosts(); len(hosts) > 0 {
+		selected = hosts[v.selectedIndex].Name
+	}
+	v.collapsed = make(map[string]bool)
+	v.selectedIndex = 0
+	for i, host := range v.visibleHosts() {
+		if host.Name == selected {
+			v.selectedIndex = i
+			break
+		}
+	}
+}
+
+// clampSelection pilnuje, aby selectedIndex mieścił się w widocznej liście
+func (v *mainView) clampSelection() {
+	if hosts := v.visibleHosts(); v.selectedIndex >= len(hosts) {
+		v.selectedIndex = max(len(hosts)-1, 0)
+	}
+}
+
+// applyFilter ustawia nowy filtr i przenosi zaznaczenie na pierwsze dopasowanie
+func (v *mainView) applyFilter(filter string) {
+	v.filter = filter
+	v.selectedIndex = 0
+	v.errMsg = ""
+}
+
+// clearFilter usuwa filtr i przywraca pełną listę hostów
+func (v *mainView) clearFilter() {
+	v.filtering = false
+	v.filterInput.Reset()
+	v.filterInput.Blur()
+	v.applyFilter("")
+}
+
+// handleFilterKey obsługuje klawisze, gdy pole filtra jest aktywne
+func (v *mainView) handleFilterKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		v.clearFilter()
+		return v, nil
+	case "enter":
+		// Zatwierdzenie filtra - zostawiamy przefiltrowaną listę
+		v.filtering = false
+		v.filterInput.Blur()
+		return v, nil
+	case "up", "down":
+		v.moveSelection(map[string]int{"up": -1, "down": 1}[msg.String()])
+		return v, nil
+	}
+
+	var cmd tea.Cmd
+	v.filterInput, cmd = v.filterInput.Update(msg)
+	if v.filterInput.Value() != v.filter {
+		v.applyFilter(v.filterInput.Value())
+	}
+	return v, cmd
+}
+
+// moveSelection przesuwa zaznaczenie w obrębie przefiltrowanej listy
+func (v *mainView) moveSelection(direction int) {
+	hosts := v.visibleHosts()
+	if len(hosts) == 0 {
+		return
+	}
+	v.selectedIndex = (v.selectedIndex + direction + len(hosts)) % len(hosts)
+	v.errMsg = ""
 }
 
 func (v *mainView) Init() tea.Cmd {
@@ -89,14 +486,22 @@ func (v *mainView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		v.width = msg.Width
 		v.height = msg.Height
 		v.model.UpdateWindowSize(msg.Width, msg.Height)
+		v.scrollHostList(v.hostListRows(), v.hostListHeight())
+		if v.broadcast != nil && v.broadcast.command != "" {
+			v.broadcast.viewport.Width, v.broadcast.viewport.Height = v.broadcastSize()
+		}
 		return v, nil
 
 	case hostKeyVerificationMsg:
+		if msg.Changed {
+			v.showHostKeyChangedPopup(msg)
+			return v, nil
+		}
 		v.popup = components.NewPopup(
 			components.PopupHostKey,
 			"Host Key Verification",
-			fmt.Sprintf("New host key for %s:%s\n\nKey fingerprint:\n%s\n",
-				msg.IP, msg.Port, msg.Fingerprint),
+			fmt.Sprintf("New host key for %s\n\nKey fingerprint:\n%s\n",
+				net.JoinHostPort(msg.IP, msg.Port), msg.Fingerprint),
 			70,
 			12,
 			v.width,
@@ -104,20 +509,74 @@ func (v *mainView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		)
 		return v, nil
 
+	case systemSSHReadyMsg:
+		host := msg.host
+		return v, tea.ExecProcess(msg.cmd, func(err error) tea.Msg {
+			return systemSSHExitedMsg{host: host, err: err}
+		})
+
+	case systemSSHExitedMsg:
+		return v.handleSystemSSHExited(msg)
+
+	case syncDoneMsg:
+		return v.handleSyncDone(msg)
+
+	case statusExpiredMsg:
+		return v.handleStatusExpired(msg)
+
+	case broadcastResultMsg:
+		return v.handleBroadcastResult(msg)
+
+	case backupRestoredMsg:
+		return v.handleBackupRestored(msg)
+
+	case spinner.TickMsg:
+		if !v.syncing {
+			return v, nil
+		}
+		var cmd tea.Cmd
+		v.syncSpinner, cmd = v.syncSpinner.Update(msg)
+		return v, cmd
+
+	case authPromptMsg:
+		v.authPrompt = &authPromptState{challenge: msg.challenge, reply: msg.reply}
+		v.showAuthPrompt()
+		return v, waitForAuthPrompt(msg.prompts)
+
 	case connectSuccessMsg:
-		v.connecting = true
+		// Baner serwera pokazujemy przed wejściem do powłoki
+		if sshClient := v.model.GetSSHClient(); sshClient != nil {
+			if banner := sanitizeBanner(sshClient.Banner()); banner != "" {
+				v.showBannerPopup(banner)
+				return v, nil
+			}
+		}
+		return v.startShell()
+
+	case keyInstalledMsg:
+		title, message := "Install Key", ""
+		switch {
+		case msg.err != nil:
+			title = "Error"
+			message = fmt.Sprintf("Failed to install key '%s' on %s:\n%v", msg.key, msg.host, msg.err)
+		case msg.installed:
+			message = fmt.Sprintf("Key '%s' added to ~/.ssh/authorized_keys on %s", msg.key, msg.host)
+		default:
+			message = fmt.Sprintf("Key '%s' is already installed on %s", msg.key, msg.host)
+		}
 		v.popup = components.NewPopup(
 			components.PopupMessage,
-			"SSH",
-			"Connecting...",
-			50,
-			7,
+			title,
+			message,
+			60,
+			8,
 			v.width,
 			v.height,
 		)
-		return v, tea.Quit
+		return v, nil
 
 	case errMsg:
+		v.cancelAuthPrompt()
 		v.popup = components.NewPopup(
 			components.PopupMessage,
 			"Error",
@@ -133,9 +592,45 @@ func (v *mainView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		v.model.SetQuitting(true)
 		return v, tea.Quit
 
+	case tea.MouseMsg:
+		return v.handleMouse(msg)
+
 	case tea.KeyMsg:
 		// Obsługa klawiszy dla popupu
 		if v.popup != nil {
+			if v.popup.Type == components.PopupSelectKey {
+				return v.handleKeySelectPopup(msg)
+			}
+			if v.popup.Type == components.PopupConfirmConnect {
+				return v.handleConfirmConnectPopup(msg)
+			}
+			if v.popup.Type == components.PopupSelectTheme {
+				return v.handleThemePickerPopup(msg)
+			}
+			if v.popup.Type == components.PopupSelectTag {
+				return v.handleTagPickerPopup(msg)
+			}
+			if v.popup.Type == components.PopupSelectProfile {
+				return v.handleProfilePickerPopup(msg)
+			}
+			if v.popup.Type == components.PopupHostKeyChanged {
+				return v.handleHostKeyChangedPopup(msg)
+			}
+			if v.popup.Type == components.PopupAuthPrompt {
+				return v.handleAuthPromptPopup(msg)
+			}
+			if v.popup.Type == components.PopupBanner {
+				return v.handleBannerPopup(msg)
+			}
+			if v.popup.Type == components.PopupSelectBackup {
+				return v.handleBackupSelectPopup(msg)
+			}
+			if v.popup.Type == components.PopupConfirmRestore {
+				return v.handleConfirmRestorePopup(msg)
+			}
+			if v.popup.Type == components.PopupDelete {
+				return v.handleDeletePopup(msg)
+			}
 			switch msg.String() {
 			case "esc", "enter":
 				if v.popup.Type == components.PopupMessage {
@@ -152,41 +647,8 @@ func (v *mainView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if v.popup.Type == components.PopupHostKey && v.waitingForKeyConfirmation {
 					v.waitingForKeyConfirmation = false
 
-					// Tworzymy instancję SSHClient
-					sshClient := ssh.NewSSHClient(v.model.GetPasswords())
-					err := sshClient.ConnectWithAcceptedKey(
-						v.pendingConnection.host,
-						v.pendingConnection.password,
-					)
-
-					if err != nil {
-						v.popup = components.NewPopup(
-							components.PopupMessage,
-							"Błąd połączenia",
-							fmt.Sprintf("Failed to connect: %v", err),
-							50,
-							7,
-							v.width,
-							v.height,
-						)
-						return v, nil
-					}
-
-					// Zapisujemy klienta SSH w modelu
-					v.model.SetSSHClient(sshClient)
-					v.connecting = true
-					v.popup = components.NewPopup(
-						components.PopupMessage,
-						"SSH",
-						"Connecting...",
-						50,
-						7,
-						v.width,
-						v.height,
-					)
-
-					// Teraz kończymy pętlę TUI, aby main.go mogło wykonać ConfigureTerminal() i StartShell()
-					return v, tea.Quit
+					v.popup = nil
+					return v, v.connectWithAcceptedKey()
 				}
 			case "n", "N":
 				if v.popup.Type == components.PopupHostKey && v.waitingForKeyConfirmation {
@@ -206,8 +668,23 @@ func (v *mainView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return v, nil
 		}
 
+		if v.broadcast != nil {
+			return v.handleBroadcastKey(msg)
+		}
+
+		// Pole filtra przejmuje wszystkie klawisze
+		if v.filtering {
+			return v.handleFilterKey(msg)
+		}
+
+		if v.showHelp {
+			return v.handleHelpKey(msg)
+		}
+
+		hosts := v.visibleHosts()
+
 		// Standardowa obsługa klawiszy nawigacji
-		switch msg.String() {
+		switch v.model.Keys().VerticalKey(msg) {
 		case "q", "ctrl+c":
 			if !v.connecting {
 				v.model.SetQuitting(true)
@@ -215,29 +692,60 @@ func (v *mainView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return v, nil
 
-		case "up", "w":
-			if len(v.hosts) > 0 && !v.connecting {
-				v.selectedIndex--
-				if v.selectedIndex < 0 {
-					v.selectedIndex = len(v.hosts) - 1
-				}
-				v.errMsg = ""
+		case "up":
+			if !v.connecting {
+				v.moveSelection(-1)
 			}
 
-		case "down", "s":
-			if len(v.hosts) > 0 && !v.connecting {
-				v.selectedIndex++
-				if v.selectedIndex >= len(v.hosts) {
-					v.selectedIndex = 0
-				}
-				v.errMsg = ""
+		case "down":
+			if !v.connecting {
+				v.moveSelection(1)
+			}
+		case "g":
+			if !v.connecting && v.groupedView() {
+				v.toggleSelectedGroup()
+			}
+		case "G":
+			if !v.connecting && v.groupedView() {
+				v.expandAllGroups()
+			}
+		case "o":
+			if !v.connecting {
+				return v, v.cycleHostSort()
+			}
+		case "ctrl+up":
+			if !v.connecting {
+				v.moveSelectedHost(-1)
+			}
+		case "ctrl+down":
+			if !v.connecting {
+				v.moveSelectedHost(1)
+			}
+		case "#":
+			if !v.connecting {
+				return v, v.openTagPicker()
+			}
+		case "P":
+			if !v.connecting {
+				return v, v.openProfilePicker()
+			}
+		case "[":
+			v.scrollNotes(-1)
+		case "]":
+			v.scrollNotes(1)
+		case "/":
+			if !v.connecting {
+				v.filtering = true
+				v.filterInput.SetValue(v.filter)
+				v.filterInput.CursorEnd()
+				return v, v.filterInput.Focus()
 			}
 		case "enter", "c":
-			if v.connecting || len(v.hosts) == 0 {
+			if v.connecting || len(hosts) == 0 {
 				return v, nil
 			}
-			return v.handleConnect()
-		case "k":
+			return v.connectSelected()
+		case "ctrl+k", "k": // "k" działa, gdy nie jest przypisane do ruchu w górę
 			if !v.connecting {
 				editView := NewEditView(v.model)
 				editView.mode = modeKeyList
@@ -246,12 +754,16 @@ func (v *mainView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				editView.selectedItemIndex = 0
 				return editView, nil
 			}
+		case "K":
+			if !v.connecting {
+				return NewKnownHostsView(v.model), nil
+			}
 		case "e", "f4":
-			if v.connecting || len(v.hosts) == 0 {
+			if v.connecting || len(hosts) == 0 {
 				return v, nil
 			}
 			editView := NewEditView(v.model)
-			editView.currentHost = &v.hosts[v.selectedIndex]
+			editView.currentHost = &hosts[v.selectedIndex]
 			editView.editingHost = true
 			editView.editing = true
 			editView.mode = modeNormal
@@ -279,24 +791,63 @@ func (v *mainView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 		case "t":
-			if v.connecting || len(v.hosts) == 0 {
+			if v.connecting {
 				return v, nil
 			}
 			return v.handleTransfer()
 
+		case "I":
+			if v.connecting || len(hosts) == 0 {
+				return v, nil
+			}
+			return v.handleInstallKey()
+
 		case "d", "f8":
-			if v.connecting || len(v.hosts) == 0 {
+			if v.connecting || len(hosts) == 0 {
 				return v, nil
 			}
 			return v.handleDelete()
 		case " ":
-			if !v.connecting && len(v.hosts) > 0 {
-				ui.SwitchTheme()
+			if !v.connecting && len(hosts) > 0 {
+				v.model.CycleTheme()
+				return v, nil
+			}
+		case "T":
+			if !v.connecting {
+				v.openThemePicker()
 				return v, nil
 			}
+		case "S":
+			if !v.connecting {
+				return v, v.startManualSync()
+			}
+
 		case "ctrl+r":
 			return v.handleRestoreBackup()
+		case "ctrl+z":
+			return v.undoDelete()
+		case "x":
+			if !v.connecting {
+				v.toggleHostMark()
+			}
+		case "X":
+			if !v.connecting {
+				return v, v.openBroadcast()
+			}
+		case "f1", "?":
+			v.showHelp = true
+			return v, nil
 		case "esc":
+			if v.filter != "" && !v.escPressed {
+				v.clearFilter()
+				return v, nil
+			}
+			if v.tagFilter != "" && !v.escPressed {
+				v.applyTagFilter("")
+				return v, nil
+			}
+			// ESC zamyka też komunikat błędu
+			v.errMsg = ""
 			v.escPressed = true
 			if v.escTimeout != nil {
 				v.escTimeout.Stop()
@@ -313,9 +864,9 @@ func (v *mainView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if v.escPressed {
 			switch msg.String() {
 			case "4":
-				if len(v.hosts) > 0 && !v.connecting {
+				if len(hosts) > 0 && !v.connecting {
 					editView := NewEditView(v.model)
-					editView.currentHost = &v.hosts[v.selectedIndex]
+					editView.currentHost = &hosts[v.selectedIndex]
 					editView.editingHost = true
 					editView.editing = true
 					editView.mode = modeNormal
@@ -325,7 +876,7 @@ func (v *mainView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				v.escPressed = false
 				return v, nil
 			case "8":
-				if len(v.hosts) > 0 && !v.connecting {
+				if len(hosts) > 0 && !v.connecting {
 					return v.handleDelete()
 				}
 				v.escPressed = false
@@ -373,94 +924,248 @@ func (v *mainView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return v, nil
 }
 
+// connectSelected łączy z zaznaczonym hostem; hosty produkcyjne
+// wymagają wcześniejszego potwierdzenia
+func (v *mainView) connectSelected() (tea.Model, tea.Cmd) {
+	host := v.visibleHosts()[v.selectedIndex]
+	if !host.IsProduction() {
+		return v.handleConnect()
+	}
+
+	v.popup = components.NewPopup(
+		components.PopupConfirmConnect,
+		"Production Host",
+		fmt.Sprintf("%s is a production host (%s@%s).\n\nConnect anyway?", host.Name, host.Login, host.IP),
+		50,
+		9,
+		v.width,
+		v.height,
+	)
+	return v, nil
+}
+
+// handleConfirmConnectPopup obsługuje potwierdzenie połączenia z hostem produkcyjnym
+func (v *mainView) handleConfirmConnectPopup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		v.popup = nil
+		return v.handleConnect()
+	case "n", "N", "esc":
+		v.popup = nil
+	}
+	return v, nil
+}
+
+// showHostKeyChangedPopup ostrzega, że host przedstawił inny klucz niż zapisany.
+// Nowego klucza nie można tu zaakceptować - stary trzeba najpierw usunąć.
+func (v *mainView) showHostKeyChangedPopup(msg hostKeyVerificationMsg) {
+	v.hostKeyChanged.ip = msg.IP
+	v.hostKeyChanged.port = msg.Port
+
+	v.popup = components.NewPopup(
+		components.PopupHostKeyChanged,
+		"WARNING: HOST KEY CHANGED",
+		hostKeyChangedMessage(msg),
+		74,
+		18+len(msg.KnownKeys),
+		v.width,
+		v.height,
+	)
+}
+
+// hostKeyChangedMessage opisuje zmieniony klucz hosta; używa go ostrzeżenie
+// w widoku głównym i w widoku transferu
+func hostKeyChangedMessage(msg hostKeyVerificationMsg) string {
+	var message strings.Builder
+	message.WriteString(ui.ErrorStyle.Render(fmt.Sprintf("The host key of %s has CHANGED!", net.JoinHostPort(msg.IP, msg.Port))) + "\n\n")
+	message.WriteString("Someone could be intercepting the connection (man-in-the-middle\n")
+	message.WriteString("attack), or the server was reinstalled and got a new key.\n\n")
+	message.WriteString("Offered key:\n" + msg.Fingerprint + "\n")
+	message.WriteString("Known key:\n" + strings.Join(msg.KnownKeys, "\n") + "\n\n")
+	message.WriteString("Verify the new key with the server administrator, then remove\n")
+	message.WriteString("the old key in known hosts and connect again.\n")
+	return message.String()
+}
+
+// handleHostKeyChangedPopup obsługuje klawisze w ostrzeżeniu o zmienionym kluczu hosta
+func (v *mainView) handleHostKeyChangedPopup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "K", "k":
+		v.waitingForKeyConfirmation = false
+		v.popup = nil
+		knownHostsView := NewKnownHostsView(v.model)
+		knownHostsView.selectEntry(v.hostKeyChanged.ip, v.hostKeyChanged.port)
+		return knownHostsView, nil
+	case "esc", "n", "N":
+		v.waitingForKeyConfirmation = false
+		v.popup = components.NewPopup(
+			components.PopupMessage,
+			"SSH",
+			"Connection cancelled",
+			50,
+			7,
+			v.width,
+			v.height,
+		)
+	}
+	return v, nil
+}
+
 func (v *mainView) handleConnect() (tea.Model, tea.Cmd) {
-	host := v.hosts[v.selectedIndex]
+	host := v.visibleHosts()[v.selectedIndex]
 	v.model.SetSelectedHost(&host)
+	v.rememberHost(host.Name)
+	if host.UseSystemSSH {
+		return v, v.prepareSystemSSH(&host)
+	}
+	prompts := make(chan authPromptMsg)
 
-	// Zwracamy komendę, która będzie wykonana asynchronicznie
-	return v, func() tea.Msg {
-		var authData string
+	// Komenda wykonywana asynchronicznie; przed połączeniem sami zamykamy
+	// kanał prompts, aby zakończyć oczekiwanie na pytania serwera
+	connect := func() tea.Msg {
+		// Przygotowanie danych autoryzacji (hasło, klucz SSH lub ssh-agent)
+		authData, err := v.model.GetHostAuthData(&host)
+		if err != nil {
+			close(prompts)
+			return errMsg(fmt.Sprintf("Cannot prepare credentials: %v", err))
+		}
+		defer crypto.Wipe(authData)
 
-		// Przygotowanie danych autoryzacji
-		if host.PasswordID < 0 {
-			// Obsługa klucza SSH
-			keyIndex := -(host.PasswordID + 1)
-			keys := v.model.GetKeys()
-			if keyIndex >= len(keys) {
-				return errMsg("Invalid key ID")
-			}
+		// Lokalne polecenie hosta (np. VPN) musi się powieść przed połączeniem
+		if err := ssh.RunPreConnectHook(&host); err != nil {
+			close(prompts)
+			return errMsg(fmt.Sprintf("Connection aborted: %v", err))
+		}
+
+		// Utworzenie klienta SSH; otwarte połączenie z hostem jest używane ponownie
+		sshClient := v.model.NewSSHClient()
 
-			key := keys[keyIndex]
-			keyPath, err := key.GetKeyPath()
-			if err != nil {
-				return errMsg(fmt.Sprintf("Failed to get key path: %v", err))
+		// Kanał do obsługi timeoutu połączenia; limit obejmuje także
+		// połączenie z hostem pośredniczącym
+		timeout := host.GetConnectTimeout()
+		if jumpHost, _, err := v.model.GetConfig().FindHostByName(host.JumpHost); host.JumpHost != "" && err == nil {
+			timeout += jumpHost.GetConnectTimeout()
+		}
+		// Czekamy na połączenie z timeoutem; pytania serwera (np. kod 2FA)
+		// trafiają do popupu przez kanał prompts
+		err = runConnect(sshClient, prompts, timeout, authData, func(authData []byte) error {
+			return sshClient.Connect(&host, authData)
+		})
+		if err != nil {
+			// Sprawdzamy czy to błąd weryfikacji klucza
+			// (może dotyczyć hosta docelowego lub pośredniczącego)
+			if verificationRequired, ok := err.(*ssh.HostKeyVerificationRequired); ok {
+				fingerprint := verificationRequired.Fingerprint
+
+				// Ustawiamy stan oczekiwania na potwierdzenie klucza
+				v.waitingForKeyConfirmation = true
+				v.hostKeyFingerprint = fingerprint
+				v.pendingConnection.host = &host
+
+				return newHostKeyVerificationMsg(verificationRequired)
 			}
-			authData = keyPath
-		} else {
-			// Obsługa hasła
-			passwords := v.model.GetPasswords()
-			if host.PasswordID >= len(passwords) {
-				return errMsg("Invalid password ID")
-			}
-
-			password := passwords[host.PasswordID]
-			decryptedPass, err := password.GetDecrypted(v.model.GetCipher())
-			if err != nil {
-				return errMsg(fmt.Sprintf("Failed to decrypt password: %v", err))
-			}
-			authData = decryptedPass
-		}
-
-		// Utworzenie klienta SSH
-		sshClient := ssh.NewSSHClient(v.model.GetPasswords())
-
-		// Kanał do obsługi timeoutu połączenia
-		connectionDone := make(chan error, 1)
-		go func() {
-			connectionDone <- sshClient.Connect(&host, authData)
-		}()
-
-		// Czekamy na połączenie z timeoutem
-		select {
-		case err := <-connectionDone:
-			if err != nil {
-				// Sprawdzamy czy to błąd weryfikacji klucza
-				if verificationRequired, ok := err.(*ssh.HostKeyVerificationRequired); ok {
-					fingerprint, err := ssh.GetHostKeyFingerprint(&host)
-					if err != nil {
-						return errMsg(fmt.Sprintf("Cannot retrieve key fingerprint: %v", err))
-					}
-
-					// Ustawiamy stan oczekiwania na potwierdzenie klucza
-					v.waitingForKeyConfirmation = true
-					v.hostKeyFingerprint = fingerprint
-					v.pendingConnection.host = &host
-					v.pendingConnection.password = authData
-
-					return hostKeyVerificationMsg{
-						IP:          verificationRequired.IP,
-						Port:        verificationRequired.Port,
-						Fingerprint: fingerprint,
-					}
-				}
-				return errMsg(fmt.Sprintf("Failed to connect: %v", err))
+			if errors.Is(err, errConnectTimedOut) {
+				return errMsg(fmt.Sprintf("Connection timed out after %v", timeout))
 			}
+			return errMsg(fmt.Sprintf("Failed to connect: %v", err))
+		}
+
+		// Połączenie udane
+		v.model.SetSSHClient(sshClient)
 
-			// Połączenie udane
-			v.model.SetSSHClient(sshClient)
+		// Zwracamy wiadomość o sukcesie po zakończeniu połączenia
+		return connectSuccessMsg{}
+	}
+
+	return v, tea.Batch(connect, waitForAuthPrompt(prompts))
+}
 
-			// Zwracamy wiadomość o sukcesie po zakończeniu połączenia
-			return connectSuccessMsg{}
+// connectWithAcceptedKey zapisuje zaakceptowany klucz hosta i łączy się w tle
+func (v *mainView) connectWithAcceptedKey() tea.Cmd {
+	host := v.pendingConnection.host
+	prompts := make(chan authPromptMsg)
 
-		case <-time.After(7 * time.Second):
-			return errMsg("Connection timed out")
+	connect := func() tea.Msg {
+		// Hasła nie czekają w pamięci na decyzję użytkownika - odszyfrowujemy je ponownie
+		authData, err := v.model.GetHostAuthData(host)
+		if err != nil {
+			close(prompts)
+			return errMsg(fmt.Sprintf("Cannot prepare credentials: %v", err))
+		}
+		defer crypto.Wipe(authData)
+
+		// Tworzymy instancję SSHClient
+		sshClient := v.model.NewSSHClient()
+		err = runConnect(sshClient, prompts, 0, authData, func(authData []byte) error {
+			return sshClient.ConnectWithAcceptedKey(host, authData)
+		})
+
+		// Przy połączeniu przez jump host drugi etap wymaga osobnego potwierdzenia
+		if verificationRequired, ok := err.(*ssh.HostKeyVerificationRequired); ok {
+			v.waitingForKeyConfirmation = true
+			v.hostKeyFingerprint = verificationRequired.Fingerprint
+			return newHostKeyVerificationMsg(verificationRequired)
+		}
+		if err != nil {
+			return errMsg(fmt.Sprintf("Failed to connect: %v", err))
 		}
+
+		// Zapisujemy klienta SSH w modelu; connectSuccessMsg kończy pętlę TUI,
+		// aby main.go mogło wykonać ConfigureTerminal() i StartShell()
+		v.model.SetSSHClient(sshClient)
+		return connectSuccessMsg{}
 	}
+
+	return tea.Batch(connect, waitForAuthPrompt(prompts))
 }
 
+// ConnectToHostByName zaznacza hosta o podanej nazwie i łączy się z nim
+// (używane przez flagę --connect); zwraca błąd, gdy hosta nie ma w konfiguracji
+func (v *mainView) ConnectToHostByName(name string) (tea.Cmd, error) {
+	for i, host := range v.visibleHosts() {
+		if host.Name == name {
+			v.selectedIndex = i
+			_, cmd := v.connectSelected()
+			return cmd, nil
+		}
+	}
+	return nil, fmt.Errorf("host '%s' not found", name)
+}
+
+// handleDelete pyta o potwierdzenie usunięcia zaznaczonego hosta
 func (v *mainView) handleDelete() (tea.Model, tea.Cmd) {
-	host := v.hosts[v.selectedIndex]
-	if err := v.model.DeleteHost(host.Name); err != nil {
+	host := v.visibleHosts()[v.selectedIndex]
+	v.pendingDelete = host.Name
+	v.popup = components.NewPopup(
+		components.PopupDelete,
+		"Delete Host",
+		fmt.Sprintf("Delete host %s (%s@%s)?\n\nThis cannot be undone. Pressing d again also confirms.", host.Name, host.Login, host.IP),
+		60,
+		9,
+		v.width,
+		v.height,
+	)
+	return v, nil
+}
+
+// handleDeletePopup usuwa host po potwierdzeniu; drugie naciśnięcie d/F8
+// działa jak "y", więc "dd" usuwa host od razu
+func (v *mainView) handleDeletePopup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y", "d", "f8":
+		v.popup = nil
+		return v.deleteHost(v.pendingDelete)
+	case "n", "N", "esc":
+		v.popup = nil
+		v.pendingDelete = ""
+	}
+	return v, nil
+}
+
+// deleteHost usuwa host i zapisuje konfigurację
+func (v *mainView) deleteHost(name string) (tea.Model, tea.Cmd) {
+	v.pendingDelete = ""
+	if err := v.model.DeleteHost(name); err != nil {
 		v.errMsg = fmt.Sprintf("Failed to delete host: %v", err)
 	} else {
 		if err := v.model.SaveConfig(); err != nil {
@@ -468,32 +1173,46 @@ func (v *mainView) handleDelete() (tea.Model, tea.Cmd) {
 			return v, nil
 		}
 		v.hosts = v.model.GetHosts()
-		if v.selectedIndex >= len(v.hosts) {
-			v.selectedIndex = len(v.hosts) - 1
-		}
-		v.status = "Host deleted successfully"
+		v.clampSelection()
+		return v, v.setStatus(fmt.Sprintf("Host %s deleted", name))
 	}
 	return v, nil
 }
 
+// undoDelete przywraca ostatnio usunięty element (również usunięty w widoku edycji)
+func (v *mainView) undoDelete() (tea.Model, tea.Cmd) {
+	restored, err := v.model.UndoDelete()
+	if err != nil {
+		v.errMsg = err.Error()
+		return v, nil
+	}
+	v.errMsg = ""
+	v.hosts = v.model.GetHosts()
+	v.clampSelection()
+	return v, v.setStatus(fmt.Sprintf("Restored %s", restored))
+}
+
 // handleTransfer pozostaje bez zmian
 
 func (v *mainView) View() string {
+	if v.showHelp {
+		return v.renderHelp()
+	}
+	if v.broadcast != nil {
+		return v.renderBroadcast()
+	}
+
 	// Przygotuj główną zawartość
 	var content strings.Builder
-	content.WriteString(ui.TitleStyle.Render("sshManager ❯ https://sshm.io") + "\n\n")
-
-	// Główny layout w stylu MC z dwoma panelami
-	leftPanel := v.renderHostPanel()
-	rightPanel := v.renderDetailsPanel()
+	title := "sshManager ❯ https://sshm.io"
+	if profile := v.model.GetConfig().GetProfile(); profile != config.DefaultProfile {
+		title += " ❯ profile: " + profile
+	}
+	content.WriteString(ui.TitleStyle.Render(title) + "\n\n")
 
-	// Połącz panele horyzontalnie
-	mainContent := lipgloss.JoinHorizontal(
-		lipgloss.Left,
-		leftPanel,
-		"  +  ", // separator
-		rightPanel,
-	)
+	// Główny layout w stylu MC z dwoma panelami (na wąskim terminalu jeden pod drugim)
+	layout := v.layout()
+	mainContent := layout.joinPanels(v.renderHostPanel(), v.renderDetailsPanel())
 
 	content.WriteString(mainContent + "\n\n")
 
@@ -532,114 +1251,209 @@ func (v *mainView) View() string {
 }
 
 func (v *mainView) renderHostPanel() string {
-	style := ui.PanelStyle.Width(45)
-	title := "Available Hosts"
+	panelWidth := v.layout().panelWidth
+	style := ui.PanelStyle.Width(panelWidth)
+	title := "Available Hosts " + ui.DescriptionStyle.Render("("+hostSortLabels[v.model.GetConfig().GetHostSort()]+")")
+	if v.tagFilter != "" {
+		title += " " + ui.LabelStyle.Render("#"+v.tagFilter)
+	}
 
+	// Linie liczymy od tytułu panelu (0); każdy wpis zaczyna się od "\n"
 	var content strings.Builder
+	line := 1
+	v.hostRows = make(map[int]int)
+	if v.filtering || v.filter != "" {
+		content.WriteString("\n" + v.filterInput.View())
+		line++
+	}
+
 	if len(v.hosts) == 0 {
-		content.WriteString(ui.DescriptionStyle.Render("\n  No hosts available\n  Press 'n' to add new host"))
+		content.WriteString(ui.DescriptionStyle.Render("\n  No hosts available\n  Press 'h' to add new host"))
+	} else if len(v.filteredHosts()) == 0 {
+		content.WriteString(ui.DescriptionStyle.Render("\n  No hosts match the filter"))
 	} else {
-		for i, host := range v.hosts {
-			prefix := "  "
-			var line string
+		// Rysujemy tylko linie mieszczące się w oknie, od hostScroll
+		rows := v.hostListRows()
+		height := v.hostListHeight()
+		v.scrollHostList(rows, height)
+		end := len(rows)
+		if height > 0 {
+			end = min(v.hostScroll+height, len(rows))
+		}
 
-			// Renderujemy nazwę hosta z użyciem HostStyle
-			hostName := ui.HostStyle.Render(host.Name)
+		for _, row := range rows[v.hostScroll:end] {
+			if row.header {
+				content.WriteString(v.renderGroupHeader(row.group, v.filteredHosts()))
+				line++
+				continue
+			}
+
+			// Drugi znak prefiksu oznacza host zaznaczony do wykonania polecenia (x)
+			mark := " "
+			if v.model.IsSelected(row.host.Name) {
+				mark = ui.SuccessStyle.Render("*")
+			}
+			prefix := " " + mark
+			var hostLine string
 
-			if i == v.selectedIndex {
+			// Renderujemy nazwę hosta w kolorze jego środowiska
+			// (skróconą, żeby nie zawijała się na wąskim panelu)
+			hostName := ui.EnvironmentStyle(row.host.Environment).Render(truncateText(row.host.Name, panelWidth-4))
+
+			if row.visibleIndex == v.selectedIndex {
 				// Ustawiamy prefix dla zaznaczonego hosta
-				prefix = ui.SuccessStyle.Render("❯ ")
+				prefix = ui.SuccessStyle.Render("❯") + mark
 				// Budujemy linię z użyciem SelectedItemStyle i HostStyle
-				line = ui.SelectedItemStyle.Render(
+				hostLine = ui.SelectedItemStyle.Render(
 					fmt.Sprintf("\n%s%s", prefix, hostName),
 				)
 			} else {
 				// Budujemy linię dla niezaznaczonego hosta z HostStyle
-				line = fmt.Sprintf("\n%s%s", prefix, hostName)
+				hostLine = fmt.Sprintf("\n%s%s", prefix, hostName)
 			}
 			// Dodajemy linię do zawartości
-			content.WriteString(line)
+			content.WriteString(hostLine)
+			line++
+			v.hostRows[line] = row.visibleIndex
+		}
+
+		if end-v.hostScroll < len(rows) {
+			content.WriteString(ui.DescriptionStyle.Render(v.hostListFooter(rows[v.hostScroll:end])))
 		}
 	}
 
 	return style.Render(title + "\n" + content.String())
 }
 
+// renderGroupHeader renderuje nagłówek grupy z licznikiem hostów
+func (v *mainView) renderGroupHeader(group string, hosts []models.Host) string {
+	count := 0
+	for _, host := range hosts {
+		if hostGroupName(host) == group {
+			count++
+		}
+	}
+
+	marker := "▾"
+	if v.collapsed[group] {
+		marker = "▸"
+	}
+	return "\n" + ui.LabelStyle.Render(fmt.Sprintf("%s %s (%d)", marker, group, count))
+}
+
 func (v *mainView) renderDetailsPanel() string {
-	style := ui.PanelStyle.Width(45)
+	style := ui.PanelStyle.Width(v.layout().panelWidth)
 	title := "Host Details"
 
 	var content strings.Builder
-	if len(v.hosts) > 0 {
-		host := v.hosts[v.selectedIndex]
+	if hosts := v.visibleHosts(); len(hosts) > 0 {
+		host := hosts[v.selectedIndex]
 		content.WriteString(fmt.Sprintf("\n  %s %s", ui.LabelStyle.Render("Name:"), ui.Infotext.Render(host.Name)))
+		if host.Environment != "" {
+			content.WriteString(fmt.Sprintf("\n  %s %s", ui.LabelStyle.Render("Environment:"), ui.EnvironmentBadge(host.Environment)))
+		}
+		if len(host.Tags) > 0 {
+			content.WriteString(fmt.Sprintf("\n  %s %s", ui.LabelStyle.Render("Tags:"), ui.Infotext.Render("#"+strings.Join(host.Tags, " #"))))
+		}
 		content.WriteString(fmt.Sprintf("\n  %s %s", ui.LabelStyle.Render("Description:"), ui.Infotext.Render(host.Description)))
 		content.WriteString(fmt.Sprintf("\n  %s %s", ui.LabelStyle.Render("Login:"), ui.Infotext.Render(host.Login)))
 		content.WriteString(fmt.Sprintf("\n  %s %s", ui.LabelStyle.Render("Address:"), ui.Infotext.Render(host.IP)))
 		content.WriteString(fmt.Sprintf("\n  %s %s", ui.LabelStyle.Render("Port:"), ui.Infotext.Render(host.Port)))
+		if len(host.LocalForwards) > 0 {
+			content.WriteString(fmt.Sprintf("\n  %s %s", ui.LabelStyle.Render("Forwards:"), ui.Infotext.Render(strings.Join(host.LocalForwards, ", "))))
+		}
+		if len(host.RemoteForwards) > 0 {
+			content.WriteString(fmt.Sprintf("\n  %s %s", ui.LabelStyle.Render("Reverse:"), ui.Infotext.Render(strings.Join(host.RemoteForwards, ", "))))
+		}
+		if len(host.Env) > 0 {
+			content.WriteString(fmt.Sprintf("\n  %s %s", ui.LabelStyle.Render("Env:"), ui.Infotext.Render(models.FormatEnv(host.Env))))
+		}
+		if host.RemoteDir != "" {
+			content.WriteString(fmt.Sprintf("\n  %s %s", ui.LabelStyle.Render("Remote dir:"), ui.Infotext.Render(host.RemoteDir)))
+		}
+		lastConnected := "never"
+		if !host.LastConnected.IsZero() {
+			lastConnected = formatTimeAgo(host.LastConnected, time.Now())
+		}
+		content.WriteString(fmt.Sprintf("\n  %s %s", ui.LabelStyle.Render("Last Connected:"), ui.Infotext.Render(lastConnected)))
+		content.WriteString(fmt.Sprintf("\n  %s %s", ui.LabelStyle.Render("Connections:"), ui.Infotext.Render(fmt.Sprint(host.ConnectCount))))
+		content.WriteString(v.renderNotes(host))
 	}
 
 	return style.Render(title + "\n" + content.String())
 }
 
+// formatTimeAgo opisuje czas względem teraz, np. "2 hours ago";
+// starsze niż miesiąc daty są pokazywane wprost
+func formatTimeAgo(t, now time.Time) string {
+	elapsed := now.Sub(t)
+	plural := func(n int, unit string) string {
+		if n == 1 {
+			return fmt.Sprintf("1 %s ago", unit)
+		}
+		return fmt.Sprintf("%d %ss ago", n, unit)
+	}
+
+	switch {
+	case elapsed < time.Minute:
+		return "just now"
+	case elapsed < time.Hour:
+		return plural(int(elapsed/time.Minute), "minute")
+	case elapsed < 24*time.Hour:
+		return plural(int(elapsed/time.Hour), "hour")
+	case elapsed < 30*24*time.Hour:
+		return plural(int(elapsed/(24*time.Hour)), "day")
+	default:
+		return t.Format("2006-01-02")
+	}
+}
+
 func (v *mainView) renderStatusBar() string {
 	// Renderowanie paska statusu
 	var status string
 	if v.errMsg != "" {
 		status = ui.ErrorStyle.Render(v.errMsg)
+	} else if v.syncing {
+		status = ui.DescriptionStyle.Render(v.syncSpinner.View() + " Syncing with API...")
+	} else if v.filter != "" || v.tagFilter != "" {
+		status = ui.DescriptionStyle.Render(fmt.Sprintf("%d/%d matches", len(v.filteredHosts()), len(v.hosts)))
 	} else if v.status != "" {
 		status = ui.SuccessStyle.Render(v.status)
 	} else if v.model.IsConnected() {
 		if host := v.model.GetSelectedHost(); host != nil {
-			status = ui.SuccessStyle.Render(fmt.Sprintf("Connected to: %s", host.Name))
+			message := fmt.Sprintf("Connected to: %s", host.Name)
+			if forwards := v.model.GetSSHClient().ActiveForwards(); len(forwards) > 0 {
+				message += fmt.Sprintf(" | Forwards: %s", strings.Join(forwards, ", "))
+			}
+			status = ui.SuccessStyle.Render(message)
 		}
-	} else {
-		status = ui.DescriptionStyle.Render("To restore data from local backup press: ctrl + r")
-	}
-
-	// Renderowanie tabeli poleceń
-	headers := []string{
-		"Connect", "Navigate", "Edit Host", "Add Host", "Pass",
-		"Transfer", "Delete Host", "List Keys", "Theme", "Quit",
-	}
-	shortcuts := []string{
-		"enter/c", "↑↓/w/s", "e/f4/ESC+4", "h", "p",
-		"t", "d/f8/ESC+8", "k", "space", "q/^c",
-	}
-
-	// Renderowanie wierszy tabeli
-	var TableStyle = func(row, col int) lipgloss.Style {
-		switch {
-		case row == -1: // Nagłówki
-			return lipgloss.NewStyle().
-				Padding(0, 1).
-				Foreground(ui.Subtle).
-				Align(lipgloss.Center)
-		default: // Skróty
-			return lipgloss.NewStyle().
-				Padding(0, 1).
-				Foreground(ui.Special)
+	} else if pending := len(v.model.GetConfig().GetPendingChanges()); pending > 0 {
+		if err := v.model.GetPushError(); err != nil {
+			status = ui.ErrorStyle.Render(fmt.Sprintf("Sync failed: %v | %d changes pending sync", err, pending))
+		} else {
+			status = ui.DescriptionStyle.Render(fmt.Sprintf("%d changes pending sync | %s", pending, v.syncStatusLabel()))
 		}
+	} else {
+		status = ui.DescriptionStyle.Render(v.syncStatusLabel() + " | To restore data from local backup press: ctrl + r")
 	}
 
-	cmdTable := table.New().
-		Border(lipgloss.NormalBorder()).
-		BorderStyle(lipgloss.NewStyle().Foreground(ui.StatusBar)).
-		StyleFunc(TableStyle).
-		Headers(headers...).
-		Row(shortcuts...)
+	// Pasek ma szerokość paneli; tabela poleceń (z tej samej listy co ekran
+	// pomocy) jest dzielona na kilka wierszy, gdy się nie mieści
+	width := v.layout().contentWidth() - 2 // Bez ramki paska
+	headers, shortcuts := shortcutTable(mainShortcuts(v.model.Keys()))
 
 	// Połączenie statusu i tabeli w jedną ramkę
 	fullContent := lipgloss.JoinVertical(
 		lipgloss.Left,
-		status,            // Pasek statusu
-		cmdTable.Render(), // Tabela poleceń
+		status, // Pasek statusu
+		renderCommandTables(headers, shortcuts, width), // Tabela poleceń
 	)
 
 	// Dodanie ramki wokół wszystkiego
 	framed := lipgloss.NewStyle().
 		Border(lipgloss.NormalBorder()).
 		BorderForeground(lipgloss.Color("240")).
+		Width(width).
 		Render(fullContent)
 
 	return framed
@@ -647,122 +1461,44 @@ func (v *mainView) renderStatusBar() string {
 
 // ReinitializeInput pozostaje bez zmian
 
-func (v *mainView) handleRestoreBackup() (tea.Model, tea.Cmd) {
-	configPath, err := config.GetDefaultConfigPath()
-	if err != nil {
-		v.popup = components.NewPopup(
-			components.PopupMessage,
-			"Error",
-			fmt.Sprintf("Could not determine config path: %v", err),
-			50,
-			7,
-			v.width,
-			v.height,
-		)
+func (v *mainView) handleTransfer() (tea.Model, tea.Cmd) {
+	// Tryb transferu wymaga hosta; bez niego panel zdalny nie miałby się z czym połączyć
+	hosts := v.visibleHosts()
+	if len(hosts) == 0 || v.selectedIndex >= len(hosts) {
+		if len(v.hosts) == 0 {
+			v.errMsg = "No hosts configured. Add a host with 'h' before transferring files"
+		} else {
+			v.errMsg = "No host selected. Select a host before transferring files"
+		}
 		return v, nil
 	}
+	host := hosts[v.selectedIndex]
+	v.model.SetSelectedHost(&host)
+	v.rememberHost(host.Name)
+	// Zaznaczenia hostów nie mogą trafić do zaznaczonych plików
+	v.model.ClearSelection()
 
-	keysDir := filepath.Join(filepath.Dir(configPath), config.DefaultKeysDir)
-	apiKey, err := v.model.GetConfig().LoadApiKey(v.model.GetCipher())
+	authData, err := v.model.GetHostAuthData(&host)
 	if err != nil {
-		v.popup = components.NewPopup(
-			components.PopupMessage,
-			"Error",
-			fmt.Sprintf("Failed to load API key: %v", err),
-			50,
-			7,
-			v.width,
-			v.height,
-		)
+		v.errMsg = fmt.Sprintf("Cannot prepare credentials: %v", err)
 		return v, nil
 	}
+	defer crypto.Wipe(authData)
 
-	// Najpierw przywróć pliki z backupu
-	if err := sync.RestoreFromBackup(configPath, keysDir); err != nil {
-		v.popup = components.NewPopup(
-			components.PopupMessage,
-			"Error",
-			fmt.Sprintf("Failed to restore backup: %v", err),
-			50,
-			7,
-			v.width,
-			v.height,
-		)
+	if err := ssh.RunPreConnectHook(&host); err != nil {
+		v.errMsg = fmt.Sprintf("Connection aborted: %v", err)
 		return v, nil
 	}
 
-	// Wypchnij przywrócone pliki do API (używając obecnego szyfru)
-	if err := sync.PushToAPI(apiKey, configPath, keysDir, v.model.GetCipher()); err != nil {
-		v.popup = components.NewPopup(
-			components.PopupMessage,
-			"Error",
-			fmt.Sprintf("Failed to push to API: %v", err),
-			50,
-			7,
-			v.width,
-			v.height,
-		)
-		return v, nil
-	}
-
-	// Informujemy o sukcesie i restartujemy
-	v.popup = components.NewPopup(
-		components.PopupMessage,
-		"Success",
-		"Backup restored and synced with API. Press Enter to restart.",
-		50,
-		7,
-		v.width,
-		v.height,
-	)
-
-	return v, tea.Sequence(
-		func() tea.Msg {
-			return messages.ReloadAppMsg{}
-		},
-	)
-}
-
-func (v *mainView) handleTransfer() (tea.Model, tea.Cmd) {
-	host := v.hosts[v.selectedIndex]
-	v.model.SetSelectedHost(&host)
-
-	var authData string
-	var err error
-
-	if host.PasswordID < 0 {
-		// Obsługa klucza SSH
-		keyIndex := -(host.PasswordID + 1) // Konwertujemy ujemny indeks na właściwy indeks klucza
-		keys := v.model.GetKeys()
-		if keyIndex >= len(keys) {
-			v.errMsg = "Invalid SSH key ID"
-			return v, nil
-		}
-
-		key := keys[keyIndex]
-		keyPath, err := key.GetKeyPath()
-		if err != nil {
-			v.errMsg = fmt.Sprintf("Failed to get key path: %v", err)
-			return v, nil
-		}
-		authData = keyPath
-	} else {
-		// Obsługa hasła
-		passwords := v.model.GetPasswords()
-		if host.PasswordID >= len(passwords) {
-			v.errMsg = "Invalid password ID"
-			return v, nil
-		}
-		password := passwords[host.PasswordID]
-		authData, err = password.GetDecrypted(v.model.GetCipher())
-		if err != nil {
-			v.errMsg = fmt.Sprintf("Failed to decrypt password: %v", err)
-			return v, nil
-		}
-	}
-
 	transfer := v.model.GetTransfer()
 	if err := transfer.Connect(&host, authData); err != nil {
+		// Zmieniony klucz hosta (docelowego lub pośredniczącego) - to samo
+		// ostrzeżenie co przy połączeniu z powłoką
+		var verificationRequired *ssh.HostKeyVerificationRequired
+		if errors.As(err, &verificationRequired) && verificationRequired.Changed {
+			v.showHostKeyChangedPopup(newHostKeyVerificationMsg(verificationRequired))
+			return v, nil
+		}
 		v.errMsg = fmt.Sprintf("Failed to establish SFTP connection: %v", err)
 		return v, nil
 	}
@@ -792,6 +1528,12 @@ func (v *mainView) ShowSessionEndedPopup() {
 	)
 }
 
+// ShowTransferFailedError zgłasza, że połączenia z zamkniętej powłoki nie udało
+// się przekazać do trybu transferu plików
+func (v *mainView) ShowTransferFailedError(err error) {
+	v.errMsg = fmt.Sprintf("Failed to open file transfer: %v", err)
+}
+
 // W main_view.go
 func (v *mainView) PostInitialize() tea.Cmd {
 	return tea.Sequence(
@@ -805,3 +1547,82 @@ func (v *mainView) PostInitialize() tea.Cmd {
 		},
 	)
 }
+
+// handleInstallKey otwiera wybór klucza, którego część publiczna zostanie
+// dopisana do authorized_keys na zaznaczonym hoście
+func (v *mainView) handleInstallKey() (tea.Model, tea.Cmd) {
+	if len(v.model.GetKeys()) == 0 {
+		v.errMsg = "No SSH keys configured. Add or generate one in key management (k)"
+		return v, nil
+	}
+
+	v.installKey.host = v.visibleHosts()[v.selectedIndex]
+	v.installKey.index = 0
+	v.showKeySelectPopup()
+	return v, nil
+}
+
+// showKeySelectPopup (re)buduje popup z listą kluczy do instalacji
+func (v *mainView) showKeySelectPopup() {
+	keys := v.model.GetKeys()
+
+	var message strings.Builder
+	message.WriteString(fmt.Sprintf("Install public key on %s:\n\n", v.installKey.host.Name))
+	for i, key := range keys {
+		if i == v.installKey.index {
+			message.WriteString(ui.SelectedItemStyle.Render("> "+key.Description) + "\n")
+		} else {
+			message.WriteString("  " + key.Description + "\n")
+		}
+	}
+
+	v.popup = components.NewPopup(
+		components.PopupSelectKey,
+		"Install Key",
+		message.String(),
+		60,
+		len(keys)+8,
+		v.width,
+		v.height,
+	)
+}
+
+// handleKeySelectPopup obsługuje klawisze w popupie wyboru klucza
+func (v *mainView) handleKeySelectPopup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	keys := v.model.GetKeys()
+
+	switch v.model.Keys().VerticalKey(msg) {
+	case "esc":
+		v.popup = nil
+	case "up":
+		v.installKey.index = (v.installKey.index + len(keys) - 1) % len(keys)
+		v.showKeySelectPopup()
+	case "down":
+		v.installKey.index = (v.installKey.index + 1) % len(keys)
+		v.showKeySelectPopup()
+	case "enter":
+		host := v.installKey.host
+		keyIndex := v.installKey.index
+		keyDescription := keys[keyIndex].Description
+
+		v.popup = components.NewPopup(
+			components.PopupMessage,
+			"Install Key",
+			fmt.Sprintf("Installing key '%s' on %s...", keyDescription, host.Name),
+			50,
+			7,
+			v.width,
+			v.height,
+		)
+		return v, func() tea.Msg {
+			installed, err := v.model.InstallKeyOnHost(&host, keyIndex)
+			return keyInstalledMsg{
+				host:      host.Name,
+				key:       keyDescription,
+				installed: installed,
+				err:       err,
+			}
+		}
+	}
+	return v, nil
+}
@@ -0,0 +1,162 @@
+// internal/ui/views/mouse.go
+
+package views
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// doubleClickInterval to maksymalny odstęp między kliknięciami w dwukliku
+const doubleClickInterval = 400 * time.Millisecond
+
+// Położenie list na ekranie. Oba widoki są rysowane od lewego górnego rogu
+// w ramce WindowStyle (ramka + margines 1 wiersz i 2 kolumny), pod tytułem
+// i pustą linią.
+const (
+	windowLeft = 3 // Pierwsza kolumna wewnątrz ramki okna
+	panelTop   = 5 // Pierwsza linia wewnątrz panelu (tytuł hostów albo ścieżka plików)
+)
+
+// mouseClick zapamiętuje ostatnie kliknięcie do wykrywania dwukliku
+type mouseClick struct {
+	index int
+	at    time.Time
+}
+
+// isDoubleClick sprawdza, czy kliknięcie w pozycję index jest drugim z dwukliku
+func (c *mouseClick) isDoubleClick(index int) bool {
+	now := time.Now()
+	double := c.index == index && now.Sub(c.at) <= doubleClickInterval
+	if double {
+		// Trzecie kliknięcie zaczyna nowy dwuklik
+		*c = mouseClick{}
+	} else {
+		*c = mouseClick{index: index, at: now}
+	}
+	return double
+}
+
+// handleMouse obsługuje mysz na liście hostów: kliknięcie zaznacza host,
+// dwuklik łączy, a kółko przesuwa zaznaczenie
+func (v *mainView) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if v.popup != nil || v.filtering || v.connecting || v.showHelp || v.broadcast != nil {
+		return v, nil
+	}
+
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		v.moveSelection(-1)
+	case tea.MouseButtonWheelDown:
+		v.moveSelection(1)
+	case tea.MouseButtonLeft:
+		if msg.Action != tea.MouseActionPress {
+			return v, nil
+		}
+		// Panel hostów jest zawsze pierwszy, także w układzie jeden pod drugim
+		if msg.X < windowLeft || msg.X >= windowLeft+v.layout().panelWidth+2 {
+			return v, nil
+		}
+		index, ok := v.hostRows[msg.Y-panelTop]
+		if !ok {
+			return v, nil
+		}
+		v.selectedIndex = index
+		v.errMsg = ""
+		if v.lastClick.isDoubleClick(index) {
+			return v.connectSelected()
+		}
+	}
+	return v, nil
+}
+
+// handleMouse obsługuje mysz w panelach plików: kliknięcie zaznacza plik
+// (i aktywuje jego panel), Shift+kliknięcie zaznacza zakres od kursora,
+// a kółko przewija aktywny panel
+func (v *transferView) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if v.popup != nil || v.preview != nil || v.showHelp || v.connecting || v.isWaitingForInput() {
+		return v, nil
+	}
+
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		v.scrollPanel(v.getActivePanel(), -1)
+	case tea.MouseButtonWheelDown:
+		v.scrollPanel(v.getActivePanel(), 1)
+	case tea.MouseButtonLeft:
+		if msg.Action != tea.MouseActionPress {
+			return v, nil
+		}
+		panel := v.panelAt(msg.X)
+		if panel == nil {
+			return v, nil
+		}
+		row, ok := panel.entryAt(msg.Y - panelTop)
+		if !ok || panel.scrollOffset+row >= len(panel.entries) {
+			return v, nil
+		}
+		if !panel.active {
+			v.switchActivePanel()
+		} else if msg.Shift && !v.transferring {
+			// Shift+kliknięcie zaznacza wpisy od kursora do klikniętego
+			v.markRange(panel, panel.selectedIndex, panel.scrollOffset+row)
+		}
+		panel.selectedIndex = panel.scrollOffset + row
+	}
+	return v, nil
+}
+
+// entryAt zwraca pozycję (względem scrollOffset) wpisu narysowanego w linii line panelu
+func (p *Panel) entryAt(line int) (int, bool) {
+	for i := 0; i+1 < len(p.entryRows); i++ {
+		if line >= p.entryRows[i] && line < p.entryRows[i+1] {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// wrappedEntryRows wyznacza, od której linii panelu zaczyna się każdy wpis.
+// Wiersze tabeli dłuższe niż panel są zawijane, więc wpis może zająć kilka
+// linii; zawijamy je tak samo jak styl panelu o szerokości width.
+func wrappedEntryRows(content string, width int) []int {
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	wrap := lipgloss.NewStyle().Width(width)
+
+	var rows []int
+	row := 0
+	for i, line := range lines {
+		// Linia 0 to ścieżka, linia 1 to nagłówek tabeli
+		if i >= 2 {
+			rows = append(rows, row)
+		}
+		row += lipgloss.Height(wrap.Render(line))
+	}
+	return append(rows, row)
+}
+
+// panelAt zwraca panel pod kolumną x (nil poza panelami albo dla
+// niepołączonego panelu zdalnego); szerokości jak w View
+func (v *transferView) panelAt(x int) *Panel {
+	panelWidth := (min(v.width-40, 160)-3)/2 + 2 // Z ramką panelu
+	switch {
+	case x >= windowLeft && x < windowLeft+panelWidth:
+		return &v.localPanel
+	case v.connected && x >= windowLeft+panelWidth+3 && x < windowLeft+2*panelWidth+3:
+		return &v.remotePanel
+	}
+	return nil
+}
+
+// scrollPanel przesuwa zaznaczenie o jedną pozycję; w przeciwieństwie do
+// strzałek kółko zatrzymuje się na końcach listy
+func (v *transferView) scrollPanel(p *Panel, direction int) {
+	next := p.selectedIndex + direction
+	if next < 0 || next >= len(p.entries) {
+		return
+	}
+	v.navigatePanel(p, direction)
+}
@@ -0,0 +1,144 @@
+// internal/ui/views/password_reveal.go
+
+package views
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// passwordRevealDuration to czas, po którym odsłonięte hasło jest znowu ukrywane
+const passwordRevealDuration = 5 * time.Second
+
+// passwordHideMsg ukrywa hasło odsłonięte przez odsłonięcie o numerze seq;
+// późniejsze odsłonięcie zmienia numer, więc stare odliczanie go nie ukryje
+type passwordHideMsg struct {
+	seq int
+}
+
+// passwordReveal to stan odsłoniętego hasła (na liście albo w formularzu)
+type passwordReveal struct {
+	index int    // Indeks hasła odsłoniętego na liście
+	value string // Odszyfrowana wartość hasła z listy (pusta, gdy żadne nie jest odsłonięte)
+	form  bool   // true, gdy pole hasła w formularzu pokazuje treść
+	seq   int
+}
+
+// decryptPassword odszyfrowuje zapisane hasło; wymaga ustawionego klucza szyfrowania
+func (v *editView) decryptPassword(index int) (string, error) {
+	cipher := v.model.GetCipher()
+	if cipher == nil {
+		return "", errors.New("encryption key is not set")
+	}
+	if index < 0 || index >= len(v.passwords) {
+		return "", errors.New("no password selected")
+	}
+	value, err := v.passwords[index].GetDecrypted(cipher)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt password: %v", err)
+	}
+	return value, nil
+}
+
+// formPasswordValue zwraca hasło z formularza; przy edycji z pustym polem
+// wczytuje do niego zapisane hasło
+func (v *editView) formPasswordValue() (string, error) {
+	if value := v.inputs[1].Value(); value != "" {
+		return value, nil
+	}
+	if v.currentPassword == nil {
+		return "", errors.New("password field is empty")
+	}
+	if v.model.GetCipher() == nil {
+		return "", errors.New("encryption key is not set")
+	}
+	value, err := v.currentPassword.GetDecrypted(v.model.GetCipher())
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt password: %v", err)
+	}
+	v.inputs[1].SetValue(value)
+	return value, nil
+}
+
+// scheduleHide uruchamia odliczanie do ukrycia hasła
+func (v *editView) scheduleHide() tea.Cmd {
+	v.reveal.seq++
+	seq := v.reveal.seq
+	return tea.Tick(passwordRevealDuration, func(time.Time) tea.Msg {
+		return passwordHideMsg{seq: seq}
+	})
+}
+
+// revealListPassword odsłania na kilka sekund hasło zaznaczone na liście (klawisz v)
+func (v *editView) revealListPassword() tea.Cmd {
+	value, err := v.decryptPassword(v.selectedItemIndex)
+	if err != nil {
+		v.errorMsg = err.Error()
+		return nil
+	}
+	v.errorMsg = ""
+	v.reveal.index = v.selectedItemIndex
+	v.reveal.value = value
+	return v.scheduleHide()
+}
+
+// toggleFormReveal pokazuje albo ukrywa treść pola hasła w formularzu (CTRL+R)
+func (v *editView) toggleFormReveal() tea.Cmd {
+	if v.reveal.form {
+		v.hidePasswords()
+		return nil
+	}
+	if _, err := v.formPasswordValue(); err != nil {
+		v.errorMsg = err.Error()
+		return nil
+	}
+	v.errorMsg = ""
+	v.reveal.form = true
+	v.inputs[1].EchoMode = textinput.EchoNormal
+	return v.scheduleHide()
+}
+
+// copyPassword kopiuje hasło do schowka systemowego (y na liście, CTRL+Y w formularzu)
+func (v *editView) copyPassword(fromForm bool) {
+	var value string
+	var err error
+	if fromForm {
+		value, err = v.formPasswordValue()
+	} else {
+		value, err = v.decryptPassword(v.selectedItemIndex)
+	}
+	if err != nil {
+		v.errorMsg = err.Error()
+		return
+	}
+	if err := clipboard.WriteAll(value); err != nil {
+		v.errorMsg = fmt.Sprintf("Failed to copy to clipboard: %v", err)
+		return
+	}
+	v.errorMsg = ""
+	v.notice = "Password copied to clipboard"
+}
+
+// hidePasswords ukrywa wszystkie odsłonięte hasła
+func (v *editView) hidePasswords() {
+	v.reveal.value = ""
+	if v.reveal.form {
+		v.reveal.form = false
+		// Pole 1 w formularzu hosta to opis, więc maskujemy je tylko w formularzu hasła
+		if v.editing && !v.editingHost && v.mode == modeNormal {
+			v.inputs[1].EchoMode = textinput.EchoPassword
+		}
+	}
+}
+
+// handlePasswordHide ukrywa hasło po upływie czasu, o ile nie odsłonięto go ponownie
+func (v *editView) handlePasswordHide(msg passwordHideMsg) {
+	if msg.seq == v.reveal.seq {
+		v.hidePasswords()
+	}
+}
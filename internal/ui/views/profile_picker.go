@@ -0,0 +1,104 @@
+// internal/ui/views/profile_picker.go
+
+package views
+
+import (
+	"sshManager/internal/config"
+	"sshManager/internal/ui/components"
+	"sshManager/internal/ui/messages"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// profileItem to pozycja listy profili konfiguracji
+type profileItem struct {
+	name    string
+	current bool
+}
+
+func (i profileItem) Title() string {
+	if i.current {
+		return i.name + " (current)"
+	}
+	return i.name
+}
+func (i profileItem) Description() string { return "" }
+func (i profileItem) FilterValue() string { return i.name }
+
+// openProfilePicker otwiera popup wyboru profilu konfiguracji
+func (v *mainView) openProfilePicker() tea.Cmd {
+	profiles, err := config.ListProfiles()
+	if err != nil {
+		return v.setStatus(err.Error())
+	}
+	if len(profiles) < 2 {
+		return v.setStatus("No other profiles; start sshm with --profile <name> to create one")
+	}
+
+	current := v.model.GetConfig().GetProfile()
+	items := make([]list.Item, 0, len(profiles))
+	selected := 0
+	for i, name := range profiles {
+		items = append(items, profileItem{name: name, current: name == current})
+		if name == current {
+			selected = i
+		}
+	}
+
+	// Lista mieści się w oknie; pozostałe profile są na kolejnych stronach
+	height := min(len(items), max(v.height-14, 5))
+	l := list.New(items, themeDelegate(), 30, height)
+	l.SetShowTitle(false)
+	l.SetShowStatusBar(false)
+	l.SetShowHelp(false)
+	l.SetFilteringEnabled(false)
+	l.KeyMap.Quit.SetEnabled(false)
+	l.Select(selected)
+
+	v.profilePicker = l
+	v.showProfilePicker()
+	return nil
+}
+
+// showProfilePicker (re)buduje popup z listą profili
+func (v *mainView) showProfilePicker() {
+	v.popup = components.NewPopup(
+		components.PopupSelectProfile,
+		"Switch Profile",
+		v.profilePicker.View(),
+		40,
+		v.profilePicker.Height()+7,
+		v.width,
+		v.height,
+	)
+}
+
+// handleProfilePickerPopup obsługuje klawisze w popupie wyboru profilu. Inny
+// profil ma własną konfigurację i klucz szyfrowania, więc po wyborze aplikacja
+// uruchamia się od nowa, od pytania o klucz.
+func (v *mainView) handleProfilePickerPopup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch v.model.Keys().VerticalKey(msg) {
+	case "esc":
+		v.popup = nil
+		return v, nil
+	case "enter":
+		v.popup = nil
+		item, ok := v.profilePicker.SelectedItem().(profileItem)
+		if !ok || item.current {
+			return v, nil
+		}
+		return v, func() tea.Msg {
+			return messages.SwitchProfileMsg(item.name)
+		}
+	case "up":
+		msg = tea.KeyMsg{Type: tea.KeyUp}
+	case "down":
+		msg = tea.KeyMsg{Type: tea.KeyDown}
+	}
+
+	var cmd tea.Cmd
+	v.profilePicker, cmd = v.profilePicker.Update(msg)
+	v.showProfilePicker()
+	return v, cmd
+}
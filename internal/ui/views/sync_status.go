@@ -0,0 +1,80 @@
+// internal/ui/views/sync_status.go
+
+package views
+
+import (
+	"fmt"
+	"time"
+
+	"sshManager/internal/ui"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// syncDoneMsg przychodzi po zakończeniu ręcznej synchronizacji
+type syncDoneMsg struct {
+	err error
+}
+
+// startManualSync uruchamia w tle pełną synchronizację z API (klawisz S)
+func (v *mainView) startManualSync() tea.Cmd {
+	if v.syncing {
+		return nil
+	}
+	if !v.model.GetConfig().HasApiKey() {
+		v.errMsg = "Sync is not configured (local mode)"
+		return nil
+	}
+
+	v.syncing = true
+	v.errMsg = ""
+	v.status = ""
+	v.syncSpinner = spinner.New(spinner.WithSpinner(spinner.Dot), spinner.WithStyle(ui.DescriptionStyle))
+
+	cfg := v.model.GetConfig()
+	return tea.Batch(
+		func() tea.Msg {
+			return syncDoneMsg{err: cfg.SyncNow()}
+		},
+		v.syncSpinner.Tick,
+	)
+}
+
+// handleSyncDone wczytuje zsynchronizowaną konfigurację albo pokazuje błąd
+func (v *mainView) handleSyncDone(msg syncDoneMsg) (tea.Model, tea.Cmd) {
+	v.syncing = false
+	if msg.err != nil {
+		v.model.SetLocalMode(true)
+		v.errMsg = fmt.Sprintf("Sync failed: %v", msg.err)
+		return v, nil
+	}
+
+	if err := v.model.GetConfig().Load(); err != nil {
+		v.errMsg = fmt.Sprintf("Failed to load synced configuration: %v", err)
+		return v, nil
+	}
+	v.model.SetLocalMode(false)
+	v.model.SetPushError(nil)
+	v.model.UpdateLists()
+	v.hosts = v.model.GetHosts()
+	v.clampSelection()
+	return v, v.setStatus("Synchronized with API")
+}
+
+// syncStatusLabel opisuje stan synchronizacji, np. "Last sync: 3 minutes ago"
+func (v *mainView) syncStatusLabel() string {
+	cfg := v.model.GetConfig()
+	if !cfg.HasApiKey() {
+		return "Local mode"
+	}
+
+	label := "Last sync: never"
+	if lastSync := cfg.GetLastSync(); !lastSync.IsZero() {
+		label = "Last sync: " + formatTimeAgo(lastSync, time.Now())
+	}
+	if v.model.IsLocalMode() {
+		label += " (local mode)"
+	}
+	return label
+}
@@ -0,0 +1,68 @@
+// internal/ui/views/system_ssh.go
+
+package views
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+
+	"sshManager/internal/models"
+	"sshManager/internal/ssh"
+	"sshManager/internal/ui/components"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// systemSSHConnectionError to kod wyjścia, którym ssh zgłasza błąd połączenia;
+// inne kody pochodzą z ostatniego polecenia zdalnej powłoki
+const systemSSHConnectionError = 255
+
+// systemSSHReadyMsg niesie przygotowane polecenie systemowego ssh
+type systemSSHReadyMsg struct {
+	host *models.Host
+	cmd  *exec.Cmd
+}
+
+// systemSSHExitedMsg przychodzi po zakończeniu systemowego ssh
+type systemSSHExitedMsg struct {
+	host *models.Host
+	err  error
+}
+
+// prepareSystemSSH uruchamia w tle polecenie przed połączeniem i buduje
+// polecenie ssh; sama sesja działa przez tea.ExecProcess
+func (v *mainView) prepareSystemSSH(host *models.Host) tea.Cmd {
+	return func() tea.Msg {
+		// Lokalne polecenie hosta (np. VPN) musi się powieść przed połączeniem
+		if err := ssh.RunPreConnectHook(host); err != nil {
+			return errMsg(fmt.Sprintf("Connection aborted: %v", err))
+		}
+
+		cmd, err := v.model.SystemSSHCommand(host)
+		if err != nil {
+			return errMsg(fmt.Sprintf("Cannot start system ssh: %v", err))
+		}
+		return systemSSHReadyMsg{host: host, cmd: cmd}
+	}
+}
+
+// handleSystemSSHExited zapisuje udane połączenie albo pokazuje błąd ssh
+func (v *mainView) handleSystemSSHExited(msg systemSSHExitedMsg) (tea.Model, tea.Cmd) {
+	var exitErr *exec.ExitError
+	if msg.err != nil && (!errors.As(msg.err, &exitErr) || exitErr.ExitCode() == systemSSHConnectionError) {
+		v.popup = components.NewPopup(
+			components.PopupMessage,
+			"Error",
+			fmt.Sprintf("System ssh failed: %v", msg.err),
+			50,
+			7,
+			v.width,
+			v.height,
+		)
+		return v, nil
+	}
+
+	v.recordConnection(msg.host)
+	return v, nil
+}
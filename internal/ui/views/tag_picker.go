@@ -0,0 +1,108 @@
+// internal/ui/views/tag_picker.go
+
+package views
+
+import (
+	"fmt"
+
+	"sshManager/internal/models"
+	"sshManager/internal/ui/components"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// tagItem to pozycja listy tagów; pusty tag oznacza wszystkie hosty
+type tagItem struct {
+	tag   string
+	count int
+}
+
+func (i tagItem) Title() string {
+	if i.tag == "" {
+		return "All hosts"
+	}
+	return fmt.Sprintf("#%s (%d)", i.tag, i.count)
+}
+func (i tagItem) Description() string { return "" }
+func (i tagItem) FilterValue() string { return i.tag }
+
+// openTagPicker otwiera popup wyboru tagu, według którego filtrujemy listę hostów
+func (v *mainView) openTagPicker() tea.Cmd {
+	tags, counts := models.HostTags(v.hosts)
+	if len(tags) == 0 {
+		return v.setStatus("No tags defined; add them in the host form")
+	}
+
+	items := []list.Item{tagItem{}}
+	selected := 0
+	for _, tag := range tags {
+		items = append(items, tagItem{tag: tag, count: counts[tag]})
+		if tag == v.tagFilter {
+			selected = len(items) - 1
+		}
+	}
+
+	// Lista mieści się w oknie; pozostałe tagi są na kolejnych stronach
+	height := min(len(items), max(v.height-14, 5))
+	l := list.New(items, themeDelegate(), 30, height)
+	l.SetShowTitle(false)
+	l.SetShowStatusBar(false)
+	l.SetShowHelp(false)
+	l.SetFilteringEnabled(false)
+	l.KeyMap.Quit.SetEnabled(false)
+	l.Select(selected)
+
+	v.tagPicker = l
+	v.showTagPicker()
+	return nil
+}
+
+// showTagPicker (re)buduje popup z listą tagów
+func (v *mainView) showTagPicker() {
+	v.popup = components.NewPopup(
+		components.PopupSelectTag,
+		"Filter by Tag",
+		v.tagPicker.View(),
+		40,
+		v.tagPicker.Height()+7,
+		v.width,
+		v.height,
+	)
+}
+
+// handleTagPickerPopup obsługuje klawisze w popupie wyboru tagu
+func (v *mainView) handleTagPickerPopup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch v.model.Keys().VerticalKey(msg) {
+	case "esc":
+		v.popup = nil
+		return v, nil
+	case "enter":
+		v.popup = nil
+		if item, ok := v.tagPicker.SelectedItem().(tagItem); ok {
+			v.applyTagFilter(item.tag)
+		}
+		return v, nil
+	case "up":
+		msg = tea.KeyMsg{Type: tea.KeyUp}
+	case "down":
+		msg = tea.KeyMsg{Type: tea.KeyDown}
+	}
+
+	var cmd tea.Cmd
+	v.tagPicker, cmd = v.tagPicker.Update(msg)
+	v.showTagPicker()
+	return v, cmd
+}
+
+// applyTagFilter ogranicza listę hostów do tagu (pusty tag pokazuje wszystkie),
+// zachowując zaznaczenie hosta, jeśli pozostaje widoczny
+func (v *mainView) applyTagFilter(tag string) {
+	var selected string
+	if hosts := v.visibleHosts(); len(hosts) > 0 {
+		selected = hosts[v.selectedIndex].Name
+	}
+	v.tagFilter = tag
+	v.selectHost(selected)
+	v.errMsg = ""
+}
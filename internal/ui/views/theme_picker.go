@@ -0,0 +1,110 @@
+// internal/ui/views/theme_picker.go
+
+package views
+
+import (
+	"fmt"
+
+	"sshManager/internal/ui"
+	"sshManager/internal/ui/components"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// themeItem to pozycja listy motywów
+type themeItem string
+
+func (i themeItem) Title() string       { return string(i) }
+func (i themeItem) Description() string { return "" }
+func (i themeItem) FilterValue() string { return string(i) }
+
+// themeDelegate tworzy delegata listy w kolorach aktualnego motywu, więc
+// podgląd obejmuje także samą listę
+func themeDelegate() list.DefaultDelegate {
+	delegate := list.NewDefaultDelegate()
+	delegate.ShowDescription = false
+	delegate.SetSpacing(0)
+	delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.
+		Foreground(ui.Highlight).
+		BorderForeground(ui.Highlight)
+	return delegate
+}
+
+// openThemePicker otwiera popup wyboru motywu z zaznaczonym aktualnym motywem
+func (v *mainView) openThemePicker() {
+	names := ui.ThemeNames()
+	current := ui.CurrentThemeName()
+
+	items := make([]list.Item, len(names))
+	selected := 0
+	for i, name := range names {
+		items[i] = themeItem(name)
+		if name == current {
+			selected = i
+		}
+	}
+
+	// Lista mieści się w oknie; pozostałe motywy są na kolejnych stronach
+	height := min(len(names), max(v.height-14, 5))
+	l := list.New(items, themeDelegate(), 30, height)
+	l.SetShowTitle(false)
+	l.SetShowStatusBar(false)
+	l.SetShowHelp(false)
+	l.SetFilteringEnabled(false)
+	l.KeyMap.Quit.SetEnabled(false)
+	l.Select(selected)
+
+	v.themePicker.list = l
+	v.themePicker.original = current
+	v.showThemePicker()
+}
+
+// showThemePicker (re)buduje popup z listą motywów
+func (v *mainView) showThemePicker() {
+	v.popup = components.NewPopup(
+		components.PopupSelectTheme,
+		"Select Theme",
+		v.themePicker.list.View(),
+		40,
+		v.themePicker.list.Height()+7,
+		v.width,
+		v.height,
+	)
+}
+
+// handleThemePickerPopup obsługuje klawisze w popupie wyboru motywu: ruch kursora
+// od razu stosuje motyw, ENTER go zapisuje, a ESC przywraca poprzedni
+func (v *mainView) handleThemePickerPopup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch v.model.Keys().VerticalKey(msg) {
+	case "esc":
+		if err := ui.SetThemeByName(v.themePicker.original); err != nil {
+			v.errMsg = err.Error()
+		}
+		v.popup = nil
+		return v, nil
+	case "enter":
+		v.popup = nil
+		name := ui.CurrentThemeName()
+		if err := v.model.SelectTheme(name); err != nil {
+			v.errMsg = err.Error()
+			return v, nil
+		}
+		return v, v.setStatus(fmt.Sprintf("Theme set to %s", name))
+	case "up":
+		msg = tea.KeyMsg{Type: tea.KeyUp}
+	case "down":
+		msg = tea.KeyMsg{Type: tea.KeyDown}
+	}
+
+	var cmd tea.Cmd
+	v.themePicker.list, cmd = v.themePicker.list.Update(msg)
+	if item, ok := v.themePicker.list.SelectedItem().(themeItem); ok {
+		if err := ui.SetThemeByName(string(item)); err != nil {
+			v.errMsg = err.Error()
+		}
+	}
+	v.themePicker.list.SetDelegate(themeDelegate())
+	v.showThemePicker()
+	return v, cmd
+}
@@ -1,25 +1,33 @@
 package views
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"runtime"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"sshManager/internal/crypto"
+	"sshManager/internal/models"
 	"sshManager/internal/ssh"
 	"sshManager/internal/ui"
 	"sshManager/internal/ui/components"
 	"sshManager/internal/utils"
 
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	ltable "github.com/charmbracelet/lipgloss/table"
+	"github.com/pkg/sftp"
 )
 
 // Dodaj na początku pliku po importach
@@ -31,6 +39,37 @@ func getHomeDir() string {
 	return home
 }
 
+// localStartDir zwraca katalog startowy panelu lokalnego: local_dir z
+// konfiguracji, jeśli istnieje, a w przeciwnym razie katalog domowy
+func localStartDir(model *ui.Model) (string, error) {
+	dir := strings.TrimSpace(model.GetConfig().GetLocalDir())
+	if dir == "" {
+		return getHomeDir(), nil
+	}
+	if dir == "~" || strings.HasPrefix(dir, "~/") || strings.HasPrefix(dir, "~"+string(filepath.Separator)) {
+		dir = filepath.Join(getHomeDir(), dir[1:])
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return getHomeDir(), fmt.Errorf("local directory %s not found, starting in the home directory", dir)
+	}
+	return filepath.Clean(dir), nil
+}
+
+// remoteStartDir zwraca katalog startowy panelu zdalnego: katalog hosta
+// (RemoteDir, "~" i ścieżki względne liczone od katalogu domowego) albo katalog domowy
+func remoteStartDir(host *models.Host, home string) string {
+	dir := strings.TrimSpace(host.RemoteDir)
+	switch {
+	case dir == "" || dir == "~":
+		return home
+	case strings.HasPrefix(dir, "~/"):
+		return path.Join(home, dir[2:])
+	case !strings.HasPrefix(dir, "/"):
+		return path.Join(home, dir)
+	}
+	return path.Clean(dir)
+}
+
 // Stałe określające tryby i stany
 const (
 	localPanelActive  = true
@@ -47,6 +86,11 @@ type FileEntry struct {
 	modTime time.Time
 	isDir   bool
 	mode    os.FileMode // Dodane pole
+	owner   string      // Właściciel i grupa (tylko pliki zdalne, np. "root:root")
+	// Dowiązania symboliczne: mode ma ModeSymlink, isDir mówi, czy cel jest
+	// katalogiem (wtedy można do niego wejść), a linkTarget to cel dowiązania
+	linkTarget string
+	brokenLink bool // Cel dowiązania nie istnieje
 
 }
 
@@ -57,12 +101,15 @@ type Panel struct {
 	selectedIndex int
 	scrollOffset  int
 	active        bool
+	entryRows     []int  // Pierwsza linia każdego widocznego wpisu (od ścieżki), plus koniec listy - do obsługi myszy
+	diskSpace     string // Wolne miejsce w systemie plików bieżącego katalogu (puste, gdy nieznane)
 }
 
 type transferProgressMsg ssh.TransferProgress
 
 type transferFinishedMsg struct {
-	err error
+	err       error
+	cancelled bool // true gdy transfer przerwano klawiszem ESC
 }
 
 // transferView implementuje główny widok transferu plików
@@ -79,16 +126,37 @@ type transferView struct {
 	showHelp      bool
 	input         textinput.Model
 	mutex         sync.Mutex
-	width         int               // Dodane
-	height        int               // Dodane
-	escPressed    bool              // flaga wskazująca czy ESC został wciśnięty
-	escTimeout    *time.Timer       // timer do resetowania stanu ESC
-	popup         *components.Popup // Zmieniamy typ na nowy komponent
+	width         int                // Dodane
+	height        int                // Dodane
+	escPressed    bool               // flaga wskazująca czy ESC został wciśnięty
+	escTimeout    *time.Timer        // timer do resetowania stanu ESC
+	popup         *components.Popup  // Zmieniamy typ na nowy komponent
+	pendingCopy   *pendingCopy       // Kopiowanie czekające na decyzje o nadpisaniu
+	copySummary   *copySummary       // Kopiowanie wielu elementów czekające na potwierdzenie
+	showHidden    bool               // true gdy panele pokazują pliki ukryte (zaczynające się od ".")
+	sortKey       int                // Aktualny klucz sortowania (sortByName, sortBySize, ...)
+	sortDesc      bool               // true dla sortowania malejącego
+	remoteUsers   map[uint32]string  // Nazwy użytkowników zdalnych (uid -> nazwa)
+	remoteGroups  map[uint32]string  // Nazwy grup zdalnych (gid -> nazwa)
+	preview       *filePreview       // Otwarty podgląd pliku (nil gdy zamknięty)
+	dirSize       *dirSizeJob        // Trwające liczenie rozmiaru katalogu (nil gdy brak)
+	bookmarkIndex int                // Zaznaczona pozycja w popupie zakładek
+	cancelCopy    context.CancelFunc // Przerywa trwający transfer (nil gdy brak)
+	editing       bool               // Zdalny plik jest pobierany, edytowany albo wysyłany
+	limited       bool               // Serwer nie obsługuje SFTP (tylko SCP i polecenia powłoki)
+	searchInput   textinput.Model    // Pole wyszukiwania w aktywnym panelu (/)
+	searching     bool               // true gdy pole wyszukiwania przyjmuje znaki
+	search        string             // Szukany tekst (pusty, gdy nie szukamy)
 
 }
+
+// connectionStatusMsg przychodzi po nawiązaniu połączenia SFTP w tle; path to
+// katalog startowy panelu zdalnego, a warning - ostrzeżenie do pokazania
 type connectionStatusMsg struct {
 	connected bool
 	err       error
+	path      string
+	warning   string
 }
 
 func NewTransferView(model *ui.Model) *transferView {
@@ -96,10 +164,12 @@ func NewTransferView(model *ui.Model) *transferView {
 	input.Placeholder = "Enter command..."
 	input.CharLimit = 255
 
+	localDir, localDirErr := localStartDir(model)
+
 	v := &transferView{
 		model: model,
 		localPanel: Panel{
-			path:   getHomeDir(),
+			path:   localDir,
 			active: true,
 			entries: []FileEntry{
 				{name: "..", isDir: true},
@@ -112,9 +182,10 @@ func NewTransferView(model *ui.Model) *transferView {
 				{name: "..", isDir: true},
 			},
 		},
-		input:  input,
-		width:  model.GetTerminalWidth(),
-		height: model.GetTerminalHeight(),
+		input:       input,
+		searchInput: newSearchInput(),
+		width:       model.GetTerminalWidth(),
+		height:      model.GetTerminalHeight(),
 	}
 
 	// Inicjalizujemy panel lokalny
@@ -122,43 +193,44 @@ func NewTransferView(model *ui.Model) *transferView {
 		v.errorMessage = fmt.Sprintf("Failed to load local directory: %v", err)
 		return v
 	}
+	if localDirErr != nil {
+		v.statusMessage = "Warning: " + localDirErr.Error()
+	}
 
-	// Inicjujemy połączenie SFTP w tle
-	if v.model.GetSelectedHost() != nil {
-		go func() {
-			// Attempt to establish connection
-			err := v.ensureConnected()
-			if err != nil {
-				v.model.Program.Send(connectionStatusMsg{
-					connected: false,
-					err:       err,
-				})
-				return
-			}
-
-			// Pobierz katalog domowy i zaktualizuj ścieżkę
-			transfer := v.model.GetTransfer()
-			if homeDir, err := transfer.GetRemoteHomeDir(); err == nil {
-				v.remotePanel.path = homeDir
-			}
-
-			// Update remote panel
-			err = v.updateRemotePanel()
-			if err != nil {
-				v.model.Program.Send(connectionStatusMsg{
-					connected: false,
-					err:       err,
-				})
-				return
-			}
+	// Bez hosta nie ma z czym się łączyć; zamiast czekać na połączenie panel
+	// zdalny kieruje do dodania lub wybrania hosta (noTransferHostMessage)
+	if v.model.GetSelectedHost() == nil {
+		return v
+	}
 
-			// Send success message
+	// Inicjujemy połączenie SFTP w tle; gorutyna nie zmienia stanu widoku,
+	// tylko przekazuje katalog startowy w connectionStatusMsg, a panel zdalny
+	// odświeża Update
+	transfer := v.model.GetTransfer()
+	host := v.model.GetSelectedHost()
+	go func() {
+		if err := connectTransfer(v.model, transfer, host); err != nil {
 			v.model.Program.Send(connectionStatusMsg{
-				connected: true,
-				err:       nil,
+				connected: false,
+				err:       err,
 			})
-		}()
-	}
+			return
+		}
+
+		// Katalog domowy, a jeśli istnieje - katalog startowy hosta
+		msg := connectionStatusMsg{connected: true}
+		if homeDir, err := transfer.GetRemoteHomeDir(); err == nil {
+			msg.path = homeDir
+			if dir := remoteStartDir(host, homeDir); dir != homeDir {
+				if info, err := transfer.GetRemoteFileInfo(dir); err == nil && info.IsDir() {
+					msg.path = dir
+				} else {
+					msg.warning = fmt.Sprintf("Warning: remote directory %s not found, starting in the home directory", dir)
+				}
+			}
+		}
+		v.model.Program.Send(msg)
+	}()
 
 	return v
 }
@@ -169,7 +241,8 @@ func (v *transferView) updateLocalPanel() error {
 	if err != nil {
 		return err
 	}
-	v.localPanel.entries = entries
+	setPanelEntries(&v.localPanel, entries)
+	v.localPanel.diskSpace = formatDiskSpace(utils.DiskSpace(v.localPanel.path))
 	return nil
 }
 
@@ -193,28 +266,30 @@ func (v *transferView) readLocalDirectory(path string) ([]FileEntry, error) {
 	}}
 
 	for _, fi := range fileInfos {
-		// Pomijamy ukryte pliki zaczynające się od "." (opcjonalnie)
-		if !strings.HasPrefix(fi.Name(), ".") || fi.Name() == ".." {
-			entries = append(entries, FileEntry{
-				name:    fi.Name(),
-				size:    fi.Size(),
-				modTime: fi.ModTime(),
-				isDir:   fi.IsDir(),
-				mode:    fi.Mode(), // Dodane
+		// Pomijamy ukryte pliki zaczynające się od ".", chyba że włączono ich wyświetlanie
+		if v.isHiddenEntry(fi.Name()) {
+			continue
+		}
+		entry := FileEntry{
+			name:    fi.Name(),
+			size:    fi.Size(),
+			modTime: fi.ModTime(),
+			isDir:   fi.IsDir(),
+			mode:    fi.Mode(), // Dodane
 
-			})
 		}
+		if fi.Mode()&os.ModeSymlink != 0 {
+			linkPath := filepath.Join(path, fi.Name())
+			entry.linkTarget, _ = os.Readlink(linkPath)
+			target, err := os.Stat(linkPath)
+			entry.brokenLink = err != nil
+			entry.isDir = err == nil && target.IsDir()
+		}
+		entries = append(entries, entry)
 	}
 
-	// Sortowanie: najpierw katalogi, potem pliki, alfabetycznie
-	sort.Slice(entries[1:], func(i, j int) bool {
-		// Przesuwamy indeksy o 1, bo pomijamy ".."
-		i, j = i+1, j+1
-		if entries[i].isDir != entries[j].isDir {
-			return entries[i].isDir
-		}
-		return strings.ToLower(entries[i].name) < strings.ToLower(entries[j].name)
-	})
+	// Sortowanie: najpierw katalogi, potem pliki, według wybranego klucza
+	v.sortEntries(entries)
 
 	return entries, nil
 }
@@ -237,10 +312,20 @@ func (v *transferView) updateRemotePanel() error {
 		v.setConnected(false) // Oznacz jako rozłączony w przypadku błędu
 		return err
 	}
-	v.remotePanel.entries = entries
+	setPanelEntries(&v.remotePanel, entries)
+	// Serwery bez rozszerzenia statvfs zwracają błąd - wtedy nic nie pokazujemy
+	v.remotePanel.diskSpace = formatDiskSpace(v.model.GetTransfer().RemoteDiskSpace(v.remotePanel.path))
 	return nil
 }
 
+// formatDiskSpace opisuje wolne miejsce, np. "Free: 12.3 GB of 100.0 GB"
+func formatDiskSpace(free, total uint64, err error) string {
+	if err != nil || total == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Free: %s of %s", formatSize(int64(free)), formatSize(int64(total)))
+}
+
 // readRemoteDirectory czyta zawartość zdalnego katalogu
 func (v *transferView) readRemoteDirectory(path string) ([]FileEntry, error) {
 	if err := v.ensureConnected(); err != nil {
@@ -261,28 +346,240 @@ func (v *transferView) readRemoteDirectory(path string) ([]FileEntry, error) {
 		modTime: time.Now(),
 	}}
 
+	// Nazwy właścicieli pobieramy raz na połączenie
+	if v.remoteUsers == nil {
+		v.remoteUsers, v.remoteGroups = transfer.RemoteOwnerNames()
+	}
+
 	for _, fi := range fileInfos {
-		if !strings.HasPrefix(fi.Name(), ".") || fi.Name() == ".." {
-			entries = append(entries, FileEntry{
-				name:    fi.Name(),
-				size:    fi.Size(),
-				modTime: fi.ModTime(),
-				isDir:   fi.IsDir(),
-				mode:    fi.Mode(), // Dodane
-			})
+		if v.isHiddenEntry(fi.Name()) {
+			continue
 		}
+		entry := FileEntry{
+			name:    fi.Name(),
+			size:    fi.Size(),
+			modTime: fi.ModTime(),
+			isDir:   fi.IsDir(),
+			mode:    fi.Mode(), // Dodane
+			owner:   v.remoteOwner(fi),
+		}
+		if fi.Mode()&os.ModeSymlink != 0 {
+			linkPath := utils.ToSFTPPath(filepath.Join(path, fi.Name()))
+			entry.linkTarget, _ = transfer.ReadRemoteLink(linkPath)
+			target, err := transfer.GetRemoteFileInfo(linkPath)
+			entry.brokenLink = err != nil
+			entry.isDir = err == nil && target.IsDir()
+		}
+		entries = append(entries, entry)
 	}
 
-	// Sortowanie: najpierw katalogi, potem pliki, alfabetycznie
-	sort.Slice(entries[1:], func(i, j int) bool {
-		i, j = i+1, j+1
-		if entries[i].isDir != entries[j].isDir {
-			return entries[i].isDir
+	// Sortowanie: najpierw katalogi, potem pliki, według wybranego klucza
+	v.sortEntries(entries)
+
+	return entries, nil
+}
+
+// Klucze sortowania list plików (cyklicznie przełączane klawiszem "o")
+const (
+	sortByName = iota
+	sortBySize
+	sortByModified
+	sortByPerms
+	sortKeyCount
+)
+
+var sortKeyNames = [sortKeyCount]string{"name", "size", "modified", "perms"}
+
+// sortEntries sortuje wpisy (poza ".." na początku): katalogi przed plikami,
+// a w ich obrębie według wybranego klucza i kierunku
+func (v *transferView) sortEntries(entries []FileEntry) {
+	if len(entries) < 2 {
+		return
+	}
+	rest := entries[1:]
+	less := func(a, b FileEntry) bool {
+		var less, equal bool
+		switch v.sortKey {
+		case sortBySize:
+			less, equal = a.size < b.size, a.size == b.size
+		case sortByModified:
+			less, equal = a.modTime.Before(b.modTime), a.modTime.Equal(b.modTime)
+		case sortByPerms:
+			less, equal = a.mode.Perm() < b.mode.Perm(), a.mode.Perm() == b.mode.Perm()
+		}
+		if v.sortKey == sortByName || equal {
+			// Nazwa rozstrzyga remisy, aby kolejność była stabilna; przy nazwach
+			// różniących się tylko wielkością liter decyduje dokładne porównanie
+			aName, bName := strings.ToLower(a.name), strings.ToLower(b.name)
+			if aName == bName {
+				return a.name < b.name
+			}
+			less = aName < bName
+		}
+		return less
+	}
+	sort.SliceStable(rest, func(i, j int) bool {
+		a, b := rest[i], rest[j]
+		if a.isDir != b.isDir {
+			return a.isDir
+		}
+		// Odwrócenie kolejności argumentów zachowuje ścisły porządek także malejąco
+		if v.sortDesc {
+			return less(b, a)
 		}
-		return strings.ToLower(entries[i].name) < strings.ToLower(entries[j].name)
+		return less(a, b)
 	})
+}
 
-	return entries, nil
+// cycleSort zmienia klucz (o) lub kierunek (O) sortowania i porządkuje oba panele
+func (v *transferView) cycleSort(reverse bool) {
+	if reverse {
+		v.sortDesc = !v.sortDesc
+	} else {
+		v.sortKey = (v.sortKey + 1) % sortKeyCount
+		v.sortDesc = false
+	}
+
+	for _, panel := range []*Panel{&v.localPanel, &v.remotePanel} {
+		entries := append([]FileEntry(nil), panel.entries...)
+		v.sortEntries(entries)
+		setPanelEntries(panel, entries)
+	}
+
+	direction := "ascending"
+	if v.sortDesc {
+		direction = "descending"
+	}
+	v.statusMessage = fmt.Sprintf("Sorted by %s (%s)", sortKeyNames[v.sortKey], direction)
+}
+
+// formatPerms zwraca uprawnienia w stylu ls, np. "drwxr-xr-x"
+func formatPerms(entry FileEntry) string {
+	if entry.name == ".." {
+		return ""
+	}
+	typeChar := "-"
+	switch {
+	case isSymlink(entry):
+		typeChar = "l"
+	case entry.isDir:
+		typeChar = "d"
+	}
+	return typeChar + entry.mode.Perm().String()[1:]
+}
+
+// isSymlink sprawdza, czy wpis jest dowiązaniem symbolicznym
+func isSymlink(entry FileEntry) bool {
+	return entry.mode&os.ModeSymlink != 0
+}
+
+// entryKind zwraca rodzaj wpisu do komunikatów ("directory", "file", "link")
+func entryKind(entry FileEntry) string {
+	switch {
+	case isSymlink(entry):
+		return "link"
+	case entry.isDir:
+		return "directory"
+	}
+	return "file"
+}
+
+// remoteOwner zwraca "właściciel:grupa" dla zdalnego pliku, z nazwami jeśli są znane
+func (v *transferView) remoteOwner(fi os.FileInfo) string {
+	stat, ok := fi.Sys().(*sftp.FileStat)
+	if !ok {
+		return ""
+	}
+
+	user, ok := v.remoteUsers[stat.UID]
+	if !ok {
+		user = strconv.FormatUint(uint64(stat.UID), 10)
+	}
+	group, ok := v.remoteGroups[stat.GID]
+	if !ok {
+		group = strconv.FormatUint(uint64(stat.GID), 10)
+	}
+	return user + ":" + group
+}
+
+// isHiddenEntry sprawdza, czy wpis katalogu ma zostać pominięty na liście;
+// "." i ".." pomijamy zawsze (".." dodajemy sami na początku listy)
+func (v *transferView) isHiddenEntry(name string) bool {
+	if name == "." || name == ".." {
+		return true
+	}
+	return !v.showHidden && strings.HasPrefix(name, ".")
+}
+
+// toggleHiddenFiles przełącza wyświetlanie plików ukrytych i odświeża oba panele
+func (v *transferView) toggleHiddenFiles() {
+	v.showHidden = !v.showHidden
+
+	if err := v.updateLocalPanel(); err != nil {
+		v.handleError(err)
+	}
+	if v.connected {
+		if err := v.updateRemotePanel(); err != nil {
+			v.handleError(err)
+		}
+	}
+
+	if v.showHidden {
+		v.statusMessage = "Showing hidden files"
+	} else {
+		v.statusMessage = "Hiding hidden files"
+	}
+}
+
+// togglePreserve włącza lub wyłącza zachowywanie uprawnień i czasu modyfikacji
+// przy kopiowaniu; wybór jest zapisywany w konfiguracji
+func (v *transferView) togglePreserve() {
+	config := v.model.GetConfig()
+	preserve := !config.GetPreserveAttributes()
+	config.SetPreserveAttributes(preserve)
+	if err := config.SaveLocal(); err != nil {
+		v.handleError(fmt.Errorf("failed to save configuration: %v", err))
+	}
+	v.model.GetTransfer().SetPreserveAttributes(preserve)
+
+	if preserve {
+		v.statusMessage = "Copies keep permissions and modification times"
+	} else {
+		v.statusMessage = "Copies get default permissions and the current time"
+	}
+}
+
+// showRateLimitPopup pyta o limit prędkości transferów dla tego połączenia
+func (v *transferView) showRateLimitPopup() {
+	v.popup = components.NewPopup(
+		components.PopupRateLimit,
+		"Speed Limit",
+		"Transfer speed limit in KB/s (0 = unlimited):",
+		50,
+		7,
+		v.width,
+		v.height,
+	)
+	v.popup.Input.SetValue(strconv.Itoa(v.model.GetTransfer().RateLimit()))
+	v.popup.Input.CursorEnd()
+	v.popup.Input.Focus()
+}
+
+// setRateLimit ustawia limit prędkości do końca połączenia (także dla
+// trwającego transferu); domyślny limit pochodzi z konfiguracji (rate_limit)
+func (v *transferView) setRateLimit(value string) error {
+	limit, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || limit < 0 {
+		return fmt.Errorf("invalid speed limit '%s': expected KB/s, 0 for unlimited", value)
+	}
+	v.model.GetTransfer().SetRateLimit(limit)
+
+	if limit == 0 {
+		v.statusMessage = "Transfer speed is not limited"
+	} else {
+		v.statusMessage = fmt.Sprintf("Transfer speed limited to %d KB/s", limit)
+	}
+	return nil
 }
 
 // getActivePanel zwraca aktywny panel
@@ -303,6 +600,7 @@ func (v *transferView) getInactivePanel() *Panel {
 
 // switchActivePanel przełącza aktywny panel
 func (v *transferView) switchActivePanel() {
+	v.clearSearch()
 	v.localPanel.active = !v.localPanel.active
 	v.remotePanel.active = !v.remotePanel.active
 }
@@ -331,10 +629,11 @@ func (v *transferView) renderPanel(p *Panel) string {
 	filesList := v.renderFileList(
 		p.entries[p.scrollOffset:min(p.scrollOffset+maxVisibleItems, len(p.entries))],
 		p.selectedIndex-p.scrollOffset,
-		p.active,
+		p == &v.remotePanel,
 		panelWidth-2,
 	)
 	panelContent.WriteString(filesList)
+	p.entryRows = wrappedEntryRows(panelContent.String(), panelWidth-2)
 
 	// Informacja o przewijaniu
 	if len(p.entries) > maxVisibleItems {
@@ -343,6 +642,9 @@ func (v *transferView) renderPanel(p *Panel) string {
 			min(p.scrollOffset+maxVisibleItems, len(p.entries)),
 			len(p.entries)))
 	}
+	if p.diskSpace != "" {
+		panelContent.WriteString("\n" + ui.DescriptionStyle.Render(p.diskSpace))
+	}
 
 	// Zastosuj styl całego panelu
 	content.WriteString(panelStyle.
@@ -363,6 +665,10 @@ func (v *transferView) View() string {
 				fmt.Sprintf(" - Connected to %s (%s)", host.Name, host.IP),
 			)
 		}
+		// Serwer bez SFTP: działa tylko SCP i polecenia powłoki
+		if v.limited {
+			titleContent += ui.ErrorStyle.Render(" [limited mode: no SFTP, SCP only]")
+		}
 	} else if host := v.model.GetSelectedHost(); host != nil {
 		if v.connecting {
 			titleContent += ui.DescriptionStyle.Render(" - Establishing connection...")
@@ -387,6 +693,10 @@ func (v *transferView) View() string {
 	}
 
 	// Obsługa widoku pomocy
+	if v.preview != nil {
+		return v.renderPreview()
+	}
+
 	if v.showHelp {
 		helpContent := ui.DescriptionStyle.Render(helpText)
 		return lipgloss.Place(
@@ -405,7 +715,9 @@ func (v *transferView) View() string {
 	// Renderuj panele
 	leftPanel := v.renderPanel(&v.localPanel)
 	rightPanel := ""
-	if !v.connected {
+	if v.model.GetSelectedHost() == nil {
+		rightPanel = ui.ErrorStyle.Render("\n  " + strings.ReplaceAll(noTransferHostMessage(v.model), "\n", "\n  "))
+	} else if !v.connected {
 		rightPanel = ui.ErrorStyle.Render("\n  No SFTP Connection\n  Press 'q' to return and connect to a host first.")
 	} else {
 		rightPanel = v.renderPanel(&v.remotePanel)
@@ -506,6 +818,45 @@ func formatSize(size int64) string {
 		float64(size)/float64(div), "KMGTPE"[exp])
 }
 
+// setPanelEntries podmienia wpisy panelu (odświeżenie, sortowanie), zostawiając
+// kursor na tym samym wpisie (po nazwie) i w tym samym wierszu ekranu. Gdy
+// wpisu już nie ma, kursor zostaje na tej samej pozycji, obciętej do długości listy.
+func setPanelEntries(p *Panel, entries []FileEntry) {
+	row := p.selectedIndex - p.scrollOffset
+	index := p.selectedIndex
+	if p.selectedIndex < len(p.entries) {
+		name := p.entries[p.selectedIndex].name
+		for i, entry := range entries {
+			if entry.name == name {
+				index = i
+				break
+			}
+		}
+	}
+
+	p.entries = entries
+	p.selectedIndex = min(index, max(len(entries)-1, 0))
+	p.scrollOffset = max(p.selectedIndex-row, 0)
+	if p.selectedIndex >= p.scrollOffset+maxVisibleItems {
+		p.scrollOffset = p.selectedIndex - maxVisibleItems + 1
+	}
+	// Bez pustych wierszy na dole, jeśli lista mieści się wyżej
+	if maxOffset := max(len(entries)-maxVisibleItems, 0); p.scrollOffset > maxOffset {
+		p.scrollOffset = maxOffset
+	}
+}
+
+// selectEntryByName ustawia kursor na wpisie o podanej nazwie (np. po utworzeniu
+// lub zmianie nazwy); brak wpisu zostawia kursor bez zmian
+func (v *transferView) selectEntryByName(p *Panel, name string) {
+	for i, entry := range p.entries {
+		if entry.name == name {
+			v.navigatePanel(p, i-p.selectedIndex)
+			return
+		}
+	}
+}
+
 // navigatePanel obsługuje nawigację w panelu
 func (v *transferView) navigatePanel(p *Panel, direction int) {
 	if len(p.entries) == 0 {
@@ -560,6 +911,11 @@ func (v *transferView) enterDirectory(p *Panel) error {
 		newPath = filepath.Join(p.path, entry.name)
 	}
 
+	return v.changeDirectory(p, newPath)
+}
+
+// changeDirectory przechodzi panelem do wskazanego katalogu
+func (v *transferView) changeDirectory(p *Panel, newPath string) error {
 	// Zapisz poprzednią ścieżkę
 	oldPath := p.path
 	p.path = newPath
@@ -578,111 +934,319 @@ func (v *transferView) enterDirectory(p *Panel) error {
 		return err
 	}
 
-	// Resetuj wybór i przewijanie
+	// Resetuj wybór, przewijanie, wyszukiwanie i zaznaczenie (dotyczy jednego katalogu)
 	p.selectedIndex = 0
 	p.scrollOffset = 0
+	if p.active {
+		v.clearSearch()
+	}
+	v.model.ClearSelection()
 	return nil
 }
 
-func (v *transferView) hasSelectedItems() bool {
-	for _, isSelected := range v.getSelectedItems() {
-		if isSelected {
-			return true
+// copyItem opisuje pojedynczy element do skopiowania między panelami
+type copyItem struct {
+	srcPath   string
+	dstPath   string
+	isDir     bool
+	overwrite bool // true gdy użytkownik potwierdził nadpisanie istniejącego pliku
+}
+
+// Decyzje dla elementów, które już istnieją w panelu docelowym
+const (
+	conflictOverwrite = "overwrite"
+	conflictSkip      = "skip"
+	conflictRename    = "rename"
+)
+
+// pendingCopy przechowuje kopiowanie wstrzymane do czasu rozstrzygnięcia konfliktów nazw
+type pendingCopy struct {
+	items       []copyItem
+	conflicts   []int  // Indeksy elementów (w items), których nazwa istnieje w panelu docelowym
+	current     int    // Indeks w conflicts aktualnie pokazywanego konfliktu
+	applyToAll  string // Decyzja zastosowana do wszystkich pozostałych konfliktów
+	skipped     map[int]bool
+	fromLocal   bool
+	dstPanel    *Panel
+	dstEntryMap map[string]bool
+}
+
+// copyBatch zlicza postęp kopiowania wielu plików (zaznaczonych lub z katalogów)
+type copyBatch struct {
+	mutex      sync.Mutex
+	totalBytes int64
+	totalFiles int
+	doneBytes  int64
+	doneFiles  int
+	itemSizes  []int64     // Rozmiary elementów w kolejności kopiowania
+	plans      []*copyPlan // Zawartość kopiowanych katalogów (nil dla plików)
+}
+
+// newCopyBatch liczy łączny rozmiar i liczbę plików do skopiowania, zapisując
+// zawartość katalogów do kopiowania; przerwane liczenie zwraca błąd
+func newCopyBatch(items []copyItem, fromLocal bool, transfer *ssh.FileTransfer, cancel <-chan struct{}) (*copyBatch, error) {
+	batch := &copyBatch{
+		itemSizes: make([]int64, len(items)),
+		plans:     make([]*copyPlan, len(items)),
+	}
+	for i, item := range items {
+		if item.isDir {
+			var plan *copyPlan
+			var err error
+			if fromLocal {
+				plan, err = localCopyPlan(item.srcPath, cancel)
+			} else {
+				plan, err = remoteCopyPlan(item.srcPath, transfer, cancel)
+			}
+			if err != nil {
+				return batch, err
+			}
+			batch.plans[i] = plan
+			batch.itemSizes[i] = plan.size
+			batch.totalBytes += plan.size
+			batch.totalFiles += plan.files
+			continue
+		}
+
+		var info os.FileInfo
+		var err error
+		if fromLocal {
+			info, err = os.Stat(item.srcPath)
+		} else {
+			info, err = transfer.GetRemoteFileInfo(utils.ToSFTPPath(item.srcPath))
 		}
+		if err == nil {
+			batch.itemSizes[i] = info.Size()
+			batch.totalBytes += info.Size()
+		}
+		batch.totalFiles++
 	}
-	return false
+	return batch, nil
+}
+
+// fileDone odnotowuje zakończenie kopiowania pliku
+func (b *copyBatch) fileDone(size int64) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.doneBytes += size
+	b.doneFiles++
 }
 
-func (v *transferView) getSelectedItems() map[string]bool {
-	selected := make(map[string]bool)
-	paths := v.model.GetSelectedPaths() // zakładając, że taka metoda istnieje w Model
-	for _, path := range paths {
-		selected[path] = true
+// annotate uzupełnia postęp pojedynczego pliku o postęp całej partii
+func (b *copyBatch) annotate(progress *ssh.TransferProgress) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.totalFiles < 2 {
+		return
+	}
+	progress.BatchTotalBytes = b.totalBytes
+	progress.BatchTotalFiles = b.totalFiles
+	progress.BatchDoneFiles = b.doneFiles
+	// Ostatni raport pliku może dotrzeć już po fileDone, stąd ograniczenie do całości
+	progress.BatchDoneBytes = b.doneBytes + progress.TransferredBytes
+	if progress.BatchDoneBytes > b.totalBytes {
+		progress.BatchDoneBytes = b.totalBytes
 	}
-	return selected
 }
 
 func (v *transferView) copyFile() tea.Cmd {
 	srcPanel := v.getActivePanel()
 	dstPanel := v.getInactivePanel()
+	isLocal := srcPanel == &v.localPanel
 
-	var itemsToCopy []struct {
-		srcPath string
-		dstPath string
-		isDir   bool
-	}
-
-	if !v.hasSelectedItems() {
-		if len(srcPanel.entries) == 0 || srcPanel.selectedIndex >= len(srcPanel.entries) {
-			v.handleError(fmt.Errorf("no file selected"))
-			return nil
-		}
-		entry := srcPanel.entries[srcPanel.selectedIndex]
-
-		isLocal := srcPanel == &v.localPanel
-		srcName := filepath.Base(entry.name)
-		dstName := srcName
+	var itemsToCopy []copyItem
 
+	// newItem buduje ścieżki źródłową i docelową dla nazwy z panelu źródłowego
+	newItem := func(srcName string, isDir bool) copyItem {
 		var srcPath, dstPath string
 		if isLocal {
 			// Local to Remote
 			srcPath = filepath.Join(srcPanel.path, srcName)
-			dstPath = utils.ToSFTPPath(filepath.Join(dstPanel.path, dstName))
+			dstPath = utils.ToSFTPPath(filepath.Join(dstPanel.path, srcName))
 		} else {
 			// Remote to Local
 			srcPath = utils.ToSFTPPath(filepath.Join(srcPanel.path, srcName))
-			dstPath = utils.ToLocalPath(filepath.Join(dstPanel.path, dstName))
+			dstPath = utils.ToLocalPath(filepath.Join(dstPanel.path, srcName))
 		}
+		return copyItem{srcPath: srcPath, dstPath: dstPath, isDir: isDir}
+	}
 
-		itemsToCopy = append(itemsToCopy, struct {
-			srcPath string
-			dstPath string
-			isDir   bool
-		}{srcPath, dstPath, entry.isDir})
+	if selected := v.selectedEntries(srcPanel); len(selected) == 0 {
+		if len(srcPanel.entries) == 0 || srcPanel.selectedIndex >= len(srcPanel.entries) {
+			v.handleError(fmt.Errorf("no file selected"))
+			return nil
+		}
+		entry := srcPanel.entries[srcPanel.selectedIndex]
+		itemsToCopy = append(itemsToCopy, newItem(filepath.Base(entry.name), entry.isDir))
 	} else {
-		// Handle selected files
-		for path, isSelected := range v.getSelectedItems() {
-			if !isSelected {
-				continue
-			}
+		// Zaznaczone wpisy aktywnego panelu
+		for _, entry := range selected {
+			itemsToCopy = append(itemsToCopy, newItem(entry.name, entry.isDir))
+		}
+	}
 
-			isLocal := srcPanel == &v.localPanel
-			srcName := filepath.Base(path)
-			dstName := srcName
+	if len(itemsToCopy) == 0 {
+		v.handleError(fmt.Errorf("no items to copy"))
+		return nil
+	}
 
-			var srcPath, dstPath string
-			if isLocal {
-				// Local to Remote
-				srcPath = filepath.Join(srcPanel.path, srcName)
-				dstPath = utils.ToSFTPPath(filepath.Join(dstPanel.path, dstName))
-			} else {
-				// Remote to Local
-				srcPath = utils.ToSFTPPath(filepath.Join(srcPanel.path, srcName))
-				dstPath = utils.ToLocalPath(filepath.Join(dstPanel.path, dstName))
-			}
+	// Elementy o nazwach istniejących w panelu docelowym wymagają decyzji użytkownika
+	pending := &pendingCopy{
+		items:       itemsToCopy,
+		skipped:     make(map[int]bool),
+		fromLocal:   isLocal,
+		dstPanel:    dstPanel,
+		dstEntryMap: make(map[string]bool),
+	}
+	for _, entry := range dstPanel.entries {
+		if entry.name != ".." {
+			pending.dstEntryMap[entry.name] = true
+		}
+	}
+	for i, item := range itemsToCopy {
+		if pending.dstEntryMap[filepath.Base(item.dstPath)] {
+			pending.conflicts = append(pending.conflicts, i)
+		}
+	}
 
-			info, err := os.Stat(path)
-			if err != nil {
-				v.handleError(fmt.Errorf("cannot access %s: %v", path, err))
-				continue
-			}
+	if len(pending.conflicts) > 0 {
+		v.pendingCopy = pending
+		v.showOverwritePopup()
+		return nil
+	}
 
-			itemsToCopy = append(itemsToCopy, struct {
-				srcPath string
-				dstPath string
-				isDir   bool
-			}{srcPath, dstPath, info.IsDir()})
+	return v.beginCopy(itemsToCopy, isLocal)
+}
+
+// showOverwritePopup pokazuje pytanie o aktualnie rozpatrywany konflikt nazw
+func (v *transferView) showOverwritePopup() {
+	pending := v.pendingCopy
+	item := pending.items[pending.conflicts[pending.current]]
+
+	message := fmt.Sprintf("'%s' already exists in the destination.\n\no - Overwrite, s - Skip, r - Rename",
+		filepath.Base(item.dstPath))
+	height := 9
+	if remaining := len(pending.conflicts) - pending.current; remaining > 1 {
+		message += fmt.Sprintf("\nO/S/R - Apply to all %d remaining conflicts", remaining)
+		height++
+	}
+
+	v.popup = components.NewPopup(
+		components.PopupOverwrite,
+		"File Exists",
+		message,
+		60,
+		height,
+		v.width,
+		v.height,
+	)
+}
+
+// handlePopupInput obsługuje klawisze popupów wymagających wyboru opcji
+func (v *transferView) handlePopupInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if v.popup.Type != components.PopupOverwrite || v.pendingCopy == nil {
+		return v, nil
+	}
+
+	var decision string
+	key := msg.String()
+	switch strings.ToLower(key) {
+	case "o":
+		decision = conflictOverwrite
+	case "s":
+		decision = conflictSkip
+	case "r":
+		decision = conflictRename
+	case "esc":
+		v.pendingCopy = nil
+		v.popup = nil
+		v.statusMessage = "Copy cancelled"
+		return v, nil
+	default:
+		return v, nil
+	}
+
+	pending := v.pendingCopy
+	if key != strings.ToLower(key) {
+		pending.applyToAll = decision
+	}
+
+	for pending.current < len(pending.conflicts) {
+		v.resolveConflict(pending.conflicts[pending.current], decision)
+		pending.current++
+		if pending.applyToAll == "" {
+			break
 		}
 	}
 
-	if len(itemsToCopy) == 0 {
-		v.handleError(fmt.Errorf("no items to copy"))
-		return nil
+	if pending.current < len(pending.conflicts) {
+		v.showOverwritePopup()
+		return v, nil
 	}
 
+	// Wszystkie konflikty rozstrzygnięte - uruchamiamy kopiowanie
+	v.popup = nil
+	v.pendingCopy = nil
+
+	var items []copyItem
+	for i, item := range pending.items {
+		if !pending.skipped[i] {
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
+		v.statusMessage = "Nothing to copy, all items skipped"
+		v.model.ClearSelection()
+		return v, nil
+	}
+	return v, v.beginCopy(items, pending.fromLocal)
+}
+
+// resolveConflict stosuje decyzję użytkownika do elementu o podanym indeksie
+func (v *transferView) resolveConflict(index int, decision string) {
+	pending := v.pendingCopy
+	item := &pending.items[index]
+
+	switch decision {
+	case conflictOverwrite:
+		item.overwrite = true
+	case conflictSkip:
+		pending.skipped[index] = true
+	case conflictRename:
+		newName := uniqueCopyName(filepath.Base(item.dstPath), pending.dstEntryMap)
+		pending.dstEntryMap[newName] = true
+		if pending.fromLocal {
+			item.dstPath = utils.ToSFTPPath(filepath.Join(pending.dstPanel.path, newName))
+		} else {
+			item.dstPath = utils.ToLocalPath(filepath.Join(pending.dstPanel.path, newName))
+		}
+	}
+}
+
+// uniqueCopyName zwraca nazwę w stylu "plik (1).txt", która nie istnieje w panelu docelowym
+func uniqueCopyName(name string, existing map[string]bool) string {
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	if base == "" { // pliki ukryte bez rozszerzenia, np. ".bashrc"
+		base, ext = name, ""
+	}
+	for i := 1; ; i++ {
+		candidate := fmt.Sprintf("%s (%d)%s", base, i, ext)
+		if !existing[candidate] {
+			return candidate
+		}
+	}
+}
+
+// startCopy uruchamia kopiowanie elementów w tle i raportuje postęp; batch
+// policzony wcześniej (podsumowanie kopiowania) jest użyty zamiast liczenia od nowa
+func (v *transferView) startCopy(itemsToCopy []copyItem, fromLocal bool, batch *copyBatch) tea.Cmd {
+	ctx, cancel := context.WithCancel(context.Background())
+
 	v.mutex.Lock()
 	v.transferring = true
-	v.statusMessage = "Copying files..."
+	v.cancelCopy = cancel
+	v.statusMessage = "Copying files... (ESC to cancel)"
 	v.mutex.Unlock()
 
 	transfer := v.model.GetTransfer()
@@ -690,22 +1254,45 @@ func (v *transferView) copyFile() tea.Cmd {
 	return func() tea.Msg {
 		progressChan := make(chan ssh.TransferProgress)
 		doneChan := make(chan error, 1)
+		batchChan := make(chan *copyBatch, 1)
 
 		go func() {
 			var totalErr error
-			for _, item := range itemsToCopy {
+			if batch == nil {
+				batch, totalErr = newCopyBatch(itemsToCopy, fromLocal, transfer, ctx.Done())
+			}
+			batchChan <- batch
+
+			for i, item := range itemsToCopy {
+				if totalErr != nil {
+					break
+				}
+				if ctx.Err() != nil {
+					break
+				}
 				var err error
+				// Nadpisywany plik usuwamy, aby nie został potraktowany jak częściowy transfer do wznowienia
+				if item.overwrite && !item.isDir {
+					if fromLocal {
+						err = transfer.RemoveRemoteFile(item.dstPath)
+					} else {
+						err = os.Remove(item.dstPath)
+					}
+					if err != nil {
+						totalErr = fmt.Errorf("error replacing %s: %v", item.dstPath, err)
+						break
+					}
+				}
 				if item.isDir {
-					if srcPanel == &v.localPanel {
-						err = v.copyDirectoryToRemote(item.srcPath, item.dstPath, transfer, progressChan)
+					if fromLocal {
+						err = v.copyDirectoryToRemote(ctx, item, transfer, progressChan, batch, batch.plans[i])
 					} else {
-						err = v.copyDirectoryFromRemote(item.srcPath, item.dstPath, transfer, progressChan)
+						err = v.copyDirectoryFromRemote(ctx, item, transfer, progressChan, batch, batch.plans[i])
 					}
 				} else {
-					if srcPanel == &v.localPanel {
-						err = transfer.UploadFile(item.srcPath, item.dstPath, progressChan)
-					} else {
-						err = transfer.DownloadFile(item.srcPath, item.dstPath, progressChan)
+					err = copyFile(ctx, transfer, fromLocal, item.overwrite, item.srcPath, item.dstPath, progressChan)
+					if err == nil {
+						batch.fileDone(batch.itemSizes[i])
 					}
 				}
 				if err != nil {
@@ -718,11 +1305,18 @@ func (v *transferView) copyFile() tea.Cmd {
 		}()
 
 		go func() {
+			batch := <-batchChan
 			for progress := range progressChan {
+				batch.annotate(&progress)
 				v.model.Program.Send(transferProgressMsg(progress))
 			}
 			err := <-doneChan
-			v.model.Program.Send(transferFinishedMsg{err: err})
+			// Błąd przerwanego transferu jest tylko skutkiem anulowania
+			if ctx.Err() != nil {
+				v.model.Program.Send(transferFinishedMsg{cancelled: true})
+			} else {
+				v.model.Program.Send(transferFinishedMsg{err: err})
+			}
 			v.model.ClearSelection()
 		}()
 
@@ -730,64 +1324,83 @@ func (v *transferView) copyFile() tea.Cmd {
 	}
 }
 
-func (v *transferView) copyDirectoryToRemote(localPath, remotePath string, transfer *ssh.FileTransfer, progressChan chan<- ssh.TransferProgress) error {
-	remotePath = utils.ToSFTPPath(remotePath)
+// copyFile kopiuje pojedynczy plik; przy nadpisaniu wybranym przez użytkownika
+// nigdy nie wznawia transferu, bo istniejący plik nie jest częściową kopią
+func copyFile(ctx context.Context, transfer *ssh.FileTransfer, fromLocal, overwrite bool, srcPath, dstPath string, progressChan chan<- ssh.TransferProgress) error {
+	switch {
+	case fromLocal && overwrite:
+		return transfer.OverwriteRemoteFile(ctx, srcPath, dstPath, progressChan)
+	case fromLocal:
+		return transfer.UploadFile(ctx, srcPath, dstPath, progressChan)
+	case overwrite:
+		return transfer.OverwriteLocalFile(ctx, srcPath, dstPath, progressChan)
+	default:
+		return transfer.DownloadFile(ctx, srcPath, dstPath, progressChan)
+	}
+}
+
+// copyDirectoryToRemote kopiuje lokalny katalog według planu zebranego przy liczeniu partii
+func (v *transferView) copyDirectoryToRemote(ctx context.Context, item copyItem, transfer *ssh.FileTransfer, progressChan chan<- ssh.TransferProgress, batch *copyBatch, plan *copyPlan) error {
+	localPath := item.srcPath
+	remotePath := utils.ToSFTPPath(item.dstPath)
 	if err := transfer.CreateRemoteDirectory(remotePath); err != nil {
 		return fmt.Errorf("failed to create remote directory: %v", err)
 	}
 
-	return filepath.Walk(localPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
+	for _, entry := range plan.entries {
+		if err := ctx.Err(); err != nil {
 			return err
 		}
-
-		relPath, err := filepath.Rel(localPath, path)
-		if err != nil {
-			return fmt.Errorf("failed to get relative path: %v", err)
+		if entry.err != nil {
+			return entry.err
 		}
 
 		// Konwersja ścieżki na format SFTP
-		remotePathFull := utils.ToSFTPPath(filepath.Join(remotePath, relPath))
+		remotePathFull := utils.ToSFTPPath(filepath.Join(remotePath, entry.relPath))
 
-		if info.IsDir() {
-			return transfer.CreateRemoteDirectory(remotePathFull)
+		if entry.isDir {
+			if err := transfer.CreateRemoteDirectory(remotePathFull); err != nil {
+				return err
+			}
+			continue
 		}
-
-		return transfer.UploadFile(path, remotePathFull, progressChan)
-	})
+		if err := copyFile(ctx, transfer, true, item.overwrite, filepath.Join(localPath, entry.relPath), remotePathFull, progressChan); err != nil {
+			return err
+		}
+		batch.fileDone(entry.size)
+	}
+	return nil
 }
 
-func (v *transferView) copyDirectoryFromRemote(remotePath, localPath string, transfer *ssh.FileTransfer, progressChan chan<- ssh.TransferProgress) error {
+// copyDirectoryFromRemote pobiera zdalny katalog według planu zebranego przy liczeniu partii
+func (v *transferView) copyDirectoryFromRemote(ctx context.Context, item copyItem, transfer *ssh.FileTransfer, progressChan chan<- ssh.TransferProgress, batch *copyBatch, plan *copyPlan) error {
+	remotePath, localPath := item.srcPath, item.dstPath
 	if err := os.MkdirAll(localPath, 0755); err != nil {
 		return fmt.Errorf("failed to create local directory: %v", err)
 	}
 
-	remotePath = utils.ToSFTPPath(remotePath)
-	entries, err := transfer.ListRemoteFiles(remotePath)
-	if err != nil {
-		return fmt.Errorf("failed to list remote directory: %v", err)
-	}
+	for _, entry := range plan.entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if entry.err != nil {
+			return entry.err
+		}
 
-	for _, entry := range entries {
-		// Pomijamy "." i ".."
-		if entry.Name() == "." || entry.Name() == ".." {
+		localDstPath := filepath.Join(localPath, entry.relPath)
+		if entry.isDir {
+			if err := os.MkdirAll(localDstPath, 0755); err != nil {
+				return fmt.Errorf("failed to create local directory: %v", err)
+			}
 			continue
 		}
 
-		remoteSrcPath := utils.ToSFTPPath(filepath.Join(remotePath, entry.Name()))
-		localDstPath := filepath.Join(localPath, entry.Name())
-
-		if entry.IsDir() {
-			if err := v.copyDirectoryFromRemote(remoteSrcPath, localDstPath, transfer, progressChan); err != nil {
-				return fmt.Errorf("failed to copy remote directory %s: %v", entry.Name(), err)
-			}
-		} else {
-			if err := transfer.DownloadFile(remoteSrcPath, localDstPath, progressChan); err != nil {
-				return fmt.Errorf("failed to download file %s: %v", entry.Name(), err)
-			}
+		remoteSrcPath := utils.ToSFTPPath(filepath.Join(remotePath, entry.relPath))
+		if err := copyFile(ctx, transfer, false, item.overwrite, remoteSrcPath, localDstPath, progressChan); err != nil {
+			return fmt.Errorf("failed to download file %s: %v", entry.relPath, err)
 		}
+		batch.fileDone(entry.size)
 	}
-
 	return nil
 }
 
@@ -798,12 +1411,16 @@ func (v *transferView) executeDelete() error {
 	path := filepath.Join(panel.path, entry.name)
 
 	var err error
-	itemType := "file"
-	if entry.isDir {
-		itemType = "directory"
-	}
+	itemType := entryKind(entry)
 
-	if panel == &v.localPanel {
+	// Usuwamy samo dowiązanie, nigdy zawartości katalogu, na który wskazuje
+	if isSymlink(entry) {
+		if panel == &v.localPanel {
+			err = os.Remove(path)
+		} else {
+			err = v.model.GetTransfer().RemoveRemoteFile(path)
+		}
+	} else if panel == &v.localPanel {
 		if entry.isDir {
 			err = os.RemoveAll(path)
 		} else {
@@ -865,37 +1482,92 @@ func (v *transferView) removeRemoteDirectory(path string, transfer *ssh.FileTran
 		}
 	}
 
-	// Na końcu usuń sam katalog
-	return transfer.RemoveRemoteFile(path)
+	// Na końcu usuń sam katalog
+	return transfer.RemoveRemoteFile(path)
+}
+
+// createDirectory tworzy nowy katalog
+func (v *transferView) createDirectory(name string) error {
+	if name == "" {
+		return fmt.Errorf("directory name cannot be empty")
+	}
+
+	// Sprawdź czy nazwa nie zawiera niedozwolonych znaków
+	if strings.ContainsAny(name, "/\\") {
+		return fmt.Errorf("directory name cannot contain path separators")
+	}
+
+	panel := v.getActivePanel()
+	path := filepath.Join(panel.path, name)
+
+	var err error
+	if panel == &v.localPanel {
+		err = os.MkdirAll(path, 0755)
+	} else {
+		if !v.connected {
+			return fmt.Errorf("not connected to remote host")
+		}
+		transfer := v.model.GetTransfer()
+		err = transfer.CreateRemoteDirectory(path)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to create directory: %v", err)
+	}
+
+	// Odśwież panel
+	if panel == &v.localPanel {
+		err = v.updateLocalPanel()
+	} else {
+		err = v.updateRemotePanel()
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to refresh panel: %v", err)
+	}
+	v.selectEntryByName(panel, name)
+
+	v.statusMessage = fmt.Sprintf("Created directory '%s'", name)
+	return nil
 }
 
-// createDirectory tworzy nowy katalog
-func (v *transferView) createDirectory(name string) error {
+// createFile tworzy pusty plik w aktywnym panelu
+func (v *transferView) createFile(name string) error {
 	if name == "" {
-		return fmt.Errorf("directory name cannot be empty")
+		return fmt.Errorf("file name cannot be empty")
 	}
 
 	// Sprawdź czy nazwa nie zawiera niedozwolonych znaków
 	if strings.ContainsAny(name, "/\\") {
-		return fmt.Errorf("directory name cannot contain path separators")
+		return fmt.Errorf("file name cannot contain path separators")
 	}
 
 	panel := v.getActivePanel()
 	path := filepath.Join(panel.path, name)
 
+	// Istniejącego pliku nie nadpisujemy
 	var err error
 	if panel == &v.localPanel {
-		err = os.MkdirAll(path, 0755)
+		var file *os.File
+		if file, err = os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644); err == nil {
+			err = file.Close()
+		}
 	} else {
 		if !v.connected {
 			return fmt.Errorf("not connected to remote host")
 		}
 		transfer := v.model.GetTransfer()
-		err = transfer.CreateRemoteDirectory(path)
+		if _, statErr := transfer.GetRemoteFileInfo(utils.ToSFTPPath(path)); statErr == nil {
+			return fmt.Errorf("'%s' already exists", name)
+		}
+		err = transfer.CreateRemoteFile(path)
 	}
 
 	if err != nil {
-		return fmt.Errorf("failed to create directory: %v", err)
+		if errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("'%s' already exists", name)
+		}
+		return fmt.Errorf("failed to create file: %v", err)
 	}
 
 	// Odśwież panel
@@ -908,8 +1580,9 @@ func (v *transferView) createDirectory(name string) error {
 	if err != nil {
 		return fmt.Errorf("failed to refresh panel: %v", err)
 	}
+	v.selectEntryByName(panel, name)
 
-	v.statusMessage = fmt.Sprintf("Created directory '%s'", name)
+	v.statusMessage = fmt.Sprintf("Created file '%s'", name)
 	return nil
 }
 
@@ -958,11 +1631,92 @@ func (v *transferView) renameFile(newName string) error {
 	if err != nil {
 		return fmt.Errorf("failed to refresh panel: %v", err)
 	}
+	v.selectEntryByName(panel, newName)
 
 	v.statusMessage = fmt.Sprintf("Renamed %s to %s", entry.name, newName)
 	return nil
 }
 
+// showChmodPopup otwiera popup zmiany uprawnień wypełniony aktualnym trybem wpisu
+func (v *transferView) showChmodPopup() {
+	panel := v.getActivePanel()
+	if len(panel.entries) == 0 || panel.selectedIndex >= len(panel.entries) {
+		return
+	}
+	entry := panel.entries[panel.selectedIndex]
+	if entry.name == ".." {
+		return
+	}
+
+	current := fmt.Sprintf("%04o", entry.mode.Perm())
+	v.popup = components.NewPopup(
+		components.PopupChmod,
+		"Change Permissions",
+		fmt.Sprintf("Octal mode for '%s' (current %s):", entry.name, current),
+		50,
+		7,
+		v.width,
+		v.height,
+	)
+	v.popup.Input.SetValue(current)
+	v.popup.Input.CursorEnd()
+	v.popup.Input.Focus()
+}
+
+// parseOctalMode parsuje uprawnienia w zapisie ósemkowym, np. "755" lub "0644"
+func parseOctalMode(value string) (os.FileMode, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || len(value) > 4 {
+		return 0, fmt.Errorf("invalid mode '%s': expected octal digits, e.g. 755", value)
+	}
+	mode, err := strconv.ParseUint(value, 8, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid mode '%s': expected octal digits, e.g. 755", value)
+	}
+	return os.FileMode(mode) & os.ModePerm, nil
+}
+
+// chmodFile zmienia uprawnienia zaznaczonego wpisu w aktywnym panelu
+func (v *transferView) chmodFile(value string) error {
+	mode, err := parseOctalMode(value)
+	if err != nil {
+		return err
+	}
+
+	panel := v.getActivePanel()
+	if len(panel.entries) == 0 || panel.selectedIndex >= len(panel.entries) {
+		return fmt.Errorf("no file selected")
+	}
+
+	entry := panel.entries[panel.selectedIndex]
+	if entry.name == ".." {
+		return fmt.Errorf("cannot change permissions of parent directory reference")
+	}
+
+	path := filepath.Join(panel.path, entry.name)
+	if panel == &v.localPanel {
+		err = os.Chmod(path, mode)
+	} else {
+		err = v.model.GetTransfer().ChmodRemote(path, mode)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to change permissions: %v", err)
+	}
+
+	// Odświeżenie panelu, aby pokazać nowe uprawnienia
+	if panel == &v.localPanel {
+		err = v.updateLocalPanel()
+	} else {
+		err = v.updateRemotePanel()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to refresh panel: %v", err)
+	}
+
+	v.statusMessage = fmt.Sprintf("Changed permissions of %s to %04o", entry.name, mode)
+	return nil
+}
+
 // handleError obsługuje błędy i wyświetla komunikat
 func (v *transferView) handleError(err error) {
 	if err != nil {
@@ -979,9 +1733,40 @@ func (v *transferView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		v.width = msg.Width
 		v.height = msg.Height
 		v.model.UpdateWindowSize(msg.Width, msg.Height)
+		if v.preview != nil {
+			v.preview.viewport.Width, v.preview.viewport.Height = v.previewSize()
+		}
 		v.mutex.Unlock()
 		return v, nil
 
+	case dirSizeMsg:
+		v.finishDirSize(msg)
+		return v, nil
+
+	case copyScanMsg:
+		v.finishCopyScan(msg)
+		return v, nil
+
+	case remoteEditReadyMsg:
+		return v.handleRemoteEditReady(msg)
+
+	case remoteEditClosedMsg:
+		return v.handleRemoteEditClosed(msg)
+
+	case remoteEditDoneMsg:
+		return v.handleRemoteEditDone(msg)
+
+	case remoteCopyDoneMsg:
+		return v.handleRemoteCopyDone(msg)
+
+	case spinner.TickMsg:
+		if v.dirSize == nil {
+			return v, nil
+		}
+		var cmd tea.Cmd
+		v.dirSize.spinner, cmd = v.dirSize.spinner.Update(msg)
+		return v, cmd
+
 	case transferProgressMsg:
 		v.mutex.Lock()
 		v.progress = ssh.TransferProgress(msg)
@@ -991,7 +1776,20 @@ func (v *transferView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case transferFinishedMsg:
 		v.mutex.Lock()
 		v.transferring = false
-		if msg.err != nil {
+		v.statusMessage = ""
+		if v.cancelCopy != nil {
+			v.cancelCopy()
+			v.cancelCopy = nil
+		}
+		if msg.cancelled {
+			// Częściowo skopiowany plik zostaje i zostanie wznowiony przy kolejnym kopiowaniu
+			v.statusMessage = "Transfer cancelled"
+			if v.getInactivePanel() == &v.localPanel {
+				v.updateLocalPanel()
+			} else {
+				v.updateRemotePanel()
+			}
+		} else if msg.err != nil {
 			v.popup = components.NewPopup(
 				components.PopupMessage,
 				"Transfer Error",
@@ -1021,10 +1819,39 @@ func (v *transferView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		v.mutex.Unlock()
 		return v, nil
 
+	case tea.MouseMsg:
+		return v.handleMouse(msg)
+
 	case connectionStatusMsg:
+		// Po udanym połączeniu wczytujemy katalog startowy panelu zdalnego
+		if msg.connected && msg.err == nil {
+			if msg.path != "" {
+				v.remotePanel.path = msg.path
+			}
+			if err := v.updateRemotePanel(); err != nil {
+				msg.connected, msg.err = false, err
+			} else if msg.warning != "" {
+				v.statusMessage = msg.warning
+			}
+		}
+
 		v.mutex.Lock()
 		v.connecting = false
-		if msg.err != nil {
+		var verificationRequired *ssh.HostKeyVerificationRequired
+		if errors.As(msg.err, &verificationRequired) && verificationRequired.Changed {
+			// Zmieniony klucz hosta ostrzegamy tak samo jak w widoku głównym
+			v.connected = false
+			keyMsg := newHostKeyVerificationMsg(verificationRequired)
+			v.popup = components.NewPopup(
+				components.PopupMessage,
+				"WARNING: HOST KEY CHANGED",
+				hostKeyChangedMessage(keyMsg),
+				74,
+				18+len(keyMsg.KnownKeys),
+				v.width,
+				v.height,
+			)
+		} else if msg.err != nil {
 			v.connected = false
 			v.popup = components.NewPopup(
 				components.PopupMessage,
@@ -1044,6 +1871,24 @@ func (v *transferView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		// Obsługa popupu
 		if v.popup != nil {
+			if v.popup.Type == components.PopupOverwrite {
+				return v.handlePopupInput(msg)
+			}
+			if v.popup.Type == components.PopupConfirmCopy {
+				return v.handleCopySummaryKey(msg)
+			}
+			if v.popup.Type == components.PopupBookmarks {
+				return v.handleBookmarksPopup(msg)
+			}
+			if v.popup.Type == components.PopupGoTo && msg.String() == "tab" {
+				v.completeGoToPath()
+				return v, nil
+			}
+			if v.popup.Type == components.PopupRemoteCopy && msg.String() == "enter" {
+				input := v.popup.Input.Value()
+				v.popup = nil
+				return v, v.startRemoteCopy(input)
+			}
 			switch msg.String() {
 			case "esc":
 				v.popup = nil
@@ -1080,6 +1925,16 @@ func (v *transferView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return v, nil
 		}
+		// Podgląd pliku przejmuje klawisze do czasu zamknięcia
+		if v.preview != nil {
+			return v.updatePreview(msg)
+		}
+
+		// Pole wyszukiwania przejmuje wszystkie klawisze
+		if v.searching {
+			return v.handleSearchKey(msg)
+		}
+
 		// Obsługa trybu pomocy
 		if v.showHelp {
 			switch msg.String() {
@@ -1091,13 +1946,29 @@ func (v *transferView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		// ESC przerywa liczenie rozmiaru katalogu
+		if v.dirSize != nil && msg.String() == "esc" {
+			v.cancelDirSize()
+			return v, nil
+		}
+
+		// ESC przerywa trwający transfer
+		if v.transferring && msg.String() == "esc" {
+			if v.cancelCopy != nil {
+				v.cancelCopy()
+				v.statusMessage = "Cancelling transfer..."
+			}
+			return v, nil
+		}
+
 		// Obsługa sekwencji ESC
 		if v.escPressed {
 			switch msg.String() {
 			case "0", "q":
-				if v.transferring {
+				if v.transferring || v.editing {
 					return v, nil
 				}
+				v.cancelDirSize()
 				if v.connected {
 					transfer := v.model.GetTransfer()
 					if transfer != nil {
@@ -1163,7 +2034,7 @@ func (v *transferView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						components.PopupDelete,
 						"Delete",
 						fmt.Sprintf("Delete %s '%s'? (y/n)",
-							map[bool]string{true: "directory", false: "file"}[entry.isDir],
+							entryKind(entry),
 							entry.name),
 						50,
 						7,
@@ -1187,6 +2058,10 @@ func (v *transferView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				v.popup = nil
 				return v, nil
 			}
+			if v.search != "" {
+				v.clearSearch()
+				return v, nil
+			}
 			v.escPressed = true
 			if v.escTimeout != nil {
 				v.escTimeout.Stop()
@@ -1200,10 +2075,10 @@ func (v *transferView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		// Standardowe klawisze funkcyjne
-		switch msg.String() {
+		switch v.model.Keys().VerticalKey(msg) {
 		case " ": // dodajemy jako pierwszy case
 			if !v.transferring {
-				ui.SwitchTheme()
+				v.model.CycleTheme()
 				return v, nil
 			}
 		case "f1":
@@ -1249,6 +2124,42 @@ func (v *transferView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return v, nil
 
+		case "C":
+			if v.connected && !v.transferring && !v.editing {
+				v.showRemoteCopyPopup()
+			}
+			return v, nil
+
+		case "/":
+			return v, v.startSearch()
+
+		case "N":
+			if v.search != "" {
+				v.nextMatch(-1)
+			}
+			return v, nil
+
+		case "n":
+			// Przy aktywnym wyszukiwaniu n przechodzi do następnego dopasowania
+			if v.search != "" {
+				v.nextMatch(1)
+				return v, nil
+			}
+			if !v.transferring {
+				v.popup = components.NewPopup(
+					components.PopupNewFile,
+					"Create File",
+					"Enter file name:",
+					50,
+					7,
+					v.width,
+					v.height,
+				)
+				v.popup.Input.SetValue("")
+				v.popup.Input.Focus()
+			}
+			return v, nil
+
 		case "f8", "d":
 			if !v.transferring {
 				panel := v.getActivePanel()
@@ -1263,7 +2174,7 @@ func (v *transferView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					components.PopupDelete,
 					"Delete",
 					fmt.Sprintf("Delete %s '%s'? (y/n)",
-						map[bool]string{true: "directory", false: "file"}[entry.isDir],
+						entryKind(entry),
 						entry.name),
 					50,
 					7,
@@ -1275,9 +2186,10 @@ func (v *transferView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Standardowe klawisze nawigacji i kontroli
 		case "q":
-			if v.transferring {
+			if v.transferring || v.editing {
 				return v, nil
 			}
+			v.cancelDirSize()
 			if v.connected {
 				transfer := v.model.GetTransfer()
 				if transfer != nil {
@@ -1294,13 +2206,13 @@ func (v *transferView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return v, nil
 
-		case "up", "w":
+		case "up":
 			panel := v.getActivePanel()
 			v.navigatePanel(panel, -1)
 			v.errorMessage = ""
 			return v, nil
 
-		case "down", "s":
+		case "down":
 			panel := v.getActivePanel()
 			v.navigatePanel(panel, 1)
 			v.errorMessage = ""
@@ -1321,19 +2233,107 @@ func (v *transferView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return v, nil
 
+		case ".":
+			v.toggleHiddenFiles()
+			return v, nil
+
+		case "t":
+			if !v.transferring {
+				v.togglePreserve()
+			}
+			return v, nil
+
+		case "L":
+			v.showRateLimitPopup()
+			return v, nil
+
+		case "p":
+			if !v.transferring {
+				v.showChmodPopup()
+			}
+			return v, nil
+
+		case "o", "O":
+			v.cycleSort(msg.String() == "O")
+			return v, nil
+
+		case "g":
+			if v.connected && !v.transferring {
+				v.showGoToPopup()
+			}
+			return v, nil
+
+		case "b":
+			if v.connected {
+				if err := v.addBookmark(); err != nil {
+					v.handleError(err)
+				}
+			}
+			return v, nil
+
+		case "B":
+			if v.connected && !v.transferring {
+				v.bookmarkIndex = 0
+				v.showBookmarksPopup()
+			}
+			return v, nil
+
+		case "z":
+			if !v.transferring && v.dirSize == nil {
+				return v, v.startDirSize()
+			}
+			return v, nil
+
+		case "e", "f4":
+			if v.connected && !v.transferring && !v.editing {
+				return v, v.startRemoteEdit()
+			}
+			return v, nil
+
+		case "v":
+			if !v.transferring {
+				if err := v.openPreview(); err != nil {
+					v.handleError(err)
+				}
+			}
+			return v, nil
+
 		case "x":
 			if !v.transferring {
 				panel := v.getActivePanel()
 				if len(panel.entries) > 0 && panel.selectedIndex < len(panel.entries) {
 					entry := panel.entries[panel.selectedIndex]
-					path := filepath.Join(panel.path, entry.name)
 					if entry.name != ".." {
-						v.model.ToggleSelection(path)
+						v.model.ToggleSelection(entryPath(panel, entry))
 					}
 				}
 			}
 			return v, nil
 
+		case "shift+up", "shift+down":
+			if !v.transferring {
+				v.extendSelection(map[string]int{"shift+up": -1, "shift+down": 1}[msg.String()])
+			}
+			return v, nil
+
+		case "+":
+			if !v.transferring {
+				v.selectAllEntries()
+			}
+			return v, nil
+
+		case "-":
+			if !v.transferring {
+				v.clearPanelSelection()
+			}
+			return v, nil
+
+		case "*":
+			if !v.transferring {
+				v.invertSelection()
+			}
+			return v, nil
+
 		}
 
 	case ssh.TransferProgress:
@@ -1359,6 +2359,22 @@ func (v *transferView) handleCommand(cmd string) error {
 		err := v.createDirectory(cmd)
 		v.popup = nil
 		return err
+	case components.PopupNewFile:
+		err := v.createFile(cmd)
+		v.popup = nil
+		return err
+	case components.PopupChmod:
+		err := v.chmodFile(cmd)
+		v.popup = nil
+		return err
+	case components.PopupGoTo:
+		err := v.goToPath(cmd)
+		v.popup = nil
+		return err
+	case components.PopupRateLimit:
+		err := v.setRateLimit(cmd)
+		v.popup = nil
+		return err
 	default:
 		v.popup = nil
 		return fmt.Errorf("unknown command")
@@ -1372,8 +2388,13 @@ func (v *transferView) formatProgressBar(width int) string {
 		return ""
 	}
 
+	var limitInfo string
+	if limit := v.model.GetTransfer().RateLimit(); limit > 0 {
+		limitInfo = fmt.Sprintf(" (limit %d KB/s)", limit)
+	}
+
 	percentage := float64(v.progress.TransferredBytes) / float64(v.progress.TotalBytes)
-	barWidth := width - 30 // Zostaw miejsce na procenty i prędkość
+	barWidth := max(width-42-len(limitInfo), 10) // Zostaw miejsce na procenty, prędkość i ETA
 	completedWidth := int(float64(barWidth) * percentage)
 
 	bar := fmt.Sprintf("[%s%s] %3.0f%%",
@@ -1381,16 +2402,54 @@ func (v *transferView) formatProgressBar(width int) string {
 		strings.Repeat(" ", barWidth-completedWidth),
 		percentage*100)
 
-	elapsed := time.Since(v.progress.StartTime).Seconds()
-	if elapsed == 0 {
-		elapsed = 1 // Zapobieganie dzieleniu przez zero
+	// Wygładzona prędkość z ostatnich próbek; średnia od startu tylko do pierwszego pomiaru
+	speed := v.progress.Speed
+	if speed <= 0 {
+		elapsed := time.Since(v.progress.StartTime).Seconds()
+		if elapsed == 0 {
+			elapsed = 1 // Zapobieganie dzieleniu przez zero
+		}
+		// Przy wznowieniu prędkość liczymy tylko z bajtów przesłanych w tej sesji
+		speed = float64(v.progress.TransferredBytes-v.progress.ResumeOffset) / elapsed
+	}
+
+	fileName := v.progress.FileName
+	if v.progress.Resumed {
+		fileName = fmt.Sprintf("%s (resuming at %.0f%%)", fileName,
+			float64(v.progress.ResumeOffset)/float64(v.progress.TotalBytes)*100)
 	}
-	speed := float64(v.progress.TransferredBytes) / elapsed
 
-	return fmt.Sprintf("%s %s %s/s",
-		v.progress.FileName,
+	line := fmt.Sprintf("%s %s %s/s ETA %s%s",
+		fileName,
 		bar,
-		formatSize(int64(speed)))
+		formatSize(int64(speed)),
+		formatETA(v.progress.TotalBytes-v.progress.TransferredBytes, speed),
+		limitInfo)
+
+	// Dla wielu plików pokaż postęp całej partii
+	if v.progress.BatchTotalFiles > 1 {
+		remainingBytes := v.progress.BatchTotalBytes - v.progress.BatchDoneBytes
+		line += fmt.Sprintf("\nTotal: %s of %s left, %d of %d files left, ETA %s",
+			formatSize(remainingBytes),
+			formatSize(v.progress.BatchTotalBytes),
+			v.progress.BatchTotalFiles-v.progress.BatchDoneFiles,
+			v.progress.BatchTotalFiles,
+			formatETA(remainingBytes, speed))
+	}
+
+	return line
+}
+
+// formatETA zwraca pozostały czas w formacie mm:ss (lub h:mm:ss)
+func formatETA(remaining int64, speed float64) string {
+	if speed <= 0 {
+		return "--:--"
+	}
+	seconds := int64(float64(remaining) / speed)
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds%3600/60, seconds%60)
+	}
+	return fmt.Sprintf("%02d:%02d", seconds/60, seconds%60)
 }
 
 // shouldShowDeleteConfirm sprawdza czy wyświetlić potwierdzenie usunięcia
@@ -1409,13 +2468,29 @@ var helpText = `
  Tab          - Switch panel
  Enter        - Enter directory
  F5/ESC+5/c   - Copy file
+ C            - Copy remote file/directory to another path on the server
  F6/ESC+6/r   - Rename
  F7/ESC+7/m   - Create directory
+ n            - Create empty file
  F8/ESC+8/d   - Delete
+ ESC          - Cancel running transfer
  F1           - Toggle help
  Ctrl+r       - Refresh
  q/ESC+0      - Exit
  x            - Select/Unselect file
+ Shift+Up/Dn  - Extend selection (Shift+click selects a range)
+ + / - / *    - Select all / none / invert in the active panel
+ /            - Search in the active panel (n/N next/previous match, ESC clears)
+ .            - Show/hide hidden files
+ t            - Keep permissions and modification times when copying (on/off)
+ L            - Limit transfer speed (KB/s, also for a running transfer)
+ v            - Preview text file (up to 1 MB)
+ e/F4         - Edit remote file in $EDITOR (uploaded if changed)
+ z            - Calculate directory size (ESC cancels)
+ g            - Go to path (Tab completes directory names)
+ b / B        - Bookmark current directory / open bookmarks
+ p            - Change permissions (chmod)
+ o / O        - Cycle sort key (name, size, modified, perms) / reverse order
 
  Navigation
  ----------
@@ -1425,8 +2500,8 @@ var helpText = `
 
 func (v *transferView) renderShortcuts() string {
 	// Nagłówki tabeli i skróty
-	headers := []string{"Switch Panel", "Select", "Copy", "Rename", "MkDir", "Delete", "Help", "Theme", "Exit"}
-	shortcuts := []string{"[Tab]", "[x]", "[F5|ESC+5|c]", "[F6|ESC+6|r]", "[F7|ESC+7|m]", "[F8|ESC+8|d]", "[F1]", "[space]", "[q|ESC+0]"}
+	headers := []string{"Switch Panel", "Select", "Copy", "Server Copy", "Rename", "MkDir", "New File", "Delete", "View", "Edit", "Size", "Go To", "Bookmarks", "Chmod", "Hidden", "Keep Attrs", "Speed Limit", "Sort", "Search", "Help", "Theme", "Exit"}
+	shortcuts := []string{"[Tab]", "[x|+|-|*]", "[F5|ESC+5|c]", "[C]", "[F6|ESC+6|r]", "[F7|ESC+7|m]", "[n]", "[F8|ESC+8|d]", "[v]", "[e|F4]", "[z]", "[g]", "[b|B]", "[p]", "[.]", "[t]", "[L]", "[o|O]", "[/]", "[F1]", "[space]", "[q|ESC+0]"}
 
 	// Funkcja stylizująca kolumny
 	var TableStyle = func(row, col int) lipgloss.Style {
@@ -1556,20 +2631,43 @@ func getFileType(entry FileEntry) string {
 // internal/ui/views/transfer.go
 // internal/ui/views/transfer.go
 
-func (v *transferView) renderFileList(entries []FileEntry, selected int, _ bool, width int) string {
-	t := table.New(
-		table.WithColumns([]table.Column{
-			{Title: " ", Width: 2}, // Kolumna na gwiazdkę
-			{Title: "Name", Width: width - 37},
-			{Title: "Size", Width: 10},
-			{Title: "Modified", Width: 19},
-		}),
-	)
+func (v *transferView) renderFileList(entries []FileEntry, selected int, remote bool, width int) string {
+	// Nagłówek kolumny sortowania dostaje strzałkę kierunku
+	titles := []string{"Name", "Size", "Modified", "Perms"}
+	arrow := "↑"
+	if v.sortDesc {
+		arrow = "↓"
+	}
+	titles[v.sortKey] += " " + arrow
+
+	// Kolumna właściciela tylko dla panelu zdalnego
+	const ownerWidth = 14
+	nameWidth := width - 48
+	if remote {
+		nameWidth -= ownerWidth + 2
+	}
+	nameWidth = max(nameWidth, 10)
+
+	columns := []table.Column{
+		{Title: " ", Width: 2}, // Kolumna na gwiazdkę
+		{Title: titles[0], Width: nameWidth},
+		{Title: titles[1], Width: 10},
+		{Title: titles[2], Width: 19},
+		{Title: titles[3], Width: 10},
+	}
+	if remote {
+		columns = append(columns, table.Column{Title: "Owner", Width: ownerWidth})
+	}
+	t := table.New(table.WithColumns(columns))
+	active := remote == v.remotePanel.active
 
 	var rows []table.Row
+	panel := &v.localPanel
+	if remote {
+		panel = &v.remotePanel
+	}
 	for _, entry := range entries {
-		path := filepath.Join(v.getActivePanel().path, entry.name)
-		isMarked := v.model.IsSelected(path)
+		isMarked := v.model.IsSelected(entryPath(panel, entry))
 
 		// Tworzenie wiersza
 		prefix := " "
@@ -1581,12 +2679,22 @@ func (v *transferView) renderFileList(entries []FileEntry, selected int, _ bool,
 		if entry.isDir {
 			name = "[" + name + "]"
 		}
+		if isSymlink(entry) {
+			name += " -> " + entry.linkTarget
+			if entry.brokenLink {
+				name += " (broken)"
+			}
+		}
 
 		row := table.Row{
 			prefix,
 			name,
 			formatSize(entry.size),
 			entry.modTime.Format("2006-01-02 15:04"),
+			formatPerms(entry),
+		}
+		if remote {
+			row = append(row, entry.owner)
 		}
 		rows = append(rows, row)
 	}
@@ -1630,6 +2738,9 @@ func (v *transferView) renderFileList(entries []FileEntry, selected int, _ bool,
 					Bold(true).
 					Background(ui.Highlight).
 					Foreground(lipgloss.Color("0"))
+			} else if active && v.matchesSearch(entry) {
+				// Dopasowania wyszukiwania w aktywnym panelu
+				style = ui.SuccessStyle.Underline(true)
 			} else if entry.isDir {
 				// Katalogi zawsze używają DirectoryStyle
 				style = ui.DirectoryStyle
@@ -1672,50 +2783,48 @@ func (v *transferView) renderFileList(entries []FileEntry, selected int, _ bool,
 	return coloredOutput.String()
 }
 
-func (v *transferView) ensureConnected() error {
-	transfer := v.model.GetTransfer()
+// noTransferHostMessage wyjaśnia, dlaczego tryb transferu nie ma hosta:
+// brak hostów w konfiguracji albo żaden nie jest zaznaczony
+func noTransferHostMessage(model *ui.Model) string {
+	if len(model.GetConfig().GetHosts()) == 0 {
+		return "No hosts configured.\nPress 'q' to return and add a host with 'h' first."
+	}
+	return "No host selected.\nPress 'q' to return and select a host first."
+}
+
+// connectTransfer łączy transfer plików z hostem (o ile nie jest już
+// połączony); nie zmienia stanu widoku, więc można ją wywołać z gorutyny
+func connectTransfer(model *ui.Model, transfer *ssh.FileTransfer, host *models.Host) error {
 	if transfer == nil {
 		return fmt.Errorf("no transfer client available")
 	}
-
-	host := v.model.GetSelectedHost()
 	if host == nil {
 		return fmt.Errorf("no host selected")
 	}
 
-	var authData string
-
-	if host.PasswordID < 0 {
-		// Obsługa klucza SSH
-		keyIndex := -(host.PasswordID + 1)
-		keys := v.model.GetKeys()
-		if keyIndex >= len(keys) {
-			return fmt.Errorf("invalid key ID")
-		}
+	authData, err := model.GetHostAuthData(host)
+	if err != nil {
+		return err
+	}
+	defer crypto.Wipe(authData)
 
-		key := keys[keyIndex]
-		keyPath, pathErr := key.GetKeyPath()
-		if pathErr != nil {
-			return fmt.Errorf("failed to get key path: %v", pathErr)
-		}
-		authData = keyPath
-	} else {
-		// Obsługa hasła
-		passwords := v.model.GetPasswords()
-		if host.PasswordID >= len(passwords) {
-			return fmt.Errorf("invalid password ID")
-		}
+	if err := transfer.Connect(host, authData); err != nil {
+		return fmt.Errorf("failed to establish SFTP connection: %w", err)
+	}
+	return nil
+}
 
-		password := passwords[host.PasswordID]
-		decryptedPass, decErr := password.GetDecrypted(v.model.GetCipher())
-		if decErr != nil {
-			return fmt.Errorf("failed to decrypt password: %v", decErr)
-		}
-		authData = decryptedPass
+func (v *transferView) ensureConnected() error {
+	transfer := v.model.GetTransfer()
+	if err := connectTransfer(v.model, transfer, v.model.GetSelectedHost()); err != nil {
+		return err
 	}
 
-	if err := transfer.Connect(host, authData); err != nil {
-		return fmt.Errorf("failed to establish SFTP connection: %v", err)
+	v.limited = transfer.Limited()
+
+	// Ostrzeżenia połączenia (np. niedostępny ssh-agent) pokazujemy w stopce
+	if warnings := transfer.Warnings(); len(warnings) > 0 {
+		v.statusMessage = "Warning: " + strings.Join(warnings, "; ")
 	}
 
 	return nil
@@ -1745,8 +2854,17 @@ func (v *transferView) renderFooter() string {
 		footerContent.WriteString("\n")
 	}
 
+	// Wyszukiwanie w panelu
+	if search := v.searchStatus(); search != "" {
+		footerContent.WriteString(search)
+		footerContent.WriteString("\n")
+	}
+
 	// Status
-	if v.statusMessage != "" {
+	if v.dirSize != nil {
+		footerContent.WriteString(ui.DescriptionStyle.Render(v.dirSizeStatus()))
+		footerContent.WriteString("\n")
+	} else if v.statusMessage != "" {
 		style := ui.DescriptionStyle
 		if v.shouldShowDeleteConfirm() {
 			style = ui.ErrorStyle
@@ -0,0 +1,179 @@
+// internal/ui/views/transfer_bookmarks.go
+
+package views
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"sshManager/internal/models"
+	"sshManager/internal/ui"
+	"sshManager/internal/ui/components"
+	"sshManager/internal/utils"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// bookmarkFor tworzy zakładkę dla ścieżki w panelu bieżącego hosta
+func (v *transferView) bookmarkFor(panel *Panel, path string) (models.Bookmark, error) {
+	host := v.model.GetSelectedHost()
+	if host == nil {
+		return models.Bookmark{}, fmt.Errorf("no host selected")
+	}
+	return models.Bookmark{
+		Host:   host.Name,
+		Remote: panel == &v.remotePanel,
+		Path:   path,
+	}, nil
+}
+
+// addBookmark zapisuje bieżący katalog aktywnego panelu w zakładkach
+func (v *transferView) addBookmark() error {
+	panel := v.getActivePanel()
+	bookmark, err := v.bookmarkFor(panel, panel.path)
+	if err != nil {
+		return err
+	}
+
+	if err := v.model.GetConfig().AddBookmark(bookmark); err != nil {
+		return err
+	}
+	if err := v.model.SaveConfig(); err != nil {
+		return fmt.Errorf("failed to save bookmark: %v", err)
+	}
+
+	v.statusMessage = fmt.Sprintf("Bookmarked %s", panel.path)
+	return nil
+}
+
+// activeBookmarks zwraca zakładki aktywnego panelu dla bieżącego hosta
+func (v *transferView) activeBookmarks() []string {
+	host := v.model.GetSelectedHost()
+	if host == nil {
+		return nil
+	}
+	return v.model.GetConfig().GetBookmarks(host.Name, v.getActivePanel() == &v.remotePanel)
+}
+
+// showBookmarksPopup pokazuje listę zakładek aktywnego panelu
+func (v *transferView) showBookmarksPopup() {
+	bookmarks := v.activeBookmarks()
+	if len(bookmarks) == 0 {
+		v.popup = components.NewPopup(
+			components.PopupMessage,
+			"Bookmarks",
+			"No bookmarks for this panel yet.\nPress 'b' to bookmark the current directory.",
+			50,
+			8,
+			v.width,
+			v.height,
+		)
+		return
+	}
+
+	if v.bookmarkIndex >= len(bookmarks) {
+		v.bookmarkIndex = len(bookmarks) - 1
+	}
+
+	side := "Local"
+	if v.getActivePanel() == &v.remotePanel {
+		side = "Remote"
+	}
+
+	var message strings.Builder
+	for i, path := range bookmarks {
+		if i == v.bookmarkIndex {
+			message.WriteString(ui.SelectedItemStyle.Render("> "+path) + "\n")
+		} else {
+			message.WriteString("  " + path + "\n")
+		}
+	}
+
+	v.popup = components.NewPopup(
+		components.PopupBookmarks,
+		side+" Bookmarks",
+		message.String(),
+		60,
+		len(bookmarks)+6,
+		v.width,
+		v.height,
+	)
+}
+
+// handleBookmarksPopup obsługuje klawisze w popupie zakładek
+func (v *transferView) handleBookmarksPopup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	bookmarks := v.activeBookmarks()
+	if len(bookmarks) == 0 {
+		v.popup = nil
+		return v, nil
+	}
+
+	switch v.model.Keys().VerticalKey(msg) {
+	case "esc":
+		v.popup = nil
+	case "up":
+		v.bookmarkIndex = (v.bookmarkIndex + len(bookmarks) - 1) % len(bookmarks)
+		v.showBookmarksPopup()
+	case "down":
+		v.bookmarkIndex = (v.bookmarkIndex + 1) % len(bookmarks)
+		v.showBookmarksPopup()
+	case "d":
+		if err := v.deleteBookmark(bookmarks[v.bookmarkIndex]); err != nil {
+			v.popup = nil
+			v.handleError(err)
+			return v, nil
+		}
+		v.showBookmarksPopup()
+	case "enter":
+		v.popup = nil
+		if err := v.goToBookmark(bookmarks[v.bookmarkIndex]); err != nil {
+			v.popup = components.NewPopup(
+				components.PopupMessage,
+				"Bookmark Error",
+				err.Error(),
+				50,
+				7,
+				v.width,
+				v.height,
+			)
+		}
+	}
+	return v, nil
+}
+
+// deleteBookmark usuwa zakładkę aktywnego panelu
+func (v *transferView) deleteBookmark(path string) error {
+	bookmark, err := v.bookmarkFor(v.getActivePanel(), path)
+	if err != nil {
+		return err
+	}
+	if err := v.model.GetConfig().DeleteBookmark(bookmark); err != nil {
+		return err
+	}
+	if err := v.model.SaveConfig(); err != nil {
+		return fmt.Errorf("failed to save bookmarks: %v", err)
+	}
+	return nil
+}
+
+// goToBookmark przechodzi do zapisanego katalogu po sprawdzeniu, że nadal istnieje
+func (v *transferView) goToBookmark(path string) error {
+	panel := v.getActivePanel()
+
+	var info os.FileInfo
+	var err error
+	if panel == &v.localPanel {
+		info, err = os.Stat(path)
+	} else {
+		info, err = v.model.GetTransfer().GetRemoteFileInfo(utils.ToSFTPPath(path))
+	}
+	if err != nil {
+		return fmt.Errorf("bookmarked directory %s is not available: %v", path, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("bookmarked path %s is not a directory", path)
+	}
+
+	return v.changeDirectory(panel, path)
+}
@@ -0,0 +1,110 @@
+// internal/ui/views/transfer_copyplan.go
+
+package views
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"sshManager/internal/ssh"
+	"sshManager/internal/utils"
+)
+
+// copyPlan to zawartość kopiowanego katalogu zebrana przy liczeniu rozmiaru
+// partii; kopiowanie idzie według planu, więc drzewo jest czytane tylko raz
+type copyPlan struct {
+	entries []planEntry // W kolejności kopiowania: katalog zawsze przed swoją zawartością
+	size    int64
+	files   int
+}
+
+// planEntry to katalog do utworzenia, plik do skopiowania albo błąd odczytu
+// drzewa, który przerwie kopiowanie w tym samym miejscu co bez planu
+type planEntry struct {
+	relPath string
+	isDir   bool
+	size    int64
+	err     error
+}
+
+// addFile dopisuje plik do planu
+func (p *copyPlan) addFile(relPath string, size int64) {
+	p.entries = append(p.entries, planEntry{relPath: relPath, size: size})
+	p.size += size
+	p.files++
+}
+
+// localCopyPlan przechodzi lokalny katalog; ESC (cancel) przerywa przejście
+func localCopyPlan(root string, cancel <-chan struct{}) (*copyPlan, error) {
+	// Walk nie wchodzi do dowiązania, więc dla dowiązanego katalogu zaczynamy od celu
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+
+	plan := &copyPlan{}
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if isCancelled(cancel) {
+			return errDirSizeCancelled
+		}
+		if err != nil {
+			plan.entries = append(plan.entries, planEntry{err: err})
+			return filepath.SkipAll
+		}
+
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return fmt.Errorf("failed to get relative path: %v", err)
+		}
+		if info.IsDir() {
+			if relPath != "." {
+				plan.entries = append(plan.entries, planEntry{relPath: relPath, isDir: true})
+			}
+			return nil
+		}
+		plan.addFile(relPath, info.Size())
+		return nil
+	})
+	return plan, err
+}
+
+// remoteCopyPlan przechodzi zdalny katalog; ESC (cancel) przerywa przejście
+func remoteCopyPlan(root string, transfer *ssh.FileTransfer, cancel <-chan struct{}) (*copyPlan, error) {
+	plan := &copyPlan{}
+	return plan, plan.addRemoteDir(root, "", transfer, cancel)
+}
+
+// addRemoteDir dopisuje do planu zawartość zdalnego katalogu root/relDir;
+// błąd listowania kończy plan, tak jak kończyłby kopiowanie
+func (p *copyPlan) addRemoteDir(root, relDir string, transfer *ssh.FileTransfer, cancel <-chan struct{}) error {
+	if isCancelled(cancel) {
+		return errDirSizeCancelled
+	}
+
+	dir := utils.ToSFTPPath(filepath.Join(root, relDir))
+	entries, err := transfer.ListRemoteFiles(dir)
+	if err != nil {
+		p.entries = append(p.entries, planEntry{err: fmt.Errorf("failed to list remote directory %s: %v", dir, err)})
+		return nil
+	}
+
+	for _, entry := range entries {
+		// Pomijamy "." i ".."
+		if entry.Name() == "." || entry.Name() == ".." {
+			continue
+		}
+		relPath := filepath.Join(relDir, entry.Name())
+		if !entry.IsDir() {
+			p.addFile(relPath, entry.Size())
+			continue
+		}
+		p.entries = append(p.entries, planEntry{relPath: relPath, isDir: true})
+		if err := p.addRemoteDir(root, relPath, transfer, cancel); err != nil {
+			return err
+		}
+		if last := p.entries[len(p.entries)-1]; last.err != nil {
+			return nil
+		}
+	}
+	return nil
+}
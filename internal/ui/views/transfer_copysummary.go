@@ -0,0 +1,143 @@
+// internal/ui/views/transfer_copysummary.go
+
+package views
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"sshManager/internal/ssh"
+	"sshManager/internal/ui/components"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// copySummary to kopiowanie wielu elementów czekające na potwierdzenie;
+// policzona partia jest użyta przy kopiowaniu, więc drzewa nie są czytane ponownie
+type copySummary struct {
+	items     []copyItem
+	fromLocal bool
+	batch     *copyBatch
+	srcPath   string // Katalog panelu źródłowego
+	dstPath   string // Katalog panelu docelowego
+}
+
+// copyScanMsg przenosi wynik liczenia rozmiaru kopiowanych elementów do widoku
+type copyScanMsg struct {
+	summary   *copySummary
+	cancelled bool
+	err       error
+}
+
+// beginCopy uruchamia kopiowanie; przy wielu elementach najpierw liczy ich
+// rozmiar i pyta o potwierdzenie, aby przypadkowy klawisz nie zaczął dużego transferu
+func (v *transferView) beginCopy(items []copyItem, fromLocal bool) tea.Cmd {
+	if len(items) < 2 {
+		return v.startCopy(items, fromLocal, nil)
+	}
+
+	summary := &copySummary{
+		items:     items,
+		fromLocal: fromLocal,
+		srcPath:   v.getActivePanel().path,
+		dstPath:   v.getInactivePanel().path,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Liczenie jest pierwszym etapem transferu: blokuje inne operacje, a ESC je przerywa
+	v.mutex.Lock()
+	v.transferring = true
+	v.cancelCopy = cancel
+	v.progress = ssh.TransferProgress{}
+	v.statusMessage = fmt.Sprintf("Calculating size of %d items... (ESC to cancel)", len(items))
+	v.mutex.Unlock()
+
+	transfer := v.model.GetTransfer()
+
+	return func() tea.Msg {
+		batch, err := newCopyBatch(items, fromLocal, transfer, ctx.Done())
+		summary.batch = batch
+		return copyScanMsg{
+			summary:   summary,
+			cancelled: ctx.Err() != nil || errors.Is(err, errDirSizeCancelled),
+			err:       err,
+		}
+	}
+}
+
+// finishCopyScan pokazuje podsumowanie kopiowania do potwierdzenia
+func (v *transferView) finishCopyScan(msg copyScanMsg) {
+	v.mutex.Lock()
+	v.transferring = false
+	v.statusMessage = ""
+	if v.cancelCopy != nil {
+		v.cancelCopy()
+		v.cancelCopy = nil
+	}
+	v.mutex.Unlock()
+
+	if msg.cancelled {
+		v.statusMessage = "Copy cancelled"
+		return
+	}
+	if msg.err != nil {
+		v.handleError(fmt.Errorf("failed to calculate copy size: %v", msg.err))
+		return
+	}
+
+	summary := msg.summary
+	src, dst := "local", "local"
+	if host := v.model.GetSelectedHost(); host != nil {
+		if summary.fromLocal {
+			dst = host.Name
+		} else {
+			src = host.Name
+		}
+	}
+
+	const width = 64
+	message := fmt.Sprintf("Copy %d items (%s in %d files)?\n\nFrom: %s\nTo:   %s",
+		len(summary.items),
+		formatSize(summary.batch.totalBytes),
+		summary.batch.totalFiles,
+		copyLocation(src, summary.srcPath, width-10),
+		copyLocation(dst, summary.dstPath, width-10),
+	)
+
+	v.copySummary = summary
+	v.popup = components.NewPopup(
+		components.PopupConfirmCopy,
+		"Confirm Copy",
+		message,
+		width,
+		10,
+		v.width,
+		v.height,
+	)
+}
+
+// copyLocation zwraca "miejsce:ścieżka" skrócone do maxWidth znaków
+func copyLocation(place, path string, maxWidth int) string {
+	prefix := place + ":"
+	return prefix + formatPath(path, max(maxWidth-len(prefix), 10))
+}
+
+// handleCopySummaryKey obsługuje klawisze popupu z podsumowaniem kopiowania
+func (v *transferView) handleCopySummaryKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	summary := v.copySummary
+	switch msg.String() {
+	case "y", "enter":
+		v.popup = nil
+		v.copySummary = nil
+		if summary == nil {
+			return v, nil
+		}
+		return v, v.startCopy(summary.items, summary.fromLocal, summary.batch)
+	case "n", "esc":
+		v.popup = nil
+		v.copySummary = nil
+		v.statusMessage = "Copy cancelled"
+	}
+	return v, nil
+}
@@ -0,0 +1,176 @@
+// internal/ui/views/transfer_dirsize.go
+
+package views
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"sshManager/internal/ssh"
+	"sshManager/internal/ui"
+	"sshManager/internal/utils"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// errDirSizeCancelled przerywa liczenie rozmiaru po wciśnięciu ESC
+var errDirSizeCancelled = errors.New("calculation cancelled")
+
+// dirSizeJob opisuje trwające liczenie rozmiaru katalogu
+type dirSizeJob struct {
+	name    string
+	cancel  chan struct{}
+	spinner spinner.Model
+}
+
+// dirSizeMsg przenosi wynik liczenia rozmiaru z goroutine do widoku
+type dirSizeMsg struct {
+	job   *dirSizeJob
+	size  int64
+	files int
+	err   error
+}
+
+// startDirSize uruchamia w tle liczenie rozmiaru zaznaczonego katalogu
+func (v *transferView) startDirSize() tea.Cmd {
+	panel := v.getActivePanel()
+	if len(panel.entries) == 0 || panel.selectedIndex >= len(panel.entries) {
+		return nil
+	}
+
+	entry := panel.entries[panel.selectedIndex]
+	if !entry.isDir || entry.name == ".." {
+		v.statusMessage = fmt.Sprintf("Size of '%s': %s", entry.name, formatSize(entry.size))
+		return nil
+	}
+
+	path := filepath.Join(panel.path, entry.name)
+	local := panel == &v.localPanel
+	transfer := v.model.GetTransfer()
+
+	job := &dirSizeJob{
+		name:    entry.name,
+		cancel:  make(chan struct{}),
+		spinner: spinner.New(spinner.WithSpinner(spinner.Dot), spinner.WithStyle(ui.DescriptionStyle)),
+	}
+	v.dirSize = job
+	v.statusMessage = ""
+
+	go func() {
+		var size int64
+		var files int
+		var err error
+		if local {
+			size, files, err = localDirSize(path, job.cancel)
+		} else {
+			size, files, err = remoteDirSize(path, transfer, job.cancel)
+		}
+		v.model.Program.Send(dirSizeMsg{job: job, size: size, files: files, err: err})
+	}()
+
+	return job.spinner.Tick
+}
+
+// cancelDirSize przerywa trwające liczenie rozmiaru
+func (v *transferView) cancelDirSize() {
+	if v.dirSize == nil {
+		return
+	}
+	close(v.dirSize.cancel)
+	v.statusMessage = fmt.Sprintf("Size calculation of '%s' cancelled", v.dirSize.name)
+	v.dirSize = nil
+}
+
+// finishDirSize pokazuje wynik liczenia w pasku statusu
+func (v *transferView) finishDirSize(msg dirSizeMsg) {
+	// Wynik przerwanego lub wcześniejszego liczenia ignorujemy
+	if msg.job != v.dirSize {
+		return
+	}
+	v.dirSize = nil
+
+	if msg.err != nil {
+		v.handleError(fmt.Errorf("failed to calculate size of '%s': %v", msg.job.name, msg.err))
+		return
+	}
+	v.statusMessage = fmt.Sprintf("Size of '%s': %s in %d files",
+		msg.job.name, formatSize(msg.size), msg.files)
+}
+
+// dirSizeStatus zwraca linię statusu wyświetlaną w trakcie liczenia
+func (v *transferView) dirSizeStatus() string {
+	return fmt.Sprintf("%s Calculating size of '%s'... (ESC to cancel)",
+		v.dirSize.spinner.View(), v.dirSize.name)
+}
+
+// isCancelled sprawdza bez blokowania czy liczenie zostało przerwane
+func isCancelled(cancel <-chan struct{}) bool {
+	select {
+	case <-cancel:
+		return true
+	default:
+		return false
+	}
+}
+
+// localDirSize sumuje rozmiary plików w lokalnym katalogu
+func localDirSize(path string, cancel <-chan struct{}) (int64, int, error) {
+	// Walk nie wchodzi do dowiązania, więc dla dowiązanego katalogu liczymy cel
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+
+	var size int64
+	var files int
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if isCancelled(cancel) {
+			return errDirSizeCancelled
+		}
+		if err != nil {
+			// Nieczytelne elementy pomijamy, aby policzyć resztę drzewa
+			return nil
+		}
+		if !info.IsDir() {
+			size += info.Size()
+			files++
+		}
+		return nil
+	})
+	return size, files, err
+}
+
+// remoteDirSize rekurencyjnie sumuje rozmiary plików w zdalnym katalogu
+func remoteDirSize(path string, transfer *ssh.FileTransfer, cancel <-chan struct{}) (int64, int, error) {
+	if isCancelled(cancel) {
+		return 0, 0, errDirSizeCancelled
+	}
+
+	entries, err := transfer.ListRemoteFiles(utils.ToSFTPPath(path))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list remote directory: %v", err)
+	}
+
+	var size int64
+	var files int
+	for _, entry := range entries {
+		if entry.Name() == "." || entry.Name() == ".." {
+			continue
+		}
+		if entry.IsDir() {
+			subSize, subFiles, err := remoteDirSize(filepath.Join(path, entry.Name()), transfer, cancel)
+			if errors.Is(err, errDirSizeCancelled) {
+				return 0, 0, err
+			}
+			// Nieczytelne podkatalogi pomijamy, tak jak przy liczeniu lokalnym
+			size += subSize
+			files += subFiles
+			continue
+		}
+		size += entry.Size()
+		files++
+	}
+	return size, files, nil
+}
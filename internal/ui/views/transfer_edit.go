@@ -0,0 +1,240 @@
+// internal/ui/views/transfer_edit.go
+
+package views
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"sshManager/internal/utils"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// remoteEdit opisuje zdalny plik otwarty w lokalnym edytorze
+type remoteEdit struct {
+	name       string
+	remotePath string
+	localPath  string // Kopia w katalogu tymczasowym (z tą samą nazwą, dla podświetlania składni)
+	tempDir    string
+	mode       os.FileMode // Uprawnienia zdalnego pliku, przywracane po wysłaniu
+	size       int64       // Rozmiar i czas modyfikacji zdalnego pliku przy pobraniu -
+	modTime    int64       // pozwalają wykryć zmianę na serwerze w trakcie edycji
+	hash       [sha256.Size]byte
+}
+
+// remoteEditReadyMsg przychodzi po pobraniu pliku do edycji
+type remoteEditReadyMsg struct {
+	edit *remoteEdit
+	err  error
+}
+
+// remoteEditClosedMsg przychodzi po zamknięciu edytora
+type remoteEditClosedMsg struct {
+	edit *remoteEdit
+	err  error
+}
+
+// remoteEditDoneMsg przychodzi po wysłaniu zmian (albo stwierdzeniu, że ich nie ma)
+type remoteEditDoneMsg struct {
+	edit    *remoteEdit
+	changed bool
+	err     error
+}
+
+// startRemoteEdit pobiera zaznaczony zdalny plik do katalogu tymczasowego;
+// edytor jest uruchamiany po nadejściu remoteEditReadyMsg
+func (v *transferView) startRemoteEdit() tea.Cmd {
+	panel := v.getActivePanel()
+	if len(panel.entries) == 0 || panel.selectedIndex >= len(panel.entries) {
+		return nil
+	}
+	if panel != &v.remotePanel {
+		v.handleError(fmt.Errorf("only remote files can be edited; switch to the remote panel with Tab"))
+		return nil
+	}
+
+	entry := panel.entries[panel.selectedIndex]
+	if entry.isDir || entry.name == ".." {
+		v.handleError(fmt.Errorf("'%s' is a directory", entry.name))
+		return nil
+	}
+
+	transfer := v.model.GetTransfer()
+	edit := &remoteEdit{
+		name:       entry.name,
+		remotePath: utils.ToSFTPPath(filepath.Join(panel.path, entry.name)),
+	}
+	v.editing = true
+	v.errorMessage = ""
+	v.statusMessage = fmt.Sprintf("Downloading '%s' for editing...", entry.name)
+
+	return func() tea.Msg {
+		info, err := transfer.GetRemoteFileInfo(edit.remotePath)
+		if err != nil {
+			return remoteEditReadyMsg{edit: edit, err: fmt.Errorf("failed to stat remote file: %v", err)}
+		}
+		edit.mode = info.Mode() & (os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky)
+		edit.size = info.Size()
+		edit.modTime = info.ModTime().Unix()
+
+		edit.tempDir, err = os.MkdirTemp("", "sshm-edit-")
+		if err != nil {
+			return remoteEditReadyMsg{edit: edit, err: fmt.Errorf("failed to create temporary directory: %v", err)}
+		}
+		edit.localPath = filepath.Join(edit.tempDir, edit.name)
+
+		if err := transfer.DownloadFile(context.Background(), edit.remotePath, edit.localPath, nil); err != nil {
+			return remoteEditReadyMsg{edit: edit, err: err}
+		}
+		// Kopia lokalna jest tylko dla nas, niezależnie od uprawnień na serwerze
+		if err := os.Chmod(edit.localPath, 0600); err != nil {
+			return remoteEditReadyMsg{edit: edit, err: fmt.Errorf("failed to protect local copy: %v", err)}
+		}
+		edit.hash, err = hashFile(edit.localPath)
+		return remoteEditReadyMsg{edit: edit, err: err}
+	}
+}
+
+// handleRemoteEditReady uruchamia edytor na pobranej kopii
+func (v *transferView) handleRemoteEditReady(msg remoteEditReadyMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		v.finishRemoteEdit(msg.edit, true)
+		v.handleError(fmt.Errorf("cannot edit '%s': %v", msg.edit.name, msg.err))
+		return v, nil
+	}
+
+	cmd, err := editorCommand(msg.edit.localPath)
+	if err != nil {
+		v.finishRemoteEdit(msg.edit, true)
+		v.handleError(err)
+		return v, nil
+	}
+
+	edit := msg.edit
+	v.statusMessage = fmt.Sprintf("Editing '%s'...", edit.name)
+	return v, tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return remoteEditClosedMsg{edit: edit, err: err}
+	})
+}
+
+// handleRemoteEditClosed wysyła plik z powrotem, jeśli jego treść się zmieniła
+func (v *transferView) handleRemoteEditClosed(msg remoteEditClosedMsg) (tea.Model, tea.Cmd) {
+	edit := msg.edit
+	if msg.err != nil {
+		// Edytor zakończony błędem (np. :cq w vim) oznacza rezygnację ze zmian
+		v.finishRemoteEdit(edit, true)
+		v.handleError(fmt.Errorf("editor exited with an error, '%s' was not uploaded: %v", edit.name, msg.err))
+		return v, nil
+	}
+
+	transfer := v.model.GetTransfer()
+	v.statusMessage = fmt.Sprintf("Uploading '%s'...", edit.name)
+
+	return v, func() tea.Msg {
+		hash, err := hashFile(edit.localPath)
+		if err != nil {
+			return remoteEditDoneMsg{edit: edit, err: err}
+		}
+		if hash == edit.hash {
+			return remoteEditDoneMsg{edit: edit}
+		}
+
+		// Nie nadpisujemy zmian, które ktoś zrobił na serwerze w trakcie edycji
+		info, err := transfer.GetRemoteFileInfo(edit.remotePath)
+		if err == nil && (info.Size() != edit.size || info.ModTime().Unix() != edit.modTime) {
+			return remoteEditDoneMsg{edit: edit, err: fmt.Errorf("the file was changed on the server while editing")}
+		}
+
+		if err := transfer.ReplaceRemoteFile(context.Background(), edit.localPath, edit.remotePath); err != nil {
+			return remoteEditDoneMsg{edit: edit, err: err}
+		}
+		if err := transfer.ChmodRemote(edit.remotePath, edit.mode); err != nil {
+			return remoteEditDoneMsg{edit: edit, changed: true, err: fmt.Errorf("uploaded, but failed to restore permissions %04o: %v", edit.mode.Perm(), err)}
+		}
+		return remoteEditDoneMsg{edit: edit, changed: true}
+	}
+}
+
+// handleRemoteEditDone kończy edycję i odświeża panel zdalny
+func (v *transferView) handleRemoteEditDone(msg remoteEditDoneMsg) (tea.Model, tea.Cmd) {
+	edit := msg.edit
+	if msg.err != nil {
+		// Niewysłane zmiany zostają w kopii lokalnej, żeby ich nie stracić
+		keep := !msg.changed
+		v.finishRemoteEdit(edit, !keep)
+		if keep {
+			v.handleError(fmt.Errorf("'%s' was not uploaded: %v (your changes are in %s)", edit.name, msg.err, edit.localPath))
+		} else {
+			v.handleError(msg.err)
+		}
+	} else {
+		v.finishRemoteEdit(edit, true)
+		if msg.changed {
+			v.statusMessage = fmt.Sprintf("Uploaded changes to '%s'", edit.name)
+		} else {
+			v.statusMessage = fmt.Sprintf("No changes to '%s'", edit.name)
+		}
+	}
+
+	if msg.changed {
+		if err := v.updateRemotePanel(); err != nil {
+			v.handleError(err)
+		}
+	}
+	return v, nil
+}
+
+// finishRemoteEdit kończy edycję; removeCopy usuwa katalog tymczasowy z kopią pliku
+func (v *transferView) finishRemoteEdit(edit *remoteEdit, removeCopy bool) {
+	v.editing = false
+	v.statusMessage = ""
+	if removeCopy && edit.tempDir != "" {
+		os.RemoveAll(edit.tempDir)
+	}
+}
+
+// editorCommand buduje polecenie edytora z $VISUAL lub $EDITOR (mogą zawierać
+// argumenty, np. "code -w"); domyślnie vi, a w Windows notepad
+func editorCommand(path string) (*exec.Cmd, error) {
+	editor := os.Getenv("VISUAL")
+	if strings.TrimSpace(editor) == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if strings.TrimSpace(editor) == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+
+	args := strings.Fields(editor)
+	if _, err := exec.LookPath(args[0]); err != nil {
+		return nil, fmt.Errorf("editor '%s' not found; set $EDITOR", args[0])
+	}
+	return exec.Command(args[0], append(args[1:], path)...), nil
+}
+
+// hashFile zwraca skrót SHA-256 zawartości pliku
+func hashFile(path string) ([sha256.Size]byte, error) {
+	var sum [sha256.Size]byte
+	file, err := os.Open(path)
+	if err != nil {
+		return sum, fmt.Errorf("failed to read local copy: %v", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return sum, fmt.Errorf("failed to read local copy: %v", err)
+	}
+	copy(sum[:], hash.Sum(nil))
+	return sum, nil
+}
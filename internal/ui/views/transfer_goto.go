@@ -0,0 +1,157 @@
+// internal/ui/views/transfer_goto.go
+
+package views
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"sshManager/internal/ui/components"
+	"sshManager/internal/utils"
+)
+
+// goToPrompt to treść popupu przejścia do ścieżki
+const goToPrompt = "Enter path (Tab completes directory names):"
+
+// showGoToPopup otwiera pole do wpisania ścieżki, wypełnione bieżącym katalogiem
+func (v *transferView) showGoToPopup() {
+	panel := v.getActivePanel()
+	v.popup = components.NewPopup(
+		components.PopupGoTo,
+		"Go To Path",
+		goToPrompt,
+		60,
+		7,
+		v.width,
+		v.height,
+	)
+	v.popup.Input.SetValue(panel.path)
+	v.popup.Input.CursorEnd()
+	v.popup.Input.Focus()
+}
+
+// pathSeparator zwraca separator ścieżek używany w danym panelu
+func (v *transferView) pathSeparator(panel *Panel) string {
+	if panel == &v.remotePanel {
+		return "/"
+	}
+	return string(filepath.Separator)
+}
+
+// resolvePath zamienia wpisaną ścieżkę na ścieżkę bezwzględną panelu:
+// rozwija "~" do katalogu domowego i dołącza ścieżki względne do bieżącego katalogu
+func (v *transferView) resolvePath(panel *Panel, input string) (string, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return "", fmt.Errorf("path cannot be empty")
+	}
+
+	remote := panel == &v.remotePanel
+	if input == "~" || strings.HasPrefix(input, "~/") || strings.HasPrefix(input, "~"+v.pathSeparator(panel)) {
+		home := getHomeDir()
+		if remote {
+			var err error
+			if home, err = v.model.GetTransfer().GetRemoteHomeDir(); err != nil {
+				return "", err
+			}
+		}
+		input = home + input[1:]
+	}
+
+	if !filepath.IsAbs(input) && !(remote && strings.HasPrefix(input, "/")) {
+		input = filepath.Join(panel.path, input)
+	}
+	return filepath.Clean(input), nil
+}
+
+// goToPath przechodzi aktywnym panelem do wpisanej ścieżki
+func (v *transferView) goToPath(input string) error {
+	panel := v.getActivePanel()
+	path, err := v.resolvePath(panel, input)
+	if err != nil {
+		return err
+	}
+	if err := v.changeDirectory(panel, path); err != nil {
+		return fmt.Errorf("cannot open %s: %v", path, err)
+	}
+	return nil
+}
+
+// completeGoToPath uzupełnia ostatni człon wpisanej ścieżki nazwą katalogu
+func (v *transferView) completeGoToPath() {
+	panel := v.getActivePanel()
+	value := v.popup.Input.Value()
+	sep := v.pathSeparator(panel)
+
+	// Podział na wpisany katalog i początek nazwy do uzupełnienia
+	cut := strings.LastIndexAny(value, "/"+sep) + 1
+	typedDir, prefix := value[:cut], value[cut:]
+
+	dir := panel.path
+	if typedDir != "" {
+		var err error
+		if dir, err = v.resolvePath(panel, typedDir); err != nil {
+			v.popup.Message = goToPrompt + "\n" + err.Error()
+			return
+		}
+	}
+
+	var names []string
+	if panel == &v.localPanel {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			v.popup.Message = goToPrompt + "\n" + err.Error()
+			return
+		}
+		for _, entry := range entries {
+			if entry.IsDir() && strings.HasPrefix(entry.Name(), prefix) {
+				names = append(names, entry.Name())
+			}
+		}
+	} else {
+		entries, err := v.model.GetTransfer().ListRemoteFiles(utils.ToSFTPPath(dir))
+		if err != nil {
+			v.popup.Message = goToPrompt + "\n" + err.Error()
+			return
+		}
+		for _, entry := range entries {
+			if entry.IsDir() && strings.HasPrefix(entry.Name(), prefix) {
+				names = append(names, entry.Name())
+			}
+		}
+	}
+
+	switch len(names) {
+	case 0:
+		v.popup.Message = goToPrompt + "\nNo matching directories"
+		return
+	case 1:
+		v.popup.Input.SetValue(typedDir + names[0] + sep)
+		v.popup.Message = goToPrompt
+	default:
+		sort.Strings(names)
+		v.popup.Input.SetValue(typedDir + commonPrefix(names))
+		candidates := []rune(strings.Join(names, "  "))
+		if maxWidth := v.popup.Width - 4; len(candidates) > maxWidth {
+			candidates = append(candidates[:maxWidth-3], []rune("...")...)
+		}
+		v.popup.Message = goToPrompt + "\n" + string(candidates)
+	}
+	v.popup.Input.CursorEnd()
+}
+
+// commonPrefix zwraca najdłuższy wspólny początek nazw
+func commonPrefix(names []string) string {
+	prefix := names[0]
+	for _, name := range names[1:] {
+		for !strings.HasPrefix(name, prefix) {
+			_, size := utf8.DecodeLastRuneInString(prefix)
+			prefix = prefix[:len(prefix)-size]
+		}
+	}
+	return prefix
+}
@@ -0,0 +1,132 @@
+// internal/ui/views/transfer_preview.go
+
+package views
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sshManager/internal/ui"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxPreviewSize to limit rozmiaru pliku otwieranego w podglądzie (1 MB)
+const maxPreviewSize = 1 << 20
+
+// filePreview przechowuje stan nakładki z podglądem pliku tekstowego
+type filePreview struct {
+	title    string
+	viewport viewport.Model
+}
+
+// openPreview wczytuje zaznaczony plik z aktywnego panelu i pokazuje go w podglądzie
+func (v *transferView) openPreview() error {
+	panel := v.getActivePanel()
+	if len(panel.entries) == 0 || panel.selectedIndex >= len(panel.entries) {
+		return fmt.Errorf("no file selected")
+	}
+
+	entry := panel.entries[panel.selectedIndex]
+	if entry.isDir {
+		return fmt.Errorf("cannot preview a directory")
+	}
+	if entry.size > maxPreviewSize {
+		return fmt.Errorf("'%s' is too large to preview (%s, limit %s)",
+			entry.name, formatSize(entry.size), formatSize(maxPreviewSize))
+	}
+
+	path := filepath.Join(panel.path, entry.name)
+
+	var data []byte
+	var err error
+	if panel == &v.localPanel {
+		data, err = readLocalPreview(path)
+	} else {
+		data, err = v.model.GetTransfer().ReadRemoteFile(path, maxPreviewSize)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Pliki binarne rozpoznajemy po bajcie NUL
+	if bytes.IndexByte(data, 0) >= 0 {
+		return fmt.Errorf("'%s' looks like a binary file and cannot be previewed", entry.name)
+	}
+
+	width, height := v.previewSize()
+	vp := viewport.New(width, height)
+	vp.SetContent(strings.ReplaceAll(string(data), "\t", "    "))
+
+	v.preview = &filePreview{
+		title:    path,
+		viewport: vp,
+	}
+	return nil
+}
+
+// readLocalPreview czyta lokalny plik z tym samym limitem rozmiaru co pliki zdalne
+func readLocalPreview(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %v", err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxPreviewSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %v", err)
+	}
+	if len(data) > maxPreviewSize {
+		return nil, fmt.Errorf("file is too large to preview (limit %s)", formatSize(maxPreviewSize))
+	}
+	return data, nil
+}
+
+// previewSize zwraca wymiary obszaru podglądu (bez ramki, tytułu i podpowiedzi)
+func (v *transferView) previewSize() (int, int) {
+	return max(v.width-8, 20), max(v.height-8, 5)
+}
+
+// updatePreview obsługuje klawisze w trybie podglądu
+func (v *transferView) updatePreview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q", "v":
+		v.preview = nil
+		return v, nil
+	case "w":
+		v.preview.viewport.LineUp(1)
+		return v, nil
+	case "s":
+		v.preview.viewport.LineDown(1)
+		return v, nil
+	}
+
+	var cmd tea.Cmd
+	v.preview.viewport, cmd = v.preview.viewport.Update(msg)
+	return v, cmd
+}
+
+// renderPreview rysuje nakładkę z podglądem w miejscu paneli (jak ekran pomocy)
+func (v *transferView) renderPreview() string {
+	var content strings.Builder
+	content.WriteString(ui.TitleStyle.Render(formatPath(v.preview.title, v.preview.viewport.Width)) + "\n")
+	content.WriteString(v.preview.viewport.View() + "\n")
+	content.WriteString(ui.DescriptionStyle.Render(fmt.Sprintf(
+		"%3.0f%%  ↑↓/w/s scroll, PgUp/PgDn page, ESC/q/v close",
+		v.preview.viewport.ScrollPercent()*100)))
+
+	return lipgloss.Place(
+		v.width,
+		v.height,
+		lipgloss.Center,
+		lipgloss.Center,
+		ui.WindowStyle.Render(content.String()),
+	)
+}
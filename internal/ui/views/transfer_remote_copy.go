@@ -0,0 +1,121 @@
+// internal/ui/views/transfer_remote_copy.go
+
+package views
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"sshManager/internal/ssh"
+	"sshManager/internal/ui/components"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// remoteCopyDoneMsg przychodzi po zakończeniu kopiowania na serwerze
+type remoteCopyDoneMsg struct {
+	name string
+	dst  string
+	err  error
+}
+
+// showRemoteCopyPopup pyta o ścieżkę docelową kopii zaznaczonego zdalnego wpisu
+func (v *transferView) showRemoteCopyPopup() {
+	panel := v.getActivePanel()
+	if len(panel.entries) == 0 || panel.selectedIndex >= len(panel.entries) {
+		return
+	}
+	if panel != &v.remotePanel {
+		v.handleError(fmt.Errorf("copying on the server works in the remote panel; switch to it with Tab"))
+		return
+	}
+
+	entry := panel.entries[panel.selectedIndex]
+	if entry.name == ".." {
+		return
+	}
+
+	v.popup = components.NewPopup(
+		components.PopupRemoteCopy,
+		"Copy on Server",
+		fmt.Sprintf("Copy '%s' to (file name or directory):", entry.name),
+		60,
+		7,
+		v.width,
+		v.height,
+	)
+	v.popup.Input.SetValue(panel.path + v.pathSeparator(panel))
+	v.popup.Input.CursorEnd()
+	v.popup.Input.Focus()
+}
+
+// startRemoteCopy kopiuje zaznaczony zdalny wpis do wpisanej ścieżki w tle;
+// ESC przerywa kopiowanie tak jak zwykły transfer
+func (v *transferView) startRemoteCopy(input string) tea.Cmd {
+	panel := &v.remotePanel
+	if len(panel.entries) == 0 || panel.selectedIndex >= len(panel.entries) {
+		return nil
+	}
+	entry := panel.entries[panel.selectedIndex]
+
+	dst, err := v.resolvePath(panel, input)
+	if err != nil {
+		v.handleError(err)
+		return nil
+	}
+	src := filepath.Join(panel.path, entry.name)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	v.cancelCopy = cancel
+	v.transferring = true
+	v.progress = ssh.TransferProgress{}
+	v.errorMessage = ""
+	v.statusMessage = fmt.Sprintf("Copying '%s' on the server... (ESC to cancel)", entry.name)
+
+	transfer := v.model.GetTransfer()
+	program := v.model.Program
+	return func() tea.Msg {
+		// Postęp jest dostępny tylko przy kopiowaniu przez SFTP
+		progressChan := make(chan ssh.TransferProgress)
+		forwarded := make(chan struct{})
+		go func() {
+			for progress := range progressChan {
+				program.Send(transferProgressMsg(progress))
+			}
+			close(forwarded)
+		}()
+
+		copied, err := transfer.CopyRemote(ctx, src, dst, progressChan)
+		close(progressChan)
+		<-forwarded
+		return remoteCopyDoneMsg{name: entry.name, dst: copied, err: err}
+	}
+}
+
+// handleRemoteCopyDone kończy kopiowanie i odświeża panel zdalny
+func (v *transferView) handleRemoteCopyDone(msg remoteCopyDoneMsg) (tea.Model, tea.Cmd) {
+	v.transferring = false
+	v.progress = ssh.TransferProgress{}
+	if v.cancelCopy != nil {
+		v.cancelCopy()
+		v.cancelCopy = nil
+	}
+
+	switch {
+	case errors.Is(msg.err, context.Canceled):
+		// Częściowa kopia zostaje na serwerze
+		v.statusMessage = fmt.Sprintf("Copy cancelled, %s may be incomplete", msg.dst)
+	case msg.err != nil:
+		v.statusMessage = ""
+		v.handleError(fmt.Errorf("cannot copy '%s': %v", msg.name, msg.err))
+	default:
+		v.statusMessage = fmt.Sprintf("Copied '%s' to %s", msg.name, msg.dst)
+	}
+
+	if err := v.updateRemotePanel(); err != nil {
+		v.handleError(err)
+	}
+	return v, nil
+}
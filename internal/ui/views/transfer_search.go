@@ -0,0 +1,125 @@
+// internal/ui/views/transfer_search.go
+
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"sshManager/internal/ui"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newSearchInput tworzy pole wyszukiwania plików w aktywnym panelu
+func newSearchInput() textinput.Model {
+	input := textinput.New()
+	input.Placeholder = "Search in panel..."
+	input.Prompt = "/ "
+	input.CharLimit = 128
+	return input
+}
+
+// startSearch otwiera pole wyszukiwania; wyszukiwanie działa na wczytanych
+// już wpisach aktywnego panelu, bez ponownego czytania katalogu
+func (v *transferView) startSearch() tea.Cmd {
+	v.searching = true
+	v.searchInput.SetValue(v.search)
+	v.searchInput.CursorEnd()
+	return v.searchInput.Focus()
+}
+
+// clearSearch kończy wyszukiwanie i usuwa podświetlenie dopasowań
+func (v *transferView) clearSearch() {
+	v.searching = false
+	v.search = ""
+	v.searchInput.Reset()
+	v.searchInput.Blur()
+}
+
+// handleSearchKey obsługuje klawisze, gdy pole wyszukiwania jest aktywne
+func (v *transferView) handleSearchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		v.clearSearch()
+		return v, nil
+	case "enter":
+		// Zatwierdzone wyszukiwanie zostaje, n/N przechodzą między dopasowaniami
+		v.searching = false
+		v.searchInput.Blur()
+		if v.search == "" {
+			v.clearSearch()
+		}
+		return v, nil
+	case "up", "down":
+		v.navigatePanel(v.getActivePanel(), map[string]int{"up": -1, "down": 1}[msg.String()])
+		return v, nil
+	}
+
+	var cmd tea.Cmd
+	v.searchInput, cmd = v.searchInput.Update(msg)
+	if value := v.searchInput.Value(); value != v.search {
+		v.search = value
+		// Każda zmiana tekstu szuka od początku listy
+		panel := v.getActivePanel()
+		if index := v.findMatch(panel, 0, 1); index >= 0 {
+			v.navigatePanel(panel, index-panel.selectedIndex)
+		}
+	}
+	return v, cmd
+}
+
+// nextMatch przechodzi do następnego (direction 1) lub poprzedniego (-1) dopasowania
+func (v *transferView) nextMatch(direction int) {
+	panel := v.getActivePanel()
+	if len(panel.entries) == 0 {
+		return
+	}
+	start := (panel.selectedIndex + direction + len(panel.entries)) % len(panel.entries)
+	if index := v.findMatch(panel, start, direction); index >= 0 {
+		v.navigatePanel(panel, index-panel.selectedIndex)
+	}
+}
+
+// findMatch szuka dopasowania od indeksu start w kierunku direction, zawijając
+// na końcu listy; zwraca -1, gdy nic nie pasuje
+func (v *transferView) findMatch(panel *Panel, start, direction int) int {
+	count := len(panel.entries)
+	for i := 0; i < count; i++ {
+		index := ((start+i*direction)%count + count) % count
+		if v.matchesSearch(panel.entries[index]) {
+			return index
+		}
+	}
+	return -1
+}
+
+// matchesSearch sprawdza, czy nazwa wpisu zawiera szukany tekst (bez względu na wielkość liter)
+func (v *transferView) matchesSearch(entry FileEntry) bool {
+	if v.search == "" || entry.name == ".." {
+		return false
+	}
+	return strings.Contains(strings.ToLower(entry.name), strings.ToLower(v.search))
+}
+
+// searchStatus zwraca linię wyszukiwania do stopki (pusta, gdy nie szukamy)
+func (v *transferView) searchStatus() string {
+	if v.searching {
+		return v.searchInput.View()
+	}
+	if v.search == "" {
+		return ""
+	}
+
+	matches := 0
+	for _, entry := range v.getActivePanel().entries {
+		if v.matchesSearch(entry) {
+			matches++
+		}
+	}
+	if matches == 0 {
+		return ui.ErrorStyle.Render(fmt.Sprintf("Search '%s': no matches (ESC clears)", v.search))
+	}
+	return ui.DescriptionStyle.Render(fmt.Sprintf("Search '%s': %d matches (n next, N previous, ESC clears)", v.search, matches))
+}
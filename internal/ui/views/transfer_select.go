@@ -0,0 +1,80 @@
+// internal/ui/views/transfer_select.go
+
+package views
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// Zaznaczenia plików są trzymane w modelu pod pełną ścieżką. Operacje na
+// zaznaczeniu (kopiowanie) biorą pod uwagę tylko wpisy aktywnego panelu,
+// a zmiana katalogu czyści zaznaczenie, aby nie mieszać plików z kilku katalogów.
+
+// entryPath zwraca ścieżkę wpisu panelu, pod którą jest zapisane jego zaznaczenie
+func entryPath(p *Panel, entry FileEntry) string {
+	return filepath.Join(p.path, entry.name)
+}
+
+// markRange zaznacza wpisy panelu od from do to (włącznie, w dowolnej kolejności)
+func (v *transferView) markRange(p *Panel, from, to int) {
+	if from > to {
+		from, to = to, from
+	}
+	for i := max(from, 0); i <= to && i < len(p.entries); i++ {
+		if entry := p.entries[i]; entry.name != ".." {
+			v.model.SetSelection(entryPath(p, entry), true)
+		}
+	}
+}
+
+// extendSelection zaznacza bieżący wpis i przesuwa kursor o direction,
+// zaznaczając także wpis docelowy (Shift+strzałki); na końcu listy nie zawija
+func (v *transferView) extendSelection(direction int) {
+	p := v.getActivePanel()
+	target := p.selectedIndex + direction
+	if target < 0 || target >= len(p.entries) {
+		v.markRange(p, p.selectedIndex, p.selectedIndex)
+		return
+	}
+	v.markRange(p, p.selectedIndex, target)
+	v.navigatePanel(p, direction)
+}
+
+// selectAllEntries zaznacza wszystkie wpisy aktywnego panelu
+func (v *transferView) selectAllEntries() {
+	p := v.getActivePanel()
+	v.markRange(p, 0, len(p.entries)-1)
+	v.statusMessage = fmt.Sprintf("Selected %d entries", len(v.selectedEntries(p)))
+}
+
+// clearPanelSelection odznacza wszystkie wpisy aktywnego panelu
+func (v *transferView) clearPanelSelection() {
+	p := v.getActivePanel()
+	for _, entry := range p.entries {
+		v.model.SetSelection(entryPath(p, entry), false)
+	}
+	v.statusMessage = "Selection cleared"
+}
+
+// invertSelection odwraca zaznaczenie wpisów aktywnego panelu
+func (v *transferView) invertSelection() {
+	p := v.getActivePanel()
+	for _, entry := range p.entries {
+		if entry.name != ".." {
+			v.model.ToggleSelection(entryPath(p, entry))
+		}
+	}
+	v.statusMessage = fmt.Sprintf("Selected %d entries", len(v.selectedEntries(p)))
+}
+
+// selectedEntries zwraca zaznaczone wpisy panelu w kolejności listy
+func (v *transferView) selectedEntries(p *Panel) []FileEntry {
+	var selected []FileEntry
+	for _, entry := range p.entries {
+		if entry.name != ".." && v.model.IsSelected(entryPath(p, entry)) {
+			selected = append(selected, entry)
+		}
+	}
+	return selected
+}
@@ -0,0 +1,16 @@
+//go:build !windows
+// +build !windows
+
+package utils
+
+import "golang.org/x/sys/unix"
+
+// DiskSpace returns the space available to the current user and the total
+// size of the filesystem holding path, in bytes
+func DiskSpace(path string) (free, total uint64, err error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(path, &stat); err != nil {
+		return 0, 0, err
+	}
+	return uint64(stat.Bavail) * uint64(stat.Bsize), uint64(stat.Blocks) * uint64(stat.Bsize), nil
+}
@@ -0,0 +1,20 @@
+//go:build windows
+// +build windows
+
+package utils
+
+import "golang.org/x/sys/windows"
+
+// DiskSpace returns the space available to the current user and the total
+// size of the volume holding path, in bytes
+func DiskSpace(path string) (free, total uint64, err error) {
+	dir, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	var totalFree uint64
+	if err := windows.GetDiskFreeSpaceEx(dir, &free, &total, &totalFree); err != nil {
+		return 0, 0, err
+	}
+	return free, total, nil
+}